@@ -1,7 +1,13 @@
 package bifrost
 
 import (
+	"context"
 	"embed"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/app"
 	"github.com/3-lines-studio/bifrost/internal/core"
@@ -9,8 +15,19 @@ import (
 
 type RedirectError = core.RedirectError
 
+// ErrHandled tells Bifrost that a loader already wrote the response via ResponseWriter.
+var ErrHandled = core.ErrHandled
+
+// ResponseWriter returns the writer for the current page request so a loader can
+// own the response (file downloads, CSV, ...). Return ErrHandled after writing.
+func ResponseWriter(req *http.Request) http.ResponseWriter {
+	return core.ResponseWriter(req)
+}
+
 type StaticPathData = core.StaticPathData
 
+type StaticDataStream = core.StaticDataStream
+
 type PageOption = core.PageOption
 
 type Framework = core.Framework
@@ -29,6 +46,9 @@ func WithFramework(fw core.Framework) ConfigOption {
 
 type App = app.App
 
+// RouteInfo describes a registered page route; see App.Routes.
+type RouteInfo = core.RouteInfo
+
 func New(assetsFS embed.FS, routes ...Route) *App {
 	return app.New(assetsFS, routes...)
 }
@@ -41,10 +61,61 @@ func NewWithOptions(assetsFS embed.FS, opts []ConfigOption, routes ...Route) *Ap
 	return app.NewWithOptions(assetsFS, opts, routes...)
 }
 
+// ListenAndServeWithGracePeriod serves a on addr and shuts down gracefully on SIGTERM or
+// SIGINT: new connections are refused, in-flight requests get up to the WithGracePeriod
+// duration (default 30s) to finish, then the Bun runtime is stopped.
+func ListenAndServeWithGracePeriod(addr string, a *App) error {
+	return app.ListenAndServeWithGracePeriod(addr, a)
+}
+
 func Page(pattern string, componentPath string, opts ...PageOption) Route {
 	return core.Page(pattern, componentPath, opts...)
 }
 
+type RouteGroup = core.RouteGroup
+
+// Group returns a RouteGroup for registering pages that share opts. Its Page method puts
+// the group's options before the page's own, so page options win; Group nests a group
+// under a base path with more options; Routes lists the pages registered on it.
+func Group(opts ...PageOption) *RouteGroup {
+	return core.Group(opts...)
+}
+
+// PagesFromDir returns an SSR route for every .tsx/.jsx file under dir (relative to the
+// working directory): pages/about.tsx → /about, pages/blog/[slug].tsx → /blog/{slug},
+// pages/docs/[...path].tsx → /docs/{path...}, pages/index.tsx → /. Files and folders
+// starting with "_" are skipped. A later Page with the same pattern replaces the generated
+// route. Panics if dir cannot be read or a file name is not a valid route.
+func PagesFromDir(dir string) []Route {
+	routes, err := core.PagesFromFS(os.DirFS(dir), dir)
+	if err != nil {
+		panic(fmt.Sprintf("bifrost: PagesFromDir(%q): %v", dir, err))
+	}
+	return routes
+}
+
+// WithDependencies shares deps (typically a struct of DB pools and clients) with every
+// loader; read them with Deps.
+func WithDependencies(deps any) ConfigOption {
+	return core.WithDependencies(deps)
+}
+
+// Deps returns the dependencies set with WithDependencies as T, or the zero value of T
+// when none are set or the type does not match.
+func Deps[T any](req *http.Request) T {
+	return core.Deps[T](req)
+}
+
+// DepsFromContext is Deps for static data loaders, which receive a context instead of a request.
+func DepsFromContext[T any](ctx context.Context) T {
+	return core.DepsFromContext[T](ctx)
+}
+
+// RequestWithDeps attaches deps to req so loaders can be called directly in tests.
+func RequestWithDeps(req *http.Request, deps any) *http.Request {
+	return core.RequestWithDependencies(req, deps)
+}
+
 func WithLoader(loader core.PropsLoader) PageOption {
 	return core.WithLoader(loader)
 }
@@ -61,18 +132,315 @@ func WithStatic() PageOption {
 	return core.WithStatic()
 }
 
+// WithStaticNoJS is WithStatic without a client bundle: the exported HTML has no script
+// or props tags.
+func WithStaticNoJS() PageOption {
+	return core.WithStaticNoJS()
+}
+
 func WithStaticData(loader core.StaticDataLoader) PageOption {
 	return core.WithStaticData(loader)
 }
 
+// WithStaticDataStream is WithStaticData for large sites: paths are rendered as the
+// stream emits them instead of being loaded into one slice.
+func WithStaticDataStream(stream StaticDataStream) PageOption {
+	return core.WithStaticDataStream(stream)
+}
+
+type HydrationStrategy = core.HydrationStrategy
+
+const (
+	HydrationLazy      = core.HydrationLazy
+	HydrationImmediate = core.HydrationImmediate
+	HydrationVisible   = core.HydrationVisible
+)
+
+// WithHydrationStrategy sets when the page hydrates: when the browser is idle (the
+// default), immediately, or when #app scrolls into view.
+func WithHydrationStrategy(strategy HydrationStrategy) PageOption {
+	return core.WithHydrationStrategy(strategy)
+}
+
+// WithLoaderCache reuses the loader result for ttl across requests for which keyFn returns
+// the same non-empty key. See App.InvalidateLoaderCache.
+func WithLoaderCache(ttl time.Duration, keyFn func(*http.Request) string) PageOption {
+	return core.WithLoaderCache(ttl, keyFn)
+}
+
+type PageCacheStore = core.PageCacheStore
+
+type MemoryPageCache = core.MemoryPageCache
+
+// ErrNoPageCache is returned by CacheInvalidation for an app without WithPageCache.
+var ErrNoPageCache = core.ErrNoPageCache
+
+// WithPageCache serves SSR pages from store when the same component was already rendered
+// with the same props, instead of rendering them again. Use NewMemoryPageCache for a single
+// instance, or the cache/redis package to share pages between instances.
+func WithPageCache(store PageCacheStore) ConfigOption {
+	return core.WithPageCache(store)
+}
+
+// WithPrefetch serves the props of SSR pages as JSON under /__data and adds a script that
+// prefetches them when a link is hovered or focused. Client code reads them with
+// window.__bifrostPrefetch.get(href).
+func WithPrefetch() ConfigOption {
+	return core.WithPrefetch()
+}
+
+// NewMemoryPageCache returns an in-process LRU PageCacheStore holding at most maxEntries
+// pages, each for defaultTTL.
+func NewMemoryPageCache(maxEntries int, defaultTTL time.Duration) *MemoryPageCache {
+	return core.NewMemoryPageCache(maxEntries, defaultTTL)
+}
+
+// PageCacheKey returns the WithPageCache key of componentPath rendered with the props
+// serialised as propsJSON: the hex SHA-256 of the two concatenated.
+func PageCacheKey(componentPath string, propsJSON []byte) string {
+	return core.PageCacheKey(componentPath, propsJSON)
+}
+
+// CacheInvalidation deletes the WithPageCache entry stored under key, so the next request
+// for that page renders it again. It returns ErrNoPageCache when app has no page cache.
+func CacheInvalidation(app *App, key string) error {
+	return app.InvalidatePageCache(key)
+}
+
+// PropsTooLargeError is the error of a page whose props exceed WithMaxPropsSize.
+type PropsTooLargeError = core.PropsTooLargeError
+
+// WithMaxPropsSize answers 500 instead of rendering an SSR page whose props serialise to
+// more than bytes of JSON. Zero means no limit.
+func WithMaxPropsSize(bytes int64) PageOption {
+	return core.WithMaxPropsSize(bytes)
+}
+
+// WithRevalidate regenerates a static page in the background once its HTML is older than ttl.
+func WithRevalidate(ttl time.Duration) PageOption {
+	return core.WithRevalidate(ttl)
+}
+
 const PropHTMLLang = core.PropHTMLLang
 
 const PropHTMLClass = core.PropHTMLClass
 
+// WithSSRTimeout bounds each SSR render. Renders that exceed it are cancelled and
+// answered with 503 Service Unavailable. Defaults to 30s.
+func WithSSRTimeout(d time.Duration) ConfigOption {
+	return core.WithSSRTimeout(d)
+}
+
+type SSRFallbackMode = core.SSRFallbackMode
+
+const (
+	FallbackError         = core.FallbackError
+	FallbackClientOnly    = core.FallbackClientOnly
+	FallbackCachedOrError = core.FallbackCachedOrError
+)
+
+// ErrRendererUnavailable marks errors where the Bun renderer could not be reached.
+var ErrRendererUnavailable = core.ErrRendererUnavailable
+
+// WithSSRFallback sets what SSR pages serve when the Bun renderer is unavailable: a 500
+// (FallbackError, the default), the client-only HTML shell (FallbackClientOnly), or the
+// last render with the same props (FallbackCachedOrError).
+func WithSSRFallback(mode SSRFallbackMode) ConfigOption {
+	return core.WithSSRFallback(mode)
+}
+
+type PageTimeouts = core.PageTimeouts
+
+// WithTimeouts bounds each page request: Loader for the props loader, Render for the Bun
+// render (replaces WithSSRTimeout) and Total for the whole request including writing the
+// HTML. Any timeout answers with 503 Service Unavailable.
+func WithTimeouts(t PageTimeouts) ConfigOption {
+	return core.WithTimeouts(t)
+}
+
+const PropMessages = core.PropMessages
+
+type MessagesLoader = core.MessagesLoader
+
+// WithMessages injects a per-request message bundle into SSR props under "__messages"
+// and uses the returned locale for <html lang> unless the loader sets PropHTMLLang.
+func WithMessages(loader MessagesLoader) ConfigOption {
+	return core.WithMessages(loader)
+}
+
+// WithLogger logs the Bun renderer's stdout and stderr through logger, one record per
+// line with source=bun, instead of copying them to the process's own.
+func WithLogger(logger *slog.Logger) ConfigOption {
+	return core.WithLogger(logger)
+}
+
+// WithRequestLogger stores a per-request logger (method, path, request_id) in the
+// request context and logs a summary line with status and duration per request.
+func WithRequestLogger(logger *slog.Logger) ConfigOption {
+	return core.WithRequestLogger(logger)
+}
+
+type RequestModifier = core.RequestModifier
+
+// WithRequestModifier runs mod on every request before route matching. Modifiers chain in
+// the order they were added; one that panics fails the request with 500.
+func WithRequestModifier(mod RequestModifier) ConfigOption {
+	return core.WithRequestModifier(mod)
+}
+
+// Logger returns the request logger from ctx, or slog.Default() when none is set.
+func Logger(ctx context.Context) *slog.Logger {
+	return core.Logger(ctx)
+}
+
+// WithFavicon serves data at /favicon.ico with Cache-Control: public, max-age=86400.
+// A public/favicon.ico file still wins when present.
+func WithFavicon(data []byte, mimeType string) ConfigOption {
+	return core.WithFavicon(data, mimeType)
+}
+
+// WithStructuredErrors makes page errors, redirects and 404s answer with JSON when the
+// request's Accept header contains application/json.
+func WithStructuredErrors(enabled bool) ConfigOption {
+	return core.WithStructuredErrors(enabled)
+}
+
+// WithErrorTransform shows the message of transform(err) for page errors, also in
+// production. Redirects are not transformed, and the original error is logged first. The
+// transform is skipped in dev unless WithErrorTransformInDev is set.
+func WithErrorTransform(transform func(err error) error) ConfigOption {
+	return core.WithErrorTransform(transform)
+}
+
+// WithErrorTransformInDev applies the WithErrorTransform transform in dev as well.
+func WithErrorTransformInDev(enabled bool) ConfigOption {
+	return core.WithErrorTransformInDev(enabled)
+}
+
+// WithBuildTarget sets the Bun build target of client bundles: browser (the default), bun
+// or node. bifrost-build fails pages that read browser globals under bun or node.
+func WithBuildTarget(target string) ConfigOption {
+	return core.WithBuildTarget(target)
+}
+
+// WithBunSmol runs the Bun renderer with --smol, trading render throughput for a smaller
+// heap, in dev and in production. Off by default.
+func WithBunSmol(enabled bool) ConfigOption {
+	return core.WithBunSmol(enabled)
+}
+
+// WithRenderRetries retries a render up to n times with short backoff when the
+// connection to the Bun runtime fails. Render errors are not retried.
+func WithRenderRetries(n int) ConfigOption {
+	return core.WithRenderRetries(n)
+}
+
+// WithDiagnostics serves a dev-only diagnostics page at path (e.g. "/_bifrost/diag"):
+// renderer status, page entries, recent render errors and build timings. In production
+// the path answers 404.
+func WithDiagnostics(path string) ConfigOption {
+	return core.WithDiagnostics(path)
+}
+
+type LinkRewriter = core.LinkRewriter
+
+// CacheBustStrategy decides how asset URLs in page HTML change between builds.
+type CacheBustStrategy = core.CacheBustStrategy
+
+var (
+	// CacheBustFilename leaves asset URLs alone and relies on Bun's hashed file names.
+	CacheBustFilename = core.CacheBustFilename
+	// CacheBustTimestamp appends ?v=<unix seconds> of the build that wrote the manifest.
+	CacheBustTimestamp = core.CacheBustTimestamp
+)
+
+// CacheBustQueryString appends ?v=version to every asset URL in page HTML.
+func CacheBustQueryString(version string) CacheBustStrategy {
+	return core.CacheBustQueryString(version)
+}
+
+// WithCacheBust sets how asset URLs in page HTML are cache-busted (default
+// CacheBustFilename).
+func WithCacheBust(strategy CacheBustStrategy) ConfigOption {
+	return core.WithCacheBust(strategy)
+}
+
+// WithLinkRewriting passes every script, stylesheet and chunk URL through rewrite before
+// it is written into page HTML, e.g. to serve under a sub-path or from a CDN.
+func WithLinkRewriting(rewrite LinkRewriter) ConfigOption {
+	return core.WithLinkRewriting(rewrite)
+}
+
+// WithStaticDataCacheTTL sets how long dev reuses a static page's WithStaticData result
+// (default 5s); a negative d reloads on every request.
+func WithStaticDataCacheTTL(d time.Duration) ConfigOption {
+	return core.WithStaticDataCacheTTL(d)
+}
+
+// EnvironmentEnvVar is checked at startup against the environment recorded in the manifest.
+const EnvironmentEnvVar = core.EnvironmentEnvVar
+
+// WithEnvironment tags the build manifest, page HTML and log lines with an environment name.
+func WithEnvironment(name string) ConfigOption {
+	return core.WithEnvironment(name)
+}
+
+// WithGracePeriod sets how long ListenAndServeWithGracePeriod waits for in-flight requests
+// on shutdown; 30s by default, which d <= 0 keeps. It has no effect on other servers.
+func WithGracePeriod(d time.Duration) ConfigOption {
+	return core.WithGracePeriod(d)
+}
+
+// WithRuntimeData extracts files under srcPrefix in fsys to destPrefix inside a runtime
+// data directory before Bun starts. SSR code finds it via process.env.BIFROST_RUNTIME_DATA_DIR.
+// The files are removed on Stop.
+func WithRuntimeData(fsys embed.FS, srcPrefix, destPrefix string) ConfigOption {
+	return core.WithRuntimeData(fsys, srcPrefix, destPrefix)
+}
+
+type MetaTag = core.MetaTag
+
+// MetaName renders <meta name="..." content="...">. Values are HTML-escaped.
+func MetaName(name, content string) MetaTag {
+	return core.MetaName(name, content)
+}
+
+// MetaProperty renders <meta property="..." content="...">, e.g. Open Graph tags.
+func MetaProperty(property, content string) MetaTag {
+	return core.MetaProperty(property, content)
+}
+
+// MetaHTTPEquiv renders <meta http-equiv="..." content="...">.
+func MetaHTTPEquiv(equiv, content string) MetaTag {
+	return core.MetaHTTPEquiv(equiv, content)
+}
+
+// WithMetaTags adds tags to the head of every page (SSR, client-only and static
+// prerender), ahead of the tags the component renders itself.
+func WithMetaTags(tags ...MetaTag) ConfigOption {
+	return core.WithMetaTags(tags...)
+}
+
+// WithTitleTemplate wraps every page title, e.g. "%s | My App". Applies to SSR,
+// client-only and static pages.
+func WithTitleTemplate(tmpl string) ConfigOption {
+	return core.WithTitleTemplate(tmpl)
+}
+
+// WithDefaultTitle sets the <title> for pages whose head has none (default "Bifrost").
+func WithDefaultTitle(title string) ConfigOption {
+	return core.WithDefaultTitle(title)
+}
+
 func WithDefaultHTMLLang(lang string) ConfigOption {
 	return core.WithDefaultHTMLLang(lang)
 }
 
+// WithPageGroup tags the page with a feature area for build summaries and App.Routes.
+func WithPageGroup(name string) PageOption {
+	return core.WithPageGroup(name)
+}
+
 func WithHTMLLang(lang string) PageOption {
 	return core.WithHTMLLang(lang)
 }
@@ -80,3 +448,418 @@ func WithHTMLLang(lang string) PageOption {
 func WithHTMLClass(class string) PageOption {
 	return core.WithHTMLClass(class)
 }
+
+// WithContentType serves the page as ct. Non-HTML types (RSS, SVG, plain text) get the
+// component's rendered body alone, without the document shell or hydration scripts.
+func WithContentType(ct string) PageOption {
+	return core.WithContentType(ct)
+}
+
+// WithContentNegotiation makes an SSR page answer with its loader props as JSON when the
+// request prefers application/json or has ?format=json, and with the page otherwise.
+func WithContentNegotiation() PageOption {
+	return core.WithContentNegotiation()
+}
+
+// WithHeaders sets static response headers on every response of the page. They replace
+// WithDefaultHeaders and WithSecureHeaders values of the same name.
+func WithHeaders(headers http.Header) PageOption {
+	return core.WithHeaders(headers)
+}
+
+// WithDefaultHeaders sets static response headers on every page response.
+func WithDefaultHeaders(headers http.Header) ConfigOption {
+	return core.WithDefaultHeaders(headers)
+}
+
+// SecureHeadersConfig sets the values of the security headers; empty fields use the defaults.
+type SecureHeadersConfig = core.SecureHeadersConfig
+
+// WithSecureHeaders adds nosniff, frame, referrer and permissions headers to every response.
+func WithSecureHeaders() ConfigOption {
+	return core.WithSecureHeaders()
+}
+
+// WithSecureHeadersConfig is WithSecureHeaders with the header values taken from cfg.
+func WithSecureHeadersConfig(cfg SecureHeadersConfig) ConfigOption {
+	return core.WithSecureHeadersConfig(cfg)
+}
+
+const DefaultCSPPolicy = core.DefaultCSPPolicy
+
+// WithCSP sets a Content-Security-Policy header (DefaultCSPPolicy) on every response.
+func WithCSP() ConfigOption {
+	return core.WithCSP()
+}
+
+func WithCSPPolicy(policy string) ConfigOption {
+	return core.WithCSPPolicy(policy)
+}
+
+// WithCSPReportURI logs CSP violations posted to /_bifrost/csp-report and adds a
+// report-uri directive for that path and uri.
+func WithCSPReportURI(uri string) ConfigOption {
+	return core.WithCSPReportURI(uri)
+}
+
+// WithCSPReportForwarding has the server forward logged violations to the report URI
+// instead of browsers posting to it directly.
+func WithCSPReportForwarding(enabled bool) ConfigOption {
+	return core.WithCSPReportForwarding(enabled)
+}
+
+// PropNonce is the props key holding the request's CSP nonce under WithCSPNonce.
+const PropNonce = core.PropNonce
+
+// WithCSPNonce generates a nonce per request for script-src, the page's script tags and
+// the component's __nonce prop.
+func WithCSPNonce() ConfigOption {
+	return core.WithCSPNonce()
+}
+
+// CSPNonce returns the nonce WithCSPNonce generated for req, or "".
+func CSPNonce(req *http.Request) string {
+	return core.CSPNonce(req)
+}
+
+// HealthCheckPath is never redirected by WithCanonicalHost or WithHTTPS.
+const HealthCheckPath = core.HealthCheckPath
+
+// WithCanonicalHost redirects requests for any other host to host, keeping path and query.
+// An "https://" prefix also upgrades plain HTTP requests; a bare host redirects to https.
+func WithCanonicalHost(host string) ConfigOption {
+	return core.WithCanonicalHost(host)
+}
+
+// WithHTTPS redirects plain HTTP requests to https. X-Forwarded-Proto is honored only
+// through WithTrustedProxies or WithTrustProxy.
+func WithHTTPS() ConfigOption {
+	return core.WithHTTPS()
+}
+
+// WithTrustProxy takes the client IP from X-Forwarded-For, trusting hops proxies in front
+// of the app, and writes it to r.RemoteAddr; X-Forwarded-Proto sets r.URL.Scheme. 0
+// leaves the request unchanged.
+func WithTrustProxy(hops int) ConfigOption {
+	return core.WithTrustProxy(hops)
+}
+
+// WithTrustedProxies applies X-Forwarded-For, X-Forwarded-Proto and X-Forwarded-Host only
+// to requests whose immediate peer is in one of cidrs.
+func WithTrustedProxies(cidrs ...string) ConfigOption {
+	return core.WithTrustedProxies(cidrs...)
+}
+
+// ClientIP returns the request's client IP without the port.
+func ClientIP(req *http.Request) string {
+	return core.ClientIP(req)
+}
+
+const (
+	PreviewParam  = core.PreviewParam
+	PreviewCookie = core.PreviewCookie
+)
+
+// WithPreview renders static pages fresh through SSR, with live static data, for requests
+// validate accepts, e.g. a valid PreviewToken. Requires a rebuild after adding it.
+func WithPreview(validate func(*http.Request) bool) ConfigOption {
+	return core.WithPreview(validate)
+}
+
+// PreviewToken returns the ?preview= query parameter, or the bifrost_preview cookie.
+func PreviewToken(req *http.Request) string {
+	return core.PreviewToken(req)
+}
+
+// IsPreview reports whether a loader runs for a WithPreview render.
+func IsPreview(ctx context.Context) bool {
+	return core.IsPreview(ctx)
+}
+
+type RouteMetrics = core.RouteMetrics
+
+// WithPageMetrics keeps per-route request counts and durations in memory; see App.Metrics.
+func WithPageMetrics() ConfigOption {
+	return core.WithPageMetrics()
+}
+
+// WithStaticPreloadList adds a <link rel="preload"> for each path to every page head, with
+// as inferred from the extension (image, style, script, font, else fetch).
+func WithStaticPreloadList(paths []string) ConfigOption {
+	return core.WithStaticPreloadList(paths)
+}
+
+// WithCustomRouteError serves 5xx page errors for paths matching pattern (http.ServeMux
+// syntax; "/admin/*" means the "/admin/" subtree) with handler instead of the default page.
+func WithCustomRouteError(pattern string, handler http.Handler) ConfigOption {
+	return core.WithCustomRouteError(pattern, handler)
+}
+
+// RouteError returns the page error a WithCustomRouteError handler is serving.
+func RouteError(req *http.Request) error {
+	return core.RouteError(req)
+}
+
+// BuildPlugin adds a Bun plugin to client and SSR builds. BunPluginSource returns
+// TypeScript defining bfPlugin_<Name>() that returns a Bun.BunPlugin.
+type BuildPlugin = core.BuildPlugin
+
+// BuildPluginSource is a BuildPlugin given as a name and TypeScript source.
+type BuildPluginSource = core.BuildPluginSource
+
+// WithBuildEnv replaces process.env.KEY in client and SSR builds with the value, as a
+// string literal. The values are fixed at build time.
+func WithBuildEnv(env map[string]string) ConfigOption {
+	return core.WithBuildEnv(env)
+}
+
+// WithBuildConstants bakes constants into client and SSR bundles: each identifier NAME in
+// page code is replaced with its value as JSON. App.BuildConstants returns them to Go.
+func WithBuildConstants(constants map[string]any) ConfigOption {
+	return core.WithBuildConstants(constants)
+}
+
+// WithBuildPlugin runs plugin in every Bun build, in dev and in bifrost-build.
+func WithBuildPlugin(plugin BuildPlugin) ConfigOption {
+	return core.WithBuildPlugin(plugin)
+}
+
+// WithErrorBoundary renders the Fallback export of fallbackComponent in place of the page
+// when the page throws while rendering. Fallback gets the page props plus error.
+func WithErrorBoundary(fallbackComponent string) PageOption {
+	return core.WithErrorBoundary(fallbackComponent)
+}
+
+// WithLoadingShell serves the Loading export of loadingComponent as an SSR page's initial
+// body and renders the page over it in the browser.
+func WithLoadingShell(loadingComponent string) PageOption {
+	return core.WithLoadingShell(loadingComponent)
+}
+
+// WithAssetIntegrity checks embedded scripts and stylesheets against the hashes recorded
+// by bifrost-build when the app starts in production, and panics if one was modified.
+func WithAssetIntegrity(enabled bool) ConfigOption {
+	return core.WithAssetIntegrity(enabled)
+}
+
+// CSRFConfig sets the name, path, SameSite and Secure attributes of the WithCSRF cookie.
+type CSRFConfig = core.CSRFConfig
+
+// PropCSRF is the props key holding the request's CSRF token under WithCSRF; forms send it
+// back in a field of the same name.
+const PropCSRF = core.PropCSRF
+
+// CSRFHeader carries the CSRF token on requests that do not send a form.
+const CSRFHeader = core.CSRFHeader
+
+// WithCSRF requires a matching CSRF token on POST, PUT, PATCH and DELETE page requests and
+// passes each page its token as the __csrf prop.
+func WithCSRF(cfg CSRFConfig) ConfigOption {
+	return core.WithCSRF(cfg)
+}
+
+// CSRFToken returns the CSRF token WithCSRF issued for req, or "".
+func CSRFToken(req *http.Request) string {
+	return core.CSRFToken(req)
+}
+
+// WithBunNodeModulesPath adds a node_modules directory, such as a monorepo's hoisted
+// "../../node_modules", that Bun resolves bare imports from.
+func WithBunNodeModulesPath(path string) ConfigOption {
+	return core.WithBunNodeModulesPath(path)
+}
+
+// PropSlots is the props key holding WithSlotInjection fragments during the server render.
+const PropSlots = core.PropSlots
+
+// WithSlotInjection passes trusted server HTML to the component as
+// props.__bifrost_slots__[slot]. Slots are left out of the hydration props.
+func WithSlotInjection(slot, content string) PageOption {
+	return core.WithSlotInjection(slot, content)
+}
+
+// RouteIndex is the WithRouteIndex file served at /__bifrost/routes.json.
+type RouteIndex = core.RouteIndex
+
+// RouteIndexEntry is one exported path of the route index.
+type RouteIndexEntry = core.RouteIndexEntry
+
+// WithRouteIndex makes bifrost-build write /__bifrost/routes.json, listing every exported
+// static path with its title and meta tags.
+func WithRouteIndex() ConfigOption {
+	return core.WithRouteIndex()
+}
+
+// AssetCacheEntry is one asset of the WithStaticCacheManifest file.
+type AssetCacheEntry = core.AssetCacheEntry
+
+// WithStaticCacheManifest makes bifrost-build write asset-cache-manifest.json, served at
+// /asset-cache-manifest.json, listing every built asset with its sha384 hash for service
+// workers to precache.
+func WithStaticCacheManifest() ConfigOption {
+	return core.WithStaticCacheManifest()
+}
+
+// WithSRI makes bifrost-build record sha384 Subresource Integrity hashes and adds
+// integrity and crossorigin attributes to page script and stylesheet tags.
+func WithSRI() ConfigOption {
+	return core.WithSRI()
+}
+
+// WithOnFirstRender calls hook with a route's pattern the first time its page renders
+// successfully on the server. The hook runs on its own goroutine.
+func WithOnFirstRender(hook func(routePattern string)) ConfigOption {
+	return core.WithOnFirstRender(hook)
+}
+
+// WithNonce turns on CSP nonces generated by generator instead of crypto/rand. It must
+// return base64 for at least 16 bytes; otherwise crypto/rand is used for that request.
+func WithNonce(generator func() string) ConfigOption {
+	return core.WithNonce(generator)
+}
+
+// WithEmbedPublicAt serves the files of another embedded FS under mountPath, e.g.
+// "/static". It can be called once per mount; /dist/ always comes from the build.
+func WithEmbedPublicAt(fsys embed.FS, mountPath string) ConfigOption {
+	return core.WithEmbedPublicAt(fsys, mountPath)
+}
+
+// WithStatusPage renders componentPath as an SSR page, with statusCode and message props,
+// whenever a page route answers with code.
+func WithStatusPage(code int, componentPath string) ConfigOption {
+	return core.WithStatusPage(code, componentPath)
+}
+
+// WithStaticFileMaxAge sends Cache-Control: public, max-age=N with every public/ file.
+func WithStaticFileMaxAge(maxAge time.Duration) ConfigOption {
+	return core.WithStaticFileMaxAge(maxAge)
+}
+
+// WithStaticFileImmutable sends Cache-Control: public, max-age=31536000, immutable with
+// every public/ file. Use it only when public file names change with their content.
+func WithStaticFileImmutable() ConfigOption {
+	return core.WithStaticFileImmutable()
+}
+
+// ManifestVersion is the manifest.json version written by this Bifrost's bifrost-build.
+const ManifestVersion = core.ManifestVersion
+
+// WithManifestVersion sets the oldest embedded manifest.json version a production app
+// starts with (default ManifestVersion). Pass 0 to accept unversioned manifests.
+func WithManifestVersion(version int) ConfigOption {
+	return core.WithManifestVersion(version)
+}
+
+// WithCustomSSREntryTemplate replaces the generated SSR entry of each page with tmpl, a
+// text/template where {{.ComponentImport}} is the component's import path.
+func WithCustomSSREntryTemplate(tmpl string) ConfigOption {
+	return core.WithCustomSSREntryTemplate(tmpl)
+}
+
+// WithReactRuntime makes the generated entries import React from importSource, such as
+// preact/compat, and its /client and /server subpaths instead of react and react-dom.
+func WithReactRuntime(importSource string) ConfigOption {
+	return core.WithReactRuntime(importSource)
+}
+
+// PropRequest is the props key holding the WithRenderHeaders request headers.
+const PropRequest = core.PropRequest
+
+// WithRenderHeaders passes the named request headers to SSR components under PropRequest,
+// as {"headers": {"user-agent": "..."}}. Only the listed headers are forwarded.
+func WithRenderHeaders(headers ...string) ConfigOption {
+	return core.WithRenderHeaders(headers...)
+}
+
+// WithConcurrentStaticExport renders up to concurrency paths of a static page at once
+// during export. Routes are still recorded in data loader order.
+func WithConcurrentStaticExport(concurrency int) ConfigOption {
+	return core.WithConcurrentStaticExport(concurrency)
+}
+
+// WithBuildTime fixes the clock used during static export so builds are reproducible.
+// SSR code reads it from process.env.BIFROST_BUILD_TIME.
+func WithBuildTime(t time.Time) ConfigOption {
+	return core.WithBuildTime(t)
+}
+
+// BuildTime returns the WithBuildTime value while exporting static pages, or time.Now otherwise.
+func BuildTime(ctx context.Context) time.Time {
+	return core.BuildTime(ctx)
+}
+
+type User = core.User
+
+type UserProvider = core.UserProvider
+
+// WithUserProvider resolves the current user once per request. Loaders read it with
+// CurrentUser, and request log lines carry its ID as user_id.
+func WithUserProvider(provider UserProvider) ConfigOption {
+	return core.WithUserProvider(provider)
+}
+
+// CurrentUser returns the user resolved by WithUserProvider for the request that ctx
+// belongs to.
+func CurrentUser(ctx context.Context) (User, bool) {
+	return core.CurrentUser(ctx)
+}
+
+type FlagProvider = core.FlagProvider
+
+// PropFlags is the props key that carries WithFeatureFlags values to the component.
+const PropFlags = core.PropFlags
+
+// WithFeatureFlags adds the current user's feature flags to every SSR page's props under
+// "flags". Visitors without a user get a stable anonymous ID from a cookie.
+func WithFeatureFlags(provider FlagProvider) ConfigOption {
+	return core.WithFeatureFlags(provider)
+}
+
+type InputValidator = core.InputValidator
+
+type InputValidatorFunc = core.InputValidatorFunc
+
+type InputRule = core.InputRule
+
+type ValidationError = core.ValidationError
+
+// WithInputValidation checks request input before the page loader runs. Invalid requests
+// get 422 with {"errors": {...}}; accepted values are available through Validated.
+func WithInputValidation(validators ...InputValidator) PageOption {
+	return core.WithInputValidation(validators...)
+}
+
+// PathValue validates a path wildcard such as {id}.
+func PathValue(name string, rule InputRule) InputValidator {
+	return core.PathValue(name, rule)
+}
+
+// QueryValue validates a query parameter; a missing one is checked as "".
+func QueryValue(name string, rule InputRule) InputValidator {
+	return core.QueryValue(name, rule)
+}
+
+// UUID accepts a canonical UUID.
+func UUID() InputRule {
+	return core.UUID()
+}
+
+// Int accepts an integer between min and max, inclusive.
+func Int(min, max int) InputRule {
+	return core.Int(min, max)
+}
+
+// OneOf accepts one of values.
+func OneOf(values ...string) InputRule {
+	return core.OneOf(values...)
+}
+
+// Regex accepts values that match pattern as a whole. It panics on an invalid pattern.
+func Regex(pattern string) InputRule {
+	return core.Regex(pattern)
+}
+
+// Validated returns the input values WithInputValidation accepted for the request.
+func Validated(ctx context.Context) map[string]string {
+	return core.Validated(ctx)
+}
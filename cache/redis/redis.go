@@ -0,0 +1,44 @@
+// Package redis provides a bifrost.PageCacheStore backed by Redis, so rendered pages are
+// shared between instances and survive restarts.
+package redis
+
+import (
+	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// PageCache stores rendered pages in Redis under a key prefix.
+type PageCache struct {
+	client    *goredis.Client
+	keyPrefix string
+}
+
+// NewRedisPageCache returns a PageCache that stores pages in client under keyPrefix
+// followed by the page's cache key. Pages stored with a zero ttl do not expire.
+func NewRedisPageCache(client *goredis.Client, keyPrefix string) *PageCache {
+	return &PageCache{client: client, keyPrefix: keyPrefix}
+}
+
+// Get returns the page stored under key. Redis errors count as a miss, so the page is
+// rendered instead.
+func (c *PageCache) Get(key string) (string, bool) {
+	html, err := c.client.Get(context.Background(), c.keyPrefix+key).Result()
+	if err != nil {
+		return "", false
+	}
+	return html, true
+}
+
+// Set stores html under key for ttl. A failed write only costs a later render, so errors
+// are dropped.
+func (c *PageCache) Set(key string, html string, ttl time.Duration) {
+	_ = c.client.Set(context.Background(), c.keyPrefix+key, html, ttl).Err()
+}
+
+// Delete removes the page stored under key, so the next request renders it again. Errors
+// are dropped, as for Set.
+func (c *PageCache) Delete(key string) {
+	_ = c.client.Del(context.Background(), c.keyPrefix+key).Err()
+}
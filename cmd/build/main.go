@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/adapters/cli"
 	"github.com/3-lines-studio/bifrost/internal/adapters/framework"
@@ -32,33 +34,131 @@ func findGoModRoot(startDir string) string {
 	return startDir
 }
 
-func parseFlags(args []string) (mainFile string, fw core.Framework, remaining []string) {
-	fw = core.FrameworkReact
+type buildFlags struct {
+	mainFile        string
+	fw              core.Framework
+	exportTimeout   time.Duration
+	jobs            int
+	compressRuntime bool
+	compressSSR     bool
+	nodeModulesPath string
+	ssrLint         usecase.SSRLintMode
+	daemon          bool
+	stopDaemon      bool
+	remaining       []string
+}
+
+func parseFlags(args []string) (buildFlags, error) {
+	flags := buildFlags{fw: core.FrameworkReact}
 
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 
 		if arg == "--framework" || arg == "-f" {
 			if i+1 < len(args) {
-				fw = core.FrameworkFromString(strings.ToLower(args[i+1]))
+				flags.fw = core.FrameworkFromString(strings.ToLower(args[i+1]))
 				i++
 			}
 			continue
 		}
 
 		if after, ok := strings.CutPrefix(arg, "--framework="); ok {
-			fw = core.FrameworkFromString(strings.ToLower(after))
+			flags.fw = core.FrameworkFromString(strings.ToLower(after))
+			continue
+		}
+
+		if arg == "--export-timeout" || strings.HasPrefix(arg, "--export-timeout=") {
+			value, ok := strings.CutPrefix(arg, "--export-timeout=")
+			if !ok {
+				if i+1 >= len(args) {
+					return flags, fmt.Errorf("--export-timeout needs a duration such as 5m")
+				}
+				value = args[i+1]
+				i++
+			}
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return flags, fmt.Errorf("invalid --export-timeout %q: want a positive duration such as 5m", value)
+			}
+			flags.exportTimeout = d
+			continue
+		}
+
+		if arg == "--jobs" || arg == "-j" || strings.HasPrefix(arg, "--jobs=") {
+			value, ok := strings.CutPrefix(arg, "--jobs=")
+			if !ok {
+				if i+1 >= len(args) {
+					return flags, fmt.Errorf("--jobs needs a number of paths")
+				}
+				value = args[i+1]
+				i++
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return flags, fmt.Errorf("invalid --jobs %q: want a positive number", value)
+			}
+			flags.jobs = n
+			continue
+		}
+
+		if arg == "--node-modules-path" || strings.HasPrefix(arg, "--node-modules-path=") {
+			value, ok := strings.CutPrefix(arg, "--node-modules-path=")
+			if !ok {
+				if i+1 >= len(args) {
+					return flags, fmt.Errorf("--node-modules-path needs a directory")
+				}
+				value = args[i+1]
+				i++
+			}
+			flags.nodeModulesPath = value
+			continue
+		}
+
+		if arg == "--ssr-lint" || strings.HasPrefix(arg, "--ssr-lint=") {
+			value, ok := strings.CutPrefix(arg, "--ssr-lint=")
+			if !ok {
+				if i+1 >= len(args) {
+					return flags, fmt.Errorf("--ssr-lint needs off, warn or error")
+				}
+				value = args[i+1]
+				i++
+			}
+			mode, err := usecase.ParseSSRLintMode(value)
+			if err != nil {
+				return flags, err
+			}
+			flags.ssrLint = mode
+			continue
+		}
+
+		if arg == "--compress-runtime" {
+			flags.compressRuntime = true
+			continue
+		}
+
+		if arg == "--compress-ssr" {
+			flags.compressSSR = true
+			continue
+		}
+
+		if arg == "--daemon" || arg == "--server" {
+			flags.daemon = true
 			continue
 		}
 
-		if mainFile == "" && !strings.HasPrefix(arg, "-") {
-			mainFile = arg
+		if arg == "--stop" {
+			flags.stopDaemon = true
+			continue
+		}
+
+		if flags.mainFile == "" && !strings.HasPrefix(arg, "-") {
+			flags.mainFile = arg
 		} else {
-			remaining = append(remaining, arg)
+			flags.remaining = append(flags.remaining, arg)
 		}
 	}
 
-	return mainFile, fw, remaining
+	return flags, nil
 }
 
 func getAdapter(fw core.Framework) core.FrameworkAdapter {
@@ -66,18 +166,36 @@ func getAdapter(fw core.Framework) core.FrameworkAdapter {
 }
 
 func main() {
-	mainFile, fw, _ := parseFlags(os.Args[1:])
+	flags, err := parseFlags(os.Args[1:])
+	mainFile, fw := flags.mainFile, flags.fw
 
-	if mainFile == "" {
+	if err == nil && flags.stopDaemon {
+		stopBuildDaemon(mainFile)
+		return
+	}
+
+	if err != nil || mainFile == "" {
 		output := cli.NewOutput()
 		output.PrintHeader("Bifrost Build")
-		output.PrintError("Missing main.go file argument")
+		if err != nil {
+			output.PrintError("%v", err)
+		} else {
+			output.PrintError("Missing main.go file argument")
+		}
 		fmt.Println()
 		output.PrintStep("", "Usage: bifrost-build [flags] <main.go>")
 		output.PrintStep("", "Example: bifrost-build ./main.go")
 		fmt.Println()
 		output.PrintStep("", "Flags:")
-		output.PrintStep("", "  -f, --framework <name>  Framework to use (react)")
+		output.PrintStep("", "  -f, --framework <name>       Framework to use (react)")
+		output.PrintStep("", "      --export-timeout <dur>   Limit for the static export run (default 10m)")
+		output.PrintStep("", "  -j, --jobs <n>               Static paths of a page rendered at once during export")
+		output.PrintStep("", "      --compress-runtime       Embed the Bun renderer gzipped (smaller binary, slower start)")
+		output.PrintStep("", "      --compress-ssr           Embed the SSR bundles gzipped (smaller binary, slower start)")
+		output.PrintStep("", "      --node-modules-path <dir> Extra node_modules directory for bare imports")
+		output.PrintStep("", "      --ssr-lint <mode>        Browser globals at component top level: off, warn (default) or error")
+		output.PrintStep("", "      --daemon                 Keep the Bun build process running for later builds (also: --server)")
+		output.PrintStep("", "      --stop                   Stop the project's build daemon and exit")
 		os.Exit(1)
 	}
 
@@ -101,19 +219,54 @@ func main() {
 	output := cli.NewOutput()
 	adapter := getAdapter(fw)
 
-	runtime, err := process.NewRenderer(core.ModeDev, adapter.DevRendererSource(), "BIFROST_PROD=1")
+	plugins, err := usecase.LoadBuildPlugins(context.Background(), goModRoot, mainFileAbs)
+	if err != nil {
+		output.PrintHeader("Bifrost Build")
+		output.PrintError("Failed to load build plugins: %v", err)
+		os.Exit(1)
+	}
+
+	env := []string{"BIFROST_PROD=1"}
+	nodeModulesEnv, err := core.NodeModulesPathEnvEntry(flags.nodeModulesPath)
+	if err != nil {
+		output.PrintHeader("Bifrost Build")
+		output.PrintError("Invalid --node-modules-path: %v", err)
+		os.Exit(1)
+	}
+	if nodeModulesEnv != "" {
+		env = append(env, nodeModulesEnv)
+	}
+
+	source := process.InjectBuildPlugins(adapter.DevRendererSource(), plugins)
+	daemon := process.NewBuildDaemon(goModRoot)
+	daemonKey := process.BuildDaemonKey(source, originalCwd, env)
+	runtime, reused := daemon.Connect(daemonKey)
+	switch {
+	case reused:
+		output.PrintStep("", "Reusing build daemon")
+	case flags.daemon:
+		runtime, err = daemon.Start(source, daemonKey, env...)
+	default:
+		runtime, err = process.NewRenderer(core.ModeDev, source, nil, env...)
+	}
 	if err != nil {
 		output.PrintHeader("Bifrost Build")
 		output.PrintError("Failed to initialize build engine: %v", err)
 		os.Exit(1)
 	}
 	defer func() { _ = runtime.Stop() }()
+	runtime.SetBuildPlugins(core.BuildPluginIDs(plugins))
 
 	buildService := usecase.NewBuildService(runtime, fsAdapter, output, adapter)
 
 	input := usecase.BuildInput{
-		MainFile:    mainFileAbs,
-		OriginalCwd: goModRoot,
+		MainFile:        mainFileAbs,
+		OriginalCwd:     goModRoot,
+		ExportTimeout:   flags.exportTimeout,
+		Jobs:            flags.jobs,
+		CompressRuntime: flags.compressRuntime,
+		CompressSSR:     flags.compressSSR,
+		SSRLint:         flags.ssrLint,
 	}
 
 	result := buildService.BuildProject(context.Background(), input)
@@ -121,5 +274,32 @@ func main() {
 		output.PrintError("%v", result.Error)
 		os.Exit(1)
 	}
+}
+
+// stopBuildDaemon stops the build daemon of the project containing mainFile, or of the
+// working directory when no file is given.
+func stopBuildDaemon(mainFile string) {
+	output := cli.NewOutput()
+	dir, err := os.Getwd()
+	if err != nil {
+		output.PrintError("Failed to get current working directory: %v", err)
+		os.Exit(1)
+	}
+	if mainFile != "" {
+		if !filepath.IsAbs(mainFile) {
+			mainFile = filepath.Join(dir, mainFile)
+		}
+		dir = filepath.Dir(mainFile)
+	}
 
+	stopped, err := process.NewBuildDaemon(findGoModRoot(dir)).Stop()
+	if err != nil {
+		output.PrintError("Failed to stop build daemon: %v", err)
+		os.Exit(1)
+	}
+	if !stopped {
+		output.PrintStep("", "No build daemon running")
+		return
+	}
+	output.PrintSuccess("Build daemon stopped")
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/3-lines-studio/bifrost/internal/adapters/cli"
+	"github.com/3-lines-studio/bifrost/internal/usecase"
+)
+
+func parseFlags(args []string) (projectDir string, all bool) {
+	projectDir = "."
+	for _, arg := range args {
+		switch {
+		case arg == "--all":
+			all = true
+		case !strings.HasPrefix(arg, "-"):
+			projectDir = arg
+		}
+	}
+	return projectDir, all
+}
+
+func main() {
+	projectDir, all := parseFlags(os.Args[1:])
+
+	output := cli.NewOutput()
+	output.PrintHeader("Bifrost Clean")
+
+	absProjectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		output.PrintError("Failed to resolve project directory: %v", err)
+		os.Exit(1)
+	}
+
+	removed, err := usecase.CleanProject(absProjectDir, all)
+	for _, path := range removed {
+		output.PrintSuccess("Removed %s", path)
+	}
+	if err != nil {
+		output.PrintError("%v", err)
+		os.Exit(1)
+	}
+
+	if len(removed) == 0 {
+		output.PrintDone("Nothing to clean")
+		return
+	}
+	output.PrintDone("Clean complete!")
+}
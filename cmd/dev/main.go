@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/adapters/cli"
+)
+
+const (
+	defaultURL   = "http://localhost:8080"
+	readyTimeout = 2 * time.Minute
+)
+
+type devFlags struct {
+	mainFile string
+	url      string
+	open     bool
+	noOpen   bool
+}
+
+func parseFlags(args []string) devFlags {
+	flags := devFlags{url: defaultURL}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--open":
+			flags.open = true
+		case arg == "--no-open":
+			flags.noOpen = true
+		case arg == "--url":
+			if i+1 < len(args) {
+				flags.url = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(arg, "--url="):
+			flags.url = strings.TrimPrefix(arg, "--url=")
+		case flags.mainFile == "" && !strings.HasPrefix(arg, "-"):
+			flags.mainFile = arg
+		}
+	}
+
+	return flags
+}
+
+// waitForReady polls url until the app answers without a server error. In dev the first
+// page request compiles the page, so a non-5xx answer means the first build succeeded.
+func waitForReady(ctx context.Context, url string, exited <-chan struct{}) error {
+	client := &http.Client{Timeout: 30 * time.Second}
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		if resp, err := client.Do(req); err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode < http.StatusInternalServerError {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-exited:
+			return errors.New("dev server exited before it was ready")
+		case <-ticker.C:
+		}
+	}
+}
+
+func main() {
+	flags := parseFlags(os.Args[1:])
+	output := cli.NewOutput()
+
+	if flags.mainFile == "" {
+		output.PrintHeader("Bifrost Dev")
+		output.PrintError("Missing main.go file argument")
+		fmt.Println()
+		output.PrintStep("", "Usage: bifrost-dev [flags] <main.go>")
+		output.PrintStep("", "Example: bifrost-dev --open ./main.go")
+		fmt.Println()
+		output.PrintStep("", "Flags:")
+		output.PrintStep("", "  --open        Open the browser once the server is ready")
+		output.PrintStep("", "  --no-open     Never open the browser (also: CI or BIFROST_NO_OPEN=1)")
+		output.PrintStep("", "  --url <url>   URL the app listens on (default %s)", defaultURL)
+		os.Exit(1)
+	}
+
+	output.PrintHeader("Bifrost Dev")
+
+	cmd := exec.Command("go", "run", flags.mainFile)
+	cmd.Env = append(os.Environ(), "BIFROST_DEV=1")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		output.PrintError("Failed to start %s: %v", flags.mainFile, err)
+		os.Exit(1)
+	}
+
+	exited := make(chan struct{})
+	var waitErr error
+	go func() {
+		waitErr = cmd.Wait()
+		close(exited)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	if flags.open && !flags.noOpen && cli.ShouldOpenBrowser(os.Getenv) {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), readyTimeout)
+			defer cancel()
+			if err := waitForReady(ctx, flags.url, exited); err != nil {
+				output.PrintWarning("Not opening browser: %v", err)
+				return
+			}
+			if err := cli.OpenBrowser(flags.url); err != nil {
+				output.PrintWarning("Failed to open browser: %v", err)
+				return
+			}
+			output.PrintSuccess("Opened %s", flags.url)
+		}()
+	}
+
+	select {
+	case sig := <-signals:
+		_ = cmd.Process.Signal(sig)
+		<-exited
+	case <-exited:
+	}
+
+	if waitErr != nil {
+		var exitErr *exec.ExitError
+		if errors.As(waitErr, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		output.PrintError("%v", waitErr)
+		os.Exit(1)
+	}
+}
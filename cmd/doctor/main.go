@@ -1,11 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 
 	"github.com/3-lines-studio/bifrost/internal/adapters/cli"
 	"github.com/3-lines-studio/bifrost/internal/adapters/fs"
+	"github.com/3-lines-studio/bifrost/internal/core"
 )
 
 func main() {
@@ -27,7 +29,7 @@ func main() {
 
 	output.PrintHeader("Bifrost Doctor")
 
-	bifrostDir := filepath.Join(absProjectDir, ".bifrost")
+	bifrostDir := filepath.Join(absProjectDir, core.OutputDir)
 	if err := fsAdapter.MkdirAll(bifrostDir, 0755); err != nil {
 		output.PrintError("Failed to create .bifrost directory: %v", err)
 		os.Exit(1)
@@ -42,5 +44,15 @@ func main() {
 		output.PrintSuccess("Created %s", gitkeepPath)
 	}
 
+	manifestPath := filepath.Join(bifrostDir, "manifest.json")
+	if data, err := fsAdapter.ReadFile(manifestPath); err == nil {
+		var man core.Manifest
+		if err := json.Unmarshal(data, &man); err != nil {
+			output.PrintWarning("Failed to parse %s: %v", manifestPath, err)
+		} else if err := core.CheckManifestEnvironment(&man, os.Getenv(core.EnvironmentEnvVar)); err != nil {
+			output.PrintWarning("%v", err)
+		}
+	}
+
 	output.PrintDone("Repair complete!")
 }
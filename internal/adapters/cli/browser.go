@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// BrowserCommand returns the platform command that opens url in the default browser.
+func BrowserCommand(goos string, url string) (name string, args []string) {
+	switch goos {
+	case "darwin":
+		return "open", []string{url}
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	default:
+		return "xdg-open", []string{url}
+	}
+}
+
+// ShouldOpenBrowser reports whether a browser may be launched. CI environments and
+// BIFROST_NO_OPEN=1 disable it, as does an explicit --no-open.
+func ShouldOpenBrowser(getenv func(string) string) bool {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	for _, key := range []string{"CI", "BIFROST_NO_OPEN"} {
+		switch getenv(key) {
+		case "", "0", "false":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// OpenBrowser launches the default browser without waiting for it to exit.
+func OpenBrowser(url string) error {
+	name, args := BrowserCommand(runtime.GOOS, url)
+	cmd := exec.Command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBrowserCommand(t *testing.T) {
+	const url = "http://localhost:8080"
+	tests := []struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		{goos: "darwin", wantName: "open", wantArgs: []string{url}},
+		{goos: "windows", wantName: "rundll32", wantArgs: []string{"url.dll,FileProtocolHandler", url}},
+		{goos: "linux", wantName: "xdg-open", wantArgs: []string{url}},
+		{goos: "freebsd", wantName: "xdg-open", wantArgs: []string{url}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			name, args := BrowserCommand(tt.goos, url)
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("BrowserCommand(%q) = %q %v, want %q %v", tt.goos, name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestShouldOpenBrowser(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "no env", env: nil, want: true},
+		{name: "CI set", env: map[string]string{"CI": "true"}, want: false},
+		{name: "CI false", env: map[string]string{"CI": "false"}, want: true},
+		{name: "no-open env", env: map[string]string{"BIFROST_NO_OPEN": "1"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ShouldOpenBrowser(func(key string) string { return tt.env[key] })
+			if got != tt.want {
+				t.Errorf("ShouldOpenBrowser() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
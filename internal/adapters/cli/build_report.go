@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"time"
 )
@@ -27,11 +28,21 @@ type BuildError struct {
 	Details []string
 }
 
+// DefaultPageGroup labels pages without WithPageGroup in the grouped summary.
+const DefaultPageGroup = "default"
+
+type buildReportPage struct {
+	entry       string
+	group       string
+	bundleBytes int64
+}
+
 type BuildReport struct {
 	colors      cliOutputWithColors
 	steps       []BuildStep
 	warnings    []BuildError
 	errors      []BuildError
+	pages       []buildReportPage
 	startTime   time.Time
 	pageCount   int
 	outputDir   string
@@ -53,6 +64,20 @@ func (r *BuildReport) SetPageCount(count int) {
 	r.pageCount = count
 }
 
+// AddPage records a page for the grouped summary; group is its WithPageGroup name.
+func (r *BuildReport) AddPage(entry string, group string) {
+	r.pages = append(r.pages, buildReportPage{entry: entry, group: group})
+}
+
+// SetBundleSize records the size of a page's client script.
+func (r *BuildReport) SetBundleSize(entry string, bytes int64) {
+	for i := range r.pages {
+		if r.pages[i].entry == entry {
+			r.pages[i].bundleBytes = bytes
+		}
+	}
+}
+
 func (r *BuildReport) StartStep(name string) *BuildStep {
 	step := BuildStep{
 		Name:      name,
@@ -100,6 +125,7 @@ func (r *BuildReport) Render() {
 
 func (r *BuildReport) renderMinimal(duration time.Duration) {
 	fmt.Printf("  "+r.colors.Green("✓ ")+"%d pages found\n", r.pageCount)
+	r.renderGroups(os.Stdout)
 
 	stepLines := make([]string, 0, len(r.steps))
 	allSuccessful := true
@@ -128,6 +154,7 @@ func (r *BuildReport) renderMinimal(duration time.Duration) {
 
 func (r *BuildReport) renderVerbose(duration time.Duration) {
 	fmt.Printf("  %d pages found\n", r.pageCount)
+	r.renderGroups(os.Stdout)
 
 	fmt.Println()
 	for _, step := range r.steps {
@@ -162,6 +189,55 @@ func (r *BuildReport) renderVerbose(duration time.Duration) {
 	}
 }
 
+// renderGroups prints page count and client script size per page group. Nothing is
+// printed unless at least one page uses WithPageGroup.
+func (r *BuildReport) renderGroups(w io.Writer) {
+	grouped := false
+	for _, page := range r.pages {
+		if page.group != "" {
+			grouped = true
+			break
+		}
+	}
+	if !grouped {
+		return
+	}
+
+	type groupTotals struct {
+		pages int
+		bytes int64
+	}
+	order := make([]string, 0)
+	totals := make(map[string]*groupTotals)
+	for _, page := range r.pages {
+		name := page.group
+		if name == "" {
+			name = DefaultPageGroup
+		}
+		t, ok := totals[name]
+		if !ok {
+			t = &groupTotals{}
+			totals[name] = t
+			order = append(order, name)
+		}
+		t.pages++
+		t.bytes += page.bundleBytes
+	}
+
+	width := 0
+	for _, name := range order {
+		width = max(width, len(name))
+	}
+	for _, name := range order {
+		t := totals[name]
+		noun := "pages"
+		if t.pages == 1 {
+			noun = "page"
+		}
+		_, _ = fmt.Fprintf(w, "    %-*s  %d %s  %s\n", width, name, t.pages, noun, r.colors.Gray(formatBytes(t.bytes)+" JS"))
+	}
+}
+
 func (r *BuildReport) renderErrors(errors []BuildError) {
 	for _, err := range errors {
 		fmt.Printf("  %s %s\n", r.colors.Red("✗"), err.Page)
@@ -185,6 +261,16 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%.1fs", float64(d)/float64(time.Second))
 }
 
+func formatBytes(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	if n < 1024*1024 {
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+}
+
 func deduplicateStrings(items []string) []string {
 	if len(items) <= 1 {
 		return items
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+type plainColors struct{}
+
+func (plainColors) Green(text string) string  { return text }
+func (plainColors) Yellow(text string) string { return text }
+func (plainColors) Red(text string) string    { return text }
+func (plainColors) Gray(text string) string   { return text }
+
+func TestBuildReportGroupsPages(t *testing.T) {
+	r := NewBuildReport(plainColors{}, "")
+	r.AddPage("pages-home-entry", "marketing")
+	r.AddPage("pages-pricing-entry", "marketing")
+	r.AddPage("pages-users-entry", "admin")
+	r.AddPage("pages-about-entry", "")
+	r.SetBundleSize("pages-home-entry", 1024)
+	r.SetBundleSize("pages-pricing-entry", 1024)
+	r.SetBundleSize("pages-users-entry", 100)
+
+	var sb strings.Builder
+	r.renderGroups(&sb)
+
+	want := "    marketing  2 pages  2.0 KB JS\n" +
+		"    admin      1 page  100 B JS\n" +
+		"    default    1 page  0 B JS\n"
+	if sb.String() != want {
+		t.Errorf("renderGroups() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
+
+func TestBuildReportSkipsGroupsWhenUnused(t *testing.T) {
+	r := NewBuildReport(plainColors{}, "")
+	r.AddPage("pages-home-entry", "")
+
+	var sb strings.Builder
+	r.renderGroups(&sb)
+	if sb.String() != "" {
+		t.Errorf("expected no group summary without WithPageGroup, got %q", sb.String())
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
 
-const ExportMarkerPath = ".bifrost/.export-mode"
+const ExportMarkerPath = core.OutputDir + "/.export-mode"
 
 func DetectAppMode() core.Mode {
 	if os.Getenv("BIFROST_EXPORT") == "1" {
@@ -18,6 +18,14 @@ var (
 	//go:embed react_client_only.txt
 	reactClientOnlyTemplate string
 
+	//go:embed react_ssr_boundary.txt
+	reactSSRBoundaryTemplate string
+
+	//go:embed react_client_boundary.txt
+	reactClientBoundaryPrelude string
+
+	//go:embed react_ssr_loading.txt
+	reactSSRLoadingTemplate string
 )
 
 type ReactAdapter struct{}
@@ -55,7 +63,52 @@ func (a *ReactAdapter) SSREntryTemplate() string {
 	return strings.ReplaceAll(reactSSRTemplate, "BIFROST_SSR_PAGE_WRAP", "pageEl")
 }
 
-func (a *ReactAdapter) ClientEntryTemplate(mode core.PageMode) string {
+const (
+	reactHydrateImmediate = `hydrateRoot(container, root);`
+
+	reactHydrateLazy = `if ('requestIdleCallback' in window) {
+		requestIdleCallback(() => hydrateRoot(container, root), { timeout: 2000 });
+	} else {
+		setTimeout(() => hydrateRoot(container, root), 100);
+	}`
+
+	reactHydrateVisible = `if ('IntersectionObserver' in window) {
+		const observer = new IntersectionObserver((entries) => {
+			if (entries.some((entry) => entry.isIntersecting)) {
+				observer.disconnect();
+				hydrateRoot(container, root);
+			}
+		});
+		observer.observe(container);
+	} else {
+		hydrateRoot(container, root);
+	}`
+)
+
+func (a *ReactAdapter) ClientEntryTemplate(mode core.PageMode, hydration core.HydrationStrategy) string {
+	return a.clientEntryTemplate(mode, hydration, false)
+}
+
+func (a *ReactAdapter) ErrorBoundarySSREntryTemplate() string {
+	return reactSSRBoundaryTemplate
+}
+
+func (a *ReactAdapter) ErrorBoundaryClientEntryTemplate(mode core.PageMode, hydration core.HydrationStrategy) string {
+	return a.clientEntryTemplate(mode, hydration, true)
+}
+
+func (a *ReactAdapter) LoadingShellSSREntryTemplate() string {
+	return reactSSRLoadingTemplate
+}
+
+// LoadingShellClientEntryTemplate hydrates the server-rendered skeleton first, so React
+// takes over the existing markup, then renders the page over it.
+func (a *ReactAdapter) LoadingShellClientEntryTemplate(tmpl string) string {
+	tmpl = strings.Replace(tmpl, `import { Page } from "COMPONENT_PATH";`+"\n", `import { Page } from "COMPONENT_PATH";`+"\n"+`import { Loading } from "LOADING_PATH";`+"\n", 1)
+	return strings.ReplaceAll(tmpl, "hydrateRoot(container, root)", "hydrateRoot(container, React.createElement(Loading, {})).render(root)")
+}
+
+func (a *ReactAdapter) clientEntryTemplate(mode core.PageMode, hydration core.HydrationStrategy, boundary bool) string {
 	var tmpl string
 	switch mode {
 	case core.ModeClientOnly:
@@ -69,7 +122,29 @@ func (a *ReactAdapter) ClientEntryTemplate(mode core.PageMode) string {
 	} else {
 		root = `React.createElement(Page, props)`
 	}
-	return strings.ReplaceAll(tmpl, "BIFROST_CLIENT_ROOT", root)
+	prelude := ""
+	if boundary {
+		pageProps := "props"
+		if mode == core.ModeClientOnly {
+			pageProps = "{}"
+		}
+		root = `React.createElement(BifrostErrorBoundary, { pageProps: ` + pageProps + ` }, ` + root + `)`
+		prelude = reactClientBoundaryPrelude
+	}
+	tmpl = strings.Replace(tmpl, "BIFROST_CLIENT_PRELUDE\n", prelude, 1)
+	tmpl = strings.ReplaceAll(tmpl, "BIFROST_CLIENT_ROOT", root)
+	return strings.ReplaceAll(tmpl, "BIFROST_HYDRATE", reactHydrateCall(hydration))
+}
+
+func reactHydrateCall(hydration core.HydrationStrategy) string {
+	switch hydration {
+	case core.HydrationImmediate:
+		return reactHydrateImmediate
+	case core.HydrationVisible:
+		return reactHydrateVisible
+	default:
+		return reactHydrateLazy
+	}
 }
 
 func (a *ReactAdapter) DevRendererSource() string {
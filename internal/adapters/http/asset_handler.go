@@ -31,7 +31,7 @@ func safeEmbedPath(raw string) (string, bool) {
 	if !ok {
 		return "", false
 	}
-	return path.Join(".bifrost", rel), true
+	return path.Join(core.OutputDir, rel), true
 }
 
 func containsDotDot(p string) bool {
@@ -76,16 +76,20 @@ func (h *AssetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 }
 
 type PublicHandler struct {
-	assetsFS embed.FS
-	next     http.Handler
-	isDev    bool
+	assetsFS     embed.FS
+	next         http.Handler
+	isDev        bool
+	cacheControl string
 }
 
-func NewPublicHandler(assetsFS embed.FS, next http.Handler, isDev bool) http.Handler {
+// NewPublicHandler serves public/ files ahead of next. A non-empty cacheControl is sent
+// as the Cache-Control header of every public file.
+func NewPublicHandler(assetsFS embed.FS, next http.Handler, isDev bool, cacheControl string) http.Handler {
 	return &PublicHandler{
-		assetsFS: assetsFS,
-		next:     next,
-		isDev:    isDev,
+		assetsFS:     assetsFS,
+		next:         next,
+		isDev:        isDev,
+		cacheControl: cacheControl,
 	}
 }
 
@@ -96,11 +100,32 @@ func (h *PublicHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if err := serveProjectFile(w, req, h.assetsFS, "public", cleaned, !h.isDev, core.GetContentType(cleaned)); err != nil {
+	if h.cacheControl != "" {
+		w.Header().Set("Cache-Control", h.cacheControl)
+	}
+	if err := servePublicFile(w, req, h.assetsFS, cleaned, !h.isDev); err != nil {
+		if h.cacheControl != "" {
+			w.Header().Del("Cache-Control")
+		}
 		h.next.ServeHTTP(w, req)
 	}
 }
 
+// servePublicFile serves public/<cleaned>. From the embed it falls back to the copy
+// bifrost-build makes under .bifrost/public, so apps that embed only all:.bifrost still
+// get their public files, dot-directories such as .well-known included.
+func servePublicFile(w http.ResponseWriter, req *http.Request, assetsFS fs.FS, cleaned string, fromEmbed bool) error {
+	contentType := core.PublicContentType(cleaned)
+	if !fromEmbed {
+		return serveFileFromDisk(w, req, filepath.Join("public", cleaned), "public", contentType)
+	}
+	err := serveFileFromEmbed(w, req, assetsFS, path.Join("public", cleaned), contentType)
+	if err != nil {
+		err = serveFileFromEmbed(w, req, assetsFS, path.Join(core.OutputDir, "public", cleaned), contentType)
+	}
+	return err
+}
+
 func isPathSafe(p, root string) bool {
 	abs, err := filepath.Abs(p)
 	if err != nil {
@@ -114,7 +139,7 @@ func isPathSafe(p, root string) bool {
 }
 
 func serveBifrostFile(w http.ResponseWriter, req *http.Request, assetsFS embed.FS, cleaned string, fromEmbed bool, contentType string) error {
-	return serveProjectFile(w, req, assetsFS, ".bifrost", cleaned, fromEmbed, contentType)
+	return serveProjectFile(w, req, assetsFS, core.OutputDir, cleaned, fromEmbed, contentType)
 }
 
 func serveProjectFile(w http.ResponseWriter, req *http.Request, assetsFS embed.FS, root string, cleaned string, fromEmbed bool, contentType string) error {
@@ -139,7 +164,7 @@ func serveFileFromDisk(w http.ResponseWriter, req *http.Request, fullPath string
 	return nil
 }
 
-func serveFileFromEmbed(w http.ResponseWriter, req *http.Request, assetsFS embed.FS, embedPath string, contentType string) error {
+func serveFileFromEmbed(w http.ResponseWriter, req *http.Request, assetsFS fs.FS, embedPath string, contentType string) error {
 	file, err := assetsFS.Open(embedPath)
 	if err != nil {
 		return err
@@ -59,7 +59,7 @@ func BenchmarkPublicHandler_ServeFromFS(b *testing.B) {
 	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTeapot)
 	})
-	handler := NewPublicHandler(embed.FS{}, fallback, true)
+	handler := NewPublicHandler(embed.FS{}, fallback, true, "")
 	req := httptest.NewRequest("GET", "/favicon.ico", nil)
 
 	b.ReportAllocs()
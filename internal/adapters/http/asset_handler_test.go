@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
@@ -137,7 +139,7 @@ func TestPublicHandler_TraversalBlocked(t *testing.T) {
 		w.WriteHeader(http.StatusTeapot)
 	})
 
-	handler := NewPublicHandler(embed.FS{}, fallback, true)
+	handler := NewPublicHandler(embed.FS{}, fallback, true, "")
 
 	traversalPaths := []string{
 		"/../../etc/passwd",
@@ -171,7 +173,7 @@ func TestPublicHandler_ServesValidDevFile(t *testing.T) {
 		w.WriteHeader(http.StatusTeapot)
 	})
 
-	handler := NewPublicHandler(embed.FS{}, fallback, true)
+	handler := NewPublicHandler(embed.FS{}, fallback, true, "")
 	req := httptest.NewRequest("GET", "/favicon.ico", nil)
 	w := httptest.NewRecorder()
 	handler.ServeHTTP(w, req)
@@ -181,6 +183,114 @@ func TestPublicHandler_ServesValidDevFile(t *testing.T) {
 	}
 }
 
+func TestPublicHandler_ServesWellKnownFromDisk(t *testing.T) {
+	tmpDir := chdirTemp(t)
+
+	wellKnown := filepath.Join(tmpDir, "public", ".well-known", "acme-challenge")
+	if err := os.MkdirAll(wellKnown, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wellKnown, "tok123"), []byte("tok123.key"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := NewPublicHandler(embed.FS{}, fallback, true, "")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/acme-challenge/tok123", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "tok123.key" {
+		t.Fatalf("got %d %q, want the challenge token", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/", nil))
+	if w.Code != http.StatusTeapot {
+		t.Errorf("directory request = %d, want fallback", w.Code)
+	}
+}
+
+func TestServePublicFile_WellKnownFromEmbed(t *testing.T) {
+	aasa := `{"applinks":{"details":[]}}`
+	tests := []struct {
+		name   string
+		assets fstest.MapFS
+	}{
+		{name: "embedded public dir", assets: fstest.MapFS{"public/.well-known/apple-app-site-association": {Data: []byte(aasa)}}},
+		{name: "build copy under .bifrost", assets: fstest.MapFS{".bifrost/public/.well-known/apple-app-site-association": {Data: []byte(aasa)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/.well-known/apple-app-site-association", nil)
+			cleaned, ok := cleanPath(req.URL.Path)
+			if !ok {
+				t.Fatal("cleanPath rejected a .well-known path")
+			}
+			if err := servePublicFile(w, req, tt.assets, cleaned, true); err != nil {
+				t.Fatalf("servePublicFile: %v", err)
+			}
+			if w.Body.String() != aasa {
+				t.Errorf("body = %q", w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	err := servePublicFile(w, httptest.NewRequest("GET", "/.well-known/missing", nil), fstest.MapFS{}, ".well-known/missing", true)
+	if err == nil {
+		t.Error("missing file should return an error so the next handler runs")
+	}
+}
+
+func TestServePublicFile_StaticCacheManifest(t *testing.T) {
+	manifest := `[{"url":"/dist/home.js","hash":"sha384-abc"}]`
+	assets := fstest.MapFS{".bifrost/public/" + core.StaticCacheManifestFile: {Data: []byte(manifest)}}
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/"+core.StaticCacheManifestFile, nil)
+	cleaned, ok := cleanPath(req.URL.Path)
+	if !ok {
+		t.Fatal("cleanPath rejected the asset cache manifest path")
+	}
+	if err := servePublicFile(w, req, assets, cleaned, true); err != nil {
+		t.Fatalf("servePublicFile: %v", err)
+	}
+	if w.Body.String() != manifest {
+		t.Errorf("body = %q", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+}
+
+func TestServePublicFile_RouteIndex(t *testing.T) {
+	index := `{"version":1,"routes":[{"path":"/about","title":"About"}]}`
+	assets := fstest.MapFS{".bifrost/public/" + core.RouteIndexFile: {Data: []byte(index)}}
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/__bifrost/routes.json", nil)
+	cleaned, ok := cleanPath(req.URL.Path)
+	if !ok {
+		t.Fatal("cleanPath rejected the route index path")
+	}
+	if err := servePublicFile(w, req, assets, cleaned, true); err != nil {
+		t.Fatalf("servePublicFile: %v", err)
+	}
+	if w.Body.String() != index {
+		t.Errorf("body = %q", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+}
+
 func TestSafeEmbedPath(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -246,3 +356,50 @@ func TestAssetHandler_ServesEmbeddedFileWithRange(t *testing.T) {
 		t.Fatal("expected Content-Range header")
 	}
 }
+
+func TestPublicHandler_CacheControl(t *testing.T) {
+	tmpDir := chdirTemp(t)
+
+	publicDir := filepath.Join(tmpDir, "public")
+	if err := os.MkdirAll(publicDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(publicDir, "logo.svg"), []byte("<svg/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	tests := []struct {
+		name   string
+		config core.Config
+		want   string
+	}{
+		{name: "default", config: core.Config{}, want: ""},
+		{name: "max age", config: core.Config{StaticFileMaxAge: 7 * 24 * time.Hour}, want: "public, max-age=604800"},
+		{name: "immutable", config: core.Config{StaticFileImmutable: true}, want: "public, max-age=31536000, immutable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewPublicHandler(embed.FS{}, fallback, true, core.PublicCacheControl(tt.config))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/logo.svg", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200", w.Code)
+			}
+			if got := w.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+
+			w = httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/missing.svg", nil))
+			if got := w.Header().Get("Cache-Control"); w.Code != http.StatusTeapot || got != "" {
+				t.Errorf("fallback got %d with Cache-Control %q, want 418 without it", w.Code, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type CanonicalHostHandler struct {
+	next http.Handler
+	cfg  core.CanonicalHostConfig
+}
+
+// NewCanonicalHostHandler redirects requests for a non-canonical host to cfg.Host and,
+// with cfg.HTTPSOnly, plain HTTP requests to HTTPS. core.HealthCheckPath is exempt.
+// GET and HEAD get 301; other methods get 308 so the method and body survive.
+func NewCanonicalHostHandler(next http.Handler, cfg core.CanonicalHostConfig) http.Handler {
+	return &CanonicalHostHandler{next: next, cfg: cfg}
+}
+
+func (h *CanonicalHostHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == core.HealthCheckPath {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+
+	secure := isHTTPS(req)
+	switch {
+	case h.cfg.Host != "" && !h.isCanonicalHost(req.Host):
+		scheme := h.cfg.Scheme
+		if h.cfg.HTTPSOnly {
+			scheme = "https"
+		}
+		h.redirect(w, req, scheme, h.cfg.Host)
+	case h.cfg.HTTPSOnly && !secure:
+		h.redirect(w, req, "https", req.Host)
+	default:
+		h.next.ServeHTTP(w, req)
+	}
+}
+
+func (h *CanonicalHostHandler) isCanonicalHost(host string) bool {
+	host = strings.ToLower(host)
+	if host == h.cfg.Host {
+		return true
+	}
+	if strings.Contains(h.cfg.Host, ":") {
+		return false
+	}
+	hostname, _, err := net.SplitHostPort(host)
+	return err == nil && hostname == h.cfg.Host
+}
+
+func (h *CanonicalHostHandler) redirect(w http.ResponseWriter, req *http.Request, scheme string, host string) {
+	code := http.StatusMovedPermanently
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		code = http.StatusPermanentRedirect
+	}
+	http.Redirect(w, req, scheme+"://"+host+req.URL.RequestURI(), code)
+}
+
+// isHTTPS reads the scheme the trust-proxy middleware resolved, never the raw
+// X-Forwarded-Proto header, which any client can send.
+func isHTTPS(req *http.Request) bool {
+	return req.TLS != nil || req.URL.Scheme == "https"
+}
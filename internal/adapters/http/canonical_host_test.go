@@ -0,0 +1,133 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func canonicalConfig(opts ...core.ConfigOption) core.CanonicalHostConfig {
+	var c core.Config
+	for _, o := range opts {
+		o(&c)
+	}
+	return c.CanonicalHost
+}
+
+func TestCanonicalHostHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	proxies, err := core.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		cfg          core.CanonicalHostConfig
+		method       string
+		target       string
+		remoteAddr   string
+		forwardProto string
+		wantCode     int
+		wantLocation string
+	}{
+		{
+			name:         "non-canonical host redirects with path and query",
+			cfg:          canonicalConfig(core.WithCanonicalHost("www.example.com")),
+			target:       "http://example.com/blog/post?page=2&sort=new",
+			wantCode:     http.StatusMovedPermanently,
+			wantLocation: "https://www.example.com/blog/post?page=2&sort=new",
+		},
+		{
+			name:     "canonical host passes through",
+			cfg:      canonicalConfig(core.WithCanonicalHost("www.example.com")),
+			target:   "http://www.example.com/blog",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "canonical host with port passes through",
+			cfg:      canonicalConfig(core.WithCanonicalHost("www.example.com")),
+			target:   "http://WWW.example.com:8080/",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "health check is exempt",
+			cfg:      canonicalConfig(core.WithCanonicalHost("https://www.example.com")),
+			target:   "http://10.0.0.5/healthz",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:         "https-only canonical host upgrades plain http",
+			cfg:          canonicalConfig(core.WithCanonicalHost("https://www.example.com")),
+			target:       "http://www.example.com/pricing?plan=pro",
+			wantCode:     http.StatusMovedPermanently,
+			wantLocation: "https://www.example.com/pricing?plan=pro",
+		},
+		{
+			name:         "https-only behind a TLS-terminating proxy",
+			cfg:          canonicalConfig(core.WithCanonicalHost("https://www.example.com")),
+			target:       "http://www.example.com/pricing",
+			remoteAddr:   "10.0.0.2:4000",
+			forwardProto: "https",
+			wantCode:     http.StatusOK,
+		},
+		{
+			name:         "https-only ignores X-Forwarded-Proto from an untrusted peer",
+			cfg:          canonicalConfig(core.WithCanonicalHost("https://www.example.com")),
+			target:       "http://www.example.com/pricing",
+			forwardProto: "https",
+			wantCode:     http.StatusMovedPermanently,
+			wantLocation: "https://www.example.com/pricing",
+		},
+		{
+			name:         "http scheme keeps http",
+			cfg:          canonicalConfig(core.WithCanonicalHost("http://intranet.local")),
+			target:       "http://10.0.0.5/",
+			wantCode:     http.StatusMovedPermanently,
+			wantLocation: "http://intranet.local/",
+		},
+		{
+			name:         "WithHTTPS alone keeps the host",
+			cfg:          canonicalConfig(core.WithHTTPS()),
+			target:       "http://example.com/a?b=c",
+			wantCode:     http.StatusMovedPermanently,
+			wantLocation: "https://example.com/a?b=c",
+		},
+		{
+			name:         "non-GET uses 308",
+			cfg:          canonicalConfig(core.WithCanonicalHost("www.example.com")),
+			method:       http.MethodPost,
+			target:       "http://example.com/api/form",
+			wantCode:     http.StatusPermanentRedirect,
+			wantLocation: "https://www.example.com/api/form",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tt.target, nil)
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			if tt.forwardProto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.forwardProto)
+			}
+			rr := httptest.NewRecorder()
+			NewTrustedProxiesHandler(NewCanonicalHostHandler(next, tt.cfg), proxies).ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.wantCode)
+			}
+			if got := rr.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("Location = %q, want %q", got, tt.wantLocation)
+			}
+		})
+	}
+}
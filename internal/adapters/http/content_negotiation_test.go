@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func newNegotiationPageHandler(calls *int) http.Handler {
+	route := core.Page("/posts", "./pages/posts.tsx", core.WithContentNegotiation(), core.WithLoader(func(*http.Request) (map[string]any, error) {
+		*calls++
+		return map[string]any{"posts": []string{"hello"}}, nil
+	}))
+	return newPageHandlerWithConfig(core.PageConfigFromRoute(route), core.Config{})
+}
+
+func TestPageHandler_NegotiatesJSON(t *testing.T) {
+	for _, target := range []string{"/posts", "/posts?format=json"} {
+		calls := 0
+		handler := newNegotiationPageHandler(&calls)
+		req := httptest.NewRequest("GET", target, nil)
+		if target == "/posts" {
+			req.Header.Set("Accept", "application/json")
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", target, rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Errorf("%s: Content-Type = %q", target, ct)
+		}
+		if vary := rr.Header().Get("Vary"); vary != "Accept" {
+			t.Errorf("%s: Vary = %q, want Accept", target, vary)
+		}
+		var props map[string][]string
+		if err := json.Unmarshal(rr.Body.Bytes(), &props); err != nil || len(props["posts"]) != 1 {
+			t.Errorf("%s: body = %s (%v)", target, rr.Body.String(), err)
+		}
+		if calls != 1 {
+			t.Errorf("%s: loader calls = %d, want 1", target, calls)
+		}
+	}
+}
+
+func TestPageHandler_NegotiationKeepsHTMLForBrowsers(t *testing.T) {
+	calls := 0
+	handler := newNegotiationPageHandler(&calls)
+	req := httptest.NewRequest("GET", "/posts?format=html", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if ct := rr.Header().Get("Content-Type"); strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want the page rather than JSON", ct)
+	}
+	if vary := rr.Header().Get("Vary"); vary != "Accept" {
+		t.Errorf("Vary = %q, want Accept", vary)
+	}
+}
+
+func TestPageHandler_NoNegotiationWithoutOption(t *testing.T) {
+	route := core.Page("/posts", "./pages/posts.tsx", core.WithLoader(func(*http.Request) (map[string]any, error) {
+		return map[string]any{"posts": []string{"hello"}}, nil
+	}))
+	handler := newPageHandlerWithConfig(core.PageConfigFromRoute(route), core.Config{})
+	req := httptest.NewRequest("GET", "/posts", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if ct := rr.Header().Get("Content-Type"); strings.HasPrefix(ct, "application/json") && strings.Contains(rr.Body.String(), "hello") {
+		t.Errorf("page without WithContentNegotiation answered with its props: %s", rr.Body.String())
+	}
+	if vary := rr.Header().Get("Vary"); vary != "" {
+		t.Errorf("Vary = %q, want none", vary)
+	}
+}
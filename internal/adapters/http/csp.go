@@ -0,0 +1,104 @@
+package http
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+const (
+	maxCSPReportBytes = 64 << 10
+	cspForwardTimeout = 5 * time.Second
+)
+
+type CSPHandler struct {
+	next   http.Handler
+	header string
+	cfg    core.CSPConfig
+	client *http.Client
+}
+
+// NewCSPHandler sets the Content-Security-Policy header when absent and, with a report
+// URI configured, serves core.CSPReportPath: each violation is logged and answered with
+// 204, and forwarded to the report URI when forwarding is on. With cfg.Nonce, each request
+// gets a fresh nonce, from cfg.NonceGenerator when set, in its context (core.CSPNonce)
+// and in the header's script-src.
+func NewCSPHandler(next http.Handler, cfg core.CSPConfig) http.Handler {
+	return &CSPHandler{
+		next:   next,
+		header: cfg.HeaderValue(),
+		cfg:    cfg,
+		client: &http.Client{Timeout: cspForwardTimeout},
+	}
+}
+
+func (h *CSPHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h.cfg.ReportURI != "" && req.URL.Path == core.CSPReportPath {
+		h.serveReport(w, req)
+		return
+	}
+	header := h.header
+	if h.cfg.Nonce {
+		nonce := h.cfg.NewNonce()
+		req = req.WithContext(core.ContextWithCSPNonce(req.Context(), nonce))
+		header = h.cfg.HeaderValueWithNonce(nonce)
+	}
+	if header != "" && w.Header().Get("Content-Security-Policy") == "" {
+		w.Header().Set("Content-Security-Policy", header)
+	}
+	h.next.ServeHTTP(w, req)
+}
+
+func (h *CSPHandler) serveReport(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxCSPReportBytes))
+	if err != nil {
+		http.Error(w, "invalid csp report", http.StatusBadRequest)
+		return
+	}
+	var report core.CSPViolationReport
+	if err := json.Unmarshal(body, &report); err != nil {
+		http.Error(w, "invalid csp report", http.StatusBadRequest)
+		return
+	}
+
+	r := report.Report
+	slog.Warn("bifrost csp violation",
+		"document_uri", r.DocumentURI,
+		"violated_directive", cmp.Or(r.EffectiveDirective, r.ViolatedDirective),
+		"blocked_uri", r.BlockedURI,
+		"source_file", r.SourceFile,
+		"line", r.LineNumber,
+		"disposition", r.Disposition,
+	)
+
+	if h.cfg.ForwardReports {
+		contentType := cmp.Or(req.Header.Get("Content-Type"), "application/csp-report")
+		go h.forward(body, contentType)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CSPHandler) forward(body []byte, contentType string) {
+	resp, err := h.client.Post(h.cfg.ReportURI, contentType, bytes.NewReader(body))
+	if err != nil {
+		slog.Error("bifrost csp report forward failed", "uri", h.cfg.ReportURI, "error", err)
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		slog.Error("bifrost csp report forward failed", "uri", h.cfg.ReportURI, "status", resp.StatusCode)
+	}
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+const testCSPReport = `{"csp-report":{"document-uri":"https://app.example.com/","violated-directive":"script-src-elem","blocked-uri":"https://evil.example.com/x.js"}}`
+
+func TestCSPHandlerSetsHeaderWithReportURI(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := NewCSPHandler(next, core.CSPConfig{Policy: core.DefaultCSPPolicy, ReportURI: "https://csp.example.com/report"})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	got := rr.Header().Get("Content-Security-Policy")
+	if !strings.HasPrefix(got, core.DefaultCSPPolicy) || !strings.Contains(got, "report-uri /_bifrost/csp-report https://csp.example.com/report") {
+		t.Errorf("Content-Security-Policy = %q", got)
+	}
+}
+
+func TestCSPHandlerKeepsExistingHeader(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := NewCSPHandler(next, core.CSPConfig{Policy: core.DefaultCSPPolicy})
+
+	rr := httptest.NewRecorder()
+	rr.Header().Set("Content-Security-Policy", "default-src 'none'")
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := rr.Header().Get("Content-Security-Policy"); got != "default-src 'none'" {
+		t.Errorf("Content-Security-Policy = %q, want existing value", got)
+	}
+}
+
+func TestCSPHandlerReportEndpoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+		wantLog    bool
+	}{
+		{name: "report is logged", method: http.MethodPost, body: testCSPReport, wantStatus: http.StatusNoContent, wantLog: true},
+		{name: "invalid body", method: http.MethodPost, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodGet, wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureDefaultLogger(t)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("report endpoint should not reach next")
+			})
+			handler := NewCSPHandler(next, core.CSPConfig{Policy: core.DefaultCSPPolicy, ReportURI: "https://csp.example.com/report"})
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, core.CSPReportPath, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/csp-report")
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			logged := logs.String()
+			if got := strings.Contains(logged, "bifrost csp violation"); got != tt.wantLog {
+				t.Fatalf("logged = %v, want %v: %s", got, tt.wantLog, logged)
+			}
+			if tt.wantLog && (!strings.Contains(logged, "script-src-elem") || !strings.Contains(logged, "https://evil.example.com/x.js")) {
+				t.Errorf("expected directive and blocked URI in log: %s", logged)
+			}
+		})
+	}
+}
+
+func TestCSPHandlerForwardsReports(t *testing.T) {
+	type forwarded struct {
+		contentType string
+		body        string
+	}
+	received := make(chan forwarded, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- forwarded{contentType: r.Header.Get("Content-Type"), body: string(body)}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer collector.Close()
+
+	captureDefaultLogger(t)
+	handler := NewCSPHandler(http.NotFoundHandler(), core.CSPConfig{
+		Policy:         core.DefaultCSPPolicy,
+		ReportURI:      collector.URL,
+		ForwardReports: true,
+	})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, core.CSPReportPath, strings.NewReader(testCSPReport))
+	req.Header.Set("Content-Type", "application/csp-report")
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rr.Code)
+	}
+	select {
+	case got := <-received:
+		if got.body != testCSPReport || got.contentType != "application/csp-report" {
+			t.Errorf("forwarded %+v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("report was not forwarded")
+	}
+}
+
+func TestCSPHandlerNoncePerRequest(t *testing.T) {
+	var seen []string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, core.CSPNonce(r))
+	})
+	handler := NewCSPHandler(next, core.CSPConfig{Policy: core.DefaultCSPPolicy, Nonce: true})
+
+	for range 2 {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		nonce := seen[len(seen)-1]
+		if nonce == "" {
+			t.Fatal("expected a nonce in the request context")
+		}
+		if got := rr.Header().Get("Content-Security-Policy"); !strings.Contains(got, "'nonce-"+nonce+"'") {
+			t.Errorf("Content-Security-Policy = %q, want nonce %s", got, nonce)
+		}
+	}
+	if seen[0] == seen[1] {
+		t.Error("expected a fresh nonce per request")
+	}
+}
+
+func TestCSPHandlerCustomNonceGenerator(t *testing.T) {
+	nonces := []string{"AAECAwQFBgcICQoLDA0ODw==", "EBESExQVFhcYGRobHB0eHw=="}
+	calls := 0
+	cfg := core.CSPConfig{Policy: core.DefaultCSPPolicy, Nonce: true, NonceGenerator: func() string {
+		nonce := nonces[calls]
+		calls++
+		return nonce
+	}}
+	var seen []string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, core.CSPNonce(r))
+	})
+	handler := NewCSPHandler(next, cfg)
+
+	for i := range nonces {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		if seen[i] != nonces[i] {
+			t.Errorf("request %d: context nonce = %q, want %q", i, seen[i], nonces[i])
+		}
+		if got := rr.Header().Get("Content-Security-Policy"); !strings.Contains(got, "'nonce-"+nonces[i]+"'") {
+			t.Errorf("request %d: Content-Security-Policy = %q", i, got)
+		}
+	}
+	if calls != len(nonces) {
+		t.Errorf("generator called %d times, want once per request", calls)
+	}
+}
+
+func TestCSPHandlerPanickingNonceGeneratorFallsBack(t *testing.T) {
+	cfg := core.CSPConfig{Policy: core.DefaultCSPPolicy, Nonce: true, NonceGenerator: func() string { panic("no entropy") }}
+	var nonce string
+	handler := NewCSPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce = core.CSPNonce(r)
+	}), cfg)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if nonce == "" || rr.Code != http.StatusOK {
+		t.Errorf("nonce = %q, status = %d; want a crypto/rand nonce", nonce, rr.Code)
+	}
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestCSRFHandlerIssuesCookieOnGet(t *testing.T) {
+	var seen string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = core.CSRFToken(r)
+	})
+	handler := NewCSRFHandler(next, core.CSRFConfig{Secure: true, SameSite: http.SameSiteStrictMode})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("cookies = %v, want one CSRF cookie", cookies)
+	}
+	c := cookies[0]
+	if c.Name != core.DefaultCSRFCookieName || c.Path != "/" || !c.HttpOnly || !c.Secure || c.SameSite != http.SameSiteStrictMode {
+		t.Errorf("cookie = %+v", c)
+	}
+	if seen == "" || seen != c.Value {
+		t.Errorf("context token = %q, want cookie value %q", seen, c.Value)
+	}
+}
+
+func TestCSRFHandlerReusesExistingCookie(t *testing.T) {
+	var seen string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = core.CSRFToken(r)
+	})
+	handler := NewCSRFHandler(next, core.CSRFConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: core.DefaultCSRFCookieName, Value: "tok"})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if len(rr.Result().Cookies()) != 0 {
+		t.Error("existing cookie should not be replaced")
+	}
+	if seen != "tok" {
+		t.Errorf("context token = %q, want tok", seen)
+	}
+}
+
+func TestCSRFHandlerValidatesUnsafeMethods(t *testing.T) {
+	form := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/contact", strings.NewReader(url.Values{core.CSRFFormField: {token}, "name": {"Ada"}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+	header := func(method, token string) *http.Request {
+		req := httptest.NewRequest(method, "/contact", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(core.CSRFHeader, token)
+		return req
+	}
+	tests := []struct {
+		name       string
+		req        *http.Request
+		cookie     bool
+		wantStatus int
+	}{
+		{name: "form field matches", req: form("tok"), cookie: true, wantStatus: http.StatusOK},
+		{name: "header matches", req: header(http.MethodDelete, "tok"), cookie: true, wantStatus: http.StatusOK},
+		{name: "form field mismatch", req: form("other"), cookie: true, wantStatus: http.StatusForbidden},
+		{name: "no token sent", req: header(http.MethodPut, ""), cookie: true, wantStatus: http.StatusForbidden},
+		{name: "no cookie", req: form("tok"), wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaderRan := false
+			var name string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				loaderRan = true
+				name = r.PostFormValue("name")
+			})
+			if tt.cookie {
+				tt.req.AddCookie(&http.Cookie{Name: core.DefaultCSRFCookieName, Value: "tok"})
+			}
+			rr := httptest.NewRecorder()
+			NewCSRFHandler(next, core.CSRFConfig{}).ServeHTTP(rr, tt.req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			if loaderRan != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("next ran = %v", loaderRan)
+			}
+			if tt.name == "form field matches" && name != "Ada" {
+				t.Errorf("form value after validation = %q, want Ada", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package http
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+const maxDiagnosticsErrors = 20
+
+type DiagnosticsRenderError struct {
+	Time    time.Time
+	Path    string
+	Entry   string
+	Message string
+}
+
+type DiagnosticsBuild struct {
+	Entry string
+	Count int
+	Last  time.Duration
+	Max   time.Duration
+	Total time.Duration
+}
+
+func (b DiagnosticsBuild) Avg() time.Duration {
+	if b.Count == 0 {
+		return 0
+	}
+	return b.Total / time.Duration(b.Count)
+}
+
+// Diagnostics collects recent render errors and dev build timings for the diagnostics
+// page. A nil *Diagnostics records nothing.
+type Diagnostics struct {
+	mu     sync.Mutex
+	errors []DiagnosticsRenderError
+	builds map[string]*DiagnosticsBuild
+}
+
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{builds: make(map[string]*DiagnosticsBuild)}
+}
+
+func (d *Diagnostics) RecordRenderError(path, entry string, err error) {
+	if d == nil || err == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.errors = append(d.errors, DiagnosticsRenderError{
+		Time:    time.Now(),
+		Path:    path,
+		Entry:   entry,
+		Message: err.Error(),
+	})
+	if len(d.errors) > maxDiagnosticsErrors {
+		d.errors = d.errors[len(d.errors)-maxDiagnosticsErrors:]
+	}
+}
+
+func (d *Diagnostics) RecordBuild(entry string, dur time.Duration) {
+	if d == nil || dur <= 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, ok := d.builds[entry]
+	if !ok {
+		b = &DiagnosticsBuild{Entry: entry}
+		d.builds[entry] = b
+	}
+	b.Count++
+	b.Last = dur
+	b.Total += dur
+	b.Max = max(b.Max, dur)
+}
+
+// snapshot returns render errors newest first and builds sorted by entry.
+func (d *Diagnostics) snapshot() ([]DiagnosticsRenderError, []DiagnosticsBuild) {
+	if d == nil {
+		return nil, nil
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	errs := make([]DiagnosticsRenderError, len(d.errors))
+	for i, e := range d.errors {
+		errs[len(errs)-1-i] = e
+	}
+	builds := make([]DiagnosticsBuild, 0, len(d.builds))
+	for _, b := range d.builds {
+		builds = append(builds, *b)
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].Entry < builds[j].Entry })
+	return errs, builds
+}
+
+// DiagnosticsSource is the app state shown on the diagnostics page.
+type DiagnosticsSource struct {
+	Routes         []core.Route
+	Manifest       *core.Manifest
+	RendererStatus func() core.RendererStatus
+	// Metrics returns WithPageMetrics statistics; nil hides the table.
+	Metrics func() map[string]core.RouteMetrics
+}
+
+type DiagnosticsHandler struct {
+	next   http.Handler
+	path   string
+	isDev  bool
+	diag   *Diagnostics
+	source DiagnosticsSource
+}
+
+// NewDiagnosticsHandler serves the diagnostics page at path in dev. In production the
+// path answers 404 so internals never leak, even if the router would match it.
+func NewDiagnosticsHandler(next http.Handler, path string, isDev bool, diag *Diagnostics, source DiagnosticsSource) http.Handler {
+	return &DiagnosticsHandler{
+		next:   next,
+		path:   path,
+		isDev:  isDev,
+		diag:   diag,
+		source: source,
+	}
+}
+
+type diagnosticsEntry struct {
+	Pattern       string
+	ComponentPath string
+	EntryName     string
+	Mode          string
+	Script        string
+	CSS           []string
+	SSR           string
+}
+
+type diagnosticsData struct {
+	Now         time.Time
+	Renderer    core.RendererStatus
+	Uptime      time.Duration
+	Entries     []diagnosticsEntry
+	Errors      []DiagnosticsRenderError
+	Builds      []DiagnosticsBuild
+	Metrics     []diagnosticsMetric
+	ShowMetrics bool // WithPageMetrics is enabled
+}
+
+type diagnosticsMetric struct {
+	Pattern string
+	core.RouteMetrics
+}
+
+func (h *DiagnosticsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != h.path {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+	if !h.isDev {
+		http.NotFound(w, req)
+		return
+	}
+
+	data := diagnosticsData{Now: time.Now()}
+	if h.source.RendererStatus != nil {
+		data.Renderer = h.source.RendererStatus()
+		data.Uptime = data.Renderer.Uptime(data.Now).Round(time.Second)
+	}
+	for _, route := range h.source.Routes {
+		config := core.PageConfigFromRoute(route)
+		entryName := core.EntryNameForPath(config.ComponentPath)
+		artifacts := core.ResolvePageArtifacts(h.source.Manifest, entryName)
+		data.Entries = append(data.Entries, diagnosticsEntry{
+			Pattern:       route.Pattern,
+			ComponentPath: config.ComponentPath,
+			EntryName:     entryName,
+			Mode:          config.Mode.BuildLabel(),
+			Script:        artifacts.Script,
+			CSS:           core.StylesheetHrefsFor(artifacts),
+			SSR:           artifacts.SSRPath,
+		})
+	}
+	data.Errors, data.Builds = h.diag.snapshot()
+	if h.source.Metrics != nil {
+		data.ShowMetrics = true
+		for pattern, metrics := range h.source.Metrics() {
+			data.Metrics = append(data.Metrics, diagnosticsMetric{Pattern: pattern, RouteMetrics: metrics})
+		}
+		sort.Slice(data.Metrics, func(i, j int) bool { return data.Metrics[i].Pattern < data.Metrics[j].Pattern })
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := diagnosticsTemplate.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var diagnosticsTemplate = template.Must(template.New("diagnostics").Parse(`<!doctype html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta http-equiv="refresh" content="5">
+    <title>Bifrost diagnostics</title>
+    <style>
+        body { font-family: ui-monospace, SFMono-Regular, monospace; background: #0a0a0a; color: #f5f5f5; padding: 24px; font-size: 13px; }
+        h1 { font-size: 1.2rem; margin-bottom: 16px; }
+        h2 { font-size: 1rem; margin: 24px 0 8px; color: #aaaaaa; }
+        table { border-collapse: collapse; width: 100%; }
+        th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #222222; vertical-align: top; }
+        .up { color: #50fa7b; }
+        .down { color: #ff5555; }
+        pre { white-space: pre-wrap; margin: 0; }
+    </style>
+</head>
+<body>
+    <h1>Bifrost diagnostics</h1>
+
+    <h2>Renderer</h2>
+    <p>
+        {{if .Renderer.Up}}<span class="up">up</span>{{else}}<span class="down">down</span>{{end}}
+        {{if .Renderer.PID}} &middot; pid {{.Renderer.PID}}{{end}}
+        {{if .Renderer.Up}} &middot; uptime {{.Uptime}}{{end}}
+    </p>
+
+    <h2>Entries</h2>
+    <table>
+        <tr><th>Route</th><th>Component</th><th>Mode</th><th>Entry</th><th>Script</th><th>CSS</th><th>SSR</th></tr>
+        {{range .Entries}}
+        <tr><td>{{.Pattern}}</td><td>{{.ComponentPath}}</td><td>{{.Mode}}</td><td>{{.EntryName}}</td><td>{{.Script}}</td><td>{{range .CSS}}{{.}}<br>{{end}}</td><td>{{.SSR}}</td></tr>
+        {{end}}
+    </table>
+
+    <h2>Recent render errors</h2>
+    {{if .Errors}}
+    <table>
+        <tr><th>Time</th><th>Path</th><th>Entry</th><th>Error</th></tr>
+        {{range .Errors}}
+        <tr><td>{{.Time.Format "15:04:05"}}</td><td>{{.Path}}</td><td>{{.Entry}}</td><td><pre>{{.Message}}</pre></td></tr>
+        {{end}}
+    </table>
+    {{else}}
+    <p>None</p>
+    {{end}}
+
+    <h2>Builds</h2>
+    {{if .Builds}}
+    <table>
+        <tr><th>Entry</th><th>Builds</th><th>Last</th><th>Avg</th><th>Max</th></tr>
+        {{range .Builds}}
+        <tr><td>{{.Entry}}</td><td>{{.Count}}</td><td>{{.Last}}</td><td>{{.Avg}}</td><td>{{.Max}}</td></tr>
+        {{end}}
+    </table>
+    {{else}}
+    <p>No builds yet</p>
+    {{end}}
+    {{if .ShowMetrics}}
+
+    <h2>Page metrics</h2>
+    {{if .Metrics}}
+    <table>
+        <tr><th>Route</th><th>Requests</th><th>Avg</th><th>Min</th><th>p50</th><th>p95</th><th>p99</th><th>Max</th></tr>
+        {{range .Metrics}}
+        <tr><td>{{.Pattern}}</td><td>{{.Count}}</td><td>{{.Avg}}</td><td>{{.Min}}</td><td>{{.P50}}</td><td>{{.P95}}</td><td>{{.P99}}</td><td>{{.Max}}</td></tr>
+        {{end}}
+    </table>
+    {{else}}
+    <p>No requests yet</p>
+    {{end}}
+    {{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func newTestDiagnosticsHandler(isDev bool, diag *Diagnostics) http.Handler {
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("next"))
+	})
+	entryName := core.EntryNameForPath("./pages/blog.tsx")
+	return NewDiagnosticsHandler(next, "/_bifrost/diag", isDev, diag, DiagnosticsSource{
+		Routes: []core.Route{
+			core.Page("/blog/{slug}", "./pages/blog.tsx", core.WithStatic()),
+			core.Page("/", "./pages/home.tsx"),
+		},
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			entryName: {Script: "/dist/blog-abc123.js", CSS: "/dist/blog-abc123.css"},
+		}},
+		RendererStatus: func() core.RendererStatus {
+			return core.RendererStatus{Up: true, PID: 4242, StartedAt: time.Now().Add(-time.Minute)}
+		},
+	})
+}
+
+func TestDiagnosticsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		isDev      bool
+		path       string
+		wantStatus int
+		wantBody   []string
+	}{
+		{
+			name:       "dev serves diagnostics page",
+			isDev:      true,
+			path:       "/_bifrost/diag",
+			wantStatus: http.StatusOK,
+			wantBody: []string{
+				`<meta http-equiv="refresh" content="5">`,
+				"./pages/blog.tsx",
+				"/dist/blog-abc123.js",
+				"/dist/blog-abc123.css",
+				"static",
+				"./pages/home.tsx",
+				"pid 4242",
+				"render exploded",
+				"pages-home-entry",
+			},
+		},
+		{
+			name:       "prod answers 404",
+			path:       "/_bifrost/diag",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "other paths pass through",
+			isDev:      true,
+			path:       "/",
+			wantStatus: http.StatusOK,
+			wantBody:   []string{"next"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diag := NewDiagnostics()
+			diag.RecordRenderError("/", "pages-home-entry", errors.New("render exploded"))
+			diag.RecordBuild("pages-home-entry", 120*time.Millisecond)
+
+			rr := httptest.NewRecorder()
+			newTestDiagnosticsHandler(tt.isDev, diag).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			body := rr.Body.String()
+			for _, want := range tt.wantBody {
+				if !strings.Contains(body, want) {
+					t.Errorf("expected %q in body:\n%s", want, body)
+				}
+			}
+			if tt.wantStatus == http.StatusNotFound && strings.Contains(body, "pages-home-entry") {
+				t.Errorf("prod 404 leaked diagnostics: %s", body)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsKeepsRecentErrors(t *testing.T) {
+	diag := NewDiagnostics()
+	for i := 0; i < maxDiagnosticsErrors+5; i++ {
+		diag.RecordRenderError("/", "e", errors.New(strings.Repeat("x", i+1)))
+	}
+	errs, _ := diag.snapshot()
+	if len(errs) != maxDiagnosticsErrors {
+		t.Fatalf("kept %d errors, want %d", len(errs), maxDiagnosticsErrors)
+	}
+	if len(errs[0].Message) != maxDiagnosticsErrors+5 {
+		t.Errorf("expected newest error first, got %q", errs[0].Message)
+	}
+}
+
+func TestPageHandlerRecordsDiagnosticsErrors(t *testing.T) {
+	diag := NewDiagnostics()
+	config := core.PageConfigFromRoute(core.Page("/report", "./pages/report.tsx", core.WithLoader(func(*http.Request) (map[string]any, error) {
+		return nil, errors.New("db down")
+	})))
+	handler := newPageHandlerWithDiagnostics(config, diag)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/report", nil))
+
+	errs, _ := diag.snapshot()
+	if len(errs) != 1 || errs[0].Message != "db down" || errs[0].Path != "/report" {
+		t.Fatalf("recorded errors = %+v", errs)
+	}
+}
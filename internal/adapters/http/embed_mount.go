@@ -0,0 +1,67 @@
+package http
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type embedMount struct {
+	fsys   fs.FS
+	root   string
+	prefix string
+}
+
+type EmbedMountHandler struct {
+	next   http.Handler
+	mounts []embedMount
+}
+
+// NewEmbedMountHandler serves files from each mount's FS under its path and passes every
+// other request, including all of /dist/, to next.
+func NewEmbedMountHandler(next http.Handler, mounts []core.EmbedMount) http.Handler {
+	h := &EmbedMountHandler{next: next}
+	for _, mount := range mounts {
+		h.mounts = append(h.mounts, embedMount{
+			fsys:   mount.FS,
+			root:   embedRoot(mount.FS),
+			prefix: strings.TrimSuffix(mount.Path, "/") + "/",
+		})
+	}
+	return h
+}
+
+// embedRoot skips the directories //go:embed keeps in front of the files: an FS whose root
+// holds a single directory (static/ for //go:embed static) is served from inside it.
+func embedRoot(fsys fs.FS) string {
+	root := "."
+	for {
+		entries, err := fs.ReadDir(fsys, root)
+		if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+			return root
+		}
+		root = path.Join(root, entries[0].Name())
+	}
+}
+
+func (h *EmbedMountHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !strings.HasPrefix(req.URL.Path, "/dist/") {
+		for _, mount := range h.mounts {
+			rest, ok := strings.CutPrefix(req.URL.Path, mount.prefix)
+			if !ok {
+				continue
+			}
+			cleaned, ok := cleanPath(rest)
+			if !ok {
+				continue
+			}
+			if serveFileFromEmbed(w, req, mount.fsys, path.Join(mount.root, cleaned), core.PublicContentType(cleaned)) == nil {
+				return
+			}
+		}
+	}
+	h.next.ServeHTTP(w, req)
+}
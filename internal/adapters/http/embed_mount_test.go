@@ -0,0 +1,41 @@
+package http
+
+import (
+	"embed"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+//go:embed testdata/mounted
+var mountedFS embed.FS
+
+func TestEmbedMountHandlerServesMountedFile(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, "next:"+req.URL.Path)
+	})
+	handler := NewEmbedMountHandler(next, []core.EmbedMount{
+		{FS: mountedFS, Path: "/static"},
+		{FS: mountedFS, Path: "/dist"},
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/static/test.css", nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != "body{margin:0}\n" {
+		t.Fatalf("GET /static/test.css = %d %q", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != core.GetContentType("test.css") {
+		t.Errorf("Content-Type = %q, want text/css", ct)
+	}
+
+	for _, p := range []string{"/dist/test.css", "/static/missing.css", "/static/../static/test.css/x", "/about"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, p, nil))
+		if rr.Body.String() != "next:"+p {
+			t.Errorf("GET %s = %q, want it passed to next", p, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func newErrorTransformPageHandler(loaderErr error, isDev bool, appConfig core.Config, diag *Diagnostics) http.Handler {
+	handler := newLoaderPageHandlerWithConfig(func(*http.Request) (map[string]any, error) {
+		return nil, loaderErr
+	}, appConfig)
+	handler.isDev = isDev
+	handler.diag = diag
+	return handler
+}
+
+func sanitize(err error) error {
+	return errors.New("something went wrong")
+}
+
+func TestPageHandler_ErrorTransformReplacesMessage(t *testing.T) {
+	logs := captureDefaultLogger(t)
+	diag := NewDiagnostics()
+	handler := newErrorTransformPageHandler(errors.New("pq: relation users does not exist"), false, core.Config{ErrorTransform: sanitize}, diag)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/report", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rr.Code)
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, "something went wrong") || strings.Contains(body, "pq:") {
+		t.Errorf("body shows %q, want only the transformed message", body)
+	}
+	if !strings.Contains(logs.String(), "pq: relation users does not exist") {
+		t.Errorf("log = %q, want the original error", logs.String())
+	}
+	if errs, _ := diag.snapshot(); len(errs) != 1 || errs[0].Message != "pq: relation users does not exist" {
+		t.Errorf("diagnostics = %+v, want the original error", errs)
+	}
+}
+
+func TestPageHandler_ErrorTransformJSON(t *testing.T) {
+	handler := newErrorTransformPageHandler(errors.New("pq: timeout"), false, core.Config{ErrorTransform: sanitize, StructuredErrors: true}, nil)
+	req := httptest.NewRequest("GET", "/report", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if got, want := strings.TrimSpace(rr.Body.String()), `{"error":"something went wrong","code":500}`; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
+
+func TestPageHandler_ErrorTransformSkipsRedirects(t *testing.T) {
+	calls := 0
+	transform := func(err error) error {
+		calls++
+		return err
+	}
+	handler := newErrorTransformPageHandler(testRedirect{}, false, core.Config{ErrorTransform: transform}, nil)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/report", nil))
+
+	if rr.Code != http.StatusFound || rr.Header().Get("Location") != "/login" {
+		t.Errorf("status = %d, Location = %q; want a redirect to /login", rr.Code, rr.Header().Get("Location"))
+	}
+	if calls != 0 {
+		t.Errorf("transform calls = %d, want 0 for a redirect", calls)
+	}
+}
+
+func TestPageHandler_ErrorTransformInDev(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      core.Config
+		transformed bool
+	}{
+		{name: "bypassed", config: core.Config{ErrorTransform: sanitize}, transformed: false},
+		{name: "enabled", config: core.Config{ErrorTransform: sanitize, ErrorTransformInDev: true}, transformed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newErrorTransformPageHandler(errors.New("pq: timeout"), true, tt.config, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest("GET", "/report", nil))
+
+			body := rr.Body.String()
+			if got := strings.Contains(body, "<pre>something went wrong</pre>"); got != tt.transformed {
+				t.Errorf("transformed = %v, want %v:\n%s", got, tt.transformed, body)
+			}
+			if !strings.Contains(body, "<pre>") {
+				t.Errorf("dev error page shows no message:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestPageHandler_ErrorTransformNilShowsStatusText(t *testing.T) {
+	handler := newErrorTransformPageHandler(errors.New("pq: timeout"), false, core.Config{ErrorTransform: func(error) error { return nil }, StructuredErrors: true}, nil)
+	req := httptest.NewRequest("GET", "/report", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if got, want := strings.TrimSpace(rr.Body.String()), `{"error":"Internal Server Error","code":500}`; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
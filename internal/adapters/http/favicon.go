@@ -0,0 +1,63 @@
+package http
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type FaviconHandler struct {
+	next    http.Handler
+	favicon core.Favicon
+	modTime time.Time
+}
+
+// NewFaviconHandler answers GET/HEAD /favicon.ico from memory and delegates everything else.
+func NewFaviconHandler(next http.Handler, favicon core.Favicon) http.Handler {
+	return &FaviconHandler{
+		next:    next,
+		favicon: favicon,
+		modTime: time.Now(),
+	}
+}
+
+func (h *FaviconHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != core.FaviconPath || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+
+	w.Header().Set("Content-Type", h.favicon.MIMEType)
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int((24*time.Hour).Seconds())))
+	http.ServeContent(w, req, "favicon.ico", h.modTime, bytes.NewReader(h.favicon.Data))
+}
+
+// PublicFileExists reports whether public/<rel> would be served by PublicHandler.
+func PublicFileExists(assetsFS embed.FS, rel string, isDev bool) bool {
+	cleaned, ok := cleanPath(rel)
+	if !ok {
+		return false
+	}
+	if !isDev {
+		for _, root := range []string{"public", path.Join(core.OutputDir, "public")} {
+			if info, err := fs.Stat(assetsFS, path.Join(root, cleaned)); err == nil && !info.IsDir() {
+				return true
+			}
+		}
+		return false
+	}
+	fullPath := filepath.Join("public", cleaned)
+	if !isPathSafe(fullPath, "public") {
+		return false
+	}
+	info, err := os.Stat(fullPath)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"embed"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestFaviconHandler_ServesBytes(t *testing.T) {
+	routerCalled := false
+	router := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		routerCalled = true
+		w.WriteHeader(http.StatusTeapot)
+	})
+	handler := NewFaviconHandler(router, core.Favicon{Data: []byte("ICON"), MIMEType: "image/png"})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/favicon.ico", nil))
+
+	if routerCalled {
+		t.Error("favicon handler must answer before the router")
+	}
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	if got := rr.Body.String(); got != "ICON" {
+		t.Errorf("body = %q, want ICON", got)
+	}
+	if got := rr.Header().Get("Content-Type"); got != "image/png" {
+		t.Errorf("Content-Type = %q, want image/png", got)
+	}
+	if got := rr.Header().Get("Cache-Control"); got != "public, max-age=86400" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/other", nil))
+	if rr.Code != http.StatusTeapot {
+		t.Errorf("other paths should reach next, got %d", rr.Code)
+	}
+}
+
+func TestWithFaviconDefaultMIMEType(t *testing.T) {
+	var cfg core.Config
+	core.WithFavicon([]byte("x"), "")(&cfg)
+	if cfg.Favicon == nil || cfg.Favicon.MIMEType != "image/x-icon" {
+		t.Fatalf("Favicon = %+v, want image/x-icon default", cfg.Favicon)
+	}
+}
+
+func TestFaviconHandler_PublicFileWins(t *testing.T) {
+	tmpDir := chdirTemp(t)
+
+	if PublicFileExists(embed.FS{}, core.FaviconPath, true) {
+		t.Fatal("PublicFileExists() = true before public/favicon.ico exists")
+	}
+
+	publicDir := filepath.Join(tmpDir, "public")
+	if err := os.MkdirAll(publicDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(publicDir, "favicon.ico"), []byte("public"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if !PublicFileExists(embed.FS{}, core.FaviconPath, true) {
+		t.Fatal("PublicFileExists() = false with public/favicon.ico present")
+	}
+
+	favicon := NewFaviconHandler(http.NotFoundHandler(), core.Favicon{Data: []byte("ICON"), MIMEType: "image/x-icon"})
+	handler := NewPublicHandler(embed.FS{}, favicon, true, "")
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/favicon.ico", nil))
+
+	if got := rr.Body.String(); got != "public" {
+		t.Errorf("body = %q, want public file contents", got)
+	}
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// anonymousIDMaxAge keeps the anonymous ID, and with it the visitor's flags, for a year.
+const anonymousIDMaxAge = 365 * 24 * 60 * 60
+
+type AnonymousIDHandler struct {
+	next http.Handler
+}
+
+// NewAnonymousIDHandler gives visitors without a CurrentUser a stable ID for feature flag
+// evaluation, issuing the core.AnonymousIDCookie on their first request.
+func NewAnonymousIDHandler(next http.Handler) http.Handler {
+	return &AnonymousIDHandler{next: next}
+}
+
+func (h *AnonymousIDHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if _, ok := core.CurrentUser(req.Context()); ok {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+
+	id := ""
+	if cookie, err := req.Cookie(core.AnonymousIDCookie); err == nil {
+		id = cookie.Value
+	}
+	if id == "" {
+		id = core.NewAnonymousID()
+		http.SetCookie(w, &http.Cookie{
+			Name:     core.AnonymousIDCookie,
+			Value:    id,
+			Path:     "/",
+			MaxAge:   anonymousIDMaxAge,
+			SameSite: http.SameSiteLaxMode,
+			HttpOnly: true,
+		})
+	}
+	h.next.ServeHTTP(w, req.WithContext(core.ContextWithAnonymousID(req.Context(), id)))
+}
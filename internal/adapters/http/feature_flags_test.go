@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestAnonymousIDHandlerKeepsIDAcrossRequests(t *testing.T) {
+	var seen []string
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		seen = append(seen, core.FlagUserID(req.Context()))
+	})
+	handler := NewAnonymousIDHandler(next)
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, httptest.NewRequest("GET", "/", nil))
+	cookies := first.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != core.AnonymousIDCookie {
+		t.Fatalf("cookies = %v, want %s", cookies, core.AnonymousIDCookie)
+	}
+
+	req := httptest.NewRequest("GET", "/about", nil)
+	req.AddCookie(cookies[0])
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, req)
+
+	if len(second.Result().Cookies()) != 0 {
+		t.Error("expected no new cookie when the visitor already has one")
+	}
+	if len(seen) != 2 || seen[0] == "" || seen[0] != seen[1] {
+		t.Errorf("flag user IDs = %v, want the same anonymous ID twice", seen)
+	}
+}
+
+func TestAnonymousIDHandlerPrefersCurrentUser(t *testing.T) {
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = core.FlagUserID(req.Context())
+	})
+	handler := NewUserHandler(NewAnonymousIDHandler(next), headerUserProvider)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-User", "42")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if got != "42" {
+		t.Errorf("flag user ID = %q, want user ID", got)
+	}
+	if len(rr.Result().Cookies()) != 0 {
+		t.Error("signed-in users should not get an anonymous ID cookie")
+	}
+}
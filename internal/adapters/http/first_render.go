@@ -0,0 +1,16 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// NewFirstRenderHandler makes the renders behind next call hook with pattern, on a new
+// goroutine, the first time their component renders successfully.
+func NewFirstRenderHandler(next http.Handler, pattern string, hook func(routePattern string)) http.Handler {
+	report := func() { go hook(pattern) }
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		next.ServeHTTP(w, req.WithContext(core.ContextWithFirstRenderHook(req.Context(), report)))
+	})
+}
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestFirstRenderHandlerPassesRoutePattern(t *testing.T) {
+	got := make(chan string, 1)
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		hook := core.FirstRenderHook(req.Context())
+		if hook == nil {
+			t.Fatal("expected a first-render hook on the request context")
+		}
+		hook()
+	})
+	handler := NewFirstRenderHandler(next, "/blog/{slug}", func(routePattern string) { got <- routePattern })
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/blog/hello", nil))
+
+	select {
+	case pattern := <-got:
+		if pattern != "/blog/{slug}" {
+			t.Errorf("pattern = %q, want /blog/{slug}", pattern)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("hook was not called")
+	}
+}
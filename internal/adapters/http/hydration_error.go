@@ -0,0 +1,48 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+const maxHydrationReportBytes = 64 << 10
+
+type HydrationErrorHandler struct {
+	next http.Handler
+}
+
+// NewHydrationErrorHandler serves core.HydrationErrorPath in dev and logs each reported
+// hydration mismatch as a warning naming the component. Other requests go to next.
+func NewHydrationErrorHandler(next http.Handler) http.Handler {
+	return &HydrationErrorHandler{next: next}
+}
+
+func (h *HydrationErrorHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != core.HydrationErrorPath {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var report core.HydrationErrorReport
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxHydrationReportBytes)).Decode(&report); err != nil {
+		http.Error(w, "invalid hydration report", http.StatusBadRequest)
+		return
+	}
+
+	slog.Warn("bifrost hydration mismatch",
+		"component", report.Component,
+		"path", report.Path,
+		"message", report.Message,
+		"hint", "server HTML and client render differ; look for Date.now, Math.random or browser-only values during render",
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func captureDefaultLogger(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestHydrationErrorHandler(t *testing.T) {
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	})
+	handler := NewHydrationErrorHandler(next)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantNext   bool
+		wantLog    bool
+	}{
+		{
+			name:       "report is logged",
+			method:     http.MethodPost,
+			path:       core.HydrationErrorPath,
+			body:       `{"component":"./pages/home.tsx","path":"/","message":"Hydration failed"}`,
+			wantStatus: http.StatusNoContent,
+			wantLog:    true,
+		},
+		{
+			name:       "invalid body",
+			method:     http.MethodPost,
+			path:       core.HydrationErrorPath,
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodGet,
+			path:       core.HydrationErrorPath,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:       "other paths pass through",
+			method:     http.MethodGet,
+			path:       "/",
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := captureDefaultLogger(t)
+			nextCalled = false
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			if nextCalled != tt.wantNext {
+				t.Errorf("next called = %v, want %v", nextCalled, tt.wantNext)
+			}
+			logged := strings.Contains(logs.String(), "bifrost hydration mismatch")
+			if logged != tt.wantLog {
+				t.Errorf("logged = %v, want %v: %s", logged, tt.wantLog, logs.String())
+			}
+			if tt.wantLog && !strings.Contains(logs.String(), "component=./pages/home.tsx") {
+				t.Errorf("log should name the component: %s", logs.String())
+			}
+		})
+	}
+}
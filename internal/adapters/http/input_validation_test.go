@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// newValidatedPageMux serves /user/{id} with a loader that writes the validated values.
+func newValidatedPageMux(loaderCalls *int, validators ...core.InputValidator) *http.ServeMux {
+	loader := func(req *http.Request) (map[string]any, error) {
+		*loaderCalls++
+		values := core.Validated(req.Context())
+		fmt.Fprintf(core.ResponseWriter(req), "id=%s tab=%s", values["id"], values["tab"])
+		return nil, core.ErrHandled
+	}
+	route := core.Page("/user/{id}", "./pages/user.tsx", core.WithLoader(loader), core.WithInputValidation(validators...))
+	handler := newPageHandlerWithConfig(core.PageConfigFromRoute(route), core.Config{})
+
+	mux := http.NewServeMux()
+	mux.Handle(route.Pattern, handler)
+	return mux
+}
+
+const testUUID = "3f2c8a4e-9b1d-4c6e-8f0a-1b2c3d4e5f60"
+
+func TestPageHandler_InputValidationPasses(t *testing.T) {
+	calls := 0
+	mux := newValidatedPageMux(&calls,
+		core.PathValue("id", core.UUID()),
+		core.QueryValue("tab", core.OneOf("posts", "likes")),
+	)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/user/"+testUUID+"?tab=likes", nil))
+
+	if calls != 1 {
+		t.Fatalf("loader calls = %d, want 1", calls)
+	}
+	if want := "id=" + testUUID + " tab=likes"; rr.Body.String() != want {
+		t.Errorf("body = %q, want %q", rr.Body.String(), want)
+	}
+}
+
+func TestPageHandler_InputValidationRejects(t *testing.T) {
+	calls := 0
+	mux := newValidatedPageMux(&calls,
+		core.PathValue("id", core.UUID()),
+		core.QueryValue("tab", core.OneOf("posts", "likes")),
+	)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/user/42?tab=admin", nil))
+
+	if calls != 0 {
+		t.Errorf("loader ran %d times for invalid input", calls)
+	}
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+	}
+	var body struct {
+		Errors map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON body %q: %v", rr.Body.String(), err)
+	}
+	if body.Errors["id"] != "must be a UUID" || body.Errors["tab"] != "must be one of posts, likes" {
+		t.Errorf("errors = %v", body.Errors)
+	}
+}
+
+func TestPageHandler_CustomInputValidator(t *testing.T) {
+	calls := 0
+	normalizeID := core.InputValidatorFunc(func(req *http.Request) (map[string]string, error) {
+		return map[string]string{"id": "user-" + req.PathValue("id")}, nil
+	})
+	mux := newValidatedPageMux(&calls, core.PathValue("id", core.Int(1, 1000)), normalizeID)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/user/7", nil))
+
+	if want := "id=user-7 tab="; rr.Body.String() != want {
+		t.Errorf("body = %q, want %q: later validators should override earlier values", rr.Body.String(), want)
+	}
+}
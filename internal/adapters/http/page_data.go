@@ -0,0 +1,17 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// NewPageDataHandler serves the WithPrefetch data route of a page: it strips
+// core.PageDataPrefix from the path, so loaders see the page's own URL, and has page
+// answer with its props instead of its HTML.
+func NewPageDataHandler(page http.Handler) http.Handler {
+	return http.StripPrefix(core.PageDataPrefix, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Cache-Control", "private, no-cache")
+		page.ServeHTTP(w, req.WithContext(core.ContextWithPageData(req.Context())))
+	}))
+}
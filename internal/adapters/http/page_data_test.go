@@ -0,0 +1,39 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestPageDataHandler_ServesProps(t *testing.T) {
+	var loaderPath, slug string
+	route := core.Page("/blog/{slug}", "./pages/post.tsx", core.WithLoader(func(req *http.Request) (map[string]any, error) {
+		loaderPath, slug = req.URL.Path, req.PathValue("slug")
+		return map[string]any{"slug": req.PathValue("slug")}, nil
+	}))
+	page := newPageHandlerWithConfig(core.PageConfigFromRoute(route), core.Config{Prefetch: true})
+	mux := http.NewServeMux()
+	mux.Handle(core.PageDataPattern(route.Pattern), NewPageDataHandler(page))
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest("GET", "/__data/blog/hello", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var props map[string]string
+	if err := json.Unmarshal(rr.Body.Bytes(), &props); err != nil || props["slug"] != "hello" {
+		t.Errorf("body = %s (%v)", rr.Body.String(), err)
+	}
+	if loaderPath != "/blog/hello" || slug != "hello" {
+		t.Errorf("loader saw path %q, slug %q; want the page URL", loaderPath, slug)
+	}
+}
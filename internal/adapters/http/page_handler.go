@@ -2,12 +2,21 @@ package http
 
 import (
 	"bytes"
+	"cmp"
+	"context"
 	"embed"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"html"
 	"io"
+	"log/slog"
 	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/core"
 	"github.com/3-lines-studio/bifrost/internal/usecase"
@@ -22,6 +31,32 @@ type PageHandler struct {
 	entryName       string
 	staticPath      string
 	defaultHTMLLang string
+	ssrTimeout      time.Duration
+	loaderTimeout   time.Duration
+	totalTimeout    time.Duration
+	messages        core.MessagesLoader
+	jsonErrors      bool
+	globalHeadHTML  string
+	title           core.TitleConfig
+	diag            *Diagnostics
+	deps            any
+	linkRewriter    core.LinkRewriter
+	assetURLs       []string
+	contentType     string
+	staticDataTTL   time.Duration
+	environment     string
+	preview         func(*http.Request) bool
+	preloadPaths    []string
+	renderHeaders   []string
+	flagProvider    core.FlagProvider
+	pageCache       core.PageCacheStore
+	ssrFallback     core.SSRFallbackMode
+	prefetch        bool
+	errorTransform  core.ErrorTransform
+	transformInDev  bool
+	headers         http.Header
+	routeErrors     *routeErrors
+	statusPages     StatusPages
 	shell           *core.HTMLDocumentShell
 }
 
@@ -32,21 +67,21 @@ func NewPageHandler(
 	assetsFS embed.FS,
 	isDev bool,
 	staticPath string,
-	defaultHTMLLang string,
-) http.Handler {
+	appConfig core.Config,
+	diag *Diagnostics,
+) *PageHandler {
 	entryName := core.EntryNameForPath(config.ComponentPath)
 	artifacts := core.ResolvePageArtifacts(manifest, entryName)
 	var shell *core.HTMLDocumentShell
-	if builtShell, err := core.NewHTMLDocumentShell(
-		artifacts.Script,
-		artifacts.CriticalCSS,
-		core.StylesheetHrefsFor(artifacts),
-		artifacts.Chunks,
-	); err == nil {
+	if builtShell, err := core.NewPageShell(config.NoJS, artifacts); err == nil {
+		if appConfig.SRI {
+			builtShell = builtShell.WithIntegrity(artifacts.SRI)
+		}
+		builtShell = builtShell.WithPreloads(appConfig.PreloadPaths).RewriteLinks(appConfig.LinkRewriter).ForEnvironment(appConfig.Environment)
 		shell = &builtShell
 	}
 
-	return &PageHandler{
+	handler := &PageHandler{
 		service:         service,
 		config:          config,
 		manifest:        manifest,
@@ -54,46 +89,193 @@ func NewPageHandler(
 		isDev:           isDev,
 		entryName:       entryName,
 		staticPath:      staticPath,
-		defaultHTMLLang: defaultHTMLLang,
+		defaultHTMLLang: appConfig.DefaultHTMLLang,
+		ssrTimeout:      cmp.Or(appConfig.Timeouts.Render, appConfig.SSRTimeout),
+		loaderTimeout:   appConfig.Timeouts.Loader,
+		totalTimeout:    appConfig.Timeouts.Total,
+		messages:        appConfig.Messages,
+		jsonErrors:      appConfig.StructuredErrors,
+		globalHeadHTML:  core.RenderMetaTags(appConfig.MetaTags),
+		title:           appConfig.Title,
+		diag:            diag,
+		deps:            appConfig.Dependencies,
+		linkRewriter:    appConfig.LinkRewriter,
+		assetURLs:       core.AssetURLs(artifacts),
+		contentType:     pageContentType(config),
+		staticDataTTL:   core.ResolveStaticDataCacheTTL(appConfig.StaticDataCacheTTL),
+		environment:     appConfig.Environment,
+		preloadPaths:    appConfig.PreloadPaths,
+		renderHeaders:   appConfig.RenderHeaders,
+		flagProvider:    appConfig.FeatureFlags,
+		pageCache:       appConfig.PageCache,
+		ssrFallback:     appConfig.SSRFallback,
+		prefetch:        appConfig.Prefetch,
+		errorTransform:  appConfig.ErrorTransform,
+		transformInDev:  appConfig.ErrorTransformInDev,
+		headers:         core.MergeHeaders(appConfig.DefaultHeaders, config.Headers),
+		routeErrors:     newRouteErrors(appConfig.RouteErrors),
 		shell:           shell,
 	}
+	if config.Mode == core.ModeStaticPrerender && !isDev {
+		handler.preview = appConfig.Preview
+	}
+	return handler
+}
+
+// SetStatusPages makes the handler render WithStatusPage components for its errors.
+func (h *PageHandler) SetStatusPages(pages StatusPages) {
+	h.statusPages = pages
+}
+
+const htmlContentType = "text/html; charset=utf-8"
+
+// pageContentType is the Content-Type for rendered page responses. Client-only pages are
+// always an HTML shell.
+func pageContentType(config core.PageConfig) string {
+	if config.ContentType == "" || config.Mode == core.ModeClientOnly {
+		return htmlContentType
+	}
+	return config.ContentType
 }
 
 var errNeedsSetup = errors.New("page needs setup but setup not implemented in adapter")
 
 func (h *PageHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	output := h.service.ServePage(req.Context(), h.servePageInput(req))
+	// Set before anything can answer, so error responses carry the headers too.
+	for name, values := range h.headers {
+		w.Header()[name] = slices.Clone(values)
+	}
+	ctx := core.ContextWithResponseWriter(req.Context(), w)
+	ctx = core.ContextWithDependencies(ctx, h.deps)
+	if h.config.ErrorBoundary != "" {
+		ctx = core.ContextWithBoundaryErrorReporter(ctx, func(err *core.BoundaryError) {
+			h.reportBoundaryError(req, err)
+		})
+	}
+	if h.totalTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.totalTimeout)
+		defer cancel()
+	}
+	if h.preview != nil && h.preview(req) {
+		ctx = core.ContextWithPreview(ctx)
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+	req = req.WithContext(ctx)
+
+	if len(h.config.Validators) > 0 {
+		values, err := core.ValidateInput(req, h.config.Validators)
+		var validationErr *core.ValidationError
+		if errors.As(err, &validationErr) {
+			writeJSON(w, validationErr.StatusCode(), validationErr)
+			return
+		}
+		if err != nil {
+			h.serveError(w, req, err)
+			return
+		}
+		ctx = core.ContextWithValidated(ctx, values)
+		req = req.WithContext(ctx)
+	}
+
+	if h.config.Mode == core.ModeSSR && core.IsPageDataRequest(ctx) {
+		h.servePageData(w, req)
+		return
+	}
+
+	if h.config.Negotiate && h.config.Mode == core.ModeSSR {
+		w.Header().Add("Vary", "Accept")
+		if core.NegotiateFormat(req) == core.FormatJSON {
+			h.servePageData(w, req)
+			return
+		}
+	}
+
+	output := h.service.ServePage(ctx, h.servePageInput(req))
+	h.diag.RecordBuild(h.entryName, output.BuildDuration)
 	if output.Error != nil {
-		h.serveError(w, req, output.Error)
+		h.serveError(w, req, h.totalTimeoutError(req, output.Error))
 		return
 	}
 	h.dispatchPageOutput(w, req, output)
 }
 
+// servePageData answers a WithContentNegotiation request for JSON, or a WithPrefetch
+// request under core.PageDataPrefix, with the page's props.
+func (h *PageHandler) servePageData(w http.ResponseWriter, req *http.Request) {
+	props, err := h.service.ServePageData(req.Context(), h.servePageInput(req))
+	if err != nil {
+		h.serveError(w, req, h.totalTimeoutError(req, err))
+		return
+	}
+	writeJSON(w, http.StatusOK, props)
+}
+
+// totalTimeoutError replaces err with a RequestTimeoutError when the total timeout, and
+// not the client going away, ended the request. Loader and render timeouts keep their
+// own error so logs name the stage that was slow.
+func (h *PageHandler) totalTimeoutError(req *http.Request, err error) error {
+	if h.totalTimeout <= 0 || !errors.Is(req.Context().Err(), context.DeadlineExceeded) {
+		return err
+	}
+	var loaderErr *core.LoaderTimeoutError
+	var renderErr *core.RenderTimeoutError
+	if errors.As(err, &loaderErr) || errors.As(err, &renderErr) {
+		return err
+	}
+	return &core.RequestTimeoutError{Path: req.URL.Path, Timeout: h.totalTimeout}
+}
+
+// reportBoundaryError records a render error the page's error boundary turned into a
+// fallback; the request itself still succeeds.
+func (h *PageHandler) reportBoundaryError(req *http.Request, err *core.BoundaryError) {
+	slog.Error("bifrost: page rendered its error boundary fallback", "path", req.URL.Path, "entry", h.entryName, "error", err.Message)
+	h.diag.RecordRenderError(req.URL.Path, h.entryName, err)
+}
+
 func (h *PageHandler) servePageInput(req *http.Request) usecase.ServePageInput {
 	return usecase.ServePageInput{
-		Config:          h.config,
-		DefaultHTMLLang: h.defaultHTMLLang,
-		IsDev:           h.isDev,
-		Manifest:        h.manifest,
-		EntryName:       h.entryName,
-		StaticPath:      h.staticPath,
-		RequestPath:     req.URL.Path,
-		Request:         req,
-		Shell:           h.shell,
+		Config:             h.config,
+		DefaultHTMLLang:    h.defaultHTMLLang,
+		IsDev:              h.isDev,
+		Manifest:           h.manifest,
+		EntryName:          h.entryName,
+		StaticPath:         h.staticPath,
+		RequestPath:        req.URL.Path,
+		Request:            req,
+		Shell:              h.shell,
+		SSRTimeout:         h.ssrTimeout,
+		LoaderTimeout:      h.loaderTimeout,
+		Messages:           h.messages,
+		GlobalHeadHTML:     h.globalHeadHTML,
+		Title:              h.title,
+		LinkRewriter:       h.linkRewriter,
+		StaticDataCacheTTL: h.staticDataTTL,
+		Environment:        h.environment,
+		Preview:            core.IsPreview(req.Context()),
+		PreloadPaths:       h.preloadPaths,
+		RenderHeaders:      h.renderHeaders,
+		FlagProvider:       h.flagProvider,
+		PageCache:          h.pageCache,
+		SSRFallback:        h.ssrFallback,
+		Prefetch:           h.prefetch,
 	}
 }
 
 func (h *PageHandler) dispatchPageOutput(w http.ResponseWriter, req *http.Request, output usecase.ServePageOutput) {
 	switch output.Action {
 	case core.ActionServeStaticFile:
+		if h.linkRewriter != nil {
+			h.serveRewrittenHTMLFile(w, req, output.StaticPath)
+			return
+		}
 		h.serveBifrostHTMLFile(w, req, output.StaticPath, "static")
 
 	case core.ActionServeRouteFile:
-		h.serveBifrostHTMLFile(w, req, output.RoutePath, "route")
+		h.serveBifrostPageFile(w, req, output.RoutePath, "route", h.contentType)
 
 	case core.ActionNotFound:
-		http.NotFound(w, req)
+		h.serveNotFound(w, req)
 
 	case core.ActionNeedsSetup:
 		h.serveError(w, req, errNeedsSetup)
@@ -101,7 +283,7 @@ func (h *PageHandler) dispatchPageOutput(w http.ResponseWriter, req *http.Reques
 	case core.ActionRenderSSR:
 		if output.Stream != nil {
 			if err := output.Stream(w); err != nil {
-				h.serveError(w, req, err)
+				h.serveError(w, req, h.totalTimeoutError(req, err))
 			}
 			return
 		}
@@ -114,46 +296,131 @@ func (h *PageHandler) dispatchPageOutput(w http.ResponseWriter, req *http.Reques
 }
 
 func (h *PageHandler) serveBifrostHTMLFile(w http.ResponseWriter, req *http.Request, logicalPath string, kind string) {
+	h.serveBifrostPageFile(w, req, logicalPath, kind, htmlContentType)
+}
+
+func (h *PageHandler) serveBifrostPageFile(w http.ResponseWriter, req *http.Request, logicalPath string, kind string, contentType string) {
 	rel, ok := cleanPath(logicalPath)
 	if !ok {
 		h.serveError(w, req, fmt.Errorf("invalid %s file path: %s", kind, logicalPath))
 		return
 	}
-	if err := serveBifrostFile(w, req, h.assetsFS, rel, h.assetsFS != (embed.FS{}), "text/html; charset=utf-8"); err != nil {
+	if err := serveBifrostFile(w, req, h.assetsFS, rel, h.assetsFS != (embed.FS{}), contentType); err != nil {
 		h.serveError(w, req, fmt.Errorf("failed to read %s file %s: %w", kind, rel, err))
 	}
 }
 
+// serveRewrittenHTMLFile serves a client-only shell written by the build, which cannot
+// call the app's link rewriter, with its asset URLs rewritten.
+func (h *PageHandler) serveRewrittenHTMLFile(w http.ResponseWriter, req *http.Request, logicalPath string) {
+	rel, ok := cleanPath(logicalPath)
+	if !ok {
+		h.serveError(w, req, fmt.Errorf("invalid static file path: %s", logicalPath))
+		return
+	}
+	var data []byte
+	var err error
+	if h.assetsFS != (embed.FS{}) {
+		data, err = h.assetsFS.ReadFile(path.Join(core.OutputDir, rel))
+	} else {
+		data, err = os.ReadFile(filepath.Join(core.OutputDir, rel))
+	}
+	if err != nil {
+		h.serveError(w, req, fmt.Errorf("failed to read static file %s: %w", rel, err))
+		return
+	}
+	h.serveHTML(w, core.RewriteHTMLAssetLinks(string(data), h.assetURLs, h.linkRewriter))
+}
+
 func (h *PageHandler) serveHTML(w http.ResponseWriter, htmlContent string) {
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Type", h.contentType)
 	w.WriteHeader(http.StatusOK)
 	_, _ = io.WriteString(w, htmlContent)
 }
 
+func (h *PageHandler) wantsJSON(req *http.Request) bool {
+	return h.jsonErrors && core.AcceptsJSON(req.Header.Get("Accept"))
+}
+
+func (h *PageHandler) serveNotFound(w http.ResponseWriter, req *http.Request) {
+	if h.wantsJSON(req) {
+		writeJSON(w, http.StatusNotFound, core.StructuredError{Error: "not found", Code: http.StatusNotFound})
+		return
+	}
+	if h.statusPages.serve(w, req, http.StatusNotFound, http.StatusText(http.StatusNotFound)) {
+		return
+	}
+	http.NotFound(w, req)
+}
+
 func (h *PageHandler) serveError(w http.ResponseWriter, req *http.Request, err error) {
+	if errors.Is(err, core.ErrHandled) {
+		return
+	}
+
 	if redirectErr, ok := err.(core.RedirectError); ok {
 		status := redirectErr.RedirectStatusCode()
 		if status == 0 {
 			status = http.StatusFound
 		}
+		if h.wantsJSON(req) {
+			writeJSON(w, http.StatusOK, core.StructuredRedirect{Redirect: redirectErr.RedirectURL(), Code: status})
+			return
+		}
 		http.Redirect(w, req, redirectErr.RedirectURL(), status)
 		return
 	}
 
+	h.diag.RecordRenderError(req.URL.Path, h.entryName, err)
+
+	status := core.ErrorStatusCode(err)
+	var detail string
+	if h.errorTransform != nil && (!h.isDev || h.transformInDev) {
+		slog.Error("bifrost: page error", "path", req.URL.Path, "entry", h.entryName, "error", err)
+		err = h.errorTransform(err)
+		if err == nil {
+			err = errors.New(http.StatusText(status))
+		}
+		detail = err.Error()
+	} else if h.isDev {
+		detail = err.Error()
+	}
+	message := cmp.Or(detail, http.StatusText(status))
+
+	if h.wantsJSON(req) {
+		writeJSON(w, status, core.StructuredError{Error: message, Code: status})
+		return
+	}
+	if status >= http.StatusInternalServerError {
+		if handler := h.routeErrors.match(req); handler != nil {
+			h.routeErrors.serve(handler, w, req, status, err)
+			return
+		}
+	}
+	if h.statusPages.serve(w, req, status, message) {
+		return
+	}
 	data := core.ErrorData{
-		Message: err.Error(),
+		Title:   http.StatusText(status),
+		Message: detail,
 		IsDev:   h.isDev,
 	}
 
 	var buf bytes.Buffer
 	if err := core.ErrorTemplate.Execute(&buf, data); err != nil {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(status)
 		_, _ = io.WriteString(w, "<!doctype html><html><body><pre>"+html.EscapeString(data.Message)+"</pre></body></html>")
 		return
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	w.WriteHeader(http.StatusInternalServerError)
+	w.WriteHeader(status)
 	_, _ = w.Write(buf.Bytes())
 }
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
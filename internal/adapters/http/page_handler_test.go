@@ -0,0 +1,340 @@
+package http
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+	"github.com/3-lines-studio/bifrost/internal/usecase"
+)
+
+func newLoaderPageHandler(loader core.PropsLoader) http.Handler {
+	return newLoaderPageHandlerWithConfig(loader, core.Config{})
+}
+
+func newLoaderPageHandlerWithConfig(loader core.PropsLoader, appConfig core.Config) *PageHandler {
+	return newPageHandlerWithConfig(core.PageConfigFromRoute(core.Page("/report", "./pages/report.tsx", core.WithLoader(loader))), appConfig)
+}
+
+func newPageHandlerWithConfig(config core.PageConfig, appConfig core.Config) *PageHandler {
+	return NewPageHandler(usecase.NewPageService(nil, nil, nil), config, nil, embed.FS{}, false, "", appConfig, nil)
+}
+
+func newPageHandlerWithDiagnostics(config core.PageConfig, diag *Diagnostics) http.Handler {
+	return NewPageHandler(usecase.NewPageService(nil, nil, nil), config, nil, embed.FS{}, false, "", core.Config{}, diag)
+}
+
+func TestPageHandler_LoaderErrHandled(t *testing.T) {
+	tests := []struct {
+		name string
+		wrap func(error) error
+	}{
+		{name: "sentinel", wrap: func(err error) error { return err }},
+		{name: "wrapped sentinel", wrap: func(err error) error { return fmt.Errorf("csv export: %w", err) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newLoaderPageHandler(func(req *http.Request) (map[string]any, error) {
+				w := core.ResponseWriter(req)
+				if w == nil {
+					return nil, errors.New("response writer missing from request")
+				}
+				w.Header().Set("Content-Type", "text/csv")
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte("a,b\n1,2\n"))
+				return nil, tt.wrap(core.ErrHandled)
+			})
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest("GET", "/report", nil))
+
+			if rr.Code != http.StatusAccepted {
+				t.Errorf("status = %d, want %d", rr.Code, http.StatusAccepted)
+			}
+			if got := rr.Header().Get("Content-Type"); got != "text/csv" {
+				t.Errorf("Content-Type = %q, want text/csv", got)
+			}
+			if got := rr.Body.String(); got != "a,b\n1,2\n" {
+				t.Errorf("body = %q, want loader output only", got)
+			}
+		})
+	}
+}
+
+func TestPageHandler_LoaderErrorRendersErrorPage(t *testing.T) {
+	handler := newLoaderPageHandler(func(*http.Request) (map[string]any, error) {
+		return nil, errors.New("boom")
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/report", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+	if !strings.Contains(rr.Body.String(), "Internal Server Error") {
+		t.Errorf("expected error page, got %q", rr.Body.String())
+	}
+}
+
+func TestResponseWriterWithoutPageHandler(t *testing.T) {
+	if w := core.ResponseWriter(httptest.NewRequest("GET", "/", nil)); w != nil {
+		t.Errorf("ResponseWriter() = %v, want nil outside page handler", w)
+	}
+}
+
+func TestPageHandler_RenderTimeoutReturns503(t *testing.T) {
+	handler := newLoaderPageHandler(func(*http.Request) (map[string]any, error) {
+		return nil, &core.RenderTimeoutError{Path: "/report", Timeout: 2 * time.Second}
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/report", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(rr.Body.String(), "Service Unavailable") {
+		t.Errorf("expected Service Unavailable title, got %q", rr.Body.String())
+	}
+}
+
+type testRedirect struct{}
+
+func (testRedirect) Error() string           { return "redirect" }
+func (testRedirect) RedirectURL() string     { return "/login" }
+func (testRedirect) RedirectStatusCode() int { return http.StatusFound }
+
+func TestPageHandler_StructuredErrors(t *testing.T) {
+	failing := func(*http.Request) (map[string]any, error) { return nil, errors.New("boom") }
+	redirecting := func(*http.Request) (map[string]any, error) { return nil, testRedirect{} }
+	structured := core.Config{StructuredErrors: true}
+
+	tests := []struct {
+		name       string
+		loader     core.PropsLoader
+		config     core.Config
+		accept     string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{
+			name:       "json error",
+			loader:     failing,
+			config:     structured,
+			accept:     "application/json",
+			wantStatus: http.StatusInternalServerError,
+			wantType:   "application/json; charset=utf-8",
+			wantBody:   `{"error":"Internal Server Error","code":500}`,
+		},
+		{
+			name:       "html error",
+			loader:     failing,
+			config:     structured,
+			accept:     "text/html",
+			wantStatus: http.StatusInternalServerError,
+			wantType:   "text/html; charset=utf-8",
+		},
+		{
+			name:       "json redirect",
+			loader:     redirecting,
+			config:     structured,
+			accept:     "application/json, text/plain",
+			wantStatus: http.StatusOK,
+			wantType:   "application/json; charset=utf-8",
+			wantBody:   `{"redirect":"/login","code":302}`,
+		},
+		{
+			name:       "disabled keeps html",
+			loader:     failing,
+			accept:     "application/json",
+			wantStatus: http.StatusInternalServerError,
+			wantType:   "text/html; charset=utf-8",
+		},
+		{
+			name:       "disabled redirect is a browser redirect",
+			loader:     redirecting,
+			accept:     "application/json",
+			wantStatus: http.StatusFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newLoaderPageHandlerWithConfig(tt.loader, tt.config)
+			req := httptest.NewRequest("GET", "/report", nil)
+			req.Header.Set("Accept", tt.accept)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			if tt.wantType != "" && rr.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", rr.Header().Get("Content-Type"), tt.wantType)
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rr.Body.String()) != tt.wantBody {
+				t.Errorf("body = %q, want %q", rr.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestPageHandler_StructuredNotFound(t *testing.T) {
+	config := core.PageConfigFromRoute(core.Page("/blog", "./pages/blog.tsx", core.WithStatic()))
+	handler := NewPageHandler(usecase.NewPageService(nil, nil, nil), config, nil, embed.FS{}, false, "", core.Config{StructuredErrors: true}, nil)
+
+	req := httptest.NewRequest("GET", "/blog/missing", nil)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rr.Code)
+	}
+	if got := strings.TrimSpace(rr.Body.String()); got != `{"error":"not found","code":404}` {
+		t.Errorf("body = %q", got)
+	}
+}
+
+func TestPageHandler_LoaderReceivesDependencies(t *testing.T) {
+	type deps struct{ DSN string }
+	var got *deps
+	handler := newLoaderPageHandlerWithConfig(func(req *http.Request) (map[string]any, error) {
+		got = core.Deps[*deps](req)
+		return nil, errors.New("stop")
+	}, core.Config{Dependencies: &deps{DSN: "postgres://"}})
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/report", nil))
+
+	if got == nil || got.DSN != "postgres://" {
+		t.Fatalf("loader deps = %+v, want DSN postgres://", got)
+	}
+}
+
+func TestPageContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []core.PageOption
+		want string
+	}{
+		{name: "default", want: "text/html; charset=utf-8"},
+		{name: "rss", opts: []core.PageOption{core.WithContentType("application/rss+xml")}, want: "application/rss+xml"},
+		{name: "client-only stays html", opts: []core.PageOption{core.WithClient(), core.WithContentType("text/plain")}, want: "text/html; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := core.PageConfigFromRoute(core.Page("/feed", "./pages/feed.tsx", tt.opts...))
+			if got := pageContentType(config); got != tt.want {
+				t.Errorf("pageContentType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageHandler_NonHTMLContentTypeSkipsShell(t *testing.T) {
+	config := core.PageConfigFromRoute(core.Page("/rss.xml", "./pages/feed.tsx", core.WithContentType("application/rss+xml")))
+	manifest := &core.Manifest{Entries: map[string]core.ManifestEntry{
+		core.EntryNameForPath(config.ComponentPath): {Script: "/dist/feed.js", SSR: "/ssr/feed-ssr.js"},
+	}}
+	service := usecase.NewPageService(&delayRenderer{}, nil, nil)
+	handler := NewPageHandler(service, config, manifest, embed.FS{}, false, "/ssr/feed-ssr.js", core.Config{}, nil)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/rss.xml", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/rss+xml" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	for _, unwanted := range []string{"<!doctype", "__BIFROST_PROPS__", "/dist/feed.js"} {
+		if strings.Contains(rr.Body.String(), unwanted) {
+			t.Errorf("body should not contain %q: %q", unwanted, rr.Body.String())
+		}
+	}
+}
+
+func TestPageHandler_LoaderSetsMultipleCookies(t *testing.T) {
+	loader := func(redirect bool) core.PropsLoader {
+		return func(req *http.Request) (map[string]any, error) {
+			w := core.ResponseWriter(req)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", Path: "/"})
+			if redirect {
+				return nil, testRedirect{}
+			}
+			return map[string]any{}, nil
+		}
+	}
+	rendered := func() http.Handler {
+		config := core.PageConfigFromRoute(core.Page("/report", "./pages/report.tsx", core.WithLoader(loader(false))))
+		manifest := &core.Manifest{Entries: map[string]core.ManifestEntry{
+			core.EntryNameForPath(config.ComponentPath): {Script: "/dist/report.js", SSR: "/ssr/report-ssr.js"},
+		}}
+		service := usecase.NewPageService(&delayRenderer{}, nil, nil)
+		return NewPageHandler(service, config, manifest, embed.FS{}, false, "/ssr/report-ssr.js", core.Config{}, nil)
+	}
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		accept     string
+		wantStatus int
+	}{
+		{name: "rendered page", handler: rendered(), wantStatus: http.StatusOK},
+		{name: "redirect", handler: newLoaderPageHandler(loader(true)), wantStatus: http.StatusFound},
+		{
+			name:       "json redirect",
+			handler:    newLoaderPageHandlerWithConfig(loader(true), core.Config{StructuredErrors: true}),
+			accept:     "application/json",
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}}
+			req, err := http.NewRequest(http.MethodGet, srv.URL+"/report", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = resp.Body.Close() }()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Values("Set-Cookie"); len(got) != 2 {
+				t.Fatalf("Set-Cookie = %q, want two headers", got)
+			}
+			got := map[string]string{}
+			for _, c := range resp.Cookies() {
+				got[c.Name] = c.Value
+			}
+			if got["session"] != "abc" || got["theme"] != "dark" {
+				t.Errorf("cookies = %v, want session and theme", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func newHeadersPageHandler(loader core.PropsLoader) http.Handler {
+	route := core.Page("/account", "./pages/account.tsx", core.WithLoader(loader), core.WithHeaders(http.Header{
+		"X-Frame-Options": {"DENY"},
+		"x-trace-route":   {"account"},
+	}))
+	appConfig := core.Config{}
+	core.WithDefaultHeaders(http.Header{
+		"X-Frame-Options":    {"SAMEORIGIN"},
+		"Permissions-Policy": {"camera=()"},
+	})(&appConfig)
+	return NewSecureHeadersHandler(newPageHandlerWithConfig(core.PageConfigFromRoute(route), appConfig), core.DefaultSecureHeadersConfig())
+}
+
+func assertPageHeaders(t *testing.T, header http.Header) {
+	t.Helper()
+	want := map[string]string{
+		"X-Frame-Options":        "DENY",
+		"X-Trace-Route":          "account",
+		"Permissions-Policy":     "camera=()",
+		"X-Content-Type-Options": "nosniff",
+	}
+	for name, value := range want {
+		if got := header.Values(name); len(got) != 1 || got[0] != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestPageHandler_HeadersLayerOverDefaults(t *testing.T) {
+	handler := newHeadersPageHandler(func(req *http.Request) (map[string]any, error) {
+		_, _ = core.ResponseWriter(req).Write([]byte("ok"))
+		return nil, core.ErrHandled
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/account", nil))
+
+	if rr.Body.String() != "ok" {
+		t.Fatalf("body = %q", rr.Body.String())
+	}
+	assertPageHeaders(t, rr.Header())
+}
+
+func TestPageHandler_HeadersKeptOnErrors(t *testing.T) {
+	handler := newHeadersPageHandler(func(*http.Request) (map[string]any, error) {
+		return nil, errors.New("database down")
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/account", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rr.Code)
+	}
+	assertPageHeaders(t, rr.Header())
+}
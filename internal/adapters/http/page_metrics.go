@@ -0,0 +1,114 @@
+package http
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// pageMetricsSampleSize bounds the durations kept per route for percentile estimates.
+const pageMetricsSampleSize = 1024
+
+// PageMetrics collects per-route request statistics for WithPageMetrics. A nil
+// *PageMetrics records nothing.
+type PageMetrics struct {
+	routes sync.Map // pattern -> *routeSamples
+}
+
+func NewPageMetrics() *PageMetrics {
+	return &PageMetrics{}
+}
+
+type routeSamples struct {
+	mu      sync.Mutex
+	count   int64
+	total   time.Duration
+	min     time.Duration
+	max     time.Duration
+	samples []time.Duration
+}
+
+// Handler times every request served by next under pattern.
+func (m *PageMetrics) Handler(pattern string, next http.Handler) http.Handler {
+	if m == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, req)
+		m.Record(pattern, time.Since(start))
+	})
+}
+
+// Record adds one request of duration d to pattern. Once a route has more requests than
+// the sample holds, each new duration replaces a random sample (reservoir sampling), so
+// percentiles stay representative of every request seen.
+func (m *PageMetrics) Record(pattern string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	v, _ := m.routes.LoadOrStore(pattern, &routeSamples{})
+	r := v.(*routeSamples)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	r.total += d
+	if r.count == 1 || d < r.min {
+		r.min = d
+	}
+	r.max = max(r.max, d)
+	if len(r.samples) < pageMetricsSampleSize {
+		r.samples = append(r.samples, d)
+	} else if i := rand.Int64N(r.count); i < pageMetricsSampleSize {
+		r.samples[i] = d
+	}
+}
+
+// Snapshot returns a copy of the statistics keyed by route pattern.
+func (m *PageMetrics) Snapshot() map[string]core.RouteMetrics {
+	out := make(map[string]core.RouteMetrics)
+	if m == nil {
+		return out
+	}
+	m.routes.Range(func(key, value any) bool {
+		out[key.(string)] = value.(*routeSamples).metrics()
+		return true
+	})
+	return out
+}
+
+// Reset drops every route's statistics.
+func (m *PageMetrics) Reset() {
+	if m == nil {
+		return
+	}
+	m.routes.Clear()
+}
+
+func (r *routeSamples) metrics() core.RouteMetrics {
+	r.mu.Lock()
+	sorted := slices.Clone(r.samples)
+	metrics := core.RouteMetrics{Count: r.count, Total: r.total, Min: r.min, Max: r.max}
+	r.mu.Unlock()
+
+	slices.Sort(sorted)
+	metrics.P50 = percentile(sorted, 0.50)
+	metrics.P95 = percentile(sorted, 0.95)
+	metrics.P99 = percentile(sorted, 0.99)
+	return metrics
+}
+
+// percentile returns the nearest-rank p-th value of sorted.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
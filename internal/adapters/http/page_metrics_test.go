@@ -0,0 +1,116 @@
+package http
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageMetricsCollectsPerRoute(t *testing.T) {
+	m := NewPageMetrics()
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	blog := m.Handler("/blog/{slug}", next)
+	home := m.Handler("/", next)
+
+	for range 3 {
+		blog.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/blog/hello", nil))
+	}
+	home.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	got := m.Snapshot()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 routes, got %v", got)
+	}
+	if got["/blog/{slug}"].Count != 3 {
+		t.Errorf("blog count = %d, want 3", got["/blog/{slug}"].Count)
+	}
+	if got["/"].Count != 1 {
+		t.Errorf("home count = %d, want 1", got["/"].Count)
+	}
+	if _, ok := got["/blog/hello"]; ok {
+		t.Error("metrics should be keyed by pattern, not request path")
+	}
+}
+
+func TestPageMetricsStatistics(t *testing.T) {
+	m := NewPageMetrics()
+	for _, ms := range []int{40, 10, 30, 20} {
+		m.Record("/", time.Duration(ms)*time.Millisecond)
+	}
+	got := m.Snapshot()["/"]
+	if got.Count != 4 || got.Total != 100*time.Millisecond {
+		t.Errorf("count/total = %d/%v", got.Count, got.Total)
+	}
+	if got.Min != 10*time.Millisecond || got.Max != 40*time.Millisecond {
+		t.Errorf("min/max = %v/%v", got.Min, got.Max)
+	}
+	if got.Avg() != 25*time.Millisecond {
+		t.Errorf("avg = %v", got.Avg())
+	}
+	if got.P50 != 20*time.Millisecond {
+		t.Errorf("p50 = %v, want 20ms", got.P50)
+	}
+}
+
+func TestPageMetricsP99Estimate(t *testing.T) {
+	m := NewPageMetrics()
+	rng := rand.New(rand.NewPCG(1, 2))
+	samples := make([]time.Duration, 1000)
+	for i := range samples {
+		samples[i] = time.Duration(rng.ExpFloat64() * float64(50*time.Millisecond))
+		m.Record("/", samples[i])
+	}
+	slices.Sort(samples)
+	want := samples[989]
+
+	got := m.Snapshot()["/"].P99
+	if diff := got - want; diff < -want/10 || diff > want/10 {
+		t.Errorf("p99 = %v, want within 10%% of %v", got, want)
+	}
+}
+
+func TestPageMetricsSnapshotIsCopy(t *testing.T) {
+	m := NewPageMetrics()
+	m.Record("/", time.Millisecond)
+
+	snap := m.Snapshot()
+	delete(snap, "/")
+	snap["/other"] = snap["/"]
+
+	got := m.Snapshot()
+	if len(got) != 1 || got["/"].Count != 1 {
+		t.Errorf("changing a snapshot changed the store: %v", got)
+	}
+
+	m.Reset()
+	if len(m.Snapshot()) != 0 {
+		t.Error("Reset should drop all routes")
+	}
+}
+
+func TestDiagnosticsShowsPageMetrics(t *testing.T) {
+	m := NewPageMetrics()
+	m.Record("/blog/{slug}", 15*time.Millisecond)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {})
+	h := NewDiagnosticsHandler(next, "/_bifrost/diag", true, NewDiagnostics(), DiagnosticsSource{Metrics: m.Snapshot})
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/_bifrost/diag", nil))
+
+	body := rr.Body.String()
+	for _, want := range []string{"Page metrics", "<td>/blog/{slug}</td><td>1</td>", "15ms"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in body:\n%s", want, body)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	NewDiagnosticsHandler(next, "/_bifrost/diag", true, NewDiagnostics(), DiagnosticsSource{}).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/_bifrost/diag", nil))
+	if strings.Contains(rr.Body.String(), "Page metrics") {
+		t.Error("metrics table should be hidden without WithPageMetrics")
+	}
+}
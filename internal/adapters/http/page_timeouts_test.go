@@ -0,0 +1,179 @@
+package http
+
+import (
+	"context"
+	"embed"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+	"github.com/3-lines-studio/bifrost/internal/usecase"
+)
+
+type delayRenderer struct {
+	delay time.Duration
+}
+
+func (r *delayRenderer) Render(string, map[string]any) (core.RenderedPage, error) {
+	return core.RenderedPage{}, nil
+}
+
+func (r *delayRenderer) RenderChunked(ctx context.Context, _ string, _ map[string]any, onHead func(string) error, onBody func(string) error) error {
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err := onHead(""); err != nil {
+		return err
+	}
+	return onBody("<rss></rss>")
+}
+
+func (r *delayRenderer) RenderBodyStream(ctx context.Context, _ string, _ map[string]any, w io.Writer, _ func(), onHead func(string) error) error {
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err := onHead(""); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "<main>report</main>")
+	return err
+}
+
+func (r *delayRenderer) Build([]string, string, []string) (map[string]core.ClientBuildResult, error) {
+	return nil, nil
+}
+
+func (r *delayRenderer) BuildSSR([]string, string) error {
+	return nil
+}
+
+func sleepLoader(d time.Duration) core.PropsLoader {
+	return func(req *http.Request) (map[string]any, error) {
+		select {
+		case <-time.After(d):
+			return map[string]any{}, nil
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+func TestPageHandler_Timeouts(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeouts    core.PageTimeouts
+		loaderDelay time.Duration
+		renderDelay time.Duration
+		wantStatus  int
+		wantBody    string
+	}{
+		{
+			name:        "within bounds",
+			timeouts:    core.PageTimeouts{Loader: time.Second, Render: time.Second, Total: 2 * time.Second},
+			loaderDelay: 5 * time.Millisecond,
+			renderDelay: 5 * time.Millisecond,
+			wantStatus:  http.StatusOK,
+			wantBody:    "<main>report</main>",
+		},
+		{
+			name:        "loader timeout fires before render starts",
+			timeouts:    core.PageTimeouts{Loader: 20 * time.Millisecond, Render: time.Second, Total: 2 * time.Second},
+			loaderDelay: time.Second,
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			name:        "render timeout fires independently of loader",
+			timeouts:    core.PageTimeouts{Loader: time.Second, Render: 20 * time.Millisecond, Total: 2 * time.Second},
+			renderDelay: time.Second,
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			name:        "total caps loader",
+			timeouts:    core.PageTimeouts{Loader: time.Second, Render: time.Second, Total: 30 * time.Millisecond},
+			loaderDelay: 500 * time.Millisecond,
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+		{
+			name:        "total caps loader plus render",
+			timeouts:    core.PageTimeouts{Loader: time.Second, Render: time.Second, Total: 60 * time.Millisecond},
+			loaderDelay: 40 * time.Millisecond,
+			renderDelay: 40 * time.Millisecond,
+			wantStatus:  http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := core.PageConfigFromRoute(core.Page("/report", "./pages/report.tsx", core.WithLoader(sleepLoader(tt.loaderDelay))))
+			entryName := core.EntryNameForPath(config.ComponentPath)
+			manifest := &core.Manifest{Entries: map[string]core.ManifestEntry{
+				entryName: {Script: "/dist/report.js", SSR: "/ssr/report-ssr.js"},
+			}}
+			service := usecase.NewPageService(&delayRenderer{delay: tt.renderDelay}, nil, nil)
+			handler := NewPageHandler(service, config, manifest, embed.FS{}, false, "/ssr/report-ssr.js", core.Config{Timeouts: tt.timeouts}, nil)
+
+			start := time.Now()
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/report", nil))
+			elapsed := time.Since(start)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %q)", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("expected %q in body, got %q", tt.wantBody, rr.Body.String())
+			}
+			if elapsed > 400*time.Millisecond {
+				t.Errorf("request took %s, want it cut short by the timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestPageHandler_RenderTimeoutWithoutStreaming(t *testing.T) {
+	config := core.PageConfigFromRoute(core.Page("/feed.xml", "./pages/feed.tsx", core.WithContentType("application/rss+xml")))
+	entryName := core.EntryNameForPath(config.ComponentPath)
+	manifest := &core.Manifest{Entries: map[string]core.ManifestEntry{
+		entryName: {Script: "/dist/feed.js", SSR: "/ssr/feed-ssr.js"},
+	}}
+	service := usecase.NewPageService(&delayRenderer{delay: time.Second}, nil, nil)
+	handler := NewPageHandler(service, config, manifest, embed.FS{}, false, "/ssr/feed-ssr.js", core.Config{SSRTimeout: 20 * time.Millisecond}, nil)
+
+	start := time.Now()
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/feed.xml", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d (body %q)", rr.Code, http.StatusServiceUnavailable, rr.Body.String())
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("request took %s, want it cut short by the render timeout", elapsed)
+	}
+}
+
+func TestPageHandler_TotalTimeoutStructuredError(t *testing.T) {
+	handler := newLoaderPageHandlerWithConfig(sleepLoader(time.Second), core.Config{
+		Timeouts:         core.PageTimeouts{Total: 20 * time.Millisecond},
+		StructuredErrors: true,
+	})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/report", nil)
+	req.Header.Set("Accept", "application/json")
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+	if want := `{"error":"Service Unavailable","code":503}`; strings.TrimSpace(rr.Body.String()) != want {
+		t.Errorf("body = %q, want %q", rr.Body.String(), want)
+	}
+}
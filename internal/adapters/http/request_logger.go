@@ -0,0 +1,82 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type RequestLoggerHandler struct {
+	next   http.Handler
+	logger *slog.Logger
+}
+
+// NewRequestLoggerHandler stores a request-scoped logger in the context and logs one
+// summary line with status and duration once next returns. Lines carry the client IP,
+// which is the forwarded address under WithTrustProxy, and the user ID under
+// WithUserProvider.
+func NewRequestLoggerHandler(next http.Handler, logger *slog.Logger) http.Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RequestLoggerHandler{next: next, logger: logger}
+}
+
+func (h *RequestLoggerHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	attrs := []any{"method", req.Method, "path", req.URL.Path, "client_ip", core.ClientIP(req)}
+	if id := req.Header.Get(core.RequestIDHeader); id != "" {
+		attrs = append(attrs, "request_id", id)
+	}
+	if user, ok := core.CurrentUser(req.Context()); ok {
+		attrs = append(attrs, "user_id", user.ID())
+	}
+	logger := h.logger.With(attrs...)
+
+	sw := &statusWriter{ResponseWriter: w}
+	h.next.ServeHTTP(sw, req.WithContext(core.ContextWithLogger(req.Context(), logger)))
+
+	logger.Info("bifrost request",
+		"status", sw.Status(),
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
+
+// statusWriter records the response status while keeping flushing available for SSR streams.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestRequestLoggerHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	handlerReturned := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		core.Logger(req.Context()).Info("fetching user", "id", 7)
+		if buf.Len() == 0 {
+			t.Error("expected logger from context to write to configured handler")
+		}
+		if strings.Contains(buf.String(), "bifrost request") {
+			t.Error("summary logged before handler returned")
+		}
+		w.WriteHeader(http.StatusTeapot)
+		handlerReturned = true
+	})
+	handler := NewRequestLoggerHandler(next, logger)
+
+	req := httptest.NewRequest("POST", "/users/7", nil)
+	req.Header.Set(core.RequestIDHeader, "req-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !handlerReturned {
+		t.Fatal("handler not called")
+	}
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	for _, entry := range lines {
+		if entry["method"] != "POST" || entry["path"] != "/users/7" || entry["request_id"] != "req-123" {
+			t.Errorf("missing request attributes in %v", entry)
+		}
+	}
+	if lines[0]["msg"] != "fetching user" || lines[0]["id"] != float64(7) {
+		t.Errorf("unexpected loader line %v", lines[0])
+	}
+	summary := lines[1]
+	if summary["msg"] != "bifrost request" {
+		t.Errorf("unexpected summary msg %v", summary["msg"])
+	}
+	if summary["status"] != float64(http.StatusTeapot) {
+		t.Errorf("status = %v, want %d", summary["status"], http.StatusTeapot)
+	}
+	if _, ok := summary["duration_ms"]; !ok {
+		t.Error("summary missing duration_ms")
+	}
+}
+
+func TestRequestLoggerHandler_NoRequestIDAndImplicitStatus(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := w.(http.Flusher); !ok {
+			t.Error("wrapped writer should keep http.Flusher for SSR streaming")
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	NewRequestLoggerHandler(next, logger).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 log line, got %d", len(lines))
+	}
+	if _, ok := lines[0]["request_id"]; ok {
+		t.Error("request_id should be absent without header")
+	}
+	if lines[0]["status"] != float64(http.StatusOK) {
+		t.Errorf("status = %v, want 200", lines[0]["status"])
+	}
+}
+
+func TestLoggerWithoutMiddlewareFallsBackToDefault(t *testing.T) {
+	if core.Logger(httptest.NewRequest("GET", "/", nil).Context()) != slog.Default() {
+		t.Error("expected slog.Default() outside request logger middleware")
+	}
+}
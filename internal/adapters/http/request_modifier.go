@@ -0,0 +1,46 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type RequestModifierHandler struct {
+	next      http.Handler
+	modifiers []core.RequestModifier
+}
+
+// NewRequestModifierHandler passes each request through modifiers, in order, before next
+// serves it. A modifier that panics or returns nil is logged and answered with 500.
+func NewRequestModifierHandler(next http.Handler, modifiers []core.RequestModifier) http.Handler {
+	return &RequestModifierHandler{next: next, modifiers: modifiers}
+}
+
+func (h *RequestModifierHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	for _, modify := range h.modifiers {
+		modified, err := h.apply(modify, req)
+		if err != nil {
+			core.Logger(req.Context()).Error("bifrost request modifier failed", "path", req.URL.Path, "error", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		req = modified
+	}
+	h.next.ServeHTTP(w, req)
+}
+
+func (h *RequestModifierHandler) apply(modify core.RequestModifier, req *http.Request) (modified *http.Request, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	modified = modify(req)
+	if modified == nil {
+		return nil, errors.New("returned a nil request")
+	}
+	return modified, nil
+}
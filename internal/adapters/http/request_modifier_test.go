@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type countryKey struct{}
+
+// newModifiedPageMux serves /shop with a loader that writes what the modifiers set.
+func newModifiedPageMux(calls *[]string, modifiers ...core.RequestModifier) http.Handler {
+	loader := func(req *http.Request) (map[string]any, error) {
+		*calls = append(*calls, "loader")
+		country, _ := req.Context().Value(countryKey{}).(string)
+		fmt.Fprintf(core.ResponseWriter(req), "country=%s trail=%s", country, req.Header.Get("X-Trail"))
+		return nil, core.ErrHandled
+	}
+	route := core.Page("/shop", "./pages/shop.tsx", core.WithLoader(loader))
+	mux := http.NewServeMux()
+	mux.Handle(route.Pattern, newPageHandlerWithConfig(core.PageConfigFromRoute(route), core.Config{}))
+	return NewRequestModifierHandler(mux, modifiers)
+}
+
+func TestRequestModifierHandler_ChainsBeforeLoader(t *testing.T) {
+	var calls []string
+	trail := func(name string) core.RequestModifier {
+		return func(r *http.Request) *http.Request {
+			calls = append(calls, name)
+			r = r.Clone(r.Context())
+			r.Header.Set("X-Trail", strings.TrimPrefix(r.Header.Get("X-Trail")+">"+name, ">"))
+			return r
+		}
+	}
+	geo := func(r *http.Request) *http.Request {
+		calls = append(calls, "geo")
+		return r.WithContext(context.WithValue(r.Context(), countryKey{}, r.Header.Get("CF-IPCountry")))
+	}
+	rewrite := func(r *http.Request) *http.Request {
+		calls = append(calls, "rewrite")
+		r = r.Clone(r.Context())
+		r.URL.Path = "/shop"
+		return r
+	}
+	handler := newModifiedPageMux(&calls, rewrite, trail("a"), geo, trail("b"))
+
+	req := httptest.NewRequest(http.MethodGet, "/store", nil)
+	req.Header.Set("CF-IPCountry", "DE")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if want := "country=DE trail=a>b"; rr.Body.String() != want {
+		t.Errorf("body = %q, want %q", rr.Body.String(), want)
+	}
+	if got := strings.Join(calls, ","); got != "rewrite,a,geo,b,loader" {
+		t.Errorf("calls = %s, want modifiers in order before the loader", got)
+	}
+	if req.Header.Get("X-Trail") != "" || req.URL.Path != "/store" {
+		t.Error("the original request was mutated")
+	}
+}
+
+func TestRequestModifierHandler_FailuresReturn500(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify core.RequestModifier
+	}{
+		{name: "panic", modify: func(*http.Request) *http.Request { panic("geo lookup failed") }},
+		{name: "nil request", modify: func(*http.Request) *http.Request { return nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			handler := newModifiedPageMux(&calls, tt.modify)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/shop", nil))
+
+			if rr.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want 500", rr.Code)
+			}
+			if len(calls) != 0 {
+				t.Errorf("loader ran after a failed modifier: %v", calls)
+			}
+		})
+	}
+}
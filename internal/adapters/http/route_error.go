@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// routeErrors picks the WithCustomRouteError handler for a failed page request.
+type routeErrors struct {
+	mux      *http.ServeMux
+	handlers map[string]http.Handler
+}
+
+// newRouteErrors returns nil when no per-route handlers are configured. Like
+// http.ServeMux.Handle, it panics on invalid or conflicting patterns.
+func newRouteErrors(configs []core.RouteErrorHandler) *routeErrors {
+	if len(configs) == 0 {
+		return nil
+	}
+	r := &routeErrors{mux: http.NewServeMux(), handlers: make(map[string]http.Handler, len(configs))}
+	for _, config := range configs {
+		pattern := core.RouteErrorMuxPattern(config.Pattern)
+		r.mux.Handle(pattern, config.Handler)
+		r.handlers[pattern] = config.Handler
+	}
+	return r
+}
+
+// match returns the handler whose pattern matches req, or nil.
+func (r *routeErrors) match(req *http.Request) http.Handler {
+	if r == nil {
+		return nil
+	}
+	// Only the registered handler counts as a match; ServeMux may also answer with a
+	// redirect or 404 handler.
+	_, pattern := r.mux.Handler(req)
+	return r.handlers[pattern]
+}
+
+// serve runs handler with err in the request context. The response gets status unless
+// the handler writes its own.
+func (r *routeErrors) serve(handler http.Handler, w http.ResponseWriter, req *http.Request, status int, err error) {
+	ctx := core.ContextWithRouteError(req.Context(), err)
+	handler.ServeHTTP(&defaultStatusWriter{ResponseWriter: w, status: status}, req.WithContext(ctx))
+}
+
+type defaultStatusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *defaultStatusWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *defaultStatusWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(w.status)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *defaultStatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func routeErrorHandler(name string, seen *error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		*seen = core.RouteError(req)
+		_, _ = w.Write([]byte(name + " error page"))
+	})
+}
+
+func TestPageHandler_CustomRouteError(t *testing.T) {
+	loaderErr := errors.New("database down")
+	var adminSeen, shopSeen error
+	appConfig := core.Config{}
+	for _, opt := range []core.ConfigOption{
+		core.WithCustomRouteError("/admin/*", routeErrorHandler("admin", &adminSeen)),
+		core.WithCustomRouteError("/shop/checkout", routeErrorHandler("checkout", &shopSeen)),
+	} {
+		opt(&appConfig)
+	}
+	handler := newLoaderPageHandlerWithConfig(func(*http.Request) (map[string]any, error) {
+		return nil, loaderErr
+	}, appConfig)
+
+	tests := []struct {
+		name     string
+		path     string
+		wantBody string
+		wantSeen *error
+	}{
+		{name: "admin subtree", path: "/admin/users", wantBody: "admin error page", wantSeen: &adminSeen},
+		{name: "exact pattern", path: "/shop/checkout", wantBody: "checkout error page", wantSeen: &shopSeen},
+		{name: "no match uses default page", path: "/shop/cart", wantBody: "<!doctype html>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminSeen, shopSeen = nil, nil
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusInternalServerError {
+				t.Fatalf("status = %d, want 500", rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+			for _, seen := range []*error{&adminSeen, &shopSeen} {
+				if seen == tt.wantSeen {
+					if !errors.Is(*seen, loaderErr) {
+						t.Fatalf("route error = %v, want %v", *seen, loaderErr)
+					}
+				} else if *seen != nil {
+					t.Fatalf("unexpected handler ran with %v", *seen)
+				}
+			}
+		})
+	}
+}
+
+func TestPageHandler_CustomRouteErrorKeepsHandlerStatus(t *testing.T) {
+	appConfig := core.Config{}
+	core.WithCustomRouteError("/", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))(&appConfig)
+	handler := newLoaderPageHandlerWithConfig(func(*http.Request) (map[string]any, error) {
+		return nil, errors.New("upstream failed")
+	}, appConfig)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want 502", rec.Code)
+	}
+}
+
+func TestPageHandler_CustomRouteErrorSkipsClientErrors(t *testing.T) {
+	called := false
+	appConfig := core.Config{}
+	core.WithCustomRouteError("/", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		called = true
+	}))(&appConfig)
+	handler := newLoaderPageHandlerWithConfig(func(*http.Request) (map[string]any, error) {
+		return nil, forbiddenError{}
+	}, appConfig)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if called || rec.Code != http.StatusForbidden {
+		t.Fatalf("called = %v, status = %d; want default 403 page", called, rec.Code)
+	}
+}
+
+type forbiddenError struct{}
+
+func (forbiddenError) Error() string   { return "no access" }
+func (forbiddenError) StatusCode() int { return http.StatusForbidden }
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type SecureHeadersHandler struct {
+	next    http.Handler
+	headers []core.SecureHeader
+}
+
+// NewSecureHeadersHandler sets the configured security headers before delegating to next.
+// Headers are only filled when absent, and because they are set before next runs, any
+// handler further down the chain (page options, asset handlers) can still override them.
+func NewSecureHeadersHandler(next http.Handler, cfg core.SecureHeadersConfig) http.Handler {
+	return &SecureHeadersHandler{
+		next:    next,
+		headers: cfg.Headers(),
+	}
+}
+
+func (h *SecureHeadersHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	header := w.Header()
+	for _, sh := range h.headers {
+		if header.Get(sh.Name) == "" {
+			header.Set(sh.Name, sh.Value)
+		}
+	}
+	h.next.ServeHTTP(w, req)
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestSecureHeadersHandler_Defaults(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewSecureHeadersHandler(next, core.DefaultSecureHeadersConfig())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	want := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "SAMEORIGIN",
+		"Referrer-Policy":        "strict-origin-when-cross-origin",
+		"Permissions-Policy":     "camera=(), microphone=(), geolocation=()",
+	}
+	for name, value := range want {
+		if got := rr.Header().Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestSecureHeadersHandler_CustomConfigFillsDefaults(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := NewSecureHeadersHandler(next, core.SecureHeadersConfig{XFrameOptions: "DENY"})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if got := rr.Header().Get("X-Frame-Options"); got != "DENY" {
+		t.Errorf("X-Frame-Options = %q, want DENY", got)
+	}
+	if got := rr.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+}
+
+func TestSecureHeadersHandler_DownstreamOverrideWins(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Frame-Options", "ALLOW-FROM https://example.com")
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := NewSecureHeadersHandler(next, core.DefaultSecureHeadersConfig())
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if got := rr.Header().Get("X-Frame-Options"); got != "ALLOW-FROM https://example.com" {
+		t.Errorf("X-Frame-Options = %q, want downstream value", got)
+	}
+}
+
+func TestSecureHeadersHandler_PreservesUpstreamHeaders(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := NewSecureHeadersHandler(next, core.DefaultSecureHeadersConfig())
+
+	rr := httptest.NewRecorder()
+	rr.Header().Set("Referrer-Policy", "no-referrer")
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if got := rr.Header().Get("Referrer-Policy"); got != "no-referrer" {
+		t.Errorf("Referrer-Policy = %q, want no-referrer", got)
+	}
+}
+
+func TestSecureHeadersHandler_AssetAndPageResponses(t *testing.T) {
+	chdirTemp(t)
+
+	pages := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+	assets := NewAssetHandler(embeddedAssetFS, false)
+	mux := http.NewServeMux()
+	mux.Handle("/dist/", assets)
+	mux.Handle("/", pages)
+	handler := NewSecureHeadersHandler(mux, core.DefaultSecureHeadersConfig())
+
+	for _, path := range []string{"/", "/dist/app.js"} {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if got := rr.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+			t.Errorf("%s: X-Content-Type-Options = %q, want nosniff", path, got)
+		}
+		if got := rr.Header().Get("X-Frame-Options"); got != "SAMEORIGIN" {
+			t.Errorf("%s: X-Frame-Options = %q, want SAMEORIGIN", path, got)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestPageHandler_SSRFallbackWithoutRenderer(t *testing.T) {
+	tests := []struct {
+		mode       core.SSRFallbackMode
+		wantStatus int
+	}{
+		{mode: core.FallbackError, wantStatus: http.StatusInternalServerError},
+		{mode: core.FallbackClientOnly, wantStatus: http.StatusOK},
+		{mode: core.FallbackCachedOrError, wantStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		route := core.Page("/home", "./pages/home.tsx")
+		handler := newPageHandlerWithConfig(core.PageConfigFromRoute(route), core.Config{SSRFallback: tt.mode})
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/home", nil))
+
+		if rr.Code != tt.wantStatus {
+			t.Errorf("mode %d: status = %d, want %d", tt.mode, rr.Code, tt.wantStatus)
+		}
+	}
+}
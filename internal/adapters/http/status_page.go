@@ -0,0 +1,84 @@
+package http
+
+import (
+	"embed"
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+	"github.com/3-lines-studio/bifrost/internal/usecase"
+)
+
+// StatusPages holds the WithStatusPage handlers by status code.
+type StatusPages map[int]http.Handler
+
+// NewStatusPages builds an SSR page handler for each WithStatusPage component. It returns
+// nil when none are configured.
+func NewStatusPages(
+	service *usecase.PageService,
+	manifest *core.Manifest,
+	assetsFS embed.FS,
+	isDev bool,
+	appConfig core.Config,
+	diag *Diagnostics,
+) StatusPages {
+	if len(appConfig.StatusPages) == 0 {
+		return nil
+	}
+	pages := make(StatusPages, len(appConfig.StatusPages))
+	for code, componentPath := range appConfig.StatusPages {
+		config := core.PageConfig{
+			ComponentPath: componentPath,
+			Mode:          core.ModeSSR,
+			PropsLoader:   core.StatusPageProps,
+		}
+		pages[code] = NewPageHandler(service, config, manifest, assetsFS, isDev, "", appConfig, diag)
+	}
+	return pages
+}
+
+// serve renders the page registered for status, if any, with status as the response code.
+func (p StatusPages) serve(w http.ResponseWriter, req *http.Request, status int, message string) bool {
+	handler, ok := p[status]
+	if !ok {
+		return false
+	}
+	ctx := core.ContextWithStatusPage(req.Context(), core.StatusPageInfo{StatusCode: status, Message: message})
+	handler.ServeHTTP(&statusPageWriter{ResponseWriter: w, status: status}, req.WithContext(ctx))
+	return true
+}
+
+// statusPageWriter turns the status page's own success status into the error status. If
+// the status page itself fails, its error status is kept.
+type statusPageWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusPageWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if code < http.StatusMultipleChoices {
+		code = w.status
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusPageWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *statusPageWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusPageWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
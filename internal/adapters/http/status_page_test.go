@@ -0,0 +1,107 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// echoStatusPage stands in for a rendered status component by writing its props.
+var echoStatusPage = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+	props, err := core.StatusPageProps(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintf(w, "status page %v: %v", props["statusCode"], props["message"])
+})
+
+func newStatusPageHandler(loader core.PropsLoader, pages StatusPages) *PageHandler {
+	handler := newLoaderPageHandlerWithConfig(loader, core.Config{})
+	handler.SetStatusPages(pages)
+	return handler
+}
+
+func TestPageHandler_StatusPages(t *testing.T) {
+	pages := StatusPages{
+		http.StatusForbidden:           echoStatusPage,
+		http.StatusInternalServerError: echoStatusPage,
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "status code error",
+			err:        forbiddenError{},
+			wantStatus: http.StatusForbidden,
+			wantBody:   "status page 403: Forbidden",
+		},
+		{
+			name:       "plain error",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "status page 500: Internal Server Error",
+		},
+		{
+			name:       "unregistered status falls back",
+			err:        &core.RenderTimeoutError{Path: "/report"},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "<h1>Service Unavailable</h1>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newStatusPageHandler(func(*http.Request) (map[string]any, error) {
+				return nil, tt.err
+			}, pages)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest("GET", "/report", nil))
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rr.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rr.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want %q", rr.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestPageHandler_StatusPageNotFound(t *testing.T) {
+	handler := newStatusPageHandler(nil, StatusPages{http.StatusNotFound: echoStatusPage})
+
+	rr := httptest.NewRecorder()
+	handler.serveNotFound(rr, httptest.NewRequest("GET", "/report/missing", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+	if got := rr.Body.String(); got != "status page 404: Not Found" {
+		t.Errorf("body = %q", got)
+	}
+}
+
+func TestStatusPageWriterKeepsFailureStatus(t *testing.T) {
+	failing := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "status page broke", http.StatusBadGateway)
+	})
+	pages := StatusPages{http.StatusNotFound: failing}
+
+	rr := httptest.NewRecorder()
+	pages.serve(rr, httptest.NewRequest("GET", "/", nil), http.StatusNotFound, "Not Found")
+
+	if rr.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusBadGateway)
+	}
+}
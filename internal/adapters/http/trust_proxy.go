@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type TrustProxyHandler struct {
+	next http.Handler
+	hops int
+}
+
+// NewTrustProxyHandler replaces RemoteAddr with the client IP from X-Forwarded-For,
+// trusting hops proxies, and sets URL.Scheme from X-Forwarded-Proto. Requests without a
+// usable address keep their RemoteAddr.
+func NewTrustProxyHandler(next http.Handler, hops int) http.Handler {
+	return &TrustProxyHandler{next: next, hops: hops}
+}
+
+func (h *TrustProxyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ip := core.ForwardedClientIP(req.Header, h.hops)
+	proto := forwardedProto(req.Header)
+	if ip != "" || proto != "" {
+		req = req.Clone(req.Context())
+		if ip != "" {
+			req.RemoteAddr = ip
+		}
+		if proto != "" {
+			req.URL.Scheme = proto
+		}
+	}
+	h.next.ServeHTTP(w, req)
+}
+
+type TrustedProxiesHandler struct {
+	next    http.Handler
+	proxies []netip.Prefix
+}
+
+// NewTrustedProxiesHandler applies X-Forwarded-For, X-Forwarded-Proto and X-Forwarded-Host
+// to requests whose RemoteAddr is one of proxies. Other requests pass through unchanged,
+// so clients cannot spoof the headers by sending them directly.
+func NewTrustedProxiesHandler(next http.Handler, proxies []netip.Prefix) http.Handler {
+	return &TrustedProxiesHandler{next: next, proxies: proxies}
+}
+
+func (h *TrustedProxiesHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !core.IsTrustedProxy(core.ClientIP(req), h.proxies) {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+	req = req.Clone(req.Context())
+	if ip := core.TrustedForwardedClientIP(req.Header, h.proxies); ip != "" {
+		req.RemoteAddr = ip
+	}
+	if proto := forwardedProto(req.Header); proto != "" {
+		req.URL.Scheme = proto
+	}
+	if host := firstForwardedValue(req.Header.Get("X-Forwarded-Host")); host != "" {
+		req.Host = host
+	}
+	h.next.ServeHTTP(w, req)
+}
+
+// forwardedProto returns X-Forwarded-Proto when it is http or https, and "" otherwise.
+func forwardedProto(header http.Header) string {
+	proto := firstForwardedValue(header.Get("X-Forwarded-Proto"))
+	if proto == "http" || proto == "https" {
+		return proto
+	}
+	return ""
+}
+
+func firstForwardedValue(value string) string {
+	first, _, _ := strings.Cut(value, ",")
+	return strings.ToLower(strings.TrimSpace(first))
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestTrustProxyHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		hops       int
+		forwarded  []string
+		wantRemote string
+		wantIP     string
+	}{
+		{
+			name:       "one hop takes the rightmost address",
+			hops:       1,
+			forwarded:  []string{"198.51.100.7, 203.0.113.9"},
+			wantRemote: "203.0.113.9",
+			wantIP:     "203.0.113.9",
+		},
+		{
+			name:       "two hops skip the nearest proxy",
+			hops:       2,
+			forwarded:  []string{"198.51.100.7, 203.0.113.9, 10.0.0.2"},
+			wantRemote: "203.0.113.9",
+			wantIP:     "203.0.113.9",
+		},
+		{
+			name:       "addresses across repeated headers",
+			hops:       2,
+			forwarded:  []string{"198.51.100.7", "10.0.0.2"},
+			wantRemote: "198.51.100.7",
+			wantIP:     "198.51.100.7",
+		},
+		{
+			name:       "ipv6 address",
+			hops:       1,
+			forwarded:  []string{"2001:db8::1"},
+			wantRemote: "2001:db8::1",
+			wantIP:     "2001:db8::1",
+		},
+		{
+			name:       "invalid address falls back to RemoteAddr",
+			hops:       1,
+			forwarded:  []string{"198.51.100.7, not-an-ip"},
+			wantRemote: "192.0.2.1:1234",
+			wantIP:     "192.0.2.1",
+		},
+		{
+			name:       "fewer addresses than hops falls back to RemoteAddr",
+			hops:       3,
+			forwarded:  []string{"198.51.100.7, 10.0.0.2"},
+			wantRemote: "192.0.2.1:1234",
+			wantIP:     "192.0.2.1",
+		},
+		{
+			name:       "zero hops is a no-op",
+			hops:       0,
+			forwarded:  []string{"198.51.100.7"},
+			wantRemote: "192.0.2.1:1234",
+			wantIP:     "192.0.2.1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRemote, gotIP string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRemote = r.RemoteAddr
+				gotIP = core.ClientIP(r)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			for _, v := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", v)
+			}
+
+			NewTrustProxyHandler(next, tt.hops).ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotRemote != tt.wantRemote {
+				t.Errorf("RemoteAddr = %q, want %q", gotRemote, tt.wantRemote)
+			}
+			if gotIP != tt.wantIP {
+				t.Errorf("ClientIP = %q, want %q", gotIP, tt.wantIP)
+			}
+		})
+	}
+}
+
+func TestTrustProxyHandlerSetsScheme(t *testing.T) {
+	var gotScheme string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotScheme = r.URL.Scheme
+	})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-Proto", "HTTPS, http")
+
+	NewTrustProxyHandler(next, 1).ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotScheme != "https" {
+		t.Errorf("URL.Scheme = %q, want https", gotScheme)
+	}
+}
+
+func TestTrustedProxiesHandler(t *testing.T) {
+	proxies, err := core.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got *http.Request
+	handler := NewTrustedProxiesHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}), proxies)
+
+	req := httptest.NewRequest(http.MethodGet, "/blog", nil)
+	req.RemoteAddr = "10.0.0.2:41234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7, 10.0.0.5")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "www.example.com")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got.RemoteAddr != "198.51.100.7" {
+		t.Errorf("RemoteAddr = %q, want 198.51.100.7", got.RemoteAddr)
+	}
+	if got.URL.Scheme != "https" {
+		t.Errorf("URL.Scheme = %q, want https", got.URL.Scheme)
+	}
+	if got.Host != "www.example.com" {
+		t.Errorf("Host = %q, want www.example.com", got.Host)
+	}
+	if req.RemoteAddr != "10.0.0.2:41234" {
+		t.Error("the original request should not be modified")
+	}
+}
+
+func TestTrustedProxiesHandlerIgnoresUntrustedPeer(t *testing.T) {
+	proxies, _ := core.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	var got *http.Request
+	handler := NewTrustedProxiesHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}), proxies)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.50:5000"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "evil.example")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got.RemoteAddr != "203.0.113.50:5000" || got.URL.Scheme != "" || got.Host != "example.com" {
+		t.Errorf("untrusted peer rewrote request: RemoteAddr=%q Scheme=%q Host=%q", got.RemoteAddr, got.URL.Scheme, got.Host)
+	}
+}
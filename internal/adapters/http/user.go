@@ -0,0 +1,42 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type UserHandler struct {
+	next     http.Handler
+	provider core.UserProvider
+}
+
+// NewUserHandler resolves the current user with provider and stores it in the request
+// context. A panicking provider is logged and answered with 500.
+func NewUserHandler(next http.Handler, provider core.UserProvider) http.Handler {
+	return &UserHandler{next: next, provider: provider}
+}
+
+func (h *UserHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	user, ok, err := h.resolve(req)
+	if err != nil {
+		core.Logger(req.Context()).Error("bifrost user provider failed", "path", req.URL.Path, "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if ok && user != nil {
+		req = req.WithContext(core.ContextWithUser(req.Context(), user))
+	}
+	h.next.ServeHTTP(w, req)
+}
+
+func (h *UserHandler) resolve(req *http.Request) (user core.User, ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	user, ok = h.provider(req)
+	return user, ok, nil
+}
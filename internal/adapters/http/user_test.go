@@ -0,0 +1,100 @@
+package http
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type testUser struct{ id, name string }
+
+func (u testUser) ID() string   { return u.id }
+func (u testUser) Name() string { return u.name }
+
+func headerUserProvider(req *http.Request) (core.User, bool) {
+	id := req.Header.Get("X-User")
+	if id == "" {
+		return nil, false
+	}
+	return testUser{id: id, name: "User " + id}, true
+}
+
+func TestUserHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantUser bool
+	}{
+		{name: "signed in", header: "42", wantUser: true},
+		{name: "anonymous", header: "", wantUser: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				called = true
+				user, ok := core.CurrentUser(req.Context())
+				if ok != tt.wantUser {
+					t.Fatalf("CurrentUser() ok = %v, want %v", ok, tt.wantUser)
+				}
+				if ok && (user.ID() != "42" || user.Name() != "User 42") {
+					t.Errorf("CurrentUser() = %v", user)
+				}
+			})
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("X-User", tt.header)
+			}
+			NewUserHandler(next, headerUserProvider).ServeHTTP(httptest.NewRecorder(), req)
+
+			if !called {
+				t.Fatal("next handler not called")
+			}
+		})
+	}
+}
+
+func TestUserHandlerLogsUserID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		core.Logger(req.Context()).Info("loading dashboard")
+	})
+	handler := NewUserHandler(NewRequestLoggerHandler(next, logger), headerUserProvider)
+
+	req := httptest.NewRequest("GET", "/dashboard", nil)
+	req.Header.Set("X-User", "42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if line["user_id"] != "42" {
+			t.Errorf("log line %v missing user_id", line)
+		}
+	}
+}
+
+func TestUserHandlerRecoversProviderPanic(t *testing.T) {
+	provider := func(*http.Request) (core.User, bool) {
+		panic("session store down")
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		t.Error("next handler should not run after a provider panic")
+	})
+
+	rr := httptest.NewRecorder()
+	NewUserHandler(next, provider).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,66 @@
+package process
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestBuildSendsDefines(t *testing.T) {
+	var got struct {
+		Defines map[string]string `json:"defines"`
+	}
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_ = json.NewDecoder(req.Body).Decode(&got)
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	r.SetBuildDefines(core.BuildEnvDefines(map[string]string{"NEXT_PUBLIC_API_URL": "https://api.example.com"}))
+
+	if err := r.BuildSSR([]string{"entry.ts"}, "out"); err != nil {
+		t.Fatalf("BuildSSR: %v", err)
+	}
+	if got.Defines["process.env.NEXT_PUBLIC_API_URL"] != `"https://api.example.com"` {
+		t.Fatalf("defines = %v", got.Defines)
+	}
+}
+
+func TestBuildEnvReplacesProcessEnv(t *testing.T) {
+	if _, err := exec.LookPath("bun"); err != nil {
+		t.Skip("bun not installed")
+	}
+
+	dir := t.TempDir()
+	entry := filepath.Join(dir, "entry.tsx")
+	if err := os.WriteFile(entry, []byte("export const apiURL = process.env.NEXT_PUBLIC_API_URL;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRenderer(core.ModeProd, RuntimeSource(core.ModeProd), nil)
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	defer func() { _ = r.Stop() }()
+	r.SetBuildDefines(core.BuildEnvDefines(map[string]string{"NEXT_PUBLIC_API_URL": "https://api.example.com"}))
+
+	outdir := filepath.Join(dir, "out")
+	if err := r.BuildSSR([]string{entry}, outdir); err != nil {
+		t.Fatalf("BuildSSR: %v", err)
+	}
+	out, err := os.ReadFile(filepath.Join(outdir, "entry.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "https://api.example.com") {
+		t.Fatalf("define not applied, output:\n%s", out)
+	}
+	if strings.Contains(string(out), "process.env.NEXT_PUBLIC_API_URL") {
+		t.Fatalf("output still reads process.env:\n%s", out)
+	}
+}
@@ -0,0 +1,103 @@
+package process
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// prefixStringsPlugin prefixes every double-quoted string literal in .ts files with "bf:".
+var prefixStringsPlugin = core.BuildPluginSource{
+	ID: "prefix_strings",
+	Source: `function bfPlugin_prefix_strings(): Bun.BunPlugin {
+  return {
+    name: "prefix-strings",
+    setup(build) {
+      build.onLoad({ filter: /\.ts$/ }, async (args) => {
+        const text = await Bun.file(args.path).text();
+        return { contents: text.replace(/"([^"]*)"/g, '"bf:$1"'), loader: "ts" };
+      });
+    },
+  };
+}`,
+}
+
+func TestInjectBuildPlugins(t *testing.T) {
+	source := RuntimeSource(core.ModeProd)
+	if !strings.Contains(source, buildPluginsMarker) {
+		t.Fatal("runtime source has no build plugins marker")
+	}
+	if got := InjectBuildPlugins(source, nil); got != source {
+		t.Fatal("no plugins should leave the source unchanged")
+	}
+
+	injected := InjectBuildPlugins(source, []core.BuildPluginSource{prefixStringsPlugin})
+	if strings.Contains(injected, buildPluginsMarker) {
+		t.Fatal("marker should be replaced")
+	}
+	for _, want := range []string{
+		"function bfPlugin_prefix_strings(): Bun.BunPlugin",
+		`buildPlugins["prefix_strings"] = bfPlugin_prefix_strings;`,
+	} {
+		if !strings.Contains(injected, want) {
+			t.Fatalf("injected source missing %q", want)
+		}
+	}
+}
+
+func TestBuildSendsPluginIDs(t *testing.T) {
+	var got struct {
+		PluginIDs []string `json:"pluginIds"`
+	}
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_ = json.NewDecoder(req.Body).Decode(&got)
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	r.SetBuildPlugins([]string{"mdx", "macros"})
+
+	if err := r.BuildSSR([]string{"entry.ts"}, "out"); err != nil {
+		t.Fatalf("BuildSSR: %v", err)
+	}
+	if strings.Join(got.PluginIDs, ",") != "mdx,macros" {
+		t.Fatalf("pluginIds = %v, want [mdx macros]", got.PluginIDs)
+	}
+}
+
+func TestBuildPluginTransformsOutput(t *testing.T) {
+	if _, err := exec.LookPath("bun"); err != nil {
+		t.Skip("bun not installed")
+	}
+
+	dir := t.TempDir()
+	entry := filepath.Join(dir, "entry.ts")
+	if err := os.WriteFile(entry, []byte(`console.log("hello");`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := InjectBuildPlugins(RuntimeSource(core.ModeProd), []core.BuildPluginSource{prefixStringsPlugin})
+	r, err := NewRenderer(core.ModeProd, source, nil)
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	defer func() { _ = r.Stop() }()
+	r.SetBuildPlugins(core.BuildPluginIDs([]core.BuildPluginSource{prefixStringsPlugin}))
+
+	outdir := filepath.Join(dir, "out")
+	if err := r.BuildSSR([]string{entry}, outdir); err != nil {
+		t.Fatalf("BuildSSR: %v", err)
+	}
+	out, err := os.ReadFile(filepath.Join(outdir, "entry.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "bf:hello") {
+		t.Fatalf("plugin did not run, output:\n%s", out)
+	}
+}
@@ -0,0 +1,39 @@
+package process
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestBuildSendsTarget(t *testing.T) {
+	var got []map[string]any
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		got = append(got, body)
+		if req.URL.Path == "/build" {
+			_, _ = io.WriteString(w, `{"ok":true,"entries":{"home":{"script":"/dist/home.js"}}}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	r.SetBuildTarget("node")
+
+	if _, err := r.Build([]string{"home.tsx"}, "out", []string{"home"}); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if err := r.BuildSSR([]string{"home-ssr.tsx"}, "ssr"); err != nil {
+		t.Fatalf("BuildSSR: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("requests = %d, want 2", len(got))
+	}
+	if got[0]["target"] != "node" || got[0]["ssr"] != nil {
+		t.Errorf("client build target = %v, ssr = %v; want node without ssr", got[0]["target"], got[0]["ssr"])
+	}
+	if got[1]["target"] != "bun" || got[1]["ssr"] != true {
+		t.Errorf("SSR build target = %v, ssr = %v; want bun with ssr", got[1]["target"], got[1]["ssr"])
+	}
+}
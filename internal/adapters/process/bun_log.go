@@ -0,0 +1,65 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// bunLogWriter logs each line the Bun process writes to one of its output streams, with
+// source=bun and the stream name. Partial lines are kept until their newline arrives.
+type bunLogWriter struct {
+	logger *slog.Logger
+	level  slog.Level
+	buf    []byte
+}
+
+// maxBunLogLine bounds a buffered partial line; longer output is logged in pieces.
+const maxBunLogLine = 64 << 10
+
+func newBunLogWriter(logger *slog.Logger, stream string, level slog.Level) *bunLogWriter {
+	return &bunLogWriter{
+		logger: logger.With("source", "bun", "stream", stream),
+		level:  level,
+	}
+}
+
+func (w *bunLogWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	if len(w.buf) >= maxBunLogLine {
+		w.logLine(string(w.buf))
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+func (w *bunLogWriter) logLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	w.logger.Log(context.Background(), bunLineLevel(line, w.level), line)
+}
+
+// bunLineLevel raises stderr lines that Bun or the runtime mark as errors to slog.LevelError.
+func bunLineLevel(line string, level slog.Level) slog.Level {
+	if level < slog.LevelWarn {
+		return level
+	}
+	lower := strings.ToLower(strings.TrimSpace(line))
+	for _, prefix := range []string{"error", "panic", "uncaught"} {
+		if strings.HasPrefix(lower, prefix) {
+			return slog.LevelError
+		}
+	}
+	return level
+}
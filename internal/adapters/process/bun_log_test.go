@@ -0,0 +1,39 @@
+package process
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestBunLogWriter(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, nil))
+	w := newBunLogWriter(logger, "stderr", slog.LevelWarn)
+
+	for _, chunk := range []string{"Bun v1.2 deprecat", "ion notice\r\n\n", "error: boom\nhalf"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d records, want 2 (blank and partial lines are not logged): %s", len(lines), out.String())
+	}
+	want := []struct{ level, msg string }{
+		{"WARN", "Bun v1.2 deprecation notice"},
+		{"ERROR", "error: boom"},
+	}
+	for i, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec["level"] != want[i].level || rec["msg"] != want[i].msg || rec["source"] != "bun" || rec["stream"] != "stderr" {
+			t.Errorf("record %d = %v, want level %s msg %q source=bun stream=stderr", i, rec, want[i].level, want[i].msg)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package process
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestBunRunCommandSmol(t *testing.T) {
+	if got := bunRunCommand(RendererOptions{}); slices.Contains(got, "--smol") {
+		t.Errorf("default command = %v, want no --smol", got)
+	}
+	got := bunRunCommand(RendererOptions{Smol: true})
+	if want := []string{"bun", "run", "--smol", "-"}; !slices.Equal(got, want) {
+		t.Errorf("smol command = %v, want %v", got, want)
+	}
+}
+
+func TestSmolEnvEntry(t *testing.T) {
+	t.Setenv("BUN_OPTIONS", "")
+	if got, want := smolEnvEntry(), "BUN_OPTIONS=--smol"; got != want {
+		t.Errorf("smolEnvEntry() = %q, want %q", got, want)
+	}
+	t.Setenv("BUN_OPTIONS", "--inspect")
+	if got, want := smolEnvEntry(), "BUN_OPTIONS=--inspect --smol"; got != want {
+		t.Errorf("smolEnvEntry() = %q, want %q", got, want)
+	}
+}
+
+func TestNewRendererWithOptionsStarts(t *testing.T) {
+	if _, err := exec.LookPath("bun"); err != nil {
+		t.Skip("bun not installed")
+	}
+	for _, smol := range []bool{false, true} {
+		r, err := NewRendererWithOptions(core.ModeProd, RuntimeSource(core.ModeProd), RendererOptions{Smol: smol})
+		if err != nil {
+			t.Fatalf("NewRendererWithOptions(smol=%v): %v", smol, err)
+		}
+		if got := slices.Contains(r.cmd.Args, "--smol"); got != smol {
+			t.Errorf("smol=%v: cmd.Args = %v", smol, r.cmd.Args)
+		}
+		_ = r.Stop()
+	}
+}
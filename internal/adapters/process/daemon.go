@@ -0,0 +1,154 @@
+package process
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BuildDaemon is a Bun build renderer that bifrost-build --daemon leaves running on a
+// stable socket, so later builds of the same project skip starting Bun. Its files live in
+// the temp dir, named after the project: the socket, the PID, the key of the runtime
+// source and env it was started with, and Bun's output log.
+type BuildDaemon struct {
+	Socket  string
+	PIDFile string
+	KeyFile string
+	LogFile string
+}
+
+// NewBuildDaemon returns the daemon paths for the project rooted at projectDir.
+func NewBuildDaemon(projectDir string) BuildDaemon {
+	return newBuildDaemon(os.TempDir(), projectDir)
+}
+
+func newBuildDaemon(dir, projectDir string) BuildDaemon {
+	sum := sha256.Sum256([]byte(filepath.Clean(projectDir)))
+	base := filepath.Join(dir, "bifrost-build-"+hex.EncodeToString(sum[:8]))
+	return BuildDaemon{
+		Socket:  base + ".sock",
+		PIDFile: base + ".pid",
+		KeyFile: base + ".key",
+		LogFile: base + ".log",
+	}
+}
+
+// BuildDaemonKey identifies what a daemon runs: the runtime source (with build plugins),
+// the env and the working directory. A daemon is only reused for the same key.
+func BuildDaemonKey(source, cwd string, env []string) string {
+	h := sha256.New()
+	h.Write([]byte(source))
+	h.Write([]byte{0})
+	h.Write([]byte(cwd))
+	for _, entry := range env {
+		h.Write([]byte{0})
+		h.Write([]byte(entry))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Connect returns a renderer for the running daemon when it was started with key. A
+// daemon that no longer answers is cleaned up; one with another key is stopped. Stopping
+// the returned renderer leaves the daemon running.
+func (d BuildDaemon) Connect(key string) (*Renderer, bool) {
+	data, err := os.ReadFile(d.KeyFile)
+	if err != nil {
+		return nil, false
+	}
+	conn, err := net.DialTimeout("unix", d.Socket, statusDialTimeout)
+	if err != nil {
+		d.removeFiles()
+		return nil, false
+	}
+	_ = conn.Close()
+	if strings.TrimSpace(string(data)) != key {
+		_, _ = d.Stop()
+		return nil, false
+	}
+	return d.renderer(), true
+}
+
+// Start runs the Bun runtime source detached on the daemon socket and records it under
+// key. Bun's output goes to LogFile.
+func (d BuildDaemon) Start(source, key string, env ...string) (*Renderer, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working directory: %w", err)
+	}
+	logFile, err := os.OpenFile(d.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open build daemon log: %w", err)
+	}
+	defer logFile.Close()
+
+	r, err := startRendererProcess(rendererProcessConfig{
+		command: []string{"bun", "run", "-"},
+		cwd:     cwd,
+		source:  source,
+		env:     env,
+		socket:  d.Socket,
+		output:  logFile,
+		detach:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pid := strconv.Itoa(r.cmd.Process.Pid)
+	if err := os.WriteFile(d.PIDFile, []byte(pid+"\n"), 0o600); err != nil {
+		_ = r.Stop()
+		return nil, fmt.Errorf("failed to write build daemon pid: %w", err)
+	}
+	if err := os.WriteFile(d.KeyFile, []byte(key+"\n"), 0o600); err != nil {
+		_ = r.Stop()
+		d.removeFiles()
+		return nil, fmt.Errorf("failed to write build daemon key: %w", err)
+	}
+	_ = r.cmd.Process.Release()
+	return d.renderer(), nil
+}
+
+// Stop kills the daemon and removes its files. It reports whether a daemon was recorded.
+func (d BuildDaemon) Stop() (bool, error) {
+	data, err := os.ReadFile(d.PIDFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		d.removeFiles()
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer d.removeFiles()
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return true, fmt.Errorf("invalid build daemon pid file %s: %w", d.PIDFile, err)
+	}
+	// The daemon may already be gone; the files are removed either way.
+	if process, err := os.FindProcess(pid); err == nil {
+		_ = process.Kill()
+	}
+	return true, nil
+}
+
+func (d BuildDaemon) renderer() *Renderer {
+	return &Renderer{
+		socket:    d.Socket,
+		client:    newHTTPClient(d.Socket),
+		startedAt: time.Now(),
+	}
+}
+
+func (d BuildDaemon) removeFiles() {
+	for _, path := range []string{d.Socket, d.PIDFile, d.KeyFile} {
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package process
+
+import "os/exec"
+
+// detachProcess leaves cmd attached; the build daemon then lives as long as its
+// process is not killed along with the console.
+func detachProcess(cmd *exec.Cmd) {}
@@ -0,0 +1,108 @@
+package process
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+)
+
+func listenDaemonSocket(t *testing.T, d BuildDaemon) {
+	t.Helper()
+	ln, err := net.Listen("unix", d.Socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+}
+
+func TestBuildDaemonConnect(t *testing.T) {
+	d := newBuildDaemon(t.TempDir(), "/work/app")
+	if _, ok := d.Connect("key-a"); ok {
+		t.Fatal("Connect() without a daemon should fail")
+	}
+
+	listenDaemonSocket(t, d)
+	if err := os.WriteFile(d.KeyFile, []byte("key-a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, ok := d.Connect("key-a")
+	if !ok {
+		t.Fatal("Connect() with matching key should reuse the daemon")
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if _, err := os.Stat(d.Socket); err != nil {
+		t.Errorf("stopping a connected renderer must leave the daemon socket: %v", err)
+	}
+
+	if _, ok := d.Connect("key-b"); ok {
+		t.Fatal("Connect() with another key should not reuse the daemon")
+	}
+	if _, err := os.Stat(d.KeyFile); !os.IsNotExist(err) {
+		t.Errorf("key file should be removed after a key mismatch, stat error = %v", err)
+	}
+}
+
+func TestBuildDaemonConnectCleansStaleFiles(t *testing.T) {
+	d := newBuildDaemon(t.TempDir(), "/work/app")
+	for _, path := range []string{d.Socket, d.KeyFile} {
+		if err := os.WriteFile(path, []byte("key-a\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, ok := d.Connect("key-a"); ok {
+		t.Fatal("Connect() to a dead socket should fail")
+	}
+	for _, path := range []string{d.Socket, d.KeyFile} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed, stat error = %v", path, err)
+		}
+	}
+}
+
+func TestBuildDaemonStop(t *testing.T) {
+	d := newBuildDaemon(t.TempDir(), "/work/app")
+	if stopped, err := d.Stop(); stopped || err != nil {
+		t.Fatalf("Stop() without a daemon = %v, %v", stopped, err)
+	}
+
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("sleep unavailable: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	if err := os.WriteFile(d.PIDFile, []byte(strconv.Itoa(cmd.Process.Pid)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stopped, err := d.Stop()
+	if !stopped || err != nil {
+		t.Fatalf("Stop() = %v, %v", stopped, err)
+	}
+	if err := <-done; err == nil {
+		t.Error("daemon process should have been killed")
+	}
+	if _, err := os.Stat(d.PIDFile); !os.IsNotExist(err) {
+		t.Errorf("pid file should be removed, stat error = %v", err)
+	}
+}
+
+func TestBuildDaemonPerProject(t *testing.T) {
+	a := newBuildDaemon("/tmp", "/work/app")
+	b := newBuildDaemon("/tmp", "/work/other")
+	if a.Socket == b.Socket {
+		t.Errorf("projects share socket %s", a.Socket)
+	}
+	if a != newBuildDaemon("/tmp", "/work/app/") {
+		t.Error("daemon paths should not depend on a trailing slash")
+	}
+	if BuildDaemonKey("src", "/work", []string{"A=1"}) == BuildDaemonKey("src", "/work", []string{"A=2"}) {
+		t.Error("daemon key should change with the env")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package process
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// detachProcess starts cmd in a new session, so the build daemon keeps running after
+// bifrost-build exits and does not get the terminal's Ctrl-C.
+func detachProcess(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
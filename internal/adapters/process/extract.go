@@ -1,8 +1,14 @@
 package process
 
 import (
+	"compress/gzip"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
@@ -12,15 +18,29 @@ import (
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
 
-func ExtractEmbeddedRuntime(assetsFS embed.FS) (string, func(), error) {
-	runtimePath := filepath.Join(".bifrost", "runtime", "bifrost-renderer")
+func embeddedRuntimePath() string {
+	runtimePath := filepath.Join(core.OutputDir, "runtime", "bifrost-renderer")
 	if runtime.GOOS == "windows" {
 		runtimePath += ".exe"
 	}
+	return runtimePath
+}
+
+// ExtractEmbeddedRuntime writes the embedded renderer to a temp dir. A runtime built with
+// --compress-runtime is decompressed and checked against its SHA-256 first.
+func ExtractEmbeddedRuntime(assetsFS embed.FS) (string, func(), error) {
+	runtimePath := embeddedRuntimePath()
 
 	data, err := assetsFS.ReadFile(runtimePath)
 	if err != nil {
-		return "", nil, fmt.Errorf("embedded runtime not found at %s: %w", runtimePath, err)
+		compressed, compressedErr := readCompressedRuntime(assetsFS, runtimePath)
+		if compressedErr == nil {
+			data = compressed
+		} else if errors.Is(compressedErr, fs.ErrNotExist) {
+			return "", nil, fmt.Errorf("embedded runtime not found at %s: %w", runtimePath, err)
+		} else {
+			return "", nil, compressedErr
+		}
 	}
 
 	tempDir, err := os.MkdirTemp("", "bifrost-runtime-*")
@@ -46,23 +66,61 @@ func ExtractEmbeddedRuntime(assetsFS embed.FS) (string, func(), error) {
 }
 
 func HasEmbeddedRuntime(assetsFS embed.FS) bool {
-	runtimePath := filepath.Join(".bifrost", "runtime", "bifrost-renderer")
-	if runtime.GOOS == "windows" {
-		runtimePath += ".exe"
+	runtimePath := embeddedRuntimePath()
+	for _, name := range []string{runtimePath, runtimePath + core.CompressedRuntimeSuffix} {
+		if f, err := assetsFS.Open(name); err == nil {
+			_ = f.Close()
+			return true
+		}
 	}
+	return false
+}
 
-	f, err := assetsFS.Open(runtimePath)
+// readCompressedRuntime decompresses the gzipped runtime written by --compress-runtime and
+// verifies it against the embedded checksum.
+func readCompressedRuntime(assetsFS fs.FS, runtimePath string) ([]byte, error) {
+	runtimePath = filepath.ToSlash(runtimePath)
+	f, err := assetsFS.Open(runtimePath + core.CompressedRuntimeSuffix)
 	if err != nil {
-		return false
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	wantSum, err := fs.ReadFile(assetsFS, runtimePath+core.RuntimeChecksumSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("compressed runtime has no checksum file: %w", err)
+	}
+
+	data, err := decompressVerified(f, wantSum)
+	if err != nil {
+		return nil, fmt.Errorf("embedded runtime: %w", err)
+	}
+	return data, nil
+}
+
+// decompressVerified gunzips r and checks the result against wantSum, a hex SHA-256 as
+// written next to a compressed file by bifrost-build.
+func decompressVerified(r io.Reader, wantSum []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	if got, want := hex.EncodeToString(sum[:]), strings.TrimSpace(string(wantSum)); got != want {
+		return nil, fmt.Errorf("checksum mismatch: got %s, want %s", got, want)
 	}
-	_ = f.Close()
-	return true
+	return data, nil
 }
 
 func ExtractSSRBundles(assetsFS embed.FS, manifest *core.Manifest) (string, func(), error) {
 	read := func(manifestSSRPath string) ([]byte, error) {
 		clean := strings.TrimPrefix(filepath.ToSlash(manifestSSRPath), "/")
-		embedPath := path.Join(".bifrost", clean)
+		embedPath := path.Join(core.OutputDir, clean)
 		return assetsFS.ReadFile(embedPath)
 	}
 	return StageSSRBundles(read, manifest)
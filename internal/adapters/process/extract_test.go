@@ -0,0 +1,58 @@
+package process
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func compressedRuntimeFS(t *testing.T, binary []byte, checksum string) fstest.MapFS {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(binary); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return fstest.MapFS{
+		"runtime/bifrost-renderer.gz":     {Data: buf.Bytes()},
+		"runtime/bifrost-renderer.sha256": {Data: []byte(checksum + "\n")},
+	}
+}
+
+func TestReadCompressedRuntime(t *testing.T) {
+	binary := []byte("#!/bin/sh\necho renderer\n")
+	sum := sha256.Sum256(binary)
+
+	data, err := readCompressedRuntime(compressedRuntimeFS(t, binary, hex.EncodeToString(sum[:])), "runtime/bifrost-renderer")
+	if err != nil {
+		t.Fatalf("readCompressedRuntime: %v", err)
+	}
+	if !bytes.Equal(data, binary) {
+		t.Fatalf("data = %q, want %q", data, binary)
+	}
+}
+
+func TestReadCompressedRuntime_ChecksumMismatch(t *testing.T) {
+	fsys := compressedRuntimeFS(t, []byte("renderer"), strings.Repeat("0", 64))
+
+	_, err := readCompressedRuntime(fsys, "runtime/bifrost-renderer")
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("err = %v, want checksum mismatch", err)
+	}
+}
+
+func TestReadCompressedRuntime_Missing(t *testing.T) {
+	_, err := readCompressedRuntime(fstest.MapFS{}, "runtime/bifrost-renderer")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("err = %v, want fs.ErrNotExist", err)
+	}
+}
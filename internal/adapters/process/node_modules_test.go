@@ -0,0 +1,58 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestBuildResolvesFromNodeModulesPath(t *testing.T) {
+	if _, err := exec.LookPath("bun"); err != nil {
+		t.Skip("bun not installed")
+	}
+
+	// The dependency is hoisted to a repo root that is not an ancestor of the entry, so
+	// Bun's normal parent-directory lookup cannot find it.
+	repo := t.TempDir()
+	outside := t.TempDir()
+	pkgDir := filepath.Join(repo, "node_modules", "greeting")
+	entry := filepath.Join(outside, "page.tsx")
+	for path, content := range map[string]string{
+		filepath.Join(pkgDir, "package.json"): `{"name":"greeting","main":"index.js"}`,
+		filepath.Join(pkgDir, "index.js"):     `export const greeting = "hello from hoisted";`,
+		entry:                                 `import { greeting } from "greeting";` + "\nexport const text = greeting;\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	env, err := core.NodeModulesPathEnvEntry(filepath.Join(repo, "node_modules"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRenderer(core.ModeProd, RuntimeSource(core.ModeProd), nil, env)
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	defer func() { _ = r.Stop() }()
+
+	outdir := filepath.Join(outside, "out")
+	if err := r.BuildSSR([]string{entry}, outdir); err != nil {
+		t.Fatalf("BuildSSR: %v", err)
+	}
+	out, err := os.ReadFile(filepath.Join(outdir, "page.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "hello from hoisted") {
+		t.Fatalf("dependency not bundled from the node_modules path, output:\n%s", out)
+	}
+}
@@ -8,6 +8,40 @@ const isDev =
 const tailwindPlugin: Bun.BunPlugin | undefined = BIFROST_TAILWIND_PLUGIN;
 const reactCompilerPlugin: Bun.BunPlugin | undefined = BIFROST_REACT_COMPILER_PLUGIN;
 
+// WithBunNodeModulesPath: bare imports that do not resolve from the importing file fall
+// back to this node_modules directory (hoisted monorepos, pnpm workspaces).
+const nodeModulesPath = process.env.BIFROST_NODE_MODULES_PATH;
+const nodeModulesPlugin: Bun.BunPlugin | undefined = nodeModulesPath
+  ? {
+      name: "bifrost-node-modules-path",
+      setup(build) {
+        const root = nodePath.dirname(nodeModulesPath);
+        build.onResolve({ filter: /^[^./]/ }, (args) => {
+          if (/^(node|bun):/.test(args.path)) {
+            return undefined;
+          }
+          const from = args.importer ? nodePath.dirname(args.importer) : process.cwd();
+          try {
+            Bun.resolveSync(args.path, from);
+            return undefined;
+          } catch {}
+          try {
+            return { path: Bun.resolveSync(args.path, root) };
+          } catch {
+            return undefined;
+          }
+        });
+      },
+    }
+  : undefined;
+if (nodeModulesPlugin) {
+  Bun.plugin(nodeModulesPlugin);
+}
+
+// WithBuildPlugin sources are inserted below; each registers its bfPlugin_<id> factory.
+const buildPlugins: Record<string, () => Bun.BunPlugin> = {};
+// BIFROST_BUILD_PLUGINS
+
 interface ErrorDetail {
   message: string;
   position?: {
@@ -32,6 +66,7 @@ interface RenderResult {
   html?: string;
   head?: string;
   stream?: ReadableStream<Uint8Array>;
+  boundaryError?: string;
 }
 
 function serializeError(error: unknown): {
@@ -413,10 +448,19 @@ async function handleRender(req: Bun.BunRequest): Promise<Response> {
       const result: RenderResult = await mod.render(props || {}, {
         streamBody: wantStream,
       });
+      let res: Response;
       if (result.stream instanceof ReadableStream) {
-        return headThenRawStreamResponse(result.head ?? "", result.stream);
+        res = headThenRawStreamResponse(result.head ?? "", result.stream);
+      } else {
+        res = renderResponse(result.head ?? "", result.html ?? "");
+      }
+      if (result.boundaryError) {
+        res.headers.set(
+          "X-Bifrost-Boundary-Error",
+          encodeURIComponent(result.boundaryError),
+        );
       }
-      return renderResponse(result.head ?? "", result.html ?? "");
+      return res;
     }
 
     const cached = componentCache.get(path);
@@ -500,7 +544,10 @@ async function handleBuild(req: Bun.BunRequest): Promise<Response> {
     entrypoints?: string[];
     outdir?: string;
     target?: string;
+    ssr?: boolean;
     entryNames?: string[];
+    pluginIds?: string[];
+    defines?: Record<string, string>;
   };
   try {
     body = await req.json();
@@ -509,7 +556,8 @@ async function handleBuild(req: Bun.BunRequest): Promise<Response> {
     return createError(`Failed to parse request: ${message}`);
   }
 
-  const { entrypoints, outdir, target, entryNames } = body;
+  const { entrypoints, outdir, target, ssr, entryNames, pluginIds, defines } =
+    body;
 
   if (!Array.isArray(entrypoints) || entrypoints.length === 0) {
     return createError("Missing entrypoints");
@@ -519,17 +567,25 @@ async function handleBuild(req: Bun.BunRequest): Promise<Response> {
     return createError("Missing outdir");
   }
 
-  const buildTarget = target === "bun" ? "bun" : "browser";
-  const isSSR = buildTarget === "bun";
+  const buildTarget =
+    target === "bun" || target === "node" ? target : "browser";
+  const isSSR = ssr ?? buildTarget === "bun";
   const hashClientAssets =
     (process.env.BIFROST_PROD === "1" ||
       process.env.BIFROST_PROD === "true") &&
     !isSSR;
 
+  const missingPlugin = (pluginIds ?? []).find((id) => !buildPlugins[id]);
+  if (missingPlugin) {
+    return createError(`Unknown build plugin: ${missingPlugin}`);
+  }
+
   try {
     const plugins = [
+      ...(nodeModulesPlugin ? [nodeModulesPlugin] : []),
       ...(reactCompilerPlugin ? [reactCompilerPlugin] : []),
       ...(!isSSR && tailwindPlugin ? [tailwindPlugin] : []),
+      ...(pluginIds ?? []).map((id) => buildPlugins[id]()),
     ];
 
     const naming = hashClientAssets
@@ -551,9 +607,10 @@ async function handleBuild(req: Bun.BunRequest): Promise<Response> {
       naming,
       plugins,
       metafile: true,
-      ...(!isDev
-        ? { define: { "process.env.NODE_ENV": '"production"' } }
-        : {}),
+      define: {
+        ...(!isDev ? { "process.env.NODE_ENV": '"production"' } : {}),
+        ...(defines ?? {}),
+      },
     });
 
     if (!result.success) {
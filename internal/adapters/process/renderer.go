@@ -10,21 +10,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
 
 const (
-	renderTimeout = 30 * time.Second
-	buildTimeout  = 120 * time.Second
-	socketTimeout = 10 * time.Second
+	renderTimeout      = 30 * time.Second
+	buildTimeout       = 120 * time.Second
+	socketTimeout      = 10 * time.Second
+	renderRetryBackoff = 50 * time.Millisecond
+	statusDialTimeout  = 200 * time.Millisecond
 )
 
 var (
@@ -35,6 +42,22 @@ var (
 	reactCompilerPluginSource string
 )
 
+const buildPluginsMarker = "// BIFROST_BUILD_PLUGINS"
+
+// InjectBuildPlugins inserts the plugin sources into a renderer source and registers each
+// bfPlugin_<id> factory for /build requests that list it in pluginIds.
+func InjectBuildPlugins(source string, plugins []core.BuildPluginSource) string {
+	if len(plugins) == 0 {
+		return source
+	}
+	var b strings.Builder
+	for _, plugin := range plugins {
+		fmt.Fprintf(&b, "// build plugin %s\n%s\n", plugin.ID, strings.TrimSpace(plugin.Source))
+		fmt.Fprintf(&b, "buildPlugins[%q] = bfPlugin_%s;\n", plugin.ID, plugin.ID)
+	}
+	return strings.Replace(source, buildPluginsMarker, b.String(), 1)
+}
+
 func RuntimeSource(mode core.Mode) string {
 	tailwindPlugin := `(await import("bun-plugin-tailwind")).default`
 	if mode == core.ModeProd {
@@ -47,10 +70,17 @@ func RuntimeSource(mode core.Mode) string {
 }
 
 type Renderer struct {
-	cmd     *exec.Cmd
-	socket  string
-	client  *http.Client
-	cleanup func()
+	cmd           *exec.Cmd
+	socket        string
+	client        *http.Client
+	cleanup       func()
+	renderRetries int
+	pluginIDs     []string
+	defines       map[string]string
+	buildTarget   string
+	startedAt     time.Time
+	stopped       atomic.Bool
+	rendered      sync.Map // component paths rendered successfully, for core.FirstRenderHook
 }
 
 type rendererProcessConfig struct {
@@ -59,6 +89,14 @@ type rendererProcessConfig struct {
 	source  string
 	env     []string
 	cleanup func()
+	logger  *slog.Logger
+	// socket is a fixed socket path; empty picks a unique one.
+	socket string
+	// output receives Bun's stdout and stderr when logger is nil; nil means the
+	// process's own.
+	output io.Writer
+	// detach starts Bun in its own session so it outlives this process.
+	detach bool
 }
 
 type renderRequestPayload struct {
@@ -80,7 +118,41 @@ func removeStaleSocket(path string) {
 	}
 }
 
-func NewRenderer(mode core.Mode, source string, extraEnv ...string) (*Renderer, error) {
+// RendererOptions controls how NewRendererWithOptions runs Bun.
+type RendererOptions struct {
+	// Logger receives Bun's stdout and stderr line by line; nil copies them to the
+	// process's own.
+	Logger *slog.Logger
+	// Env is added to the environment of the Bun process.
+	Env []string
+	// Smol runs Bun with --smol, which keeps the heap small at some cost in throughput.
+	// A compiled runtime gets it through BUN_OPTIONS.
+	Smol bool
+}
+
+// smolEnvEntry is the BUN_OPTIONS entry that passes --smol to a compiled runtime, keeping
+// any options already set in the environment.
+func smolEnvEntry() string {
+	return "BUN_OPTIONS=" + strings.TrimSpace(os.Getenv("BUN_OPTIONS")+" --smol")
+}
+
+// bunRunCommand is the command that runs the runtime source from stdin.
+func bunRunCommand(opts RendererOptions) []string {
+	command := []string{"bun", "run"}
+	if opts.Smol {
+		command = append(command, "--smol")
+	}
+	return append(command, "-")
+}
+
+// NewRenderer starts Bun with the runtime source. With a non-nil logger, Bun's stdout and
+// stderr are logged line by line instead of being copied to the process's own.
+func NewRenderer(mode core.Mode, source string, logger *slog.Logger, extraEnv ...string) (*Renderer, error) {
+	return NewRendererWithOptions(mode, source, RendererOptions{Logger: logger, Env: extraEnv})
+}
+
+// NewRendererWithOptions is NewRenderer with the Bun process configured by opts.
+func NewRendererWithOptions(mode core.Mode, source string, opts RendererOptions) (*Renderer, error) {
 	if source == "" {
 		source = RuntimeSource(mode)
 	}
@@ -91,17 +163,30 @@ func NewRenderer(mode core.Mode, source string, extraEnv ...string) (*Renderer,
 	}
 
 	return startRendererProcess(rendererProcessConfig{
-		command: []string{"bun", "run", "-"},
+		command: bunRunCommand(opts),
 		cwd:     cwd,
 		source:  source,
-		env:     extraEnv,
+		env:     opts.Env,
+		logger:  opts.Logger,
 	})
 }
 
-func NewRendererFromExecutable(executablePath string, cleanup func()) (*Renderer, error) {
+func NewRendererFromExecutable(executablePath string, cleanup func(), logger *slog.Logger, extraEnv ...string) (*Renderer, error) {
+	return NewRendererFromExecutableWithOptions(executablePath, cleanup, RendererOptions{Logger: logger, Env: extraEnv})
+}
+
+// NewRendererFromExecutableWithOptions is NewRendererFromExecutable with the process
+// configured by opts.
+func NewRendererFromExecutableWithOptions(executablePath string, cleanup func(), opts RendererOptions) (*Renderer, error) {
+	env := opts.Env
+	if opts.Smol {
+		env = append(slices.Clip(env), smolEnvEntry())
+	}
 	return startRendererProcess(rendererProcessConfig{
 		command: []string{executablePath},
 		cleanup: cleanup,
+		env:     env,
+		logger:  opts.Logger,
 	})
 }
 
@@ -126,7 +211,10 @@ func newHTTPClient(socket string) *http.Client {
 }
 
 func startRendererProcess(cfg rendererProcessConfig) (*Renderer, error) {
-	socket := uniqueSocketPath()
+	socket := cfg.socket
+	if socket == "" {
+		socket = uniqueSocketPath()
+	}
 	removeStaleSocket(socket)
 
 	cmd := exec.Command(cfg.command[0], cfg.command[1:]...)
@@ -134,6 +222,17 @@ func startRendererProcess(cfg rendererProcessConfig) (*Renderer, error) {
 	cmd.Env = append(os.Environ(), append([]string{"BIFROST_SOCKET=" + socket}, cfg.env...)...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if cfg.output != nil {
+		cmd.Stdout = cfg.output
+		cmd.Stderr = cfg.output
+	}
+	if cfg.detach {
+		detachProcess(cmd)
+	}
+	if cfg.logger != nil {
+		cmd.Stdout = newBunLogWriter(cfg.logger, "stdout", slog.LevelInfo)
+		cmd.Stderr = newBunLogWriter(cfg.logger, "stderr", slog.LevelWarn)
+	}
 	if cfg.source != "" {
 		cmd.Stdin = strings.NewReader(cfg.source)
 	}
@@ -150,10 +249,11 @@ func startRendererProcess(cfg rendererProcessConfig) (*Renderer, error) {
 	}
 
 	return &Renderer{
-		cmd:     cmd,
-		socket:  socket,
-		client:  newHTTPClient(socket),
-		cleanup: cfg.cleanup,
+		cmd:       cmd,
+		socket:    socket,
+		client:    newHTTPClient(socket),
+		cleanup:   cfg.cleanup,
+		startedAt: time.Now(),
 	}, nil
 }
 
@@ -171,6 +271,7 @@ func waitForStartedSocket(cmd *exec.Cmd, socket string, cleanup func()) error {
 }
 
 func (r *Renderer) Stop() error {
+	r.stopped.Store(true)
 	if r.cmd == nil || r.cmd.Process == nil {
 		if r.cleanup != nil {
 			r.cleanup()
@@ -186,6 +287,25 @@ func (r *Renderer) Stop() error {
 	return err
 }
 
+// Status reports the renderer process. The renderer counts as up while its socket
+// accepts connections.
+func (r *Renderer) Status() core.RendererStatus {
+	status := core.RendererStatus{StartedAt: r.startedAt}
+	if r.cmd != nil && r.cmd.Process != nil {
+		status.PID = r.cmd.Process.Pid
+	}
+	if r.stopped.Load() || r.socket == "" {
+		return status
+	}
+	conn, err := net.DialTimeout("unix", r.socket, statusDialTimeout)
+	if err != nil {
+		return status
+	}
+	_ = conn.Close()
+	status.Up = true
+	return status
+}
+
 type renderErrJSON struct {
 	Message string `json:"message"`
 	Stack   string `json:"stack"`
@@ -235,16 +355,86 @@ func MarshalRenderRequestJSON(path string, props map[string]any, streamBody bool
 	})
 }
 
+// SetBuildPlugins makes Build and BuildSSR run the given plugins, which must have been
+// injected into the renderer source with InjectBuildPlugins.
+func (r *Renderer) SetBuildPlugins(ids []string) {
+	r.pluginIDs = ids
+}
+
+// SetBuildDefines makes Build and BuildSSR replace each key (such as process.env.API_URL)
+// with its value, a JavaScript expression, as Bun's define option does.
+func (r *Renderer) SetBuildDefines(defines map[string]string) {
+	r.defines = defines
+}
+
+// SetBuildTarget makes Build bundle for target (browser, bun or node) instead of the
+// browser. BuildSSR always targets Bun.
+func (r *Renderer) SetBuildTarget(target string) {
+	r.buildTarget = target
+}
+
+// SetRenderRetries sets how many times a render is retried after a connection-level
+// error (runtime restarting, EPIPE, refused socket). Build requests are never retried.
+func (r *Renderer) SetRenderRetries(n int) {
+	if n < 0 {
+		n = 0
+	}
+	r.renderRetries = n
+}
+
+// boundaryErrorHeader carries the URL-encoded message of an error caught by a
+// WithErrorBoundary entry; the response body is the fallback render.
+const boundaryErrorHeader = "X-Bifrost-Boundary-Error"
+
 func (r *Renderer) postRender(ctx context.Context, path string, props map[string]any, streamBody bool) (*http.Response, error) {
 	jsonBody, err := MarshalRenderRequestJSON(path, props, streamBody)
 	if err != nil {
 		return nil, err
 	}
-	req, err := newJSONRequest(ctx, "/render", jsonBody)
+	resp, err := r.doWithRetries(ctx, "/render", jsonBody, r.renderRetries)
+	if err == nil {
+		reportBoundaryError(ctx, resp.Header.Get(boundaryErrorHeader))
+	}
+	return resp, err
+}
+
+func reportBoundaryError(ctx context.Context, encoded string) {
+	if encoded == "" {
+		return
+	}
+	message, err := url.PathUnescape(encoded)
 	if err != nil {
-		return nil, err
+		message = encoded
+	}
+	core.ReportBoundaryError(ctx, &core.BoundaryError{Message: message})
+}
+
+// doWithRetries retries only when no HTTP response was received; an error status from
+// the runtime is returned as-is. Backoff doubles from renderRetryBackoff.
+func (r *Renderer) doWithRetries(ctx context.Context, endpoint string, body []byte, retries int) (*http.Response, error) {
+	backoff := renderRetryBackoff
+	for attempt := 0; ; attempt++ {
+		req, err := newJSONRequest(ctx, endpoint, body)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := r.client.Do(req)
+		if err == nil || ctx.Err() != nil {
+			return resp, err
+		}
+		if attempt >= retries {
+			return nil, fmt.Errorf("%w: %w", core.ErrRendererUnavailable, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+		backoff *= 2
 	}
-	return r.client.Do(req)
 }
 
 func newJSONRequest(ctx context.Context, endpoint string, body []byte) (*http.Request, error) {
@@ -310,7 +500,24 @@ func (r *Renderer) RenderChunked(ctx context.Context, path string, props map[str
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	return renderChunkedFromDecoder(json.NewDecoder(resp.Body), onHead, onBody)
+	if err := renderChunkedFromDecoder(json.NewDecoder(resp.Body), onHead, onBody); err != nil {
+		return err
+	}
+	r.markRendered(ctx, path)
+	return nil
+}
+
+// markRendered calls the first-render hook on ctx the first time path renders successfully.
+// Renders without a hook do not count, so a build-time render cannot hide a page's first
+// request.
+func (r *Renderer) markRendered(ctx context.Context, path string) {
+	hook := core.FirstRenderHook(ctx)
+	if hook == nil {
+		return
+	}
+	if _, seen := r.rendered.LoadOrStore(path, struct{}{}); !seen {
+		hook()
+	}
 }
 
 type renderFirstLine struct {
@@ -392,13 +599,17 @@ func (r *Renderer) RenderBodyStream(ctx context.Context, path string, props map[
 		if flush != nil {
 			flush()
 		}
+		r.markRendered(ctx, path)
 		return nil
 	}
 	if err := onHead(head); err != nil {
 		return err
 	}
-	_, err = copyResponseBodyWithFlush(w, br, flush, true)
-	return err
+	if _, err := copyResponseBodyWithFlush(w, br, flush, true); err != nil {
+		return err
+	}
+	r.markRendered(ctx, path)
+	return nil
 }
 
 func (r *Renderer) Render(path string, props map[string]any) (core.RenderedPage, error) {
@@ -443,6 +654,15 @@ func (r *Renderer) Build(entrypoints []string, outdir string, entryNames []strin
 		"outdir":      outdir,
 		"entryNames":  entryNames,
 	}
+	if r.buildTarget != "" {
+		reqBody["target"] = r.buildTarget
+	}
+	if len(r.pluginIDs) > 0 {
+		reqBody["pluginIds"] = r.pluginIDs
+	}
+	if len(r.defines) > 0 {
+		reqBody["defines"] = r.defines
+	}
 
 	var result struct {
 		OK      bool                              `json:"ok"`
@@ -523,6 +743,13 @@ func (r *Renderer) BuildSSR(entrypoints []string, outdir string) error {
 		"entrypoints": entrypoints,
 		"outdir":      outdir,
 		"target":      "bun",
+		"ssr":         true,
+	}
+	if len(r.pluginIDs) > 0 {
+		reqBody["pluginIds"] = r.pluginIDs
+	}
+	if len(r.defines) > 0 {
+		reqBody["defines"] = r.defines
 	}
 
 	var result struct {
@@ -0,0 +1,53 @@
+package process
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestRenderChunkedReportsBoundaryError(t *testing.T) {
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set(boundaryErrorHeader, "Cannot%20read%20user")
+		_, _ = io.WriteString(w, `{"head":"","html":"<p>fallback</p>"}`+"\n")
+	}))
+
+	var reported []string
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ctx = core.ContextWithBoundaryErrorReporter(ctx, func(err *core.BoundaryError) {
+		reported = append(reported, err.Message)
+	})
+
+	var body string
+	err := r.RenderChunked(ctx, "page.js", nil, func(string) error { return nil }, func(b string) error {
+		body = b
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RenderChunked: %v", err)
+	}
+	if body != "<p>fallback</p>" {
+		t.Errorf("body = %q, want the fallback", body)
+	}
+	if len(reported) != 1 || reported[0] != "Cannot read user" {
+		t.Errorf("reported = %q, want [Cannot read user]", reported)
+	}
+}
+
+func TestRenderChunkedWithoutBoundaryErrorReportsNothing(t *testing.T) {
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, `{"head":"","html":"<p>ok</p>"}`+"\n")
+	}))
+
+	ctx := core.ContextWithBoundaryErrorReporter(context.Background(), func(err *core.BoundaryError) {
+		t.Errorf("unexpected boundary error %q", err.Message)
+	})
+	if err := r.RenderChunked(ctx, "page.js", nil, func(string) error { return nil }, func(string) error { return nil }); err != nil {
+		t.Fatalf("RenderChunked: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package process
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestRenderChunkedCallsFirstRenderHookOnce(t *testing.T) {
+	var fail atomic.Bool
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if fail.Load() {
+			_, _ = io.WriteString(w, `{"error":{"message":"boom"}}`+"\n")
+			return
+		}
+		_, _ = io.WriteString(w, `{"head":"","html":"<p>ok</p>"}`+"\n")
+	}))
+
+	calls := 0
+	ctx := core.ContextWithFirstRenderHook(context.Background(), func() { calls++ })
+	render := func(path string) error {
+		return r.RenderChunked(ctx, path, nil, func(string) error { return nil }, func(string) error { return nil })
+	}
+
+	fail.Store(true)
+	if err := render("home.js"); err == nil {
+		t.Fatal("expected render error")
+	}
+	if calls != 0 {
+		t.Fatalf("hook called %d times after a failed render, want 0", calls)
+	}
+
+	fail.Store(false)
+	for range 3 {
+		if err := render("home.js"); err != nil {
+			t.Fatalf("RenderChunked: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("hook called %d times for one component, want 1", calls)
+	}
+
+	if err := render("about.js"); err != nil {
+		t.Fatalf("RenderChunked: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("hook called %d times for two components, want 2", calls)
+	}
+}
+
+func TestRenderWithoutFirstRenderHookDoesNotMarkPath(t *testing.T) {
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, `{"head":"","html":"<p>ok</p>"}`+"\n")
+	}))
+
+	if _, err := r.Render("home.js", nil); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	called := false
+	ctx := core.ContextWithFirstRenderHook(context.Background(), func() { called = true })
+	if err := r.RenderChunked(ctx, "home.js", nil, func(string) error { return nil }, func(string) error { return nil }); err != nil {
+		t.Fatalf("RenderChunked: %v", err)
+	}
+	if !called {
+		t.Error("a render without a hook should not use up the first render")
+	}
+}
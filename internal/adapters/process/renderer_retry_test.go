@@ -0,0 +1,68 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// flakyRuntime drops the connection for the first failures requests, like a restarting runtime.
+func flakyRuntime(failures int32, status int) (http.Handler, *atomic.Int32) {
+	var calls atomic.Int32
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := calls.Add(1)
+		if n <= failures {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, `{"head":"","html":"<p>ok</p>"}`+"\n")
+	}), &calls
+}
+
+func TestRenderRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		retries   int
+		failures  int32
+		status    int
+		wantErr   bool
+		wantCalls int32
+	}{
+		{name: "no retries by default", retries: 0, failures: 1, status: http.StatusOK, wantErr: true, wantCalls: 1},
+		{name: "retries connection errors", retries: 2, failures: 2, status: http.StatusOK, wantCalls: 3},
+		{name: "gives up after n retries", retries: 1, failures: 3, status: http.StatusOK, wantErr: true, wantCalls: 2},
+		{name: "http errors are not retried", retries: 3, failures: 0, status: http.StatusInternalServerError, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, calls := flakyRuntime(tt.failures, tt.status)
+			r := newSocketTestRenderer(t, handler)
+			r.SetRenderRetries(tt.retries)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err := r.RenderBodyStream(ctx, "page.js", nil, io.Discard, nil, func(string) error { return nil })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderBodyStream() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, core.ErrRendererUnavailable) {
+				t.Errorf("RenderBodyStream() error = %v, want ErrRendererUnavailable", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("runtime calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
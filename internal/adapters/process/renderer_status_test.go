@@ -0,0 +1,24 @@
+package process
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestRendererStatus(t *testing.T) {
+	r := newSocketTestRenderer(t, http.NotFoundHandler())
+	if !r.Status().Up {
+		t.Fatal("expected renderer with listening socket to be up")
+	}
+
+	_ = r.Stop()
+	if r.Status().Up {
+		t.Error("expected stopped renderer to be down")
+	}
+
+	missing := &Renderer{socket: filepath.Join(t.TempDir(), "missing.sock")}
+	if missing.Status().Up {
+		t.Error("expected renderer without socket to be down")
+	}
+}
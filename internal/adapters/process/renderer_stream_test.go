@@ -3,9 +3,13 @@ package process
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseRenderFirstLine_HeadOnly(t *testing.T) {
@@ -96,3 +100,78 @@ func TestCopyResponseBodyWithFlush_FlushesEveryChunkWhenRequested(t *testing.T)
 		t.Fatalf("flushes = %d, want at least 2", flushes)
 	}
 }
+
+// chunkWriter reports each write on got, so a test can see chunks arrive one by one.
+type chunkWriter struct {
+	got chan string
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.got <- string(p)
+	return len(p), nil
+}
+
+// suspenseRuntime streams the shell, then waits for release before sending the resolved
+// boundary, like renderToReadableStream with a pending Suspense boundary. Non-streaming
+// requests get the same HTML in one piece.
+func suspenseRuntime(release <-chan struct{}) http.Handler {
+	const shell = `<main><!--$?--><template id="B:0"></template><p>loading</p><!--/$--></main>`
+	const resolved = `<div hidden id="S:0"><p>done</p></div>`
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			StreamBody bool `json:"streamBody"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		if !body.StreamBody {
+			data, _ := json.Marshal(map[string]string{"head": "H", "html": shell + resolved})
+			_, _ = w.Write(append(data, '\n'))
+			return
+		}
+		_, _ = io.WriteString(w, `{"head":"H"}`+"\n"+shell)
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = io.WriteString(w, resolved)
+	})
+}
+
+func TestRenderBodyStream_DeliversChunksIncrementally(t *testing.T) {
+	release := make(chan struct{})
+	r := newSocketTestRenderer(t, suspenseRuntime(release))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	w := &chunkWriter{got: make(chan string, 8)}
+	done := make(chan error, 1)
+	go func() {
+		done <- r.RenderBodyStream(ctx, "page.js", nil, w, nil, func(string) error { return nil })
+	}()
+
+	var streamed strings.Builder
+	select {
+	case chunk := <-w.got:
+		if !strings.Contains(chunk, `<!--$?-->`) {
+			t.Fatalf("first chunk = %q, want the Suspense fallback", chunk)
+		}
+		streamed.WriteString(chunk)
+	case <-ctx.Done():
+		t.Fatal("first chunk not delivered before the boundary resolved")
+	}
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("RenderBodyStream: %v", err)
+	}
+	close(w.got)
+	for chunk := range w.got {
+		streamed.WriteString(chunk)
+	}
+
+	page, err := r.Render("page.js", nil)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if streamed.String() != page.Body {
+		t.Fatalf("streamed body = %q, want the non-streaming body %q", streamed.String(), page.Body)
+	}
+}
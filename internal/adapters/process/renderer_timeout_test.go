@@ -0,0 +1,84 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newSocketTestRenderer(t *testing.T, handler http.Handler) *Renderer {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "r.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	srv := &http.Server{Handler: handler}
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(func() {
+		_ = srv.Close()
+		_ = os.Remove(socket)
+	})
+	return &Renderer{socket: socket, client: newHTTPClient(socket)}
+}
+
+func TestRenderBodyStream_CompletesWithinDeadline(t *testing.T) {
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, `{"head":"<title>ok</title>"}`+"\n<main>fast</main>")
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var head string
+	var body stringWriter
+	err := r.RenderBodyStream(ctx, "page.js", nil, &body, nil, func(h string) error {
+		head = h
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("RenderBodyStream() error = %v", err)
+	}
+	if head != "<title>ok</title>" || body.s != "<main>fast</main>" {
+		t.Fatalf("head = %q, body = %q", head, body.s)
+	}
+}
+
+func TestRenderBodyStream_DeadlineCancelsRuntimeRequest(t *testing.T) {
+	cancelled := make(chan struct{})
+	r := newSocketTestRenderer(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(io.Discard, req.Body)
+		select {
+		case <-req.Context().Done():
+			close(cancelled)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := r.RenderBodyStream(ctx, "page.js", nil, io.Discard, nil, func(string) error { return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("RenderBodyStream() error = %v, want deadline exceeded", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("runtime request was not cancelled after the deadline")
+	}
+}
+
+type stringWriter struct{ s string }
+
+func (w *stringWriter) Write(p []byte) (int, error) {
+	w.s += string(p)
+	return len(p), nil
+}
@@ -0,0 +1,69 @@
+package process
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// StageRuntimeData copies every file under each source's SrcPrefix into
+// dataDir/DestPrefix, keeping the relative layout below the prefix.
+func StageRuntimeData(dataDir string, sources []core.RuntimeData) error {
+	for _, src := range sources {
+		if src.FS == nil {
+			return fmt.Errorf("runtime data: nil filesystem for %q", src.SrcPrefix)
+		}
+		srcRoot := path.Clean(strings.Trim(filepath.ToSlash(src.SrcPrefix), "/"))
+		if srcRoot == "" {
+			srcRoot = "."
+		}
+		destRoot, err := safeDataDest(dataDir, src.DestPrefix)
+		if err != nil {
+			return err
+		}
+
+		err = fs.WalkDir(src.FS, srcRoot, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel := p
+			switch {
+			case p == srcRoot:
+				rel = path.Base(p)
+			case srcRoot != ".":
+				rel = strings.TrimPrefix(p, srcRoot+"/")
+			}
+			data, err := fs.ReadFile(src.FS, p)
+			if err != nil {
+				return err
+			}
+			dest := filepath.Join(destRoot, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+				return err
+			}
+			return os.WriteFile(dest, data, 0o644)
+		})
+		if err != nil {
+			return fmt.Errorf("runtime data %s: %w", src.SrcPrefix, err)
+		}
+	}
+	return nil
+}
+
+func safeDataDest(dataDir string, destPrefix string) (string, error) {
+	for _, part := range strings.Split(filepath.ToSlash(destPrefix), "/") {
+		if part == ".." {
+			return "", fmt.Errorf("runtime data: invalid destination %q", destPrefix)
+		}
+	}
+	clean := path.Clean("/" + filepath.ToSlash(destPrefix))
+	return filepath.Join(dataDir, filepath.FromSlash(strings.TrimPrefix(clean, "/"))), nil
+}
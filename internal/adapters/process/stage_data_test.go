@@ -0,0 +1,65 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestStageRuntimeData(t *testing.T) {
+	fsys := fstest.MapFS{
+		"content/posts/hello.md":  {Data: []byte("# Hello")},
+		"content/posts/world.md":  {Data: []byte("# World")},
+		"content/data/nav.json":   {Data: []byte(`{"items":[]}`)},
+		"other/ignored.txt":       {Data: []byte("nope")},
+		"content/data/deep/a.txt": {Data: []byte("a")},
+	}
+
+	dataDir := t.TempDir()
+	err := StageRuntimeData(dataDir, []core.RuntimeData{
+		{FS: fsys, SrcPrefix: "content/posts", DestPrefix: "posts"},
+		{FS: fsys, SrcPrefix: "/content/data/", DestPrefix: ""},
+	})
+	if err != nil {
+		t.Fatalf("StageRuntimeData() error = %v", err)
+	}
+
+	want := map[string]string{
+		"posts/hello.md": "# Hello",
+		"posts/world.md": "# World",
+		"nav.json":       `{"items":[]}`,
+		"deep/a.txt":     "a",
+	}
+	for rel, content := range want {
+		data, err := os.ReadFile(filepath.Join(dataDir, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Errorf("missing %s: %v", rel, err)
+			continue
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", rel, data, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dataDir, "ignored.txt")); err == nil {
+		t.Error("files outside srcPrefix should not be staged")
+	}
+}
+
+func TestStageRuntimeDataRejectsTraversal(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}}
+	err := StageRuntimeData(t.TempDir(), []core.RuntimeData{{FS: fsys, SrcPrefix: ".", DestPrefix: "../escape"}})
+	if err == nil {
+		t.Fatal("expected error for destination outside the data dir")
+	}
+}
+
+func TestStageRuntimeDataMissingPrefix(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}}
+	err := StageRuntimeData(t.TempDir(), []core.RuntimeData{{FS: fsys, SrcPrefix: "missing", DestPrefix: "x"}})
+	if err == nil {
+		t.Fatal("expected error for missing source prefix")
+	}
+}
@@ -1,9 +1,13 @@
 package process
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/3-lines-studio/bifrost/internal/core"
@@ -12,9 +16,10 @@ import (
 // ReadSSRBundle reads one SSR bundle by manifest-relative path (e.g. "/ssr/foo-ssr.js").
 type ReadSSRBundle func(manifestSSRPath string) ([]byte, error)
 
-// StageSSRBundles copies all non-empty SSR paths from the manifest into a temp directory,
-// preserving path segments (e.g. /ssr/x.js -> temp/ssr/x.js). Used for both embedded
-// assets and on-disk export layouts.
+// StageSSRBundles copies all non-empty SSR paths from the manifest, and the chunks listed
+// next to them, into a temp directory, preserving path segments (e.g. /ssr/x.js ->
+// temp/ssr/x.js). Used for both embedded assets and on-disk export layouts. Bundles built
+// with --compress-ssr are decompressed and checked against their SHA-256.
 func StageSSRBundles(read ReadSSRBundle, manifest *core.Manifest) (tempDir string, cleanup func(), err error) {
 	if manifest == nil {
 		return "", nil, fmt.Errorf("manifest is nil")
@@ -28,29 +33,95 @@ func StageSSRBundles(read ReadSSRBundle, manifest *core.Manifest) (tempDir strin
 		_ = os.RemoveAll(tempDir)
 	}
 
+	staged := make(map[string]struct{})
 	for entryName, entry := range manifest.Entries {
 		if entry.SSR == "" {
 			continue
 		}
-		data, rerr := read(entry.SSR)
+		data, rerr := readSSRFile(read, entry.SSR)
 		if rerr != nil {
 			cleanup()
 			return "", nil, fmt.Errorf("failed to read SSR bundle %s: %w", entry.SSR, rerr)
 		}
-		destPath := ResolveStagedSSRBundlePath(tempDir, entry.SSR)
-		if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
-			cleanup()
-			return "", nil, fmt.Errorf("failed to create SSR dest dir: %w", err)
-		}
-		if err := os.WriteFile(destPath, data, 0o644); err != nil {
+		if err := writeStagedSSRFile(tempDir, entry.SSR, data); err != nil {
 			cleanup()
 			return "", nil, fmt.Errorf("failed to write SSR bundle %s: %w", entryName, err)
 		}
+		for _, chunk := range entry.SSRChunks {
+			if _, ok := staged[chunk]; ok {
+				continue
+			}
+			staged[chunk] = struct{}{}
+			data, rerr := readSSRFile(read, chunk)
+			if rerr != nil {
+				cleanup()
+				return "", nil, fmt.Errorf("failed to read SSR chunk %s for page %s: %w", chunk, entryName, rerr)
+			}
+			if err := writeStagedSSRFile(tempDir, chunk, data); err != nil {
+				cleanup()
+				return "", nil, fmt.Errorf("failed to write SSR chunk %s: %w", chunk, err)
+			}
+		}
+	}
+
+	if err := VerifySSRChunks(tempDir, manifest); err != nil {
+		cleanup()
+		return "", nil, err
 	}
 
 	return tempDir, cleanup, nil
 }
 
+// readSSRFile reads an SSR bundle or chunk, falling back to the gzipped copy and checksum
+// bifrost-build --compress-ssr writes in its place.
+func readSSRFile(read ReadSSRBundle, manifestSSRPath string) ([]byte, error) {
+	data, err := read(manifestSSRPath)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return data, err
+	}
+	compressed, cerr := read(manifestSSRPath + core.CompressedSSRSuffix)
+	if cerr != nil {
+		if errors.Is(cerr, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, cerr
+	}
+	wantSum, serr := read(manifestSSRPath + core.SSRChecksumSuffix)
+	if serr != nil {
+		return nil, fmt.Errorf("compressed SSR bundle has no checksum file: %w", serr)
+	}
+	return decompressVerified(bytes.NewReader(compressed), wantSum)
+}
+
+func writeStagedSSRFile(tempDir, manifestSSRPath string, data []byte) error {
+	destPath := ResolveStagedSSRBundlePath(tempDir, manifestSSRPath)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create SSR dest dir: %w", err)
+	}
+	return os.WriteFile(destPath, data, 0o644)
+}
+
+// VerifySSRChunks checks that every SSR bundle in the manifest and each chunk it lists
+// exist under tempDir, so a missing chunk fails at startup instead of on first render.
+func VerifySSRChunks(tempDir string, manifest *core.Manifest) error {
+	var missing []string
+	for entryName, entry := range manifest.Entries {
+		if entry.SSR == "" {
+			continue
+		}
+		for _, file := range append([]string{entry.SSR}, entry.SSRChunks...) {
+			if _, err := os.Stat(ResolveStagedSSRBundlePath(tempDir, file)); err != nil {
+				missing = append(missing, fmt.Sprintf("page %s: %s", entryName, file))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("SSR files missing after extraction: %s", strings.Join(missing, "; "))
+	}
+	return nil
+}
+
 // ResolveStagedSSRBundlePath maps a manifest SSR path such as /ssr/page-ssr.js to the
 // absolute path used inside an extracted SSR temp directory.
 func ResolveStagedSSRBundlePath(tempDir string, manifestSSRPath string) string {
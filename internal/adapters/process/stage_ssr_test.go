@@ -1,6 +1,11 @@
 package process
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -52,6 +57,89 @@ func TestStageSSRBundles(t *testing.T) {
 	}
 }
 
+func TestStageSSRBundlesDecompressesCompressedBundles(t *testing.T) {
+	t.Parallel()
+	gzipped := func(data string) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write([]byte(data))
+		_ = zw.Close()
+		return buf.Bytes()
+	}
+	checksum := func(data string) []byte {
+		sum := sha256.Sum256([]byte(data))
+		return []byte(hex.EncodeToString(sum[:]) + "\n")
+	}
+	man := &core.Manifest{
+		Entries: map[string]core.ManifestEntry{
+			"pages-home-entry": {SSR: "/ssr/pages-home-entry-ssr.js", SSRChunks: []string{"/ssr/chunk-a.js"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		files   map[string][]byte
+		wantErr string
+	}{
+		{
+			name: "compressed bundle and chunk",
+			files: map[string][]byte{
+				"/ssr/pages-home-entry-ssr.js.gz":     gzipped("// home"),
+				"/ssr/pages-home-entry-ssr.js.sha256": checksum("// home"),
+				"/ssr/chunk-a.js.gz":                  gzipped("// chunk"),
+				"/ssr/chunk-a.js.sha256":              checksum("// chunk"),
+			},
+		},
+		{
+			name: "checksum mismatch",
+			files: map[string][]byte{
+				"/ssr/pages-home-entry-ssr.js.gz":     gzipped("// home"),
+				"/ssr/pages-home-entry-ssr.js.sha256": checksum("// tampered"),
+			},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name: "missing checksum",
+			files: map[string][]byte{
+				"/ssr/pages-home-entry-ssr.js.gz": gzipped("// home"),
+			},
+			wantErr: "no checksum file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read := func(manifestSSRPath string) ([]byte, error) {
+				if data, ok := tt.files[manifestSSRPath]; ok {
+					return data, nil
+				}
+				return nil, fs.ErrNotExist
+			}
+
+			tempDir, cleanup, err := StageSSRBundles(read, man)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cleanup()
+
+			for file, want := range map[string]string{"pages-home-entry-ssr.js": "// home", "chunk-a.js": "// chunk"} {
+				got, err := os.ReadFile(filepath.Join(tempDir, "ssr", file))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("%s = %q, want %q", file, got, want)
+				}
+			}
+		})
+	}
+}
+
 func TestExtractSSRBundlesFromEmbed(t *testing.T) {
 	t.Parallel()
 
@@ -88,3 +176,73 @@ func TestResolveStagedSSRBundlePath(t *testing.T) {
 		t.Fatalf("ResolveStagedSSRBundlePath() = %q, want %q", got, want)
 	}
 }
+
+func TestStageSSRBundlesCopiesChunks(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"/ssr/pages-home-entry-ssr.js": `import "./chunk-abc.js"`,
+		"/ssr/chunk-abc.js":            "// shared",
+	}
+	read := func(manifestSSRPath string) ([]byte, error) {
+		data, ok := files[manifestSSRPath]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(data), nil
+	}
+
+	man := &core.Manifest{
+		Entries: map[string]core.ManifestEntry{
+			"pages-home-entry": {SSR: "/ssr/pages-home-entry-ssr.js", SSRChunks: []string{"/ssr/chunk-abc.js"}},
+		},
+	}
+	tempDir, cleanup, err := StageSSRBundles(read, man)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if _, err := os.Stat(filepath.Join(tempDir, "ssr", "chunk-abc.js")); err != nil {
+		t.Fatalf("chunk not staged: %v", err)
+	}
+
+	man.Entries["pages-about-entry"] = core.ManifestEntry{SSR: "/ssr/pages-home-entry-ssr.js", SSRChunks: []string{"/ssr/chunk-gone.js"}}
+	_, _, err = StageSSRBundles(read, man)
+	if err == nil {
+		t.Fatal("expected error for missing chunk")
+	}
+	if !strings.Contains(err.Error(), "/ssr/chunk-gone.js") || !strings.Contains(err.Error(), "pages-about-entry") {
+		t.Fatalf("error should name page and chunk: %v", err)
+	}
+}
+
+func TestVerifySSRChunks(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tempDir, "ssr"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "ssr", "pages-home-entry-ssr.js"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	man := &core.Manifest{
+		Entries: map[string]core.ManifestEntry{
+			"pages-home-entry": {SSR: "/ssr/pages-home-entry-ssr.js", SSRChunks: []string{"/ssr/chunk-abc.js"}},
+			"pages-app-entry":  {Mode: "client"},
+		},
+	}
+
+	err := VerifySSRChunks(tempDir, man)
+	if err == nil {
+		t.Fatal("expected error for missing chunk")
+	}
+	if !strings.Contains(err.Error(), "page pages-home-entry: /ssr/chunk-abc.js") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, "ssr", "chunk-abc.js"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifySSRChunks(tempDir, man); err != nil {
+		t.Fatalf("VerifySSRChunks: %v", err)
+	}
+}
@@ -3,9 +3,11 @@ package runtime
 import (
 	"embed"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/adapters/framework"
 	"github.com/3-lines-studio/bifrost/internal/adapters/process"
@@ -13,24 +15,120 @@ import (
 )
 
 type Host struct {
-	client     *process.Renderer
-	assetsFS   embed.FS
-	isDev      bool
-	manifest   *core.Manifest
-	ssrTempDir string
-	ssrCleanup func()
-	adapter    core.FrameworkAdapter
+	client         *process.Renderer
+	assetsFS       embed.FS
+	isDev          bool
+	manifest       *core.Manifest
+	ssrTempDir     string
+	ssrCleanup     func()
+	adapter        core.FrameworkAdapter
+	runtimeData    []core.RuntimeData
+	runtimeDataDir string
+	buildPlugins   []core.BuildPluginSource
+	buildDefines   map[string]string
+	assetIntegrity bool
+	nodeModules    string
+	// minManifestVersion is the oldest embedded manifest version production mode accepts.
+	minManifestVersion int
+	logger             *slog.Logger
+	buildTime          time.Time
+	bunSmol            bool
+	buildTarget        string
+	// sourceCleanup runs on Stop for renderers started from source, which do not own a cleanup.
+	sourceCleanup func()
 }
 
-func NewHost(assetsFS embed.FS, mode core.Mode, adapter core.FrameworkAdapter) (*Host, error) {
+type HostOption func(*Host)
+
+// WithRuntimeData extracts the given files before the renderer starts and exposes their
+// location to Bun through core.RuntimeDataDirEnv.
+func WithRuntimeData(sources []core.RuntimeData) HostOption {
+	return func(h *Host) {
+		h.runtimeData = append(h.runtimeData, sources...)
+	}
+}
+
+// WithBuildPlugins adds the plugins to the dev renderer's Bun builds.
+func WithBuildPlugins(plugins []core.BuildPluginSource) HostOption {
+	return func(h *Host) {
+		h.buildPlugins = append(h.buildPlugins, plugins...)
+	}
+}
+
+// WithBuildDefines adds the defines to the dev renderer's Bun builds.
+func WithBuildDefines(defines map[string]string) HostOption {
+	return func(h *Host) {
+		h.buildDefines = defines
+	}
+}
+
+// WithAssetIntegrity verifies embedded assets against the manifest hashes before the
+// production renderer starts; NewHost fails on a mismatch.
+func WithAssetIntegrity() HostOption {
+	return func(h *Host) {
+		h.assetIntegrity = true
+	}
+}
+
+// WithNodeModulesPath lets the renderer resolve bare imports from the given node_modules
+// directory as well (core.NodeModulesPathEnv).
+func WithNodeModulesPath(path string) HostOption {
+	return func(h *Host) {
+		h.nodeModules = path
+	}
+}
+
+// WithMinManifestVersion changes the oldest embedded manifest.json version production mode
+// accepts from core.ManifestVersion to version.
+func WithMinManifestVersion(version int) HostOption {
+	return func(h *Host) {
+		h.minManifestVersion = version
+	}
+}
+
+// WithLogger sends the Bun process's output through logger instead of the Go process's
+// stdout and stderr.
+func WithLogger(logger *slog.Logger) HostOption {
+	return func(h *Host) {
+		h.logger = logger
+	}
+}
+
+// WithBuildTime passes t to the renderer through core.BuildTimeEnv.
+func WithBuildTime(t time.Time) HostOption {
+	return func(h *Host) {
+		h.buildTime = t
+	}
+}
+
+// WithBuildTarget makes the dev renderer bundle client code for target.
+func WithBuildTarget(target string) HostOption {
+	return func(h *Host) {
+		h.buildTarget = target
+	}
+}
+
+// WithBunSmol runs the renderer with Bun's --smol flag, passed through BUN_OPTIONS to a
+// compiled runtime.
+func WithBunSmol(enabled bool) HostOption {
+	return func(h *Host) {
+		h.bunSmol = enabled
+	}
+}
+
+func NewHost(assetsFS embed.FS, mode core.Mode, adapter core.FrameworkAdapter, opts ...HostOption) (*Host, error) {
 	if adapter == nil {
 		adapter = framework.DefaultAdapter()
 	}
 
 	r := &Host{
-		isDev:    mode == core.ModeDev,
-		assetsFS: assetsFS,
-		adapter:  adapter,
+		isDev:              mode == core.ModeDev,
+		assetsFS:           assetsFS,
+		adapter:            adapter,
+		minManifestVersion: core.ManifestVersion,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
 
 	switch mode {
@@ -46,7 +144,7 @@ func NewHost(assetsFS embed.FS, mode core.Mode, adapter core.FrameworkAdapter) (
 func (r *Host) initExportMode() (*Host, error) {
 	exportDir := os.Getenv("BIFROST_EXPORT_DIR")
 	if exportDir == "" {
-		exportDir = ".bifrost"
+		exportDir = core.OutputDir
 	}
 
 	man, err := loadManifestFromDisk(exportDir)
@@ -89,12 +187,18 @@ func (r *Host) initProdMode() (*Host, error) {
 		return nil, fmt.Errorf("embed.FS is required in production mode")
 	}
 
-	man, err := loadManifestFromEmbed(r.assetsFS)
+	man, err := loadManifestFromEmbed(r.assetsFS, r.minManifestVersion)
 	if err != nil {
 		return nil, err
 	}
 	r.manifest = man
 
+	if r.assetIntegrity {
+		if err := core.VerifyAssetIntegrity(man, r.assetsFS); err != nil {
+			return nil, err
+		}
+	}
+
 	if core.HasSSREntries(man) {
 		if err := r.setupEmbeddedRuntime(); err != nil {
 			return nil, err
@@ -104,12 +208,12 @@ func (r *Host) initProdMode() (*Host, error) {
 	return r, nil
 }
 
-func loadManifestFromEmbed(assetsFS embed.FS) (*core.Manifest, error) {
-	data, err := assetsFS.ReadFile(".bifrost/manifest.json")
+func loadManifestFromEmbed(assetsFS embed.FS, minVersion int) (*core.Manifest, error) {
+	data, err := assetsFS.ReadFile(core.ManifestPath)
 	if err != nil {
 		return nil, fmt.Errorf("manifest.json not found in embedded assets: %w", err)
 	}
-	return core.ParseManifest(data)
+	return core.ParseProdManifest(data, minVersion)
 }
 
 func (r *Host) setupEmbeddedRuntime() error {
@@ -134,9 +238,13 @@ func (r *Host) setupEmbeddedRuntime() error {
 }
 
 func (r *Host) initDevMode() (*Host, error) {
-	if err := r.startRendererFromSource(core.ModeDev, r.adapter.DevRendererSource(), nil); err != nil {
+	source := process.InjectBuildPlugins(r.adapter.DevRendererSource(), r.buildPlugins)
+	if err := r.startRendererFromSource(core.ModeDev, source, nil); err != nil {
 		return nil, err
 	}
+	r.client.SetBuildPlugins(core.BuildPluginIDs(r.buildPlugins))
+	r.client.SetBuildDefines(r.buildDefines)
+	r.client.SetBuildTarget(r.buildTarget)
 	return r, nil
 }
 
@@ -146,6 +254,9 @@ func (h *Host) Manifest() *core.Manifest { return h.manifest }
 
 func (h *Host) SSRTempDir() string { return h.ssrTempDir }
 
+// RuntimeDataDir is where WithRuntimeData files were extracted, or "" when none were set.
+func (h *Host) RuntimeDataDir() string { return h.runtimeDataDir }
+
 func (h *Host) ResolveSSRBundlePath(manifestSSRPath string) string {
 	if manifestSSRPath == "" {
 		return ""
@@ -158,11 +269,30 @@ func (h *Host) ResolveSSRBundlePath(manifestSSRPath string) string {
 
 func (h *Host) IsDev() bool { return h.isDev }
 
+// RendererStatus reports the Bun renderer; a host without a renderer is down.
+func (h *Host) RendererStatus() core.RendererStatus {
+	if h == nil || h.client == nil {
+		return core.RendererStatus{}
+	}
+	return h.client.Status()
+}
+
+func (h *Host) SetRenderRetries(n int) {
+	if h != nil && h.client != nil {
+		h.client.SetRenderRetries(n)
+	}
+}
+
 func (h *Host) Stop() error {
+	var err error
 	if h.client != nil {
-		return h.client.Stop()
+		err = h.client.Stop()
 	}
-	return nil
+	if h.sourceCleanup != nil {
+		h.sourceCleanup()
+		h.sourceCleanup = nil
+	}
+	return err
 }
 
 func copySSRBundlesFromDisk(exportDir string, manifest *core.Manifest) (string, func(), error) {
@@ -174,8 +304,73 @@ func copySSRBundlesFromDisk(exportDir string, manifest *core.Manifest) (string,
 	return process.StageSSRBundles(read, manifest)
 }
 
+// prepareRuntimeData stages runtime data next to the SSR bundles (or in its own temp dir
+// when there are none) and returns the env entry for the renderer.
+func (r *Host) prepareRuntimeData() (env []string, cleanup func(), err error) {
+	if len(r.runtimeData) == 0 {
+		return nil, nil, nil
+	}
+
+	baseDir := r.ssrTempDir
+	ownsBase := false
+	if baseDir == "" {
+		baseDir, err = os.MkdirTemp("", "bifrost-data-*")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create runtime data dir: %w", err)
+		}
+		ownsBase = true
+	}
+	dataDir := filepath.Join(baseDir, "data")
+	cleanup = func() {
+		if ownsBase {
+			_ = os.RemoveAll(baseDir)
+			return
+		}
+		_ = os.RemoveAll(dataDir)
+	}
+
+	if err := process.StageRuntimeData(dataDir, r.runtimeData); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	r.runtimeDataDir = dataDir
+	return []string{core.RuntimeDataDirEnv + "=" + dataDir}, cleanup, nil
+}
+
+// rendererEnv is the env for the Bun renderer: staged runtime data, the extra
+// node_modules directory and the build time.
+func (r *Host) rendererEnv() (env []string, cleanup func(), err error) {
+	entry, err := core.NodeModulesPathEnvEntry(r.nodeModules)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid node_modules path: %w", err)
+	}
+	env, cleanup, err = r.prepareRuntimeData()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, entry := range []string{entry, core.BuildTimeEnvEntry(r.buildTime)} {
+		if entry != "" {
+			env = append(env, entry)
+		}
+	}
+	return env, cleanup, nil
+}
+
 func (r *Host) startRendererFromSource(mode core.Mode, source string, cleanup func()) error {
-	client, err := process.NewRenderer(mode, source)
+	env, dataCleanup, err := r.rendererEnv()
+	if err != nil {
+		if cleanup != nil {
+			cleanup()
+		}
+		return err
+	}
+	cleanup = combineCleanup(dataCleanup, cleanup)
+
+	client, err := process.NewRendererWithOptions(mode, source, process.RendererOptions{
+		Logger: r.logger,
+		Env:    env,
+		Smol:   r.bunSmol,
+	})
 	if err != nil {
 		if cleanup != nil {
 			cleanup()
@@ -184,11 +379,25 @@ func (r *Host) startRendererFromSource(mode core.Mode, source string, cleanup fu
 	}
 	r.client = client
 	r.ssrCleanup = cleanup
+	r.sourceCleanup = cleanup
 	return nil
 }
 
 func (r *Host) startRendererFromExecutable(executablePath string, cleanup func()) error {
-	client, err := process.NewRendererFromExecutable(executablePath, cleanup)
+	env, dataCleanup, err := r.rendererEnv()
+	if err != nil {
+		if cleanup != nil {
+			cleanup()
+		}
+		return err
+	}
+	cleanup = combineCleanup(dataCleanup, cleanup)
+
+	client, err := process.NewRendererFromExecutableWithOptions(executablePath, cleanup, process.RendererOptions{
+		Logger: r.logger,
+		Env:    env,
+		Smol:   r.bunSmol,
+	})
 	if err != nil {
 		if cleanup != nil {
 			cleanup()
@@ -2,9 +2,14 @@ package app
 
 import (
 	"embed"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"net/http"
 	"os"
+	"strconv"
+	"sync/atomic"
 
 	"github.com/3-lines-studio/bifrost/internal/adapters/env"
 	"github.com/3-lines-studio/bifrost/internal/adapters/framework"
@@ -30,6 +35,11 @@ type App struct {
 	config       *core.Config
 	adapter      core.FrameworkAdapter
 	routesSealed bool
+	inFlight     atomic.Int64
+	diagnostics  *adaptershttp.Diagnostics
+	revalidate   *usecase.RevalidateCache
+	loaderCache  *usecase.LoaderCache
+	metrics      *adaptershttp.PageMetrics
 }
 
 func New(assetsFS embed.FS, routes ...core.Route) *App {
@@ -65,32 +75,151 @@ func newApp(assetsFS embed.FS, routes []core.Route, config *core.Config) *App {
 		config:      config,
 		adapter:     framework.ResolveAdapter(config.Framework),
 	}
+	if err := core.ValidateTrustProxy(config.TrustProxyHops, config.TrustedProxies); err != nil {
+		panic("bifrost: " + err.Error())
+	}
+	if config.ReactRuntime != "" {
+		adapter, err := core.WithReactRuntimeSource(app.adapter, config.ReactRuntime)
+		if err != nil {
+			panic("bifrost: " + err.Error())
+		}
+		app.adapter = adapter
+	}
+	if config.SSREntryTemplate != "" {
+		adapter, err := core.WithSSREntryTemplate(app.adapter, config.SSREntryTemplate)
+		if err != nil {
+			panic("bifrost: " + err.Error())
+		}
+		app.adapter = adapter
+	}
 	app.addRoutes(routes)
+	if config.PageMetrics {
+		app.metrics = adaptershttp.NewPageMetrics()
+	}
 
 	if env.IsExportMarkerPresent() {
 		return app
 	}
 
+	if out := os.Getenv(core.BuildPluginsOutEnv); out != "" {
+		if err := usecase.WriteBuildPlugins(out, config.BuildPlugins); err != nil {
+			fmt.Fprintf(os.Stderr, "bifrost: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	if mode == core.ModeExport {
 		return app
 	}
 
-	h, err := runtime.NewHost(assetsFS, mode, app.adapter)
+	h, err := runtime.NewHost(assetsFS, mode, app.adapter, app.hostOptions()...)
 	if err != nil {
 		panic(fmt.Sprintf("failed to create bifrost renderer: %v", err))
 	}
+	h.SetRenderRetries(config.RenderRetries)
 	app.host = h
 	app.manifest = h.Manifest()
+	if mode == core.ModeProd {
+		if err := core.CheckManifestEnvironment(app.manifest, os.Getenv(core.EnvironmentEnvVar)); err != nil {
+			slog.Warn("bifrost: " + err.Error())
+		}
+	}
 
 	return app
 }
 
+// addRoutes registers routes; a route whose pattern is already registered replaces the
+// earlier one, so pages from PagesFromDir can be overridden with an explicit Page.
 func (a *App) addRoutes(routes []core.Route) {
 	for _, route := range routes {
+		replaced := false
+		for i, existing := range a.routes {
+			if existing.Pattern == route.Pattern {
+				a.routes[i] = route
+				a.dropUnusedPageConfig(existing.ComponentPath)
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			a.routes = append(a.routes, route)
+		}
+
 		pc := core.PageConfigFromRoute(route)
 		a.pageConfigs[route.ComponentPath] = &pc
 	}
-	a.routes = append(a.routes, routes...)
+}
+
+// dropUnusedPageConfig deletes the page config for componentPath once no route renders
+// it, so a replaced route's component is not built or exported.
+func (a *App) dropUnusedPageConfig(componentPath string) {
+	for _, route := range a.routes {
+		if route.ComponentPath == componentPath {
+			return
+		}
+	}
+	delete(a.pageConfigs, componentPath)
+}
+
+// Routes returns a summary of every registered page route in registration order. The
+// result is a copy; changing it does not affect the app.
+func (a *App) Routes() []core.RouteInfo {
+	infos := make([]core.RouteInfo, 0, len(a.routes))
+	for _, route := range a.routes {
+		infos = append(infos, core.RouteInfoFor(route))
+	}
+	return infos
+}
+
+func (a *App) hostOptions() []runtime.HostOption {
+	if a.config == nil {
+		return nil
+	}
+	var opts []runtime.HostOption
+	if len(a.config.RuntimeData) > 0 {
+		opts = append(opts, runtime.WithRuntimeData(a.config.RuntimeData))
+	}
+	if len(a.config.BuildPlugins) > 0 {
+		plugins, err := core.BuildPluginSources(a.config.BuildPlugins)
+		if err != nil {
+			panic("bifrost: " + err.Error())
+		}
+		opts = append(opts, runtime.WithBuildPlugins(plugins))
+	}
+	defines, err := core.BuildDefines(a.config.BuildEnv, a.config.BuildConstants)
+	if err != nil {
+		panic("bifrost: " + err.Error())
+	}
+	if len(defines) > 0 {
+		opts = append(opts, runtime.WithBuildDefines(defines))
+	}
+	if a.config.AssetIntegrity {
+		opts = append(opts, runtime.WithAssetIntegrity())
+	}
+	if a.config.NodeModulesPath != "" {
+		opts = append(opts, runtime.WithNodeModulesPath(a.config.NodeModulesPath))
+	}
+	if a.config.Logger != nil {
+		logger := a.config.Logger
+		if a.config.Environment != "" {
+			logger = logger.With("env", a.config.Environment)
+		}
+		opts = append(opts, runtime.WithLogger(logger))
+	}
+	if a.config.MinManifestVersion != nil {
+		opts = append(opts, runtime.WithMinManifestVersion(*a.config.MinManifestVersion))
+	}
+	if a.config.BunSmol {
+		opts = append(opts, runtime.WithBunSmol(true))
+	}
+	if a.config.BuildTarget != "" {
+		if err := core.ValidateBuildTarget(a.config.BuildTarget); err != nil {
+			panic("bifrost: " + err.Error())
+		}
+		opts = append(opts, runtime.WithBuildTarget(a.config.BuildTarget))
+	}
+	return opts
 }
 
 func (a *App) Handle(routes ...core.Route) {
@@ -101,17 +230,24 @@ func (a *App) Handle(routes ...core.Route) {
 }
 
 func (a *App) runExportMode() {
-	h, err := runtime.NewHost(a.assetsFS, core.ModeExport, a.adapter)
+	opts := a.hostOptions()
+	if a.config != nil && !a.config.BuildTime.IsZero() {
+		opts = append(opts, runtime.WithBuildTime(a.config.BuildTime))
+	}
+	h, err := runtime.NewHost(a.assetsFS, core.ModeExport, a.adapter, opts...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
 		os.Exit(1)
 	}
 	a.host = h
 	a.manifest = h.Manifest()
+	if jobs, err := strconv.Atoi(os.Getenv(core.ExportJobsEnv)); err == nil && jobs > 0 && a.config != nil {
+		a.config.StaticExportConcurrency = jobs
+	}
 
 	outputDir := os.Getenv("BIFROST_EXPORT_DIR")
 	if outputDir == "" {
-		outputDir = ".bifrost"
+		outputDir = core.OutputDir
 	}
 
 	if err := a.ExportStaticPages(outputDir); err != nil {
@@ -142,23 +278,67 @@ func (a *App) Wrap(api Router) http.Handler {
 
 	a.routesSealed = true
 
-	defaultLang := ""
+	var appConfig core.Config
 	if a.config != nil {
-		defaultLang = a.config.DefaultHTMLLang
+		appConfig = *a.config
+	}
+	appConfig.LinkRewriter = a.linkRewriter()
+
+	if appConfig.Preview != nil && !a.isDev && !core.HasPreviewEntries(a.manifest) {
+		slog.Warn("bifrost: WithPreview is set but the build has no preview bundles; rebuild with bifrost-build")
+		appConfig.Preview = nil
+	}
+
+	if a.isDev && appConfig.DiagnosticsPath != "" {
+		a.diagnostics = adaptershttp.NewDiagnostics()
 	}
 
 	fsAdapter := adaptersfs.NewEmbedFileSystem(a.assetsFS)
 	pageService := usecase.NewPageService(a.host.Client(), fsAdapter, a.adapter)
+	if !a.isDev && a.hasRevalidatingRoutes() {
+		cache, err := usecase.NewRevalidateCache()
+		if err != nil {
+			panic(fmt.Sprintf("bifrost: failed to create revalidate cache: %v", err))
+		}
+		a.revalidate = cache
+		pageService.SetRevalidateCache(cache)
+	}
+	if a.loaderCache == nil {
+		a.loaderCache = usecase.NewLoaderCache()
+	}
+	pageService.SetLoaderCache(a.loaderCache)
 
+	statusPages := adaptershttp.NewStatusPages(pageService, a.manifest, a.assetsFS, a.isDev, appConfig, a.diagnostics)
 	for _, route := range a.routes {
 		config := core.PageConfigFromRoute(route)
 		staticPath := a.getStaticPath(config)
 
-		handler := adaptershttp.NewPageHandler(pageService, config, a.manifest, a.assetsFS, a.isDev, staticPath, defaultLang)
-		api.Handle(route.Pattern, handler)
+		pageHandler := adaptershttp.NewPageHandler(pageService, config, a.manifest, a.assetsFS, a.isDev, staticPath, appConfig, a.diagnostics)
+		pageHandler.SetStatusPages(statusPages)
+		var handler http.Handler = pageHandler
+		if appConfig.CSRF != nil {
+			handler = adaptershttp.NewCSRFHandler(handler, *appConfig.CSRF)
+		}
+		if appConfig.OnFirstRender != nil {
+			handler = adaptershttp.NewFirstRenderHandler(handler, route.Pattern, appConfig.OnFirstRender)
+		}
+		api.Handle(route.Pattern, a.metrics.Handler(route.Pattern, handler))
+		if appConfig.Prefetch && config.Mode == core.ModeSSR {
+			api.Handle(core.PageDataPattern(route.Pattern), adaptershttp.NewPageDataHandler(handler))
+		}
 	}
 
-	return createAssetHandler(api, a)
+	return a.trackInFlight(a.wrapMiddleware(createAssetHandler(api, a)))
+}
+
+func (a *App) hasRevalidatingRoutes() bool {
+	for _, route := range a.routes {
+		config := core.PageConfigFromRoute(route)
+		if config.Mode == core.ModeStaticPrerender && config.Revalidate > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 func (a *App) Handler() http.Handler {
@@ -200,11 +380,44 @@ func (a *App) getSSBundlePath(entryName string) string {
 	return entry.SSR
 }
 
+// Metrics returns a copy of the WithPageMetrics statistics keyed by route pattern. It is
+// empty when the option is not set.
+func (a *App) Metrics() map[string]core.RouteMetrics {
+	return a.metrics.Snapshot()
+}
+
+// InvalidateLoaderCache drops the WithLoaderCache results stored under key, for every
+// page, so the next request runs the loader again.
+func (a *App) InvalidateLoaderCache(key string) {
+	a.loaderCache.Invalidate(key)
+}
+
+// BuildConstants returns a copy of the WithBuildConstants values, the ones bifrost-build
+// baked into the client and SSR bundles.
+func (a *App) BuildConstants() map[string]any {
+	if a.config == nil {
+		return nil
+	}
+	return maps.Clone(a.config.BuildConstants)
+}
+
+// InvalidatePageCache deletes the WithPageCache entry stored under key (see
+// core.PageCacheKey), so the next request for that page renders it again.
+func (a *App) InvalidatePageCache(key string) error {
+	if a.config == nil || a.config.PageCache == nil {
+		return core.ErrNoPageCache
+	}
+	a.config.PageCache.Delete(key)
+	return nil
+}
+
 func (a *App) Stop() error {
+	a.metrics.Reset()
+	cacheErr := a.revalidate.Close()
 	if a.host != nil {
-		return a.host.Stop()
+		return errors.Join(a.host.Stop(), cacheErr)
 	}
-	return nil
+	return cacheErr
 }
 
 func (a *App) ExportStaticPages(outputDir string) error {
@@ -212,17 +425,91 @@ func (a *App) ExportStaticPages(outputDir string) error {
 	if a.host != nil {
 		r = a.host.Client()
 	}
+	appConfig := a.config
+	if appConfig != nil {
+		exportConfig := *appConfig
+		exportConfig.LinkRewriter = a.linkRewriter()
+		appConfig = &exportConfig
+	}
 	return usecase.ExportStaticPages(usecase.ExportStaticPagesInput{
 		OutputDir:    outputDir,
 		Routes:       a.routes,
 		PageConfigs:  a.pageConfigs,
 		Manifest:     a.manifest,
-		AppConfig:    a.config,
+		AppConfig:    appConfig,
 		SSBundlePath: a.getSSBundlePath,
 		Renderer:     r,
 	})
 }
 
+// linkRewriter is the WithLinkRewriting rewriter followed by the WithCacheBust version.
+func (a *App) linkRewriter() core.LinkRewriter {
+	if a.config == nil {
+		return nil
+	}
+	return core.CacheBustLinkRewriter(a.config.LinkRewriter, a.config.CacheBust.Version(a.manifest))
+}
+
+func (a *App) diagnosticsMetrics() func() map[string]core.RouteMetrics {
+	if a.metrics == nil {
+		return nil
+	}
+	return a.metrics.Snapshot
+}
+
+func (a *App) wrapMiddleware(handler http.Handler) http.Handler {
+	if a.isDev {
+		handler = adaptershttp.NewHydrationErrorHandler(handler)
+	}
+	if a.config == nil {
+		return handler
+	}
+	if len(a.config.RequestModifiers) > 0 {
+		handler = adaptershttp.NewRequestModifierHandler(handler, a.config.RequestModifiers)
+	}
+	if a.config.DiagnosticsPath != "" {
+		handler = adaptershttp.NewDiagnosticsHandler(handler, a.config.DiagnosticsPath, a.isDev, a.diagnostics, adaptershttp.DiagnosticsSource{
+			Routes:         a.routes,
+			Manifest:       a.manifest,
+			RendererStatus: a.host.RendererStatus,
+			Metrics:        a.diagnosticsMetrics(),
+		})
+	}
+	if a.config.CSP.Policy != "" || a.config.CSP.ReportURI != "" || a.config.CSP.Nonce {
+		handler = adaptershttp.NewCSPHandler(handler, a.config.CSP)
+	}
+	if a.config.SecureHeaders != nil {
+		handler = adaptershttp.NewSecureHeadersHandler(handler, *a.config.SecureHeaders)
+	}
+	if !a.isDev && a.config.CanonicalHost.Enabled() {
+		handler = adaptershttp.NewCanonicalHostHandler(handler, a.config.CanonicalHost)
+	}
+	if a.config.FeatureFlags != nil {
+		handler = adaptershttp.NewAnonymousIDHandler(handler)
+	}
+	if a.config.RequestLogger != nil {
+		logger := a.config.RequestLogger
+		if a.config.Environment != "" {
+			logger = logger.With("env", a.config.Environment)
+		}
+		handler = adaptershttp.NewRequestLoggerHandler(handler, logger)
+	}
+	if a.config.UserProvider != nil {
+		handler = adaptershttp.NewUserHandler(handler, a.config.UserProvider)
+	}
+	if a.config.TrustProxyHops > 0 {
+		handler = adaptershttp.NewTrustProxyHandler(handler, a.config.TrustProxyHops)
+	}
+	if len(a.config.TrustedProxies) > 0 {
+		proxies, err := core.ParseTrustedProxies(a.config.TrustedProxies)
+		if err != nil {
+			panic("bifrost: " + err.Error())
+		}
+		handler = adaptershttp.NewTrustedProxiesHandler(handler, proxies)
+	}
+	return handler
+}
+
 func createAssetHandler(router Router, app *App) http.Handler {
 	isDev := app.isDev
 	assetHandler := adaptershttp.NewAssetHandler(app.assetsFS, isDev)
@@ -238,5 +525,20 @@ func createAssetHandler(router Router, app *App) http.Handler {
 		router.ServeHTTP(w, req)
 	})
 
-	return adaptershttp.NewPublicHandler(app.assetsFS, distHandler, isDev)
+	var next http.Handler = distHandler
+	if app.config != nil && len(app.config.EmbedMounts) > 0 {
+		next = adaptershttp.NewEmbedMountHandler(next, app.config.EmbedMounts)
+	}
+	if app.config != nil && app.config.Favicon != nil {
+		if adaptershttp.PublicFileExists(app.assetsFS, core.FaviconPath, isDev) {
+			slog.Warn("bifrost: public/favicon.ico exists and takes precedence over WithFavicon")
+		}
+		next = adaptershttp.NewFaviconHandler(next, *app.config.Favicon)
+	}
+
+	var cacheControl string
+	if app.config != nil {
+		cacheControl = core.PublicCacheControl(*app.config)
+	}
+	return adaptershttp.NewPublicHandler(app.assetsFS, next, isDev, cacheControl)
 }
@@ -3,14 +3,19 @@ package app
 import (
 	"context"
 	"embed"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os/exec"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 	"unsafe"
 
+	adaptershttp "github.com/3-lines-studio/bifrost/internal/adapters/http"
 	"github.com/3-lines-studio/bifrost/internal/adapters/runtime"
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
@@ -390,3 +395,143 @@ func TestDevModeSetupBeforeStaticDataLoader(t *testing.T) {
 		t.Error("StaticDataLoader not set")
 	}
 }
+
+func TestAddRoutesReplacesSamePattern(t *testing.T) {
+	a := &App{pageConfigs: make(map[string]*core.PageConfig)}
+	a.addRoutes([]core.Route{
+		core.Page("/", "./pages/index.tsx"),
+		core.Page("/about", "./pages/about.tsx"),
+	})
+	a.addRoutes([]core.Route{core.Page("/about", "./pages/about.tsx", core.WithClient())})
+
+	if len(a.routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(a.routes))
+	}
+	if mode := core.PageConfigFromRoute(a.routes[1]).Mode; mode != core.ModeClientOnly {
+		t.Errorf("expected override to win, got mode %v", mode)
+	}
+	if a.pageConfigs["./pages/about.tsx"].Mode != core.ModeClientOnly {
+		t.Errorf("expected page config to follow the override")
+	}
+}
+
+func TestAddRoutesDropsReplacedPageConfig(t *testing.T) {
+	a := &App{pageConfigs: make(map[string]*core.PageConfig)}
+	a.addRoutes([]core.Route{
+		core.Page("/", "./pages/index.tsx"),
+		core.Page("/about", "./pages/about.tsx"),
+		core.Page("/team", "./pages/about.tsx"),
+	})
+	a.addRoutes([]core.Route{
+		core.Page("/", "./pages/home.tsx"),
+		core.Page("/about", "./pages/about-v2.tsx"),
+	})
+
+	if _, ok := a.pageConfigs["./pages/index.tsx"]; ok {
+		t.Error("page config for the replaced ./pages/index.tsx was kept")
+	}
+	if _, ok := a.pageConfigs["./pages/about.tsx"]; !ok {
+		t.Error("page config for ./pages/about.tsx was dropped while /team still uses it")
+	}
+	for _, path := range []string{"./pages/home.tsx", "./pages/about-v2.tsx"} {
+		if _, ok := a.pageConfigs[path]; !ok {
+			t.Errorf("page config for %s missing", path)
+		}
+	}
+}
+
+func TestRoutesDescribesRegisteredPages(t *testing.T) {
+	loader := func(*http.Request) (map[string]any, error) { return nil, nil }
+	staticData := func(context.Context) ([]core.StaticPathData, error) { return nil, nil }
+
+	a := &App{pageConfigs: make(map[string]*core.PageConfig)}
+	a.addRoutes([]core.Route{
+		core.Page("/", "./pages/home.tsx", core.WithLoader(loader)),
+		core.Page("/admin", "./pages/admin.tsx", core.WithClient(), core.WithPageGroup("admin")),
+		core.Page("/blog/{slug}", "./pages/post.tsx", core.WithStaticData(staticData), core.WithPageGroup("marketing")),
+	})
+
+	want := []core.RouteInfo{
+		{Pattern: "/", ComponentPath: "./pages/home.tsx", Mode: "ssr", HasLoader: true},
+		{Pattern: "/admin", ComponentPath: "./pages/admin.tsx", Mode: "client", Group: "admin"},
+		{Pattern: "/blog/{slug}", ComponentPath: "./pages/post.tsx", Mode: "static", HasStaticData: true, Group: "marketing"},
+	}
+	got := a.Routes()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Routes() = %+v, want %+v", got, want)
+	}
+
+	got[0].Pattern = "/changed"
+	if a.Routes()[0].Pattern != "/" {
+		t.Error("Routes() should return a copy")
+	}
+}
+
+func TestMetricsResetOnStop(t *testing.T) {
+	a := &App{pageConfigs: make(map[string]*core.PageConfig)}
+	if got := a.Metrics(); len(got) != 0 {
+		t.Fatalf("Metrics() without WithPageMetrics = %v, want empty", got)
+	}
+
+	a.metrics = adaptershttp.NewPageMetrics()
+	a.metrics.Record("/blog/{slug}", 5*time.Millisecond)
+	if got := a.Metrics()["/blog/{slug}"].Count; got != 1 {
+		t.Fatalf("Count = %d, want 1", got)
+	}
+
+	if err := a.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if got := a.Metrics(); len(got) != 0 {
+		t.Errorf("Metrics() after Stop = %v, want empty", got)
+	}
+}
+
+func TestInvalidatePageCache(t *testing.T) {
+	a := &App{config: &core.Config{}}
+	if err := a.InvalidatePageCache("key"); err != core.ErrNoPageCache {
+		t.Fatalf("InvalidatePageCache() without WithPageCache = %v, want ErrNoPageCache", err)
+	}
+
+	store := core.NewMemoryPageCache(10, time.Minute)
+	core.WithPageCache(store)(a.config)
+	store.Set("key", "<p>cached</p>", 0)
+	store.Set("other", "<p>other</p>", 0)
+
+	if err := a.InvalidatePageCache("key"); err != nil {
+		t.Fatalf("InvalidatePageCache() error = %v", err)
+	}
+	if _, ok := store.Get("key"); ok {
+		t.Error("entry still cached after InvalidatePageCache")
+	}
+	if _, ok := store.Get("other"); !ok {
+		t.Error("InvalidatePageCache removed another entry")
+	}
+}
+
+func TestWithEnvironmentKeepsDefaultLogger(t *testing.T) {
+	skipIfNoBun(t)
+	t.Setenv("BIFROST_DEV", "1")
+
+	before := slog.Default()
+	a := NewWithOptions(testFS, []core.ConfigOption{core.WithEnvironment("staging")}, core.Page("/", "./test.tsx"))
+	defer func() { _ = a.Stop() }()
+	a.Handler()
+	a.Handler()
+
+	if slog.Default() != before {
+		t.Error("Handler() replaced the default slog logger")
+	}
+}
+
+func TestTrustProxyWithTrustedProxiesPanics(t *testing.T) {
+	t.Setenv("BIFROST_DEV", "1")
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(fmt.Sprint(r), "WithTrustedProxies") {
+			t.Errorf("recover() = %v, want a panic naming the conflicting options", r)
+		}
+	}()
+	NewWithOptions(testFS, []core.ConfigOption{core.WithTrustProxy(1), core.WithTrustedProxies("10.0.0.0/8")})
+}
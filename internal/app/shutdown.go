@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+const shutdownPollInterval = 10 * time.Millisecond
+
+func (a *App) trackInFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		a.inFlight.Add(1)
+		defer a.inFlight.Add(-1)
+		next.ServeHTTP(w, req)
+	})
+}
+
+// Shutdown waits for in-flight Bifrost requests to return, or for ctx to end, and then
+// stops the Bun runtime. It returns ctx.Err() when requests were still running.
+func (a *App) Shutdown(ctx context.Context) error {
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+
+	var waitErr error
+	for a.inFlight.Load() > 0 && waitErr == nil {
+		select {
+		case <-ctx.Done():
+			waitErr = ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return errors.Join(waitErr, a.Stop())
+}
+
+func (a *App) gracePeriod() time.Duration {
+	if a.config == nil {
+		return core.DefaultGracePeriod
+	}
+	return core.ResolveGracePeriod(a.config.GracePeriod)
+}
+
+// ListenAndServeWithGracePeriod serves the app on addr until SIGTERM or SIGINT, then stops
+// accepting connections, lets in-flight requests finish within the grace period, and
+// stops the Bun runtime last.
+func ListenAndServeWithGracePeriod(addr string, a *App) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return a.serveWithGracePeriod(ln, a.Handler())
+}
+
+func (a *App) serveWithGracePeriod(ln net.Listener, handler http.Handler) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	srv := &http.Server{Handler: handler}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ln) }()
+
+	select {
+	case err := <-serveErr:
+		_ = a.Stop()
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.gracePeriod())
+	defer cancel()
+
+	err := srv.Shutdown(shutdownCtx)
+	if serr := <-serveErr; serr != nil && !errors.Is(serr, http.ErrServerClosed) {
+		err = errors.Join(err, serr)
+	}
+	return errors.Join(err, a.Shutdown(shutdownCtx))
+}
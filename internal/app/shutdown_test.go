@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/adapters/runtime"
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func newShutdownTestApp() *App {
+	return &App{
+		host:        &runtime.Host{},
+		pageConfigs: make(map[string]*core.PageConfig),
+		config:      &core.Config{GracePeriod: 5 * time.Second},
+	}
+}
+
+func TestServeWithGracePeriodDrainsOnSIGTERM(t *testing.T) {
+	t.Setenv("BIFROST_DEV", "")
+	a := newShutdownTestApp()
+
+	started := make(chan struct{})
+	var handlerDone atomic.Bool
+	api := http.NewServeMux()
+	api.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		_, _ = io.WriteString(w, "done")
+		handlerDone.Store(true)
+	})
+	handler := a.Wrap(api)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	served := make(chan error, 1)
+	go func() { served <- a.serveWithGracePeriod(ln, handler) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{body: string(body), err: err}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request never reached the handler")
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Fatalf("serveWithGracePeriod() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down after SIGTERM")
+	}
+	if !handlerDone.Load() {
+		t.Error("server returned (and stopped the runtime) before the in-flight handler finished")
+	}
+
+	res := <-responses
+	if res.err != nil || res.body != "done" {
+		t.Fatalf("in-flight request = %q, %v; want completed response", res.body, res.err)
+	}
+
+	if _, err := net.DialTimeout("tcp", addr, time.Second); err == nil {
+		t.Error("expected new connections to be refused after shutdown")
+	}
+}
+
+func TestShutdownWaitsForInFlightRequests(t *testing.T) {
+	a := newShutdownTestApp()
+	a.inFlight.Add(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := a.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() with stuck request = %v, want deadline exceeded", err)
+	}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		a.inFlight.Add(-1)
+	}()
+	if err := a.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() after drain = %v", err)
+	}
+}
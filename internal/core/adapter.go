@@ -29,7 +29,16 @@ type FrameworkAdapter interface {
 	FileExtension() string
 	EntryFileExtension() string
 	SSREntryTemplate() string
-	ClientEntryTemplate(mode PageMode) string
+	ClientEntryTemplate(mode PageMode, hydration HydrationStrategy) string
+	// ErrorBoundarySSREntryTemplate and ErrorBoundaryClientEntryTemplate are the entry
+	// templates for WithErrorBoundary pages; FALLBACK_PATH is the fallback import.
+	ErrorBoundarySSREntryTemplate() string
+	ErrorBoundaryClientEntryTemplate(mode PageMode, hydration HydrationStrategy) string
+	// LoadingShellSSREntryTemplate is the SSR entry of WithLoadingShell pages, which renders
+	// the LOADING_PATH skeleton instead of the page. LoadingShellClientEntryTemplate turns a
+	// hydration entry template into one that replaces the skeleton with the page.
+	LoadingShellSSREntryTemplate() string
+	LoadingShellClientEntryTemplate(tmpl string) string
 	DevRendererSource() string
 	ProdRendererSource() string
 	BuildPlugins() []string
@@ -11,14 +11,16 @@ type PageArtifacts struct {
 	Chunks      []string
 	IsStatic    bool
 	SSRPath     string
+	SRI         map[string]string
 }
 
 // ResolvePageArtifacts returns asset metadata for entryName.
-// If manifest is nil or the entry has no script, uses the dev/public URL convention:
+// If manifest is nil or the entry has no script (and is not a WithStaticNoJS page), uses
+// the dev/public URL convention:
 // /dist/{entryName}.js and /dist/{entryName}.css.
 func ResolvePageArtifacts(manifest *Manifest, entryName string) PageArtifacts {
 	if manifest != nil {
-		if entry, ok := manifest.Entries[entryName]; ok && entry.Script != "" {
+		if entry, ok := manifest.Entries[entryName]; ok && (entry.Script != "" || entry.NoJS) {
 			return PageArtifacts{
 				Script:      entry.Script,
 				CriticalCSS: entry.CriticalCSS,
@@ -27,6 +29,7 @@ func ResolvePageArtifacts(manifest *Manifest, entryName string) PageArtifacts {
 				Chunks:      entry.Chunks,
 				IsStatic:    entry.Static,
 				SSRPath:     entry.SSR,
+				SRI:         entry.SRI,
 			}
 		}
 	}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// WithAssetIntegrity makes production startup check every embedded script and stylesheet
+// against the SHA-256 recorded by bifrost-build, and fail if one was modified. Assets the
+// manifest has no hash for (builds before hashes were recorded) are not checked.
+func WithAssetIntegrity(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.AssetIntegrity = enabled
+	}
+}
+
+// AssetIntegrityHash is the hex SHA-256 of data as stored in ManifestEntry.IntegrityHash.
+func AssetIntegrityHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// AssetIntegrityError lists the asset URLs whose embedded file is missing or does not
+// match the manifest hash.
+type AssetIntegrityError struct {
+	Assets []string
+}
+
+func (e *AssetIntegrityError) Error() string {
+	return fmt.Sprintf("asset integrity check failed for %s", strings.Join(e.Assets, ", "))
+}
+
+// VerifyAssetIntegrity hashes each asset recorded in the manifest's IntegrityHash fields,
+// reading URL /dist/x.js from .bifrost/dist/x.js in assetsFS.
+func VerifyAssetIntegrity(man *Manifest, assetsFS fs.FS) error {
+	if man == nil {
+		return nil
+	}
+	var failed []string
+	for _, entry := range man.Entries {
+		for url, want := range entry.IntegrityHash {
+			data, err := fs.ReadFile(assetsFS, path.Join(OutputDir, strings.TrimPrefix(url, "/")))
+			if err != nil || AssetIntegrityHash(data) != want {
+				failed = append(failed, url)
+			}
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	sort.Strings(failed)
+	return &AssetIntegrityError{Assets: failed}
+}
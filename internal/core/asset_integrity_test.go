@@ -0,0 +1,56 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func integrityFixture() (fstest.MapFS, *Manifest) {
+	assets := fstest.MapFS{
+		".bifrost/dist/home.js":  {Data: []byte("console.log('home')")},
+		".bifrost/dist/home.css": {Data: []byte("body{margin:0}")},
+	}
+	man := &Manifest{Entries: map[string]ManifestEntry{
+		"home": {
+			Script: "/dist/home.js",
+			CSS:    "/dist/home.css",
+			IntegrityHash: map[string]string{
+				"/dist/home.js":  AssetIntegrityHash([]byte("console.log('home')")),
+				"/dist/home.css": AssetIntegrityHash([]byte("body{margin:0}")),
+			},
+		},
+	}}
+	return assets, man
+}
+
+func TestVerifyAssetIntegrity(t *testing.T) {
+	assets, man := integrityFixture()
+	if err := VerifyAssetIntegrity(man, assets); err != nil {
+		t.Fatalf("VerifyAssetIntegrity() = %v, want nil", err)
+	}
+}
+
+func TestVerifyAssetIntegrityModifiedAsset(t *testing.T) {
+	assets, man := integrityFixture()
+	assets[".bifrost/dist/home.js"] = &fstest.MapFile{Data: []byte("console.log('pwned')")}
+	delete(assets, ".bifrost/dist/home.css")
+
+	err := VerifyAssetIntegrity(man, assets)
+	var integrityErr *AssetIntegrityError
+	if !errors.As(err, &integrityErr) {
+		t.Fatalf("VerifyAssetIntegrity() = %v, want *AssetIntegrityError", err)
+	}
+	want := []string{"/dist/home.css", "/dist/home.js"}
+	if len(integrityErr.Assets) != len(want) || integrityErr.Assets[0] != want[0] || integrityErr.Assets[1] != want[1] {
+		t.Errorf("failed assets = %v, want %v", integrityErr.Assets, want)
+	}
+}
+
+func TestVerifyAssetIntegritySkipsEntriesWithoutHashes(t *testing.T) {
+	assets, man := integrityFixture()
+	man.Entries["about"] = ManifestEntry{Script: "/dist/about.js"}
+	if err := VerifyAssetIntegrity(man, assets); err != nil {
+		t.Fatalf("VerifyAssetIntegrity() = %v, want entries without hashes skipped", err)
+	}
+}
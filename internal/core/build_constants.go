@@ -0,0 +1,59 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+)
+
+var buildConstantName = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// WithBuildConstants bakes constants into every Bun build, in dev and in bifrost-build:
+// each bare identifier NAME in client and SSR code is replaced with its value as JSON, so
+// both sides see the same value. Unlike WithBuildEnv the values keep their type, so
+// numbers and booleans stay numbers and booleans. App.BuildConstants returns them to Go.
+func WithBuildConstants(constants map[string]any) ConfigOption {
+	return func(c *Config) {
+		if c.BuildConstants == nil {
+			c.BuildConstants = make(map[string]any, len(constants))
+		}
+		maps.Copy(c.BuildConstants, constants)
+	}
+}
+
+// BuildConstantDefines returns constants as Bun defines: each name mapped to its value as
+// JSON. Names must be JavaScript identifiers and values must encode as JSON. It returns
+// nil for no constants.
+func BuildConstantDefines(constants map[string]any) (map[string]string, error) {
+	if len(constants) == 0 {
+		return nil, nil
+	}
+	defines := make(map[string]string, len(constants))
+	for name, value := range constants {
+		if !buildConstantName.MatchString(name) {
+			return nil, fmt.Errorf("build constant %q is not a JavaScript identifier", name)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("build constant %s: %w", name, err)
+		}
+		defines[name] = string(encoded)
+	}
+	return defines, nil
+}
+
+// BuildDefines returns the Bun defines of WithBuildEnv env and WithBuildConstants
+// constants together, or nil when there are none.
+func BuildDefines(env map[string]string, constants map[string]any) (map[string]string, error) {
+	defines, err := BuildConstantDefines(constants)
+	if err != nil {
+		return nil, err
+	}
+	envDefines := BuildEnvDefines(env)
+	if defines == nil {
+		return envDefines, nil
+	}
+	maps.Copy(defines, envDefines)
+	return defines, nil
+}
@@ -0,0 +1,42 @@
+package core
+
+import "testing"
+
+func TestBuildDefinesIncludesConstants(t *testing.T) {
+	var config Config
+	WithBuildEnv(map[string]string{"NEXT_PUBLIC_API_URL": "https://api.example.com"})(&config)
+	WithBuildConstants(map[string]any{"BIFROST_BUILD_SHA": "abc123", "BIFROST_BUILD_NUMBER": 42})(&config)
+	WithBuildConstants(map[string]any{"BIFROST_BETA": true})(&config)
+
+	defines, err := BuildDefines(config.BuildEnv, config.BuildConstants)
+	if err != nil {
+		t.Fatalf("BuildDefines() error = %v", err)
+	}
+	want := map[string]string{
+		"process.env.NEXT_PUBLIC_API_URL": `"https://api.example.com"`,
+		"BIFROST_BUILD_SHA":               `"abc123"`,
+		"BIFROST_BUILD_NUMBER":            `42`,
+		"BIFROST_BETA":                    `true`,
+	}
+	if len(defines) != len(want) {
+		t.Fatalf("defines = %v, want %v", defines, want)
+	}
+	for name, value := range want {
+		if defines[name] != value {
+			t.Errorf("define %s = %s, want %s", name, defines[name], value)
+		}
+	}
+
+	if defines, err := BuildDefines(nil, nil); err != nil || defines != nil {
+		t.Errorf("BuildDefines(nil, nil) = %v, %v; want nil", defines, err)
+	}
+}
+
+func TestBuildConstantDefinesRejectsInvalidConstants(t *testing.T) {
+	if _, err := BuildConstantDefines(map[string]any{"process.env.SHA": "abc"}); err == nil {
+		t.Error("a name that is not an identifier should fail")
+	}
+	if _, err := BuildConstantDefines(map[string]any{"BIFROST_CH": make(chan int)}); err == nil {
+		t.Error("a value that does not encode as JSON should fail")
+	}
+}
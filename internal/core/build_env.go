@@ -0,0 +1,33 @@
+package core
+
+import (
+	"encoding/json"
+	"maps"
+)
+
+// WithBuildEnv bakes env into every Bun build, in dev and in bifrost-build:
+// process.env.KEY in client and SSR code is replaced with the value as a string literal.
+// The values are fixed when the bundle is built and cannot change at runtime, and they
+// ship in the client bundle, so never pass secrets.
+func WithBuildEnv(env map[string]string) ConfigOption {
+	return func(c *Config) {
+		if c.BuildEnv == nil {
+			c.BuildEnv = make(map[string]string, len(env))
+		}
+		maps.Copy(c.BuildEnv, env)
+	}
+}
+
+// BuildEnvDefines returns env as Bun defines: process.env.KEY mapped to the value as a
+// JSON string. It returns nil for an empty env.
+func BuildEnvDefines(env map[string]string) map[string]string {
+	if len(env) == 0 {
+		return nil
+	}
+	defines := make(map[string]string, len(env))
+	for key, value := range env {
+		quoted, _ := json.Marshal(value)
+		defines["process.env."+key] = string(quoted)
+	}
+	return defines
+}
@@ -0,0 +1,20 @@
+package core
+
+import "testing"
+
+func TestBuildEnvDefines(t *testing.T) {
+	var config Config
+	WithBuildEnv(map[string]string{"NEXT_PUBLIC_API_URL": "https://api.example.com"})(&config)
+	WithBuildEnv(map[string]string{"NEXT_PUBLIC_QUOTE": `say "hi"`})(&config)
+
+	defines := BuildEnvDefines(config.BuildEnv)
+	if got := defines["process.env.NEXT_PUBLIC_API_URL"]; got != `"https://api.example.com"` {
+		t.Errorf("API URL define = %s", got)
+	}
+	if got := defines["process.env.NEXT_PUBLIC_QUOTE"]; got != `"say \"hi\""` {
+		t.Errorf("quoted define = %s", got)
+	}
+	if BuildEnvDefines(nil) != nil {
+		t.Error("BuildEnvDefines(nil) should be nil")
+	}
+}
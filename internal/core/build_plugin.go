@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// BuildPlugin adds a Bun plugin to client and SSR builds. BunPluginSource returns
+// TypeScript that defines a function bfPlugin_<Name>() returning a Bun.BunPlugin; it is
+// inserted into the renderer source before Bun starts.
+type BuildPlugin interface {
+	Name() string
+	BunPluginSource() string
+}
+
+// BuildPluginsOutEnv asks the app to write its build plugins as JSON to the named file and
+// exit. bifrost-build sets it when main.go calls WithBuildPlugin.
+const BuildPluginsOutEnv = "BIFROST_BUILD_PLUGINS_OUT"
+
+// WithBuildPlugin runs plugin in every Bun build, in dev and in bifrost-build. Plugins run
+// after the built-in ones, in the order they were added.
+func WithBuildPlugin(plugin BuildPlugin) ConfigOption {
+	return func(c *Config) {
+		c.BuildPlugins = append(c.BuildPlugins, plugin)
+	}
+}
+
+// BuildPluginSource is a BuildPlugin given as a name and TypeScript source, also used to
+// pass plugins from the app to bifrost-build.
+type BuildPluginSource struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+}
+
+func (p BuildPluginSource) Name() string            { return p.ID }
+func (p BuildPluginSource) BunPluginSource() string { return p.Source }
+
+var buildPluginNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// BuildPluginSources validates plugins and returns them in serializable form. Names must
+// be unique JavaScript identifiers, since they become part of bfPlugin_<Name>.
+func BuildPluginSources(plugins []BuildPlugin) ([]BuildPluginSource, error) {
+	sources := make([]BuildPluginSource, 0, len(plugins))
+	seen := make(map[string]bool, len(plugins))
+	for _, plugin := range plugins {
+		name := plugin.Name()
+		if !buildPluginNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("build plugin name %q must be a JavaScript identifier", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("build plugin %q added twice", name)
+		}
+		seen[name] = true
+		sources = append(sources, BuildPluginSource{ID: name, Source: plugin.BunPluginSource()})
+	}
+	return sources, nil
+}
+
+// BuildPluginIDs returns the names of plugins, in order.
+func BuildPluginIDs(plugins []BuildPluginSource) []string {
+	ids := make([]string, len(plugins))
+	for i, plugin := range plugins {
+		ids[i] = plugin.ID
+	}
+	return ids
+}
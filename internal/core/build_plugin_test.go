@@ -0,0 +1,46 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildPluginSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []BuildPlugin
+		wantIDs []string
+		wantErr string
+	}{
+		{name: "none", wantIDs: []string{}},
+		{
+			name: "keeps order",
+			plugins: []BuildPlugin{
+				BuildPluginSource{ID: "mdx", Source: "function bfPlugin_mdx() {}"},
+				BuildPluginSource{ID: "macros_2", Source: "function bfPlugin_macros_2() {}"},
+			},
+			wantIDs: []string{"mdx", "macros_2"},
+		},
+		{name: "invalid name", plugins: []BuildPlugin{BuildPluginSource{ID: "my-plugin"}}, wantErr: "JavaScript identifier"},
+		{name: "duplicate", plugins: []BuildPlugin{BuildPluginSource{ID: "mdx"}, BuildPluginSource{ID: "mdx"}}, wantErr: "added twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources, err := BuildPluginSources(tt.plugins)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := BuildPluginIDs(sources)
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Fatalf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package core
+
+import "fmt"
+
+// Bun build targets for client bundles.
+const (
+	BuildTargetBrowser = "browser"
+	BuildTargetBun     = "bun"
+	BuildTargetNode    = "node"
+)
+
+// WithBuildTarget sets the Bun build target of client bundles, in dev and in
+// bifrost-build: browser (the default), bun or node. SSR bundles always target Bun. With
+// bun or node the bundles cannot use browser globals such as window or document, and the
+// build fails for components that read them.
+func WithBuildTarget(target string) ConfigOption {
+	return func(c *Config) {
+		c.BuildTarget = target
+	}
+}
+
+// ValidateBuildTarget checks that target is a WithBuildTarget value; empty means browser.
+func ValidateBuildTarget(target string) error {
+	switch target {
+	case "", BuildTargetBrowser, BuildTargetBun, BuildTargetNode:
+		return nil
+	}
+	return fmt.Errorf("invalid build target %q: want browser, bun or node", target)
+}
@@ -0,0 +1,16 @@
+package core
+
+import "testing"
+
+func TestValidateBuildTarget(t *testing.T) {
+	for _, target := range []string{"", BuildTargetBrowser, BuildTargetBun, BuildTargetNode} {
+		if err := ValidateBuildTarget(target); err != nil {
+			t.Errorf("ValidateBuildTarget(%q) = %v", target, err)
+		}
+	}
+	for _, target := range []string{"deno", "Browser", "esm"} {
+		if err := ValidateBuildTarget(target); err == nil {
+			t.Errorf("ValidateBuildTarget(%q) = nil, want an error", target)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// BuildTimeEnv names the env var that gives the Bun renderer the fixed build time during
+// static export, in RFC 3339 format.
+const BuildTimeEnv = "BIFROST_BUILD_TIME"
+
+// WithBuildTime fixes the clock static export uses, so pages that show the current time
+// render the same output on every build. Static data loaders read it with BuildTime and
+// SSR code with process.env.BIFROST_BUILD_TIME.
+func WithBuildTime(t time.Time) ConfigOption {
+	return func(c *Config) {
+		c.BuildTime = t
+	}
+}
+
+type buildTimeKey struct{}
+
+// ContextWithBuildTime returns ctx carrying t for BuildTime. A zero t leaves ctx unchanged.
+func ContextWithBuildTime(ctx context.Context, t time.Time) context.Context {
+	if t.IsZero() {
+		return ctx
+	}
+	return context.WithValue(ctx, buildTimeKey{}, t)
+}
+
+// BuildTime returns the build time set with WithBuildTime while exporting, or time.Now
+// otherwise.
+func BuildTime(ctx context.Context) time.Time {
+	if t, ok := ctx.Value(buildTimeKey{}).(time.Time); ok {
+		return t
+	}
+	return time.Now()
+}
+
+// BuildTimeEnvEntry returns the BuildTimeEnv entry for t, or "" when t is zero.
+func BuildTimeEnvEntry(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return BuildTimeEnv + "=" + t.UTC().Format(time.RFC3339Nano)
+}
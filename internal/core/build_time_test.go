@@ -0,0 +1,28 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBuildTime(t *testing.T) {
+	fixed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	if got := BuildTime(ContextWithBuildTime(context.Background(), fixed)); !got.Equal(fixed) {
+		t.Errorf("BuildTime() = %v, want %v", got, fixed)
+	}
+
+	before := time.Now()
+	got := BuildTime(ContextWithBuildTime(context.Background(), time.Time{}))
+	if got.Before(before) {
+		t.Errorf("BuildTime() without build time = %v, want current time", got)
+	}
+
+	if got := BuildTimeEnvEntry(fixed); got != "BIFROST_BUILD_TIME=2024-05-01T10:00:00Z" {
+		t.Errorf("BuildTimeEnvEntry() = %q", got)
+	}
+	if got := BuildTimeEnvEntry(time.Time{}); got != "" {
+		t.Errorf("BuildTimeEnvEntry(zero) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,10 @@
+package core
+
+// WithBunSmol runs the Bun renderer with --smol when enabled, which keeps its heap small
+// at some cost in render throughput. It applies to the compiled runtime bifrost-build
+// embeds too, through BUN_OPTIONS. Off by default.
+func WithBunSmol(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.BunSmol = enabled
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CacheBustStrategy decides how asset URLs in page HTML change between builds so browsers
+// fetch new assets after a deploy.
+type CacheBustStrategy struct {
+	version   string
+	timestamp bool
+}
+
+var (
+	// CacheBustFilename relies on the content hash Bun puts in asset file names and leaves
+	// URLs unchanged. It is the default.
+	CacheBustFilename = CacheBustStrategy{}
+	// CacheBustTimestamp appends ?v=<unix seconds> of the build that wrote the manifest.
+	CacheBustTimestamp = CacheBustStrategy{timestamp: true}
+)
+
+// CacheBustQueryString appends ?v=version to asset URLs, for pipelines that serve assets
+// under stable file names.
+func CacheBustQueryString(version string) CacheBustStrategy {
+	return CacheBustStrategy{version: version}
+}
+
+// WithCacheBust sets how asset URLs in page HTML are cache-busted (default
+// CacheBustFilename).
+func WithCacheBust(strategy CacheBustStrategy) ConfigOption {
+	return func(c *Config) {
+		c.CacheBust = strategy
+	}
+}
+
+// Version returns the ?v= value the strategy appends for assets of manifest, or "" when
+// it leaves URLs unchanged, as CacheBustTimestamp does without a built manifest.
+func (s CacheBustStrategy) Version(manifest *Manifest) string {
+	if !s.timestamp {
+		return s.version
+	}
+	if manifest == nil || manifest.BuiltAt.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(manifest.BuiltAt.Unix(), 10)
+}
+
+// AppendCacheBustVersion adds v=version to the query of url. A url that already has a v
+// parameter, or an empty version, leaves url unchanged.
+func AppendCacheBustVersion(url string, version string) string {
+	if url == "" || version == "" {
+		return url
+	}
+	_, query, hasQuery := strings.Cut(url, "?")
+	if !hasQuery {
+		return url + "?v=" + version
+	}
+	for param := range strings.SplitSeq(query, "&") {
+		if param == "v" || strings.HasPrefix(param, "v=") {
+			return url
+		}
+	}
+	return url + "&v=" + version
+}
+
+// CacheBustLinkRewriter returns a LinkRewriter that applies rewrite, then appends
+// v=version. It returns rewrite itself when version is "".
+func CacheBustLinkRewriter(rewrite LinkRewriter, version string) LinkRewriter {
+	if version == "" {
+		return rewrite
+	}
+	return func(url string) string {
+		return AppendCacheBustVersion(rewrite.rewrite(url), version)
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func renderCacheBustShell(t *testing.T, strategy CacheBustStrategy, manifest *Manifest) string {
+	t.Helper()
+	shell, err := NewHTMLDocumentShell("/dist/home-entry.js", "", []string{"/dist/home-entry.css"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, err := shell.RewriteLinks(CacheBustLinkRewriter(nil, strategy.Version(manifest))).Render("", nil, "", "en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return html
+}
+
+func TestCacheBustQueryString(t *testing.T) {
+	html := renderCacheBustShell(t, CacheBustQueryString("1.0"), nil)
+	for _, want := range []string{
+		`<script src="/dist/home-entry.js?v=1.0" type="module" defer>`,
+		`<link rel="stylesheet" href="/dist/home-entry.css?v=1.0" />`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in:\n%s", want, html)
+		}
+	}
+}
+
+func TestCacheBustFilenameLeavesURLs(t *testing.T) {
+	html := renderCacheBustShell(t, CacheBustFilename, &Manifest{BuiltAt: time.Unix(1700000000, 0)})
+	if !strings.Contains(html, `<script src="/dist/home-entry.js" type="module" defer>`) || strings.Contains(html, "?v=") {
+		t.Errorf("CacheBustFilename changed asset URLs:\n%s", html)
+	}
+}
+
+func TestCacheBustTimestampUsesManifestBuildTime(t *testing.T) {
+	html := renderCacheBustShell(t, CacheBustTimestamp, &Manifest{BuiltAt: time.Unix(1700000000, 0)})
+	if !strings.Contains(html, `src="/dist/home-entry.js?v=1700000000"`) {
+		t.Errorf("expected the build time as version in:\n%s", html)
+	}
+	if v := CacheBustTimestamp.Version(nil); v != "" {
+		t.Errorf("Version(nil) = %q, want none without a built manifest", v)
+	}
+}
+
+func TestAppendCacheBustVersionDoesNotDoubleAppend(t *testing.T) {
+	rewrite := CacheBustLinkRewriter(prefixApp, "1.0")
+	once := rewrite("/dist/home-entry.js")
+	if once != "/app/dist/home-entry.js?v=1.0" {
+		t.Fatalf("rewrite = %q", once)
+	}
+	if twice := AppendCacheBustVersion(once, "1.0"); twice != once {
+		t.Errorf("second append = %q, want %q", twice, once)
+	}
+	if got := AppendCacheBustVersion("/dist/a.js?lang=en", "1.0"); got != "/dist/a.js?lang=en&v=1.0" {
+		t.Errorf("append to query = %q", got)
+	}
+
+	html := `<script src="/dist/home-entry.js" type="module"></script>`
+	urls := []string{"/dist/home-entry.js"}
+	rewritten := RewriteHTMLAssetLinks(html, urls, CacheBustLinkRewriter(nil, "1.0"))
+	if again := RewriteHTMLAssetLinks(rewritten, urls, CacheBustLinkRewriter(nil, "1.0")); again != rewritten || strings.Count(again, "v=1.0") != 1 {
+		t.Errorf("rewriting twice = %q", again)
+	}
+}
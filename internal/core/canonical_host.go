@@ -0,0 +1,50 @@
+package core
+
+import "strings"
+
+// HealthCheckPath is never redirected by WithCanonicalHost or WithHTTPS, so load balancer
+// probes that hit the app by IP or over plain HTTP keep working.
+const HealthCheckPath = "/healthz"
+
+// CanonicalHostConfig holds the host and scheme redirects for WithCanonicalHost and
+// WithHTTPS.
+type CanonicalHostConfig struct {
+	// Host is the canonical host (with port, if any); "" only enforces HTTPSOnly.
+	Host string
+	// Scheme is used for redirects to Host: "https" unless Host was given as http://.
+	Scheme string
+	// HTTPSOnly redirects plain HTTP requests to HTTPS.
+	HTTPSOnly bool
+}
+
+// Enabled reports whether any redirect is configured.
+func (c CanonicalHostConfig) Enabled() bool {
+	return c.Host != "" || c.HTTPSOnly
+}
+
+// WithCanonicalHost redirects (301) requests for any other host to host, keeping path and
+// query. host may carry a scheme: "https://www.example.com" also redirects plain HTTP to
+// HTTPS, "http://..." redirects to HTTP, and a bare host redirects to HTTPS.
+func WithCanonicalHost(host string) ConfigOption {
+	return func(c *Config) {
+		scheme := "https"
+		if rest, ok := strings.CutPrefix(host, "https://"); ok {
+			host = rest
+			c.CanonicalHost.HTTPSOnly = true
+		} else if rest, ok := strings.CutPrefix(host, "http://"); ok {
+			host = rest
+			scheme = "http"
+		}
+		c.CanonicalHost.Host = strings.ToLower(strings.TrimSuffix(host, "/"))
+		c.CanonicalHost.Scheme = scheme
+	}
+}
+
+// WithHTTPS redirects (301) plain HTTP requests to HTTPS. Requests count as HTTPS when
+// served over TLS or when X-Forwarded-Proto: https arrives through WithTrustedProxies or
+// WithTrustProxy; the header is ignored otherwise.
+func WithHTTPS() ConfigOption {
+	return func(c *Config) {
+		c.CanonicalHost.HTTPSOnly = true
+	}
+}
@@ -0,0 +1,34 @@
+package core
+
+import "testing"
+
+func TestWithCanonicalHost(t *testing.T) {
+	tests := []struct {
+		input string
+		want  CanonicalHostConfig
+	}{
+		{"www.example.com", CanonicalHostConfig{Host: "www.example.com", Scheme: "https"}},
+		{"WWW.Example.com/", CanonicalHostConfig{Host: "www.example.com", Scheme: "https"}},
+		{"https://www.example.com", CanonicalHostConfig{Host: "www.example.com", Scheme: "https", HTTPSOnly: true}},
+		{"http://intranet.local:8080", CanonicalHostConfig{Host: "intranet.local:8080", Scheme: "http"}},
+	}
+	for _, tt := range tests {
+		var c Config
+		WithCanonicalHost(tt.input)(&c)
+		if c.CanonicalHost != tt.want {
+			t.Errorf("WithCanonicalHost(%q) = %+v, want %+v", tt.input, c.CanonicalHost, tt.want)
+		}
+		if !c.CanonicalHost.Enabled() {
+			t.Errorf("WithCanonicalHost(%q) not enabled", tt.input)
+		}
+	}
+
+	var c Config
+	if c.CanonicalHost.Enabled() {
+		t.Error("zero config should not be enabled")
+	}
+	WithHTTPS()(&c)
+	if !c.CanonicalHost.Enabled() || c.CanonicalHost.Host != "" {
+		t.Errorf("WithHTTPS() = %+v", c.CanonicalHost)
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import "strings"
+
+// CSPReportPath receives Content-Security-Policy violation reports when a report URI is set.
+const CSPReportPath = "/_bifrost/csp-report"
+
+// DefaultCSPPolicy allows same-origin scripts, styles and images plus the inline critical
+// CSS Bifrost writes into each page.
+const DefaultCSPPolicy = "default-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; object-src 'none'; base-uri 'self'"
+
+// CSPConfig holds the Content-Security-Policy header and its violation reporting.
+type CSPConfig struct {
+	Policy string
+	// ReportURI is an external collector for violation reports.
+	ReportURI string
+	// ForwardReports sends reports only to CSPReportPath, which logs them and forwards
+	// them to ReportURI, instead of having browsers post to both.
+	ForwardReports bool
+	// Nonce generates a per-request nonce (WithCSPNonce).
+	Nonce bool
+	// NonceGenerator replaces NewCSPNonce (WithNonce).
+	NonceGenerator func() string
+}
+
+// HeaderValue returns the Content-Security-Policy header value, or "" when no policy is set.
+func (c CSPConfig) HeaderValue() string {
+	if c.Policy == "" {
+		return ""
+	}
+	if c.ReportURI == "" {
+		return c.Policy
+	}
+	policy := strings.TrimRight(strings.TrimSpace(c.Policy), ";")
+	reportURI := "report-uri " + CSPReportPath
+	if !c.ForwardReports {
+		reportURI += " " + c.ReportURI
+	}
+	return policy + "; " + reportURI
+}
+
+// WithCSP sets the Content-Security-Policy header to DefaultCSPPolicy on every response.
+func WithCSP() ConfigOption {
+	return WithCSPPolicy(DefaultCSPPolicy)
+}
+
+// WithCSPPolicy sets the Content-Security-Policy header to policy on every response.
+func WithCSPPolicy(policy string) ConfigOption {
+	return func(c *Config) {
+		c.CSP.Policy = policy
+	}
+}
+
+// WithCSPReportURI logs violation reports posted to CSPReportPath and adds a report-uri
+// directive naming that path and uri to the policy.
+func WithCSPReportURI(uri string) ConfigOption {
+	return func(c *Config) {
+		c.CSP.ReportURI = uri
+	}
+}
+
+// WithCSPReportForwarding makes the server forward each logged report to the report URI
+// rather than listing that URI in the header.
+func WithCSPReportForwarding(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.CSP.ForwardReports = enabled
+	}
+}
+
+// CSPViolationReport is the legacy report-uri body: {"csp-report": {...}}.
+type CSPViolationReport struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		Referrer           string `json:"referrer"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		BlockedURI         string `json:"blocked-uri"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         int    `json:"line-number"`
+		Disposition        string `json:"disposition"`
+	} `json:"csp-report"`
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"html"
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// PropNonce is the props key that carries the request's CSP nonce to the component, so it
+// can render <script nonce={props.__nonce}>.
+const PropNonce = "__nonce"
+
+// WithCSPNonce generates a nonce for every request, adds it to the policy's script-src,
+// puts it on the page's own script tags and passes it to the component as PropNonce.
+// Pages served from build output (client-only and static prerender in production) are
+// written ahead of time and carry no nonce.
+func WithCSPNonce() ConfigOption {
+	return func(c *Config) {
+		c.CSP.Nonce = true
+	}
+}
+
+// WithNonce turns on WithCSPNonce with generator in place of the crypto/rand default, for
+// example a FIPS-approved DRBG. generator is called once per request and must return
+// base64 (standard or URL alphabet) for at least 16 bytes; an empty or shorter value, or a
+// panic, falls back to NewCSPNonce for that request.
+func WithNonce(generator func() string) ConfigOption {
+	return func(c *Config) {
+		c.CSP.Nonce = true
+		c.CSP.NonceGenerator = generator
+	}
+}
+
+// minCSPNonceBytes is the least randomness a WithNonce generator must provide.
+const minCSPNonceBytes = 16
+
+// NewNonce returns a nonce from NonceGenerator when it yields a valid one, and from
+// NewCSPNonce otherwise.
+func (c CSPConfig) NewNonce() string {
+	if c.NonceGenerator != nil {
+		if nonce := generateNonce(c.NonceGenerator); validNonce(nonce) {
+			return nonce
+		}
+	}
+	return NewCSPNonce()
+}
+
+func generateNonce(generator func() string) (nonce string) {
+	defer func() {
+		if recover() != nil {
+			nonce = ""
+		}
+	}()
+	return generator()
+}
+
+func validNonce(nonce string) bool {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(nonce); err == nil {
+			return len(b) >= minCSPNonceBytes
+		}
+	}
+	return false
+}
+
+// NewCSPNonce returns a random base64 nonce (128 bits).
+func NewCSPNonce() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+type cspNonceKey struct{}
+
+func ContextWithCSPNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, cspNonceKey{}, nonce)
+}
+
+// CSPNonce returns the nonce generated for req by WithCSPNonce, or "".
+func CSPNonce(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	nonce, _ := req.Context().Value(cspNonceKey{}).(string)
+	return nonce
+}
+
+// HeaderValueWithNonce is HeaderValue with 'nonce-<nonce>' added to script-src. A policy
+// without script-src gets one copied from default-src; with neither, scripts are not
+// restricted and the policy is left alone.
+func (c CSPConfig) HeaderValueWithNonce(nonce string) string {
+	header := c.HeaderValue()
+	if header == "" || nonce == "" {
+		return header
+	}
+	source := "'nonce-" + nonce + "'"
+
+	directives := strings.Split(header, ";")
+	defaultSources := ""
+	for i, d := range directives {
+		name, sources, _ := strings.Cut(strings.TrimSpace(d), " ")
+		switch strings.ToLower(name) {
+		case "script-src":
+			directives[i] = strings.TrimRight(d, " ") + " " + source
+			return strings.Join(directives, ";")
+		case "default-src":
+			defaultSources = sources
+		}
+	}
+	if defaultSources == "" {
+		return header
+	}
+	return header + "; script-src " + defaultSources + " " + source
+}
+
+// WithNonceProp returns props with PropNonce set to nonce, leaving the original map
+// untouched. An empty nonce returns props as is.
+func WithNonceProp(props map[string]any, nonce string) map[string]any {
+	if nonce == "" {
+		return props
+	}
+	out := make(map[string]any, len(props)+1)
+	maps.Copy(out, props)
+	out[PropNonce] = nonce
+	return out
+}
+
+func nonceAttr(nonce string) string {
+	if nonce == "" {
+		return ""
+	}
+	return ` nonce="` + html.EscapeString(nonce) + `"`
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCSPConfigHeaderValueWithNonce(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{
+			name:   "script-src present",
+			policy: "default-src 'self'; script-src 'self' https://cdn.example.com; object-src 'none'",
+			want:   "default-src 'self'; script-src 'self' https://cdn.example.com 'nonce-abc'; object-src 'none'",
+		},
+		{
+			name:   "copied from default-src",
+			policy: DefaultCSPPolicy,
+			want:   DefaultCSPPolicy + "; script-src 'self' 'nonce-abc'",
+		},
+		{
+			name:   "scripts unrestricted",
+			policy: "img-src 'self'",
+			want:   "img-src 'self'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (CSPConfig{Policy: tt.policy}).HeaderValueWithNonce("abc"); got != tt.want {
+				t.Errorf("HeaderValueWithNonce() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCSPNonceIsUnique(t *testing.T) {
+	a, b := NewCSPNonce(), NewCSPNonce()
+	if a == "" || a == b {
+		t.Fatalf("expected distinct nonces, got %q and %q", a, b)
+	}
+}
+
+func TestCSPNonceFromRequest(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if got := CSPNonce(req); got != "" {
+		t.Errorf("CSPNonce() without nonce = %q", got)
+	}
+	req = req.WithContext(ContextWithCSPNonce(req.Context(), "abc"))
+	if got := CSPNonce(req); got != "abc" {
+		t.Errorf("CSPNonce() = %q, want abc", got)
+	}
+}
+
+func TestWithNonceProp(t *testing.T) {
+	props := map[string]any{"title": "Home"}
+	got := WithNonceProp(props, "abc")
+	if got[PropNonce] != "abc" || got["title"] != "Home" {
+		t.Errorf("WithNonceProp() = %v", got)
+	}
+	if _, ok := props[PropNonce]; ok {
+		t.Error("WithNonceProp must not modify the input map")
+	}
+	if got := WithNonceProp(props, ""); len(got) != 1 {
+		t.Errorf("empty nonce should leave props alone, got %v", got)
+	}
+}
+
+func TestHTMLShellWithNonce(t *testing.T) {
+	shell, err := NewHTMLDocumentShell("/dist/app.js", "", nil, []string{"/dist/chunk.js"})
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+
+	out, err := shell.WithNonce("abc").Render("", nil, "", "en", "")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, want := range []string{
+		`<link rel="modulepreload" href="/dist/chunk.js" nonce="abc" />`,
+		`<link rel="modulepreload" href="/dist/app.js" nonce="abc" />`,
+		`<script id="__BIFROST_PROPS__" type="application/json" nonce="abc">`,
+		`<script src="/dist/chunk.js" type="module" nonce="abc" defer></script>`,
+		`<script src="/dist/app.js" type="module" nonce="abc" defer></script>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in\n%s", want, out)
+		}
+	}
+
+	out, err = shell.Render("", nil, "", "en", "")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if strings.Contains(out, "nonce") {
+		t.Errorf("expected no nonce without WithNonce, got %s", out)
+	}
+}
+
+func TestHydrationReporterScriptWithNonce(t *testing.T) {
+	script := HydrationReporterScriptWithNonce("./pages/home.tsx", "abc")
+	if !strings.HasPrefix(script, `<script data-bifrost-dev nonce="abc">`) {
+		t.Errorf("expected nonce on reporter script, got %s", script)
+	}
+	if !strings.HasPrefix(HydrationReporterScript("./pages/home.tsx"), `<script data-bifrost-dev>`) {
+		t.Error("expected no nonce by default")
+	}
+}
+
+func TestCSPConfigNewNonce(t *testing.T) {
+	const custom = "AAECAwQFBgcICQoLDA0ODw==" // 16 bytes
+	tests := []struct {
+		name      string
+		generator func() string
+		want      string
+	}{
+		{name: "custom", generator: func() string { return custom }, want: custom},
+		{name: "empty", generator: func() string { return "" }},
+		{name: "too short", generator: func() string { return "AAECAw==" }},
+		{name: "not base64", generator: func() string { return "not a nonce!!!!!!!!!!!!!" }},
+		{name: "panics", generator: func() string { panic("no entropy") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := (CSPConfig{Nonce: true, NonceGenerator: tt.generator}).NewNonce()
+			if tt.want != "" {
+				if got != tt.want {
+					t.Errorf("NewNonce() = %q, want %q", got, tt.want)
+				}
+				return
+			}
+			if got == "" || !validNonce(got) {
+				t.Errorf("NewNonce() = %q, want a crypto/rand fallback", got)
+			}
+		})
+	}
+}
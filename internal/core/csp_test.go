@@ -0,0 +1,36 @@
+package core
+
+import "testing"
+
+func TestCSPConfigHeaderValue(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ConfigOption
+		want string
+	}{
+		{name: "unset", want: ""},
+		{name: "default policy", opts: []ConfigOption{WithCSP()}, want: DefaultCSPPolicy},
+		{name: "report uri without policy", opts: []ConfigOption{WithCSPReportURI("https://csp.example.com/report")}, want: ""},
+		{
+			name: "report uri",
+			opts: []ConfigOption{WithCSPPolicy("default-src 'self';"), WithCSPReportURI("https://csp.example.com/report")},
+			want: "default-src 'self'; report-uri /_bifrost/csp-report https://csp.example.com/report",
+		},
+		{
+			name: "forwarding",
+			opts: []ConfigOption{WithCSPPolicy("default-src 'self'"), WithCSPReportURI("https://csp.example.com/report"), WithCSPReportForwarding(true)},
+			want: "default-src 'self'; report-uri /_bifrost/csp-report",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c Config
+			for _, o := range tt.opts {
+				o(&c)
+			}
+			if got := c.CSP.HeaderValue(); got != tt.want {
+				t.Errorf("HeaderValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
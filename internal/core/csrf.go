@@ -0,0 +1,98 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"maps"
+	"net/http"
+)
+
+const (
+	// PropCSRF is the props key that carries the request's CSRF token to the component, so
+	// forms can render <input type="hidden" name="__csrf" value={props.__csrf} />.
+	PropCSRF = "__csrf"
+	// CSRFFormField is the form field checked on unsafe requests.
+	CSRFFormField = PropCSRF
+	// CSRFHeader is checked on unsafe requests that do not send a form, e.g. fetch calls.
+	CSRFHeader = "X-CSRF-Token"
+
+	DefaultCSRFCookieName = "bifrost_csrf"
+)
+
+// CSRFConfig sets the cookie that holds the CSRF token. The cookie is HttpOnly and lasts
+// for the browser session.
+type CSRFConfig struct {
+	// CookieName defaults to DefaultCSRFCookieName.
+	CookieName string
+	// Path defaults to "/".
+	Path string
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+	// Secure sends the cookie over HTTPS only; set it when the app is served over HTTPS.
+	Secure bool
+}
+
+func (c CSRFConfig) WithDefaults() CSRFConfig {
+	c.CookieName = cmp.Or(c.CookieName, DefaultCSRFCookieName)
+	c.Path = cmp.Or(c.Path, "/")
+	if c.SameSite == 0 {
+		c.SameSite = http.SameSiteLaxMode
+	}
+	return c
+}
+
+// WithCSRF protects page routes with a double-submit CSRF token. Every page request gets
+// a token, kept in a cookie and passed to the component as PropCSRF. POST, PUT, PATCH and
+// DELETE requests must send it back in the CSRFFormField form field or the CSRFHeader
+// header, or they are answered with 403 before the loader runs.
+func WithCSRF(cfg CSRFConfig) ConfigOption {
+	return func(c *Config) {
+		cfg := cfg.WithDefaults()
+		c.CSRF = &cfg
+	}
+}
+
+// NewCSRFToken returns a random URL-safe token (256 bits).
+func NewCSRFToken() string {
+	var b [32]byte
+	_, _ = rand.Read(b[:])
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+type csrfTokenKey struct{}
+
+func ContextWithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfTokenKey{}, token)
+}
+
+// CSRFToken returns the token WithCSRF issued for req, or "".
+func CSRFToken(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	token, _ := req.Context().Value(csrfTokenKey{}).(string)
+	return token
+}
+
+// WithCSRFProp returns props with PropCSRF set to token, leaving the original map
+// untouched. An empty token returns props as is.
+func WithCSRFProp(props map[string]any, token string) map[string]any {
+	if token == "" {
+		return props
+	}
+	out := make(map[string]any, len(props)+1)
+	maps.Copy(out, props)
+	out[PropCSRF] = token
+	return out
+}
+
+// IsUnsafeMethod reports whether method can change state and so needs a CSRF token.
+func IsUnsafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	}
+	return true
+}
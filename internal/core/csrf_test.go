@@ -0,0 +1,32 @@
+package core
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWithCSRFDefaults(t *testing.T) {
+	var c Config
+	WithCSRF(CSRFConfig{Secure: true})(&c)
+	if c.CSRF == nil {
+		t.Fatal("WithCSRF did not set Config.CSRF")
+	}
+	want := CSRFConfig{CookieName: DefaultCSRFCookieName, Path: "/", SameSite: http.SameSiteLaxMode, Secure: true}
+	if *c.CSRF != want {
+		t.Errorf("CSRF = %+v, want %+v", *c.CSRF, want)
+	}
+}
+
+func TestWithCSRFProp(t *testing.T) {
+	props := map[string]any{"title": "Contact"}
+	got := WithCSRFProp(props, "tok")
+	if got[PropCSRF] != "tok" || got["title"] != "Contact" {
+		t.Errorf("WithCSRFProp() = %v", got)
+	}
+	if _, ok := props[PropCSRF]; ok {
+		t.Error("WithCSRFProp must not modify the input map")
+	}
+	if got := WithCSRFProp(props, ""); len(got) != 1 {
+		t.Errorf("WithCSRFProp with empty token = %v, want props unchanged", got)
+	}
+}
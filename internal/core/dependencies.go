@@ -0,0 +1,45 @@
+package core
+
+import (
+	"context"
+	"net/http"
+)
+
+// WithDependencies makes deps available to every loader through Deps, so loaders do not
+// have to capture shared handles (DB pools, clients) in closures.
+func WithDependencies(deps any) ConfigOption {
+	return func(c *Config) {
+		c.Dependencies = deps
+	}
+}
+
+type dependenciesKey struct{}
+
+func ContextWithDependencies(ctx context.Context, deps any) context.Context {
+	if deps == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, dependenciesKey{}, deps)
+}
+
+// RequestWithDependencies returns a copy of req carrying deps, for calling loaders
+// directly in tests.
+func RequestWithDependencies(req *http.Request, deps any) *http.Request {
+	return req.WithContext(ContextWithDependencies(req.Context(), deps))
+}
+
+// DepsFromContext returns the dependencies stored in ctx as T, or the zero value of T
+// when none are set or they have a different type.
+func DepsFromContext[T any](ctx context.Context) T {
+	deps, _ := ctx.Value(dependenciesKey{}).(T)
+	return deps
+}
+
+// Deps returns the app dependencies for a loader request as T.
+func Deps[T any](req *http.Request) T {
+	if req == nil {
+		var zero T
+		return zero
+	}
+	return DepsFromContext[T](req.Context())
+}
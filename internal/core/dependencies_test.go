@@ -0,0 +1,32 @@
+package core
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+type testDeps struct {
+	Name string
+}
+
+func TestDeps(t *testing.T) {
+	deps := &testDeps{Name: "db"}
+	req := RequestWithDependencies(httptest.NewRequest("GET", "/", nil), deps)
+
+	if got := Deps[*testDeps](req); got != deps {
+		t.Errorf("Deps() = %v, want %v", got, deps)
+	}
+	if got := Deps[string](req); got != "" {
+		t.Errorf("Deps() with wrong type = %q, want zero value", got)
+	}
+	if got := Deps[*testDeps](httptest.NewRequest("GET", "/", nil)); got != nil {
+		t.Errorf("Deps() without dependencies = %v, want nil", got)
+	}
+	if got := Deps[*testDeps](nil); got != nil {
+		t.Errorf("Deps(nil) = %v, want nil", got)
+	}
+	if got := DepsFromContext[*testDeps](ContextWithDependencies(context.Background(), nil)); got != nil {
+		t.Errorf("DepsFromContext() with nil deps = %v, want nil", got)
+	}
+}
@@ -0,0 +1,27 @@
+package core
+
+import "time"
+
+// WithDiagnostics serves a dev-only diagnostics page at path (e.g. "/_bifrost/diag")
+// with renderer status, manifest entries, recent render errors and build timings.
+// In production the path answers 404.
+func WithDiagnostics(path string) ConfigOption {
+	return func(c *Config) {
+		c.DiagnosticsPath = path
+	}
+}
+
+// RendererStatus describes the Bun renderer process.
+type RendererStatus struct {
+	Up        bool
+	PID       int
+	StartedAt time.Time
+}
+
+// Uptime returns how long the renderer has been running at now, or 0 when it is down.
+func (s RendererStatus) Uptime(now time.Time) time.Duration {
+	if !s.Up || s.StartedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(s.StartedAt)
+}
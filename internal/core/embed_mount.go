@@ -0,0 +1,22 @@
+package core
+
+import (
+	"embed"
+	"strings"
+)
+
+// EmbedMount is an extra embedded FS served under Path (WithEmbedPublicAt).
+type EmbedMount struct {
+	FS   embed.FS
+	Path string
+}
+
+// WithEmbedPublicAt serves the files in fsys under mountPath, e.g. "/static", next to the
+// app's own assets. Leading directories that hold nothing else are skipped, so with
+// //go:embed dist a request for /static/app.css reads dist/app.css. Call it once per
+// mount; /dist/ always comes from the build output.
+func WithEmbedPublicAt(fsys embed.FS, mountPath string) ConfigOption {
+	return func(c *Config) {
+		c.EmbedMounts = append(c.EmbedMounts, EmbedMount{FS: fsys, Path: "/" + strings.Trim(mountPath, "/")})
+	}
+}
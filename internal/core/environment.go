@@ -0,0 +1,40 @@
+package core
+
+import (
+	"fmt"
+	"html"
+)
+
+// EnvironmentEnvVar names the deploy target; when set it must match the environment the
+// build was made for.
+const EnvironmentEnvVar = "BIFROST_ENV"
+
+// WithEnvironment names the environment (e.g. "staging") the app is built and run for. The
+// build records it in the manifest, pages carry it as data-bifrost-env on #app, and log
+// lines of the WithRequestLogger and WithLogger loggers get an env attribute.
+func WithEnvironment(name string) ConfigOption {
+	return func(c *Config) {
+		c.Environment = name
+	}
+}
+
+// environmentAttr returns the data-bifrost-env attribute for the #app element, or "".
+func environmentAttr(env string) string {
+	if env == "" {
+		return ""
+	}
+	return ` data-bifrost-env="` + html.EscapeString(env) + `"`
+}
+
+// CheckManifestEnvironment returns an error when deployEnv is set and differs from the
+// environment recorded in the manifest, i.e. the build is deployed to the wrong target.
+func CheckManifestEnvironment(man *Manifest, deployEnv string) error {
+	if man == nil || deployEnv == "" || man.Environment == deployEnv {
+		return nil
+	}
+	built := man.Environment
+	if built == "" {
+		built = "(none)"
+	}
+	return fmt.Errorf("build environment %s does not match %s=%s", built, EnvironmentEnvVar, deployEnv)
+}
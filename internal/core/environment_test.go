@@ -0,0 +1,73 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCheckManifestEnvironment(t *testing.T) {
+	tests := []struct {
+		name      string
+		built     string
+		deployEnv string
+		wantErr   bool
+	}{
+		{"match", "staging", "staging", false},
+		{"unset deploy env", "staging", "", false},
+		{"mismatch", "staging", "production", true},
+		{"untagged build", "", "production", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckManifestEnvironment(&Manifest{Environment: tt.built}, tt.deployEnv)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckManifestEnvironment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), EnvironmentEnvVar+"="+tt.deployEnv) {
+				t.Errorf("error %q should name %s", err, EnvironmentEnvVar)
+			}
+		})
+	}
+}
+
+func TestManifestJSONIncludesEnvironment(t *testing.T) {
+	data, err := json.Marshal(Manifest{Environment: "staging"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"environment":"staging"`) {
+		t.Errorf("expected environment in manifest JSON, got %s", data)
+	}
+
+	data, err = json.Marshal(Manifest{})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(data), "environment") {
+		t.Errorf("expected no environment field when unset, got %s", data)
+	}
+}
+
+func TestHTMLShellForEnvironment(t *testing.T) {
+	shell, err := NewHTMLDocumentShell("/dist/app.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+
+	out, err := shell.ForEnvironment("staging").Render("<p>hi</p>", nil, "", "en", "")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, `<div id="app" data-bifrost-env="staging"><p>hi</p>`) {
+		t.Errorf("expected data-bifrost-env on #app, got %s", out)
+	}
+
+	out, err = shell.Render("<p>hi</p>", nil, "", "en", "")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(out, `<div id="app"><p>hi</p>`) {
+		t.Errorf("expected plain #app without an environment, got %s", out)
+	}
+}
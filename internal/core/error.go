@@ -1,14 +1,35 @@
 package core
 
 import (
+	"errors"
 	"html/template"
+	"net/http"
 )
 
+// ErrorData fills ErrorTemplate. Outside dev, Message is shown only when set, as for a
+// WithErrorTransform message.
 type ErrorData struct {
+	Title   string
 	Message string
 	IsDev   bool
 }
 
+// StatusCodeError lets an error choose the HTTP status of the error page.
+type StatusCodeError interface {
+	StatusCode() int
+}
+
+// ErrorStatusCode returns the status carried by err, or 500 when it has none.
+func ErrorStatusCode(err error) int {
+	var sc StatusCodeError
+	if errors.As(err, &sc) {
+		if status := sc.StatusCode(); status >= 400 && status <= 599 {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
 var ErrorTemplate = template.Must(template.New("error").Parse(`<!doctype html>
 <html lang="en">
 <head>
@@ -53,9 +74,11 @@ var ErrorTemplate = template.Must(template.New("error").Parse(`<!doctype html>
 </head>
 <body>
     <div class="container">
-        <h1>Internal Server Error</h1>
+        <h1>{{if .Title}}{{.Title}}{{else}}Internal Server Error{{end}}</h1>
         {{if .IsDev}}
         <pre>{{.Message}}</pre>
+        {{else if .Message}}
+        <p>{{.Message}}</p>
         {{else}}
         <p>An error occurred while processing your request.</p>
         {{end}}
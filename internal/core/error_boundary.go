@@ -0,0 +1,39 @@
+package core
+
+import "context"
+
+// WithErrorBoundary renders fallbackComponent's Fallback export instead of the page when
+// the page throws while rendering, on the server and in the browser. The fallback gets the
+// page props plus error, the error message. Without it a render error fails the request.
+func WithErrorBoundary(fallbackComponent string) PageOption {
+	return func(c *PageConfig) {
+		c.ErrorBoundary = fallbackComponent
+	}
+}
+
+// BoundaryError is a page render error caught by the page's error boundary. The page was
+// still served, with the fallback in place of the page.
+type BoundaryError struct {
+	Message string
+}
+
+func (e *BoundaryError) Error() string {
+	return "error boundary: " + e.Message
+}
+
+type boundaryErrorReporterKey struct{}
+
+// ContextWithBoundaryErrorReporter makes renders under ctx pass boundary errors to report.
+func ContextWithBoundaryErrorReporter(ctx context.Context, report func(*BoundaryError)) context.Context {
+	if report == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, boundaryErrorReporterKey{}, report)
+}
+
+// ReportBoundaryError passes err to the reporter set on ctx, if any.
+func ReportBoundaryError(ctx context.Context, err *BoundaryError) {
+	if report, ok := ctx.Value(boundaryErrorReporterKey{}).(func(*BoundaryError)); ok {
+		report(err)
+	}
+}
@@ -0,0 +1,23 @@
+package core
+
+// ErrorTransform replaces a page error with the one shown to the user.
+type ErrorTransform func(err error) error
+
+// WithErrorTransform passes every page error, except redirects, through transform before
+// it is shown, so messages such as SQL errors do not reach users. The page shows the
+// message of the transformed error, also in production, where it otherwise shows only the
+// status text. The status code still comes from the original error, which is logged and
+// recorded before the transform. A nil result shows the status text. The transform is
+// skipped in dev unless WithErrorTransformInDev is set.
+func WithErrorTransform(transform ErrorTransform) ConfigOption {
+	return func(c *Config) {
+		c.ErrorTransform = transform
+	}
+}
+
+// WithErrorTransformInDev applies the WithErrorTransform transform in dev as well.
+func WithErrorTransformInDev(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.ErrorTransformInDev = enabled
+	}
+}
@@ -0,0 +1,19 @@
+package core
+
+// FaviconPath is the URL served by WithFavicon.
+const FaviconPath = "/favicon.ico"
+
+type Favicon struct {
+	Data     []byte
+	MIMEType string
+}
+
+// WithFavicon serves data at /favicon.ico. An empty mimeType defaults to image/x-icon.
+func WithFavicon(data []byte, mimeType string) ConfigOption {
+	return func(c *Config) {
+		if mimeType == "" {
+			mimeType = "image/x-icon"
+		}
+		c.Favicon = &Favicon{Data: data, MIMEType: mimeType}
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"maps"
+)
+
+const (
+	// PropFlags is the props key that carries WithFeatureFlags values to the component.
+	PropFlags = "flags"
+	// AnonymousIDCookie keeps the ID flags are evaluated for when a visitor has no
+	// CurrentUser, so they see the same flags on every request.
+	AnonymousIDCookie = "bifrost_anon"
+)
+
+// FlagProvider evaluates feature flags, e.g. from LaunchDarkly or Unleash, for a user ID.
+type FlagProvider interface {
+	Flags(userID string) map[string]bool
+}
+
+// WithFeatureFlags adds the flags provider returns for the current user to the props of
+// every SSR page under PropFlags. A "flags" value returned by the page loader wins.
+func WithFeatureFlags(provider FlagProvider) ConfigOption {
+	return func(c *Config) {
+		c.FeatureFlags = provider
+	}
+}
+
+// NewAnonymousID returns a random URL-safe visitor ID (128 bits).
+func NewAnonymousID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+type anonymousIDKey struct{}
+
+func ContextWithAnonymousID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, anonymousIDKey{}, id)
+}
+
+// FlagUserID returns the ID feature flags are evaluated for: the CurrentUser's ID, or the
+// visitor's anonymous ID when there is no user.
+func FlagUserID(ctx context.Context) string {
+	if user, ok := CurrentUser(ctx); ok {
+		return user.ID()
+	}
+	id, _ := ctx.Value(anonymousIDKey{}).(string)
+	return id
+}
+
+// WithFlagsProp returns props with PropFlags set to flags, leaving the original map
+// untouched. Props that already have PropFlags are returned as is.
+func WithFlagsProp(props map[string]any, flags map[string]bool) map[string]any {
+	if _, ok := props[PropFlags]; ok {
+		return props
+	}
+	if flags == nil {
+		flags = map[string]bool{}
+	}
+	out := make(map[string]any, len(props)+1)
+	maps.Copy(out, props)
+	out[PropFlags] = flags
+	return out
+}
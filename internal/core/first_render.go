@@ -0,0 +1,29 @@
+package core
+
+import "context"
+
+// WithOnFirstRender calls hook with a route's pattern the first time one of its pages
+// renders successfully in this process. The hook runs on its own goroutine, so it never
+// delays the response.
+func WithOnFirstRender(hook func(routePattern string)) ConfigOption {
+	return func(c *Config) {
+		c.OnFirstRender = hook
+	}
+}
+
+type firstRenderHookKey struct{}
+
+// ContextWithFirstRenderHook makes renders under ctx call hook after a component's first
+// successful render.
+func ContextWithFirstRenderHook(ctx context.Context, hook func()) context.Context {
+	if hook == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, firstRenderHookKey{}, hook)
+}
+
+// FirstRenderHook returns the hook set on ctx, or nil.
+func FirstRenderHook(ctx context.Context) func() {
+	hook, _ := ctx.Value(firstRenderHookKey{}).(func())
+	return hook
+}
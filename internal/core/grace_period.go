@@ -0,0 +1,23 @@
+package core
+
+import "time"
+
+// DefaultGracePeriod is used by ListenAndServeWithGracePeriod when WithGracePeriod is not set.
+const DefaultGracePeriod = 30 * time.Second
+
+// WithGracePeriod sets how long ListenAndServeWithGracePeriod lets in-flight requests
+// finish after SIGTERM or SIGINT before closing them. d <= 0 keeps DefaultGracePeriod
+// (30s). Other ways of serving the app, such as http.ListenAndServe, ignore it.
+func WithGracePeriod(d time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.GracePeriod = d
+	}
+}
+
+// ResolveGracePeriod returns d, or DefaultGracePeriod when d is not positive.
+func ResolveGracePeriod(d time.Duration) time.Duration {
+	if d <= 0 {
+		return DefaultGracePeriod
+	}
+	return d
+}
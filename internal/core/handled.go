@@ -0,0 +1,27 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrHandled is returned by a loader that has already written the full response
+// through ResponseWriter. Bifrost skips rendering and leaves the response untouched.
+var ErrHandled = errors.New("bifrost: response handled by loader")
+
+type responseWriterKey struct{}
+
+func ContextWithResponseWriter(ctx context.Context, w http.ResponseWriter) context.Context {
+	return context.WithValue(ctx, responseWriterKey{}, w)
+}
+
+// ResponseWriter returns the writer for the page request being served, or nil when
+// the request did not come through a Bifrost page handler.
+func ResponseWriter(req *http.Request) http.ResponseWriter {
+	if req == nil {
+		return nil
+	}
+	w, _ := req.Context().Value(responseWriterKey{}).(http.ResponseWriter)
+	return w
+}
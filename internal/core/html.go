@@ -3,7 +3,6 @@ package core
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"html"
 	"io"
 	"strings"
@@ -12,24 +11,111 @@ import (
 var emptyPropsJSON = []byte("{}")
 
 type HTMLDocumentShell struct {
-	scriptSrc string
-	styleTags string
-	chunks    []string
+	scriptSrc   string
+	criticalCSS string
+	cssHrefs    []string
+	styleTags   string
+	chunks      []string
+	appAttrs    string
+	nonceAttr   string
+	preloads    []string
+	sri         map[string]string
+	noJS        bool
 }
 
+// NewHTMLDocumentShell returns the shell for a page's client script. An empty scriptSrc
+// gives the shell of a page without client JavaScript, as NewStaticHTMLShell.
 func NewHTMLDocumentShell(scriptSrc string, criticalCSS string, cssHrefs []string, chunks []string) (HTMLDocumentShell, error) {
 	if scriptSrc == "" {
-		return HTMLDocumentShell{}, errors.New("missing script src")
+		return NewStaticHTMLShell(criticalCSS, cssHrefs), nil
 	}
 	return HTMLDocumentShell{
-		scriptSrc: scriptSrc,
-		styleTags: RenderStyleTags(criticalCSS, cssHrefs),
-		chunks:    append([]string(nil), chunks...),
+		scriptSrc:   scriptSrc,
+		criticalCSS: criticalCSS,
+		cssHrefs:    append([]string(nil), cssHrefs...),
+		styleTags:   RenderStyleTags(criticalCSS, cssHrefs),
+		chunks:      append([]string(nil), chunks...),
 	}, nil
 }
 
+// NewStaticHTMLShell returns a shell for pages without client JavaScript, such as
+// WithStaticNoJS pages: the document with its stylesheets, but no script, modulepreload
+// or props tags.
+func NewStaticHTMLShell(criticalCSS string, cssHrefs []string) HTMLDocumentShell {
+	return HTMLDocumentShell{
+		criticalCSS: criticalCSS,
+		cssHrefs:    append([]string(nil), cssHrefs...),
+		styleTags:   RenderStyleTags(criticalCSS, cssHrefs),
+		noJS:        true,
+	}
+}
+
+// RewriteLinks returns a copy of the shell with its script, stylesheet, chunk and preload
+// URLs passed through rewrite. A nil rewrite returns the shell unchanged.
+func (s HTMLDocumentShell) RewriteLinks(rewrite LinkRewriter) HTMLDocumentShell {
+	if rewrite == nil {
+		return s
+	}
+	out := s
+	if s.scriptSrc != "" {
+		out.scriptSrc = rewrite.rewrite(s.scriptSrc)
+	}
+	out.cssHrefs = make([]string, len(s.cssHrefs))
+	for i, href := range s.cssHrefs {
+		out.cssHrefs[i] = rewrite.rewrite(href)
+	}
+	out.chunks = make([]string, len(s.chunks))
+	for i, chunk := range s.chunks {
+		out.chunks[i] = rewrite.rewrite(chunk)
+	}
+	if s.sri != nil {
+		out.sri = make(map[string]string, len(s.sri))
+		for url, hash := range s.sri {
+			out.sri[rewrite.rewrite(url)] = hash
+		}
+	}
+	out.styleTags = RenderStyleTagsWithIntegrity(out.criticalCSS, out.cssHrefs, out.sri)
+	if s.preloads != nil {
+		out.preloads = make([]string, len(s.preloads))
+		for i, p := range s.preloads {
+			out.preloads[i] = rewrite.rewrite(p)
+		}
+	}
+	return out
+}
+
+// WithPreloads returns a copy of the shell that writes a <link rel="preload"> for each of
+// paths at the top of the head.
+func (s HTMLDocumentShell) WithPreloads(paths []string) HTMLDocumentShell {
+	s.preloads = append([]string(nil), paths...)
+	return s
+}
+
+// WithIntegrity returns a copy of the shell that adds integrity and crossorigin attributes
+// to the script, chunk and stylesheet tags whose URL has a hash in sri. Apply it before
+// RewriteLinks, since sri is keyed by the manifest URLs.
+func (s HTMLDocumentShell) WithIntegrity(sri map[string]string) HTMLDocumentShell {
+	s.sri = sri
+	s.styleTags = RenderStyleTagsWithIntegrity(s.criticalCSS, s.cssHrefs, sri)
+	return s
+}
+
+// ForEnvironment returns a copy of the shell that marks #app with data-bifrost-env.
+func (s HTMLDocumentShell) ForEnvironment(env string) HTMLDocumentShell {
+	s.appAttrs = environmentAttr(env)
+	return s
+}
+
+// WithNonce returns a copy of the shell that puts nonce on its script and modulepreload
+// tags. An empty nonce leaves the tags without one.
+func (s HTMLDocumentShell) WithNonce(nonce string) HTMLDocumentShell {
+	s.nonceAttr = nonceAttr(nonce)
+	return s
+}
+
 // MarshalBifrostPropsJSON marshals props for embedding in the __BIFROST_PROPS__ script tag.
 func MarshalBifrostPropsJSON(props map[string]any) ([]byte, error) {
+	props = withoutSlotsProp(props)
 	if len(props) == 0 {
 		return emptyPropsJSON, nil
 	}
@@ -105,6 +191,12 @@ func (s HTMLDocumentShell) WritePreamble(w io.Writer, headHTML string, htmlLang
 		return err
 	}
 
+	if len(s.preloads) > 0 {
+		if _, err := io.WriteString(w, RenderPreloadTags(s.preloads)); err != nil {
+			return err
+		}
+	}
+
 	if !hasCustomTitle {
 		if _, err := io.WriteString(w, "<title>Bifrost</title>"); err != nil {
 			return err
@@ -121,6 +213,25 @@ func (s HTMLDocumentShell) WritePreamble(w io.Writer, headHTML string, htmlLang
 		}
 	}
 
+	if !s.noJS {
+		if err := s.writeModulePreloads(w); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.WriteString(w, "\n  </head>\n  <body>\n    <div id=\"app\""); err != nil {
+		return err
+	}
+	if s.appAttrs != "" {
+		if _, err := io.WriteString(w, s.appAttrs); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, ">")
+	return err
+}
+
+func (s HTMLDocumentShell) writeModulePreloads(w io.Writer) error {
 	for _, chunk := range s.chunks {
 		if _, err := io.WriteString(w, `<link rel="modulepreload" href="`); err != nil {
 			return err
@@ -128,7 +239,7 @@ func (s HTMLDocumentShell) WritePreamble(w io.Writer, headHTML string, htmlLang
 		if _, err := io.WriteString(w, chunk); err != nil {
 			return err
 		}
-		if _, err := io.WriteString(w, `" />`); err != nil {
+		if _, err := io.WriteString(w, `"`+SRIAttrs(s.sri, chunk)+s.nonceAttr+` />`); err != nil {
 			return err
 		}
 	}
@@ -138,19 +249,21 @@ func (s HTMLDocumentShell) WritePreamble(w io.Writer, headHTML string, htmlLang
 	if _, err := io.WriteString(w, s.scriptSrc); err != nil {
 		return err
 	}
-	if _, err := io.WriteString(w, `" />`); err != nil {
-		return err
-	}
-
-	_, err := io.WriteString(w, "\n  </head>\n  <body>\n    <div id=\"app\">")
+	_, err := io.WriteString(w, `"`+SRIAttrs(s.sri, s.scriptSrc)+s.nonceAttr+` />`)
 	return err
 }
 
+// WriteSuffix closes the document. Shells from NewStaticHTMLShell write no
+// props or script tags.
 func (s HTMLDocumentShell) WriteSuffix(w io.Writer, propsJSON []byte) error {
+	if s.noJS {
+		_, err := io.WriteString(w, "</div>\n  </body>\n</html>\n")
+		return err
+	}
 	if len(propsJSON) == 0 {
 		propsJSON = emptyPropsJSON
 	}
-	if _, err := io.WriteString(w, "</div>\n    <script id=\"__BIFROST_PROPS__\" type=\"application/json\">"); err != nil {
+	if _, err := io.WriteString(w, "</div>\n    <script id=\"__BIFROST_PROPS__\" type=\"application/json\""+s.nonceAttr+">"); err != nil {
 		return err
 	}
 	if _, err := w.Write(propsJSON); err != nil {
@@ -167,7 +280,7 @@ func (s HTMLDocumentShell) WriteSuffix(w io.Writer, propsJSON []byte) error {
 		if _, err := io.WriteString(w, chunk); err != nil {
 			return err
 		}
-		if _, err := io.WriteString(w, "\" type=\"module\" defer></script>\n"); err != nil {
+		if _, err := io.WriteString(w, "\" type=\"module\""+SRIAttrs(s.sri, chunk)+s.nonceAttr+" defer></script>\n"); err != nil {
 			return err
 		}
 	}
@@ -178,7 +291,7 @@ func (s HTMLDocumentShell) WriteSuffix(w io.Writer, propsJSON []byte) error {
 	if _, err := io.WriteString(w, s.scriptSrc); err != nil {
 		return err
 	}
-	_, err := io.WriteString(w, "\" type=\"module\" defer></script>\n  </body>\n</html>\n")
+	_, err := io.WriteString(w, "\" type=\"module\""+SRIAttrs(s.sri, s.scriptSrc)+s.nonceAttr+" defer></script>\n  </body>\n</html>\n")
 	return err
 }
 
@@ -203,6 +316,11 @@ func (s HTMLDocumentShell) Render(bodyHTML string, props map[string]any, headHTM
 }
 
 func RenderStyleTags(criticalCSS string, cssHrefs []string) string {
+	return RenderStyleTagsWithIntegrity(criticalCSS, cssHrefs, nil)
+}
+
+// RenderStyleTagsWithIntegrity is RenderStyleTags with SRIAttrs added to each stylesheet link.
+func RenderStyleTagsWithIntegrity(criticalCSS string, cssHrefs []string, sri map[string]string) string {
 	if criticalCSS == "" && len(cssHrefs) == 0 {
 		return ""
 	}
@@ -219,7 +337,7 @@ func RenderStyleTags(criticalCSS string, cssHrefs []string) string {
 		}
 		sb.WriteString(`<link rel="stylesheet" href="`)
 		sb.WriteString(href)
-		sb.WriteString(`" />`)
+		sb.WriteString(`"` + SRIAttrs(sri, href) + ` />`)
 	}
 	return sb.String()
 }
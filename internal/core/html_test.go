@@ -123,9 +123,49 @@ func TestRenderHTMLShell_Basic(t *testing.T) {
 }
 
 func TestRenderHTMLShell_MissingScript(t *testing.T) {
-	_, err := RenderHTMLShell("", nil, "", "", "", nil, nil, "", "")
-	if err == nil {
-		t.Error("expected error for missing script src")
+	html, err := RenderHTMLShell("<main>Docs</main>", map[string]any{"x": 1}, "", "", "", []string{"/dist/page.css"}, []string{"/dist/chunk.js"}, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(html, "<main>Docs</main>") || !strings.Contains(html, `href="/dist/page.css"`) {
+		t.Errorf("expected body and stylesheet in output:\n%s", html)
+	}
+	for _, unwanted := range []string{"<script", "modulepreload", "__BIFROST_PROPS__"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("shell without script src should not contain %s:\n%s", unwanted, html)
+		}
+	}
+}
+
+func TestNewHTMLDocumentShell_WithoutScript(t *testing.T) {
+	shell, err := NewHTMLDocumentShell("", "", []string{"/dist/page.css"}, []string{"/dist/chunk.js"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var b strings.Builder
+	if err := shell.WritePreamble(&b, "<title>Docs</title>", "en", ""); err != nil {
+		t.Fatalf("WritePreamble: %v", err)
+	}
+	b.WriteString("<main>Docs</main>")
+	if err := shell.WriteSuffix(&b, []byte(`{"x":1}`)); err != nil {
+		t.Fatalf("WriteSuffix: %v", err)
+	}
+	html := b.String()
+
+	if !strings.HasPrefix(html, "<!doctype html>") || !strings.HasSuffix(html, "</html>\n") {
+		t.Errorf("expected a complete document:\n%s", html)
+	}
+	if !strings.Contains(html, `<div id="app"><main>Docs</main></div>`) {
+		t.Errorf("expected body inside the app root:\n%s", html)
+	}
+	if !strings.Contains(html, `<link rel="stylesheet" href="/dist/page.css"`) {
+		t.Errorf("expected stylesheet link:\n%s", html)
+	}
+	for _, unwanted := range []string{`type="module"`, "<script", "modulepreload"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("shell without script src should not contain %s:\n%s", unwanted, html)
+		}
 	}
 }
 
@@ -331,3 +371,20 @@ func TestRenderHTMLShell_MultipleStylesheets(t *testing.T) {
 		t.Fatalf("expected 2 stylesheet links, got: %q", html)
 	}
 }
+
+func TestHydrationReporterScript(t *testing.T) {
+	script := HydrationReporterScript("./pages/</script>.tsx")
+
+	if !strings.HasPrefix(script, "<script data-bifrost-dev>") || !strings.HasSuffix(script, "</script>") {
+		t.Fatalf("unexpected script wrapper: %q", script)
+	}
+	if strings.Count(script, "</script>") != 1 {
+		t.Fatalf("component path must not close the script early: %q", script)
+	}
+	if !strings.Contains(script, `"`+HydrationErrorPath+`"`) {
+		t.Fatalf("script should post to %s: %q", HydrationErrorPath, script)
+	}
+	if strings.Contains(script, "BIFROST_") {
+		t.Fatalf("unreplaced placeholder in %q", script)
+	}
+}
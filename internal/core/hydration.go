@@ -0,0 +1,46 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// HydrationErrorPath receives hydration mismatch reports from the dev reporter script.
+const HydrationErrorPath = "/__bifrost/hydration-error"
+
+// HydrationErrorReport is the JSON body posted to HydrationErrorPath.
+type HydrationErrorReport struct {
+	Component string `json:"component"`
+	Path      string `json:"path"`
+	Message   string `json:"message"`
+}
+
+const hydrationReporterTemplate = `<script data-bifrost-devBIFROST_NONCE>(function(){` +
+	`var c=BIFROST_COMPONENT,u=BIFROST_HYDRATION_URL,s={};` +
+	`function r(m){m=String(m||"");if(!/hydrat|did not match/i.test(m)||s[m])return;s[m]=1;` +
+	`var b=JSON.stringify({component:c,path:location.pathname,message:m.slice(0,4000)});` +
+	`if(navigator.sendBeacon){navigator.sendBeacon(u,new Blob([b],{type:"application/json"}));}` +
+	`else{fetch(u,{method:"POST",headers:{"Content-Type":"application/json"},body:b,keepalive:true});}}` +
+	`var e=console.error;console.error=function(){try{r(Array.prototype.map.call(arguments,String).join(" "));}catch(_){}return e.apply(console,arguments);};` +
+	`window.addEventListener("error",function(ev){r(ev&&(ev.message||(ev.error&&ev.error.message)));});` +
+	`})();</script>`
+
+// HydrationReporterScript returns the dev-only inline script that forwards React hydration
+// errors for componentPath to HydrationErrorPath. It must run before the client entry.
+func HydrationReporterScript(componentPath string) string {
+	return HydrationReporterScriptWithNonce(componentPath, "")
+}
+
+// HydrationReporterScriptWithNonce is HydrationReporterScript with a CSP nonce on the tag.
+func HydrationReporterScriptWithNonce(componentPath string, nonce string) string {
+	return strings.NewReplacer(
+		"BIFROST_NONCE", nonceAttr(nonce),
+		"BIFROST_COMPONENT", inlineJSONString(componentPath),
+		"BIFROST_HYDRATION_URL", inlineJSONString(HydrationErrorPath),
+	).Replace(hydrationReporterTemplate)
+}
+
+func inlineJSONString(s string) string {
+	b, _ := json.Marshal(s)
+	return strings.ReplaceAll(string(b), "</", `<\/`)
+}
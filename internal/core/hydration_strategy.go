@@ -0,0 +1,22 @@
+package core
+
+// HydrationStrategy controls when the client entry hydrates server-rendered HTML.
+type HydrationStrategy int
+
+const (
+	// HydrationLazy waits for the browser to go idle (requestIdleCallback, or a 100ms
+	// setTimeout fallback). It is the default.
+	HydrationLazy HydrationStrategy = iota
+	// HydrationImmediate hydrates as soon as the client entry runs.
+	HydrationImmediate
+	// HydrationVisible waits until the #app element enters the viewport.
+	HydrationVisible
+)
+
+// WithHydrationStrategy sets how the page hydrates. It has no effect on client-only pages,
+// which render on the client instead of hydrating.
+func WithHydrationStrategy(strategy HydrationStrategy) PageOption {
+	return func(c *PageConfig) {
+		c.Hydration = strategy
+	}
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// InputValidator checks request input before the page loader runs. It returns the
+// validated values by name, or a *ValidationError listing what is wrong.
+type InputValidator interface {
+	Validate(req *http.Request) (map[string]string, error)
+}
+
+// InputValidatorFunc adapts a function to InputValidator.
+type InputValidatorFunc func(req *http.Request) (map[string]string, error)
+
+func (f InputValidatorFunc) Validate(req *http.Request) (map[string]string, error) {
+	return f(req)
+}
+
+// InputRule checks a single input value.
+type InputRule func(value string) error
+
+// ValidationError maps input names to what is wrong with them. Pages answer it with 422.
+type ValidationError struct {
+	Errors map[string]string `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for _, name := range slices.Sorted(maps.Keys(e.Errors)) {
+		names = append(names, name+": "+e.Errors[name])
+	}
+	return "invalid input: " + strings.Join(names, ", ")
+}
+
+func (e *ValidationError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// WithInputValidation runs the validators in order before the page loader. Requests that
+// fail any of them get 422 with {"errors": {...}} and never reach the loader; the values
+// they validated are available through Validated.
+func WithInputValidation(validators ...InputValidator) PageOption {
+	return func(c *PageConfig) {
+		c.Validators = append(c.Validators, validators...)
+	}
+}
+
+// PathValue validates the path wildcard name with rule.
+func PathValue(name string, rule InputRule) InputValidator {
+	return inputValue(name, rule, func(req *http.Request) string { return req.PathValue(name) })
+}
+
+// QueryValue validates the query parameter name with rule. A missing parameter is
+// checked as "".
+func QueryValue(name string, rule InputRule) InputValidator {
+	return inputValue(name, rule, func(req *http.Request) string { return req.URL.Query().Get(name) })
+}
+
+func inputValue(name string, rule InputRule, value func(*http.Request) string) InputValidator {
+	return InputValidatorFunc(func(req *http.Request) (map[string]string, error) {
+		v := value(req)
+		if err := rule(v); err != nil {
+			return nil, &ValidationError{Errors: map[string]string{name: err.Error()}}
+		}
+		return map[string]string{name: v}, nil
+	})
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// UUID accepts a UUID in its canonical 8-4-4-4-12 hex form.
+func UUID() InputRule {
+	return func(value string) error {
+		if !uuidPattern.MatchString(value) {
+			return fmt.Errorf("must be a UUID")
+		}
+		return nil
+	}
+}
+
+// Int accepts a base-10 integer between min and max, inclusive.
+func Int(min, max int) InputRule {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		if n < min || n > max {
+			return fmt.Errorf("must be between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+// OneOf accepts exactly one of values.
+func OneOf(values ...string) InputRule {
+	return func(value string) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+	}
+}
+
+// Regex accepts values that match pattern as a whole. It panics when pattern does not
+// compile.
+func Regex(pattern string) InputRule {
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		panic("bifrost: invalid Regex pattern: " + err.Error())
+	}
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match %s", pattern)
+		}
+		return nil
+	}
+}
+
+// ValidateInput runs validators in order and merges what they return. Every validator
+// runs, so the error lists all invalid inputs; the first message for a name wins.
+func ValidateInput(req *http.Request, validators []InputValidator) (map[string]string, error) {
+	values := make(map[string]string)
+	var invalid map[string]string
+	for _, validator := range validators {
+		validated, err := validator.Validate(req)
+		if err == nil {
+			maps.Copy(values, validated)
+			continue
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			return nil, err
+		}
+		if invalid == nil {
+			invalid = make(map[string]string)
+		}
+		for name, message := range validationErr.Errors {
+			if _, seen := invalid[name]; !seen {
+				invalid[name] = message
+			}
+		}
+	}
+	if invalid != nil {
+		return nil, &ValidationError{Errors: invalid}
+	}
+	return values, nil
+}
+
+type validatedKey struct{}
+
+func ContextWithValidated(ctx context.Context, values map[string]string) context.Context {
+	return context.WithValue(ctx, validatedKey{}, values)
+}
+
+// Validated returns the values WithInputValidation accepted for the request, or nil.
+func Validated(ctx context.Context) map[string]string {
+	values, _ := ctx.Value(validatedKey{}).(map[string]string)
+	return values
+}
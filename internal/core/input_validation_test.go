@@ -0,0 +1,68 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInputRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  InputRule
+		value string
+		ok    bool
+	}{
+		{name: "uuid", rule: UUID(), value: "3F2C8A4E-9B1D-4C6E-8F0A-1B2C3D4E5F60", ok: true},
+		{name: "uuid without dashes", rule: UUID(), value: "3f2c8a4e9b1d4c6e8f0a1b2c3d4e5f60", ok: false},
+		{name: "int in range", rule: Int(1, 10), value: "10", ok: true},
+		{name: "int out of range", rule: Int(1, 10), value: "11", ok: false},
+		{name: "int not a number", rule: Int(1, 10), value: "1e3", ok: false},
+		{name: "one of", rule: OneOf("asc", "desc"), value: "desc", ok: true},
+		{name: "one of missing", rule: OneOf("asc", "desc"), value: "", ok: false},
+		{name: "regex", rule: Regex(`[a-z]+(-[a-z]+)*`), value: "hello-world", ok: true},
+		{name: "regex matches whole value", rule: Regex(`[a-z]+`), value: "hello world", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule(tt.value); (err == nil) != tt.ok {
+				t.Errorf("rule(%q) error = %v, want ok = %v", tt.value, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRegexPanicsOnInvalidPattern(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for invalid pattern")
+		}
+	}()
+	Regex("(")
+}
+
+func TestValidateInputAppliesValidatorsInOrder(t *testing.T) {
+	var order []string
+	record := func(name string, err error) InputValidator {
+		return InputValidatorFunc(func(*http.Request) (map[string]string, error) {
+			order = append(order, name)
+			return map[string]string{"step": name}, err
+		})
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+
+	values, err := ValidateInput(req, []InputValidator{record("first", nil), record("second", nil)})
+	if err != nil {
+		t.Fatalf("ValidateInput() error = %v", err)
+	}
+	if len(order) != 2 || order[0] != "first" || order[1] != "second" || values["step"] != "second" {
+		t.Errorf("order = %v, values = %v", order, values)
+	}
+
+	boom := errors.New("lookup failed")
+	if _, err := ValidateInput(req, []InputValidator{record("third", boom)}); !errors.Is(err, boom) {
+		t.Errorf("ValidateInput() error = %v, want custom error", err)
+	}
+}
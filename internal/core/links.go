@@ -0,0 +1,56 @@
+package core
+
+import "strings"
+
+// LinkRewriter maps an asset URL from the manifest, such as /dist/home-entry.abc123.js,
+// to the URL written into HTML.
+type LinkRewriter func(url string) string
+
+// WithLinkRewriting rewrites every script, stylesheet and chunk URL in page HTML, e.g. to
+// serve under a sub-path or from a CDN. rewrite must be a pure function of its input.
+func WithLinkRewriting(rewrite LinkRewriter) ConfigOption {
+	return func(c *Config) {
+		c.LinkRewriter = rewrite
+	}
+}
+
+func (r LinkRewriter) rewrite(url string) string {
+	if r == nil || url == "" {
+		return url
+	}
+	return r(url)
+}
+
+// RewriteHTMLAssetLinks rewrites src and href attributes whose value is exactly one of
+// urls. It is used for HTML written by the build, which cannot call the app's rewriter;
+// other attributes and inline content such as the props JSON are left alone.
+func RewriteHTMLAssetLinks(html string, urls []string, rewrite LinkRewriter) string {
+	if rewrite == nil {
+		return html
+	}
+	pairs := make([]string, 0, len(urls)*4)
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		next := rewrite.rewrite(u)
+		if next == u {
+			continue
+		}
+		pairs = append(pairs, `src="`+u+`"`, `src="`+next+`"`, `href="`+u+`"`, `href="`+next+`"`)
+	}
+	if len(pairs) == 0 {
+		return html
+	}
+	return strings.NewReplacer(pairs...).Replace(html)
+}
+
+// AssetURLs lists the script, stylesheet and chunk URLs of a page.
+func AssetURLs(a PageArtifacts) []string {
+	var urls []string
+	if a.Script != "" {
+		urls = append(urls, a.Script)
+	}
+	urls = append(urls, StylesheetHrefsFor(a)...)
+	return append(urls, a.Chunks...)
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func prefixApp(url string) string { return "/app" + url }
+
+func TestHTMLDocumentShellRewriteLinks(t *testing.T) {
+	shell, err := NewHTMLDocumentShell("/dist/home-entry.abc123.js", "body{color:red}", []string{"/dist/home-entry.css"}, []string{"/dist/chunk-1.js"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := map[string]any{"asset": "/dist/home-entry.abc123.js"}
+	html, err := shell.RewriteLinks(prefixApp).Render("<p>hi</p>", props, "", "en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<script src="/app/dist/home-entry.abc123.js" type="module" defer>`,
+		`<link rel="modulepreload" href="/app/dist/home-entry.abc123.js" />`,
+		`<link rel="stylesheet" href="/app/dist/home-entry.css" />`,
+		`<script src="/app/dist/chunk-1.js" type="module" defer>`,
+		`<link rel="modulepreload" href="/app/dist/chunk-1.js" />`,
+		`<style data-bifrost-critical>body{color:red}</style>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in:\n%s", want, html)
+		}
+	}
+	if !strings.Contains(html, `{"asset":"/dist/home-entry.abc123.js"}`) {
+		t.Errorf("props JSON should not be rewritten:\n%s", html)
+	}
+	if strings.Contains(html, `"/dist/chunk-1.js"`) {
+		t.Errorf("unrewritten chunk URL left in:\n%s", html)
+	}
+}
+
+func TestHTMLDocumentShellRewriteLinksIdentity(t *testing.T) {
+	shell, err := NewHTMLDocumentShell("/dist/a.js", "", []string{"/dist/a.css"}, []string{"/dist/c.js"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := shell.Render("", nil, "", "en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, rewrite := range map[string]LinkRewriter{
+		"nil":      nil,
+		"identity": func(url string) string { return url },
+	} {
+		got, err := shell.RewriteLinks(rewrite).Render("", nil, "", "en", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s rewriter changed HTML:\n%s\nwant:\n%s", name, got, want)
+		}
+	}
+}
+
+func TestRewriteHTMLAssetLinks(t *testing.T) {
+	in := `<link rel="stylesheet" href="/dist/a.css" /><script src="/dist/a.js" type="module"></script>` +
+		`<script id="__BIFROST_PROPS__" type="application/json">{"src":"/dist/a.js"}</script><a href="/dist/a.js.map">map</a>`
+	urls := AssetURLs(PageArtifacts{Script: "/dist/a.js", CSS: "/dist/a.css"})
+
+	got := RewriteHTMLAssetLinks(in, urls, prefixApp)
+	want := `<link rel="stylesheet" href="/app/dist/a.css" /><script src="/app/dist/a.js" type="module"></script>` +
+		`<script id="__BIFROST_PROPS__" type="application/json">{"src":"/dist/a.js"}</script><a href="/dist/a.js.map">map</a>`
+	if got != want {
+		t.Errorf("RewriteHTMLAssetLinks() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := RewriteHTMLAssetLinks(in, urls, func(url string) string { return url }); got != in {
+		t.Errorf("identity rewriter changed HTML:\n%s", got)
+	}
+}
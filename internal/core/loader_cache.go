@@ -0,0 +1,26 @@
+package core
+
+import (
+	"net/http"
+	"time"
+)
+
+// LoaderCache is a page's WithLoaderCache setting.
+type LoaderCache struct {
+	TTL time.Duration
+	Key func(*http.Request) string
+}
+
+// WithLoaderCache reuses the page's loader result for ttl across requests whose keyFn
+// returns the same key. Errors are never cached, and an empty key skips the cache for that
+// request. App.InvalidateLoaderCache drops a key early. Each request gets a shallow copy of
+// the cached props, so nested maps and slices are shared and must be treated as read-only.
+func WithLoaderCache(ttl time.Duration, keyFn func(*http.Request) string) PageOption {
+	return func(c *PageConfig) {
+		if ttl <= 0 || keyFn == nil {
+			c.LoaderCache = nil
+			return
+		}
+		c.LoaderCache = &LoaderCache{TTL: ttl, Key: keyFn}
+	}
+}
@@ -0,0 +1,12 @@
+package core
+
+// WithLoadingShell answers SSR requests with the Loading export of loadingComponent as the
+// page body, and renders the page in the browser over it once the client bundle has
+// loaded. The loader still runs and its props are sent with the page, and Head still
+// renders on the server; the skeleton gets no props, so it must not need data. Other page
+// modes ignore it.
+func WithLoadingShell(loadingComponent string) PageOption {
+	return func(c *PageConfig) {
+		c.LoadingShell = loadingComponent
+	}
+}
@@ -2,34 +2,138 @@ package core
 
 import (
 	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
 )
 
+// ManifestVersion is the manifest.json schema written by this version of bifrost-build.
+// Production rejects manifests without a version unless WithManifestVersion(0) is set;
+// ParseManifest, used by dev, static export and the tools, upgrades them.
+const ManifestVersion = 1
+
 type ManifestEntry struct {
-	Script       string            `json:"script"`
-	CriticalCSS  string            `json:"criticalCSS,omitempty"`
-	CSS          string            `json:"css,omitempty"`
-	CSSFiles     []string          `json:"cssFiles,omitempty"`
-	Chunks       []string          `json:"chunks,omitempty"`
-	Static       bool              `json:"static,omitempty"`
-	SSR          string            `json:"ssr,omitempty"`
+	Script      string   `json:"script"`
+	CriticalCSS string   `json:"criticalCSS,omitempty"`
+	CSS         string   `json:"css,omitempty"`
+	CSSFiles    []string `json:"cssFiles,omitempty"`
+	Chunks      []string `json:"chunks,omitempty"`
+	Static      bool     `json:"static,omitempty"`
+	SSR         string   `json:"ssr,omitempty"`
+	// SSRChunks lists the files next to SSR that the bundle may import; they are extracted
+	// with it in production.
+	SSRChunks    []string          `json:"ssrChunks,omitempty"`
 	Mode         string            `json:"mode,omitempty"`
 	HTML         string            `json:"html,omitempty"`
 	StaticRoutes map[string]string `json:"staticRoutes,omitempty"`
+	Revalidate   bool              `json:"revalidate,omitempty"`
+	// Preview marks a static page that keeps its SSR bundle for WithPreview renders.
+	Preview bool `json:"preview,omitempty"`
+	// NoJS marks a WithStaticNoJS page, which has stylesheets but no Script.
+	NoJS bool `json:"noJS,omitempty"`
+	// LoadingShell is the WithLoadingShell component of an SSR page.
+	LoadingShell string `json:"loadingShell,omitempty"`
+	// IntegrityHash maps each script, stylesheet and chunk URL to the hex SHA-256 of the
+	// built file, checked at startup by WithAssetIntegrity.
+	IntegrityHash map[string]string `json:"integrityHash,omitempty"`
+	// SRI maps the same URLs to their Subresource Integrity value when WithSRI is used.
+	SRI map[string]string `json:"sri,omitempty"`
+	// Target is the WithBuildTarget the client bundle was built for; empty means browser.
+	Target string `json:"target,omitempty"`
 }
 
 type Manifest struct {
+	Version int                      `json:"version"`
 	Entries map[string]ManifestEntry `json:"entries"`
 	Chunks  map[string]string        `json:"chunks,omitempty"`
+	// Environment is the WithEnvironment name the build was made for.
+	Environment string `json:"environment,omitempty"`
+	// BuiltAt is when bifrost-build wrote the manifest, used by CacheBustTimestamp.
+	BuiltAt time.Time `json:"builtAt,omitzero"`
+}
+
+// ManifestVersionError reports a manifest written by a newer bifrost-build than the
+// running binary understands, or one older than a production app accepts.
+type ManifestVersionError struct {
+	Version int
+	// MinVersion is set when the manifest is too old rather than too new.
+	MinVersion int
+}
+
+func (e *ManifestVersionError) Error() string {
+	if e.Version < e.MinVersion {
+		return fmt.Sprintf("manifest.json has version %d but this app requires version %d or later; "+
+			"rebuild required: run bifrost-build and embed the new .bifrost output", e.Version, e.MinVersion)
+	}
+	return fmt.Sprintf("manifest.json has version %d but this Bifrost reads up to version %d; "+
+		"the bifrost binary is too old: run bifrost-build from the same Bifrost version as the app and rebuild", e.Version, ManifestVersion)
 }
 
+// WithManifestVersion sets the oldest manifest.json version a production app starts with.
+// The default is ManifestVersion; 0 also accepts manifests from before versioning.
+func WithManifestVersion(version int) ConfigOption {
+	return func(c *Config) {
+		c.MinManifestVersion = &version
+	}
+}
+
+// ParseManifest decodes manifest.json and upgrades older schemas to ManifestVersion.
 func ParseManifest(data []byte) (*Manifest, error) {
+	m, err := decodeManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	upgradeManifest(m)
+	return m, nil
+}
+
+// ParseProdManifest is ParseManifest for the manifest a production app embeds. Manifests
+// older than minVersion are rejected so they get rebuilt instead of silently missing newer
+// fields; older ones that are still accepted are upgraded with a warning.
+func ParseProdManifest(data []byte, minVersion int) (*Manifest, error) {
+	m, err := decodeManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	if m.Version < minVersion {
+		return nil, &ManifestVersionError{Version: m.Version, MinVersion: minVersion}
+	}
+	if m.Version < ManifestVersion {
+		slog.Warn("bifrost: manifest.json is from an older bifrost-build; rebuild to use the current format, older versions will be rejected in a future release",
+			"version", m.Version, "current", ManifestVersion)
+	}
+	upgradeManifest(m)
+	return m, nil
+}
+
+func decodeManifest(data []byte) (*Manifest, error) {
 	var m Manifest
 	if err := json.Unmarshal(data, &m); err != nil {
 		return nil, err
 	}
+	if m.Version > ManifestVersion {
+		return nil, &ManifestVersionError{Version: m.Version}
+	}
+	if m.Version < 0 {
+		return nil, fmt.Errorf("manifest.json has invalid version %d", m.Version)
+	}
 	return &m, nil
 }
 
+// upgradeManifest migrates an unversioned manifest: entries from before page modes were
+// recorded are SSR pages when they have an SSR bundle.
+func upgradeManifest(m *Manifest) {
+	if m.Version == 0 {
+		for name, entry := range m.Entries {
+			if entry.Mode == "" && entry.SSR != "" {
+				entry.Mode = "ssr"
+				m.Entries[name] = entry
+			}
+		}
+	}
+	m.Version = ManifestVersion
+}
+
 type ClientBuildResult struct {
 	Script      string   `json:"script"`
 	CriticalCSS string   `json:"criticalCSS,omitempty"`
@@ -66,12 +170,27 @@ func GetAssets(man *Manifest, entryName string) Assets {
 	return ResolvePageArtifacts(man, entryName)
 }
 
+// HasSSREntries reports whether any entry renders at request time: SSR pages and static
+// pages that revalidate or can be previewed.
 func HasSSREntries(man *Manifest) bool {
 	if man == nil {
 		return false
 	}
 	for _, entry := range man.Entries {
-		if entry.Mode == "ssr" {
+		if entry.Mode == "ssr" || entry.Revalidate || entry.Preview {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPreviewEntries reports whether the build kept SSR bundles for WithPreview.
+func HasPreviewEntries(man *Manifest) bool {
+	if man == nil {
+		return false
+	}
+	for _, entry := range man.Entries {
+		if entry.Preview {
 			return true
 		}
 	}
@@ -2,6 +2,8 @@ package core
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -35,6 +37,22 @@ func TestHasSSREntries_StaticWithSSRBundle(t *testing.T) {
 	}
 }
 
+func TestHasSSREntries_StaticRevalidate(t *testing.T) {
+	man := &Manifest{
+		Entries: map[string]ManifestEntry{
+			"pages-pricing-entry": {
+				Script:     "/dist/pages-pricing-entry.js",
+				Mode:       "static",
+				SSR:        "/ssr/pages-pricing-entry-ssr.js",
+				Revalidate: true,
+			},
+		},
+	}
+	if !HasSSREntries(man) {
+		t.Error("expected HasSSREntries=true for revalidating static page")
+	}
+}
+
 func TestHasSSRBundles_StaticWithSSRBundle(t *testing.T) {
 	man := &Manifest{
 		Entries: map[string]ManifestEntry{
@@ -177,6 +195,70 @@ func TestParseManifest(t *testing.T) {
 	}
 }
 
+func TestParseManifest_UpgradesUnversioned(t *testing.T) {
+	raw := `{"entries": {"pages-home-entry": {"script": "/dist/home.js", "ssr": "/ssr/home-ssr.js"}}}`
+
+	man, err := ParseManifest([]byte(raw))
+	if err != nil {
+		t.Fatalf("ParseManifest failed: %v", err)
+	}
+	if man.Version != ManifestVersion {
+		t.Errorf("version = %d, want %d", man.Version, ManifestVersion)
+	}
+	if mode := man.Entries["pages-home-entry"].Mode; mode != "ssr" {
+		t.Errorf("mode = %q, want ssr for an entry with an SSR bundle", mode)
+	}
+	if !HasSSREntries(man) {
+		t.Error("upgraded manifest should report SSR entries")
+	}
+}
+
+func TestParseManifest_NewerVersion(t *testing.T) {
+	_, err := ParseManifest([]byte(`{"version": 99, "entries": {}}`))
+	var versionErr *ManifestVersionError
+	if !errors.As(err, &versionErr) || versionErr.Version != 99 {
+		t.Fatalf("err = %v, want ManifestVersionError for version 99", err)
+	}
+}
+
+func TestParseProdManifest(t *testing.T) {
+	unversioned := `{"entries": {"pages-home-entry": {"script": "/dist/home.js", "ssr": "/ssr/home-ssr.js"}}}`
+	current := `{"version": 1, "entries": {"pages-home-entry": {"script": "/dist/home.js", "mode": "ssr"}}}`
+	future := `{"version": 2, "entries": {}}`
+
+	tests := []struct {
+		name       string
+		raw        string
+		minVersion int
+		wantErr    string
+	}{
+		{name: "unversioned rejected", raw: unversioned, minVersion: ManifestVersion, wantErr: "rebuild required"},
+		{name: "unversioned allowed", raw: unversioned, minVersion: 0},
+		{name: "current", raw: current, minVersion: ManifestVersion},
+		{name: "future", raw: future, minVersion: ManifestVersion, wantErr: "bifrost binary is too old"},
+		{name: "future with lower minimum", raw: future, minVersion: 0, wantErr: "bifrost binary is too old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			man, err := ParseProdManifest([]byte(tt.raw), tt.minVersion)
+			if tt.wantErr != "" {
+				var versionErr *ManifestVersionError
+				if !errors.As(err, &versionErr) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want ManifestVersionError containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseProdManifest failed: %v", err)
+			}
+			if man.Version != ManifestVersion || man.Entries["pages-home-entry"].Mode != "ssr" {
+				t.Errorf("manifest = %+v, want an upgraded current manifest", man)
+			}
+		})
+	}
+}
+
 func TestParseManifest_Invalid(t *testing.T) {
 	_, err := ParseManifest([]byte("not json"))
 	if err == nil {
@@ -0,0 +1,31 @@
+package core
+
+import "fmt"
+
+// WithMaxPropsSize fails the page with 500 when the JSON of its server-rendered props is
+// larger than bytes. Zero or less means no limit.
+func WithMaxPropsSize(bytes int64) PageOption {
+	return func(c *PageConfig) {
+		c.MaxPropsSize = bytes
+	}
+}
+
+// PropsTooLargeError is returned when a page's props exceed its WithMaxPropsSize limit. It
+// carries only the sizes, never the props.
+type PropsTooLargeError struct {
+	Path  string
+	Size  int
+	Limit int64
+}
+
+func (e *PropsTooLargeError) Error() string {
+	return fmt.Sprintf("props too large for %s: %d bytes of JSON exceeds the %d byte limit", e.Path, e.Size, e.Limit)
+}
+
+// CheckPropsSize returns a PropsTooLargeError when propsJSON is longer than limit.
+func CheckPropsSize(path string, propsJSON []byte, limit int64) error {
+	if limit > 0 && int64(len(propsJSON)) > limit {
+		return &PropsTooLargeError{Path: path, Size: len(propsJSON), Limit: limit}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package core
+
+import "net/http"
+
+// PropMessages is the props key holding the message bundle injected by WithMessages.
+const PropMessages = "__messages"
+
+// MessagesLoader returns the locale and translation strings for a request.
+type MessagesLoader func(*http.Request) (locale string, messages map[string]string)
+
+func WithMessages(loader MessagesLoader) ConfigOption {
+	return func(c *Config) {
+		c.Messages = loader
+	}
+}
+
+// ApplyMessages adds the message bundle under PropMessages and, unless the loader already
+// chose one via PropHTMLLang, uses locale as the document language. props is not mutated.
+func ApplyMessages(props map[string]any, locale string, messages map[string]string) map[string]any {
+	out := make(map[string]any, len(props)+2)
+	for k, v := range props {
+		out[k] = v
+	}
+	if messages == nil {
+		messages = map[string]string{}
+	}
+	out[PropMessages] = messages
+	if _, ok := out[PropHTMLLang]; !ok && locale != "" {
+		out[PropHTMLLang] = locale
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package core
+
+import "testing"
+
+func TestApplyMessages(t *testing.T) {
+	tests := []struct {
+		name     string
+		props    map[string]any
+		locale   string
+		messages map[string]string
+		wantLang any
+	}{
+		{
+			name:     "nil props",
+			locale:   "de",
+			messages: map[string]string{"hi": "Hallo"},
+			wantLang: "de",
+		},
+		{
+			name:     "loader lang wins",
+			props:    map[string]any{PropHTMLLang: "fr"},
+			locale:   "de",
+			messages: map[string]string{"hi": "Hallo"},
+			wantLang: "fr",
+		},
+		{
+			name:     "empty locale leaves lang unset",
+			props:    map[string]any{"title": "x"},
+			wantLang: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := ApplyMessages(tt.props, tt.locale, tt.messages)
+			if got := out[PropHTMLLang]; got != tt.wantLang {
+				t.Errorf("%s = %v, want %v", PropHTMLLang, got, tt.wantLang)
+			}
+			msgs, ok := out[PropMessages].(map[string]string)
+			if !ok {
+				t.Fatalf("%s missing or wrong type: %T", PropMessages, out[PropMessages])
+			}
+			if len(msgs) != len(tt.messages) {
+				t.Errorf("messages = %v, want %v", msgs, tt.messages)
+			}
+			if _, ok := tt.props[PropMessages]; ok {
+				t.Error("input props were mutated")
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"html"
+	"strings"
+)
+
+// MetaTag renders one tag into the document head.
+type MetaTag interface {
+	HTML() string
+}
+
+type metaTag struct {
+	attr    string
+	key     string
+	content string
+}
+
+func (m metaTag) HTML() string {
+	return `<meta ` + m.attr + `="` + html.EscapeString(m.key) + `" content="` + html.EscapeString(m.content) + `" />`
+}
+
+func MetaName(name, content string) MetaTag {
+	return metaTag{attr: "name", key: name, content: content}
+}
+
+func MetaProperty(property, content string) MetaTag {
+	return metaTag{attr: "property", key: property, content: content}
+}
+
+func MetaHTTPEquiv(equiv, content string) MetaTag {
+	return metaTag{attr: "http-equiv", key: equiv, content: content}
+}
+
+// WithMetaTags adds tags to the head of every page, before the component's own head.
+func WithMetaTags(tags ...MetaTag) ConfigOption {
+	return func(c *Config) {
+		c.MetaTags = append(c.MetaTags, tags...)
+	}
+}
+
+func RenderMetaTags(tags []MetaTag) string {
+	var sb strings.Builder
+	for _, tag := range tags {
+		if tag == nil {
+			continue
+		}
+		sb.WriteString(tag.HTML())
+	}
+	return sb.String()
+}
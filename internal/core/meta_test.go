@@ -0,0 +1,49 @@
+package core
+
+import "testing"
+
+func TestRenderMetaTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []MetaTag
+		want string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name: "name property and http-equiv",
+			tags: []MetaTag{
+				MetaName("description", "Docs"),
+				MetaProperty("og:site_name", "Acme"),
+				MetaHTTPEquiv("x-ua-compatible", "IE=edge"),
+			},
+			want: `<meta name="description" content="Docs" />` +
+				`<meta property="og:site_name" content="Acme" />` +
+				`<meta http-equiv="x-ua-compatible" content="IE=edge" />`,
+		},
+		{
+			name: "escapes content and key",
+			tags: []MetaTag{MetaName(`a"b`, `"><script>alert(1)</script>`)},
+			want: `<meta name="a&#34;b" content="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;" />`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMetaTags(tt.tags); got != tt.want {
+				t.Errorf("RenderMetaTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMetaTagsAppends(t *testing.T) {
+	var cfg Config
+	WithMetaTags(MetaName("a", "1"))(&cfg)
+	WithMetaTags(MetaName("b", "2"))(&cfg)
+	if len(cfg.MetaTags) != 2 {
+		t.Fatalf("expected 2 tags, got %d", len(cfg.MetaTags))
+	}
+}
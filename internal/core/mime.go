@@ -1,6 +1,7 @@
 package core
 
 import (
+	"path"
 	"path/filepath"
 	"strings"
 )
@@ -20,6 +21,25 @@ var contentTypes = map[string]string{
 	".ttf":   "font/ttf",
 	".eot":   "application/vnd.ms-fontobject",
 	".ico":   "image/x-icon",
+	".txt":   "text/plain; charset=utf-8",
+}
+
+// wellKnownJSON lists extensionless /.well-known files that are JSON documents.
+var wellKnownJSON = map[string]bool{
+	"apple-app-site-association": true,
+}
+
+// PublicContentType is GetContentType for files served from public/. Extensionless files
+// under .well-known (apple-app-site-association, ACME challenge tokens) are JSON or plain
+// text rather than application/octet-stream.
+func PublicContentType(p string) string {
+	if filepath.Ext(p) != "" || !strings.HasPrefix(p, ".well-known/") {
+		return GetContentType(p)
+	}
+	if wellKnownJSON[path.Base(p)] {
+		return "application/json"
+	}
+	return "text/plain; charset=utf-8"
 }
 
 func GetContentType(p string) string {
@@ -36,3 +56,11 @@ func GetContentType(p string) string {
 	}
 	return "application/octet-stream"
 }
+
+// IsHTMLContentType reports whether ct is empty or text/html, ignoring parameters such
+// as charset.
+func IsHTMLContentType(ct string) bool {
+	mediaType, _, _ := strings.Cut(ct, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "" || strings.EqualFold(mediaType, "text/html")
+}
@@ -19,3 +19,44 @@ func TestGetContentType(t *testing.T) {
 		})
 	}
 }
+
+func TestPublicContentType(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{".well-known/apple-app-site-association", "application/json"},
+		{".well-known/acme-challenge/tok123", "text/plain; charset=utf-8"},
+		{".well-known/security.txt", "text/plain; charset=utf-8"},
+		{".well-known/assetlinks.json", "application/json"},
+		{"robots", "application/octet-stream"},
+		{"logo.svg", "image/svg+xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := PublicContentType(tt.path); got != tt.want {
+				t.Errorf("PublicContentType(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsHTMLContentType(t *testing.T) {
+	tests := []struct {
+		ct   string
+		want bool
+	}{
+		{"", true},
+		{"text/html", true},
+		{"text/html; charset=utf-8", true},
+		{"Text/HTML", true},
+		{"application/rss+xml", false},
+		{"image/svg+xml; charset=utf-8", false},
+		{"text/plain", false},
+	}
+	for _, tt := range tests {
+		if got := IsHTMLContentType(tt.ct); got != tt.want {
+			t.Errorf("IsHTMLContentType(%q) = %v, want %v", tt.ct, got, tt.want)
+		}
+	}
+}
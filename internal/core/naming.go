@@ -5,11 +5,16 @@ import (
 	"strings"
 )
 
+// dynamicSegmentReplacer keeps file-based route names ("[slug]", "[...path]") out of
+// generated entry file names and asset URLs.
+var dynamicSegmentReplacer = strings.NewReplacer("[...", "__", "[", "_", "]", "_")
+
 func EntryNameForPath(componentPath string) string {
 	name := strings.TrimPrefix(componentPath, "./")
 	name = strings.TrimPrefix(name, "/")
 	name = strings.ReplaceAll(filepath.ToSlash(name), "/", "-")
 	name = strings.TrimSuffix(name, filepath.Ext(name))
+	name = dynamicSegmentReplacer.Replace(name)
 	if name == "" {
 		return "page-entry"
 	}
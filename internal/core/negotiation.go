@@ -0,0 +1,83 @@
+package core
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Response formats of a WithContentNegotiation page.
+const (
+	FormatHTML = "html"
+	FormatJSON = "json"
+)
+
+// FormatQueryParam is the query parameter that picks a WithContentNegotiation page's
+// format regardless of Accept.
+const FormatQueryParam = "format"
+
+// WithContentNegotiation lets an SSR page answer API clients as well as browsers: a
+// request that prefers JSON gets the props loader's result as JSON instead of the
+// rendered page. See NegotiateFormat for how the format is chosen.
+func WithContentNegotiation() PageOption {
+	return func(c *PageConfig) {
+		c.Negotiate = true
+	}
+}
+
+// NegotiateFormat returns the format of a WithContentNegotiation page for req:
+//
+//  1. ?format=json or ?format=html, when present;
+//  2. FormatJSON when Accept ranks application/json above text/html, with ranges such
+//     as */* counting for types not listed;
+//  3. FormatHTML otherwise, including ties and requests without Accept.
+func NegotiateFormat(req *http.Request) string {
+	switch strings.ToLower(req.URL.Query().Get(FormatQueryParam)) {
+	case FormatJSON:
+		return FormatJSON
+	case FormatHTML:
+		return FormatHTML
+	}
+	accept := req.Header.Get("Accept")
+	if acceptQuality(accept, "application", "json") > acceptQuality(accept, "text", "html") {
+		return FormatJSON
+	}
+	return FormatHTML
+}
+
+// acceptQuality returns the q value accept gives typ/subtype: from the most specific
+// matching media range, or 0 when none matches.
+func acceptQuality(accept string, typ string, subtype string) float64 {
+	quality, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		rangeType, rangeSubtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mediaRange)), "/")
+		if !ok {
+			continue
+		}
+		var s int
+		switch {
+		case rangeType == typ && rangeSubtype == subtype:
+			s = 2
+		case rangeType == typ && rangeSubtype == "*":
+			s = 1
+		case rangeType == "*" && rangeSubtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+		quality, specificity = 1, s
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+	}
+	return quality
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		want   string
+	}{
+		{name: "no accept", target: "/posts", want: FormatHTML},
+		{name: "json only", target: "/posts", accept: "application/json", want: FormatJSON},
+		{name: "browser", target: "/posts", accept: "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", want: FormatHTML},
+		{name: "any", target: "/posts", accept: "*/*", want: FormatHTML},
+		{name: "json preferred", target: "/posts", accept: "text/html;q=0.5, application/json", want: FormatJSON},
+		{name: "json over wildcard", target: "/posts", accept: "application/json, */*;q=0.1", want: FormatJSON},
+		{name: "html preferred", target: "/posts", accept: "application/json;q=0.4, text/*", want: FormatHTML},
+		{name: "query json", target: "/posts?format=json", accept: "text/html", want: FormatJSON},
+		{name: "query html", target: "/posts?format=HTML", accept: "application/json", want: FormatHTML},
+		{name: "unknown query", target: "/posts?format=xml", accept: "application/json", want: FormatJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if got := NegotiateFormat(req); got != tt.want {
+				t.Errorf("NegotiateFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package core
+
+import "path/filepath"
+
+// NodeModulesPathEnv names the env var that gives the Bun runtime an extra node_modules
+// directory to resolve bare imports from.
+const NodeModulesPathEnv = "BIFROST_NODE_MODULES_PATH"
+
+// WithBunNodeModulesPath resolves bare imports that are not found next to the importing
+// file from path, a node_modules directory such as "../../node_modules" in a monorepo with
+// hoisted dependencies. Relative paths are taken from the working directory.
+func WithBunNodeModulesPath(path string) ConfigOption {
+	return func(c *Config) {
+		c.NodeModulesPath = path
+	}
+}
+
+// NodeModulesPathEnvEntry returns the NodeModulesPathEnv entry for the renderer with path
+// made absolute, or "" when path is empty.
+func NodeModulesPathEnvEntry(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return NodeModulesPathEnv + "=" + abs, nil
+}
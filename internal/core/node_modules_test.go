@@ -0,0 +1,21 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNodeModulesPathEnvEntry(t *testing.T) {
+	if got, err := NodeModulesPathEnvEntry(""); err != nil || got != "" {
+		t.Fatalf("empty path = %q, %v; want no entry", got, err)
+	}
+
+	got, err := NodeModulesPathEnvEntry("../../node_modules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := filepath.Abs("../../node_modules")
+	if got != NodeModulesPathEnv+"="+want {
+		t.Errorf("entry = %q, want %q", got, NodeModulesPathEnv+"="+want)
+	}
+}
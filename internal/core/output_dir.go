@@ -0,0 +1,9 @@
+package core
+
+// OutputDir is the directory bifrost-build writes to, relative to the project root, and the
+// root the app reads its build output from in the embedded assets FS. The app's
+// //go:embed directive must name the same directory.
+const OutputDir = ".bifrost"
+
+// ManifestPath is the embedded path of the build manifest.
+const ManifestPath = OutputDir + "/manifest.json"
@@ -0,0 +1,123 @@
+package core
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// PageCacheStore keeps the full HTML of rendered SSR pages, typically outside the process
+// so it survives restarts and is shared between instances. Set with a zero ttl stores the
+// page for the store's default lifetime.
+type PageCacheStore interface {
+	Get(key string) (html string, ok bool)
+	Set(key string, html string, ttl time.Duration)
+	Delete(key string)
+}
+
+// ErrNoPageCache is returned when invalidating a page cache entry of an app without
+// WithPageCache.
+var ErrNoPageCache = errors.New("bifrost: no page cache configured; use WithPageCache")
+
+// WithPageCache serves SSR pages from store when a page was already rendered with the same
+// props, skipping the renderer. The loader still runs, since its result is part of the key.
+func WithPageCache(store PageCacheStore) ConfigOption {
+	return func(c *Config) {
+		c.PageCache = store
+	}
+}
+
+// PageCacheKey is the WithPageCache key of a page: the hex SHA-256 of its component path
+// followed by its props JSON.
+func PageCacheKey(componentPath string, propsJSON []byte) string {
+	h := sha256.New()
+	h.Write([]byte(componentPath))
+	h.Write(propsJSON)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryPageCache is an in-process PageCacheStore that evicts the least recently used page
+// once it holds maxEntries.
+type MemoryPageCache struct {
+	maxEntries int
+	defaultTTL time.Duration
+	now        func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryPageCacheEntry struct {
+	key       string
+	html      string
+	expiresAt time.Time
+}
+
+// NewMemoryPageCache returns a MemoryPageCache of at most maxEntries pages (unbounded when
+// maxEntries is not positive) that keeps each page for defaultTTL unless Set is given a
+// ttl. A defaultTTL of zero keeps pages until they are evicted or deleted.
+func NewMemoryPageCache(maxEntries int, defaultTTL time.Duration) *MemoryPageCache {
+	return &MemoryPageCache{
+		maxEntries: maxEntries,
+		defaultTTL: defaultTTL,
+		now:        time.Now,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (c *MemoryPageCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry := elem.Value.(*memoryPageCacheEntry)
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return entry.html, true
+}
+
+func (c *MemoryPageCache) Set(key string, html string, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = c.defaultTTL
+	}
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*memoryPageCacheEntry)
+		entry.html = html
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&memoryPageCacheEntry{key: key, html: html, expiresAt: expiresAt})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryPageCacheEntry).key)
+	}
+}
+
+func (c *MemoryPageCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
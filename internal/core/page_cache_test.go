@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryPageCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryPageCache(2, 0)
+	cache.Set("a", "<p>a</p>", 0)
+	cache.Set("b", "<p>b</p>", 0)
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("a missing before eviction")
+	}
+	cache.Set("c", "<p>c</p>", 0)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b kept, want the least recently used entry evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("%s evicted", key)
+		}
+	}
+}
+
+func TestMemoryPageCacheExpiresEntries(t *testing.T) {
+	now := time.Unix(0, 0)
+	cache := NewMemoryPageCache(0, time.Minute)
+	cache.now = func() time.Time { return now }
+	cache.Set("default", "<p>default</p>", 0)
+	cache.Set("short", "<p>short</p>", time.Second)
+
+	now = now.Add(2 * time.Second)
+	if _, ok := cache.Get("short"); ok {
+		t.Error("entry served after its own ttl")
+	}
+	if html, ok := cache.Get("default"); !ok || html != "<p>default</p>" {
+		t.Errorf("Get(default) = %q, %v, want the entry within the default ttl", html, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.Get("default"); ok {
+		t.Error("entry served after the default ttl")
+	}
+}
+
+func TestPageCacheKeyCoversComponentAndProps(t *testing.T) {
+	key := PageCacheKey("./pages/home.tsx", []byte(`{"id":1}`))
+	if len(key) != 64 {
+		t.Errorf("key = %q, want hex SHA-256", key)
+	}
+	if key == PageCacheKey("./pages/home.tsx", []byte(`{"id":2}`)) {
+		t.Error("different props share a key")
+	}
+	if key == PageCacheKey("./pages/about.tsx", []byte(`{"id":1}`)) {
+		t.Error("different components share a key")
+	}
+}
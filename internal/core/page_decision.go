@@ -20,6 +20,8 @@ type PageRequest struct {
 	EntryName   string
 	StaticPath  string
 	HasRenderer bool
+	// Preview renders a static prerender page through SSR instead of serving its HTML.
+	Preview bool
 }
 
 type PageDecision struct {
@@ -58,6 +60,9 @@ func decideClientOnlyAction(req PageRequest, entry *ManifestEntry) PageDecision
 }
 
 func decideStaticPrerenderAction(req PageRequest, entry *ManifestEntry, normalizedPath string) PageDecision {
+	if req.Preview && req.HasRenderer && req.StaticPath != "" {
+		return PageDecision{Action: ActionRenderStaticPrerender}
+	}
 	if req.HasManifest && entry != nil {
 		if htmlPath, ok := LookupStaticRoute(entry, normalizedPath); ok {
 			return PageDecision{Action: ActionServeRouteFile, HTMLPath: htmlPath}
@@ -227,3 +227,23 @@ func TestMatchStaticRoute(t *testing.T) {
 		}
 	})
 }
+
+func TestDecidePageAction_ProdStaticPrerender_Preview(t *testing.T) {
+	entry := &ManifestEntry{StaticRoutes: map[string]string{"/": "/pages/routes/index.html"}}
+	req := PageRequest{
+		Mode:        ModeStaticPrerender,
+		RequestPath: "/",
+		HasManifest: true,
+		StaticPath:  "/tmp/ssr/pages-home-entry-ssr.js",
+		HasRenderer: true,
+		Preview:     true,
+	}
+	if decision := DecidePageAction(req, entry); decision.Action != ActionRenderStaticPrerender {
+		t.Errorf("expected ActionRenderStaticPrerender, got %d", decision.Action)
+	}
+
+	req.HasRenderer = false
+	if decision := DecidePageAction(req, entry); decision.Action != ActionServeRouteFile {
+		t.Errorf("without renderer: expected ActionServeRouteFile, got %d", decision.Action)
+	}
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"net/http"
+	"slices"
+)
+
+// WithHeaders sets static response headers on every response of the page: rendered and
+// prebuilt pages alike, and its error responses. They replace WithDefaultHeaders and
+// WithSecureHeaders values of the same name.
+func WithHeaders(headers http.Header) PageOption {
+	return func(c *PageConfig) {
+		c.Headers = MergeHeaders(c.Headers, headers)
+	}
+}
+
+// WithDefaultHeaders sets static response headers on every page response. Pages override
+// them by name with WithHeaders.
+func WithDefaultHeaders(headers http.Header) ConfigOption {
+	return func(c *Config) {
+		c.DefaultHeaders = MergeHeaders(c.DefaultHeaders, headers)
+	}
+}
+
+// MergeHeaders returns a copy of base with override applied on top: a header named in
+// override replaces all of its values in base. It returns nil when both are empty.
+func MergeHeaders(base, override http.Header) http.Header {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(http.Header, len(base)+len(override))
+	for name, values := range base {
+		merged[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+	}
+	for name, values := range override {
+		merged[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+	}
+	return merged
+}
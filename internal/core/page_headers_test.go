@@ -0,0 +1,22 @@
+package core
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMergeHeadersReplacesByName(t *testing.T) {
+	merged := MergeHeaders(
+		http.Header{"Link": {"</a.css>; rel=preload", "</b.css>; rel=preload"}, "X-Robots-Tag": {"all"}},
+		http.Header{"link": {"</c.css>; rel=preload"}},
+	)
+	if got := merged.Values("Link"); len(got) != 1 || got[0] != "</c.css>; rel=preload" {
+		t.Errorf("Link = %q, want the page value only", got)
+	}
+	if merged.Get("X-Robots-Tag") != "all" {
+		t.Errorf("X-Robots-Tag = %q, want the default kept", merged.Get("X-Robots-Tag"))
+	}
+	if MergeHeaders(nil, nil) != nil {
+		t.Error("MergeHeaders(nil, nil) should be nil")
+	}
+}
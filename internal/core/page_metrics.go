@@ -0,0 +1,31 @@
+package core
+
+import "time"
+
+// WithPageMetrics records per-route request counts and durations in memory; read them with
+// App.Metrics or on the WithDiagnostics page.
+func WithPageMetrics() ConfigOption {
+	return func(c *Config) {
+		c.PageMetrics = true
+	}
+}
+
+// RouteMetrics summarizes the page requests served by one route pattern. Percentiles are
+// estimated from a uniform sample of every request since the last reset.
+type RouteMetrics struct {
+	Count int64
+	Total time.Duration
+	Min   time.Duration
+	Max   time.Duration
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+// Avg returns the mean request duration, or 0 before the first request.
+func (m RouteMetrics) Avg() time.Duration {
+	if m.Count == 0 {
+		return 0
+	}
+	return m.Total / time.Duration(m.Count)
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// PageTimeouts bounds the stages of a page request. Zero fields are not enforced,
+// except Render, which falls back to the SSR timeout.
+type PageTimeouts struct {
+	// Loader bounds the page's props loader.
+	Loader time.Duration
+	// Render bounds the Bun render.
+	Render time.Duration
+	// Total bounds the whole request: loader, render and writing the HTML.
+	Total time.Duration
+}
+
+// WithTimeouts sets loader, render and total timeouts for every page. A timeout in
+// any stage answers with 503 Service Unavailable.
+func WithTimeouts(t PageTimeouts) ConfigOption {
+	return func(c *Config) {
+		c.Timeouts = t
+	}
+}
+
+// LoaderTimeoutError is returned when a props loader does not finish within PageTimeouts.Loader.
+type LoaderTimeoutError struct {
+	Path    string
+	Timeout time.Duration
+}
+
+func (e *LoaderTimeoutError) Error() string {
+	return fmt.Sprintf("loader for %s exceeded the %s loader timeout", e.Path, e.Timeout)
+}
+
+func (e *LoaderTimeoutError) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
+
+// RequestTimeoutError is returned when a page request does not finish within PageTimeouts.Total.
+type RequestTimeoutError struct {
+	Path    string
+	Timeout time.Duration
+}
+
+func (e *RequestTimeoutError) Error() string {
+	return fmt.Sprintf("request for %s exceeded the %s total timeout", e.Path, e.Timeout)
+}
+
+func (e *RequestTimeoutError) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
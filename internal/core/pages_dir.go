@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+var pageFileExts = map[string]bool{
+	".tsx": true,
+	".jsx": true,
+}
+
+// PagesFromFS walks fsys and returns one SSR route per page file. prefix is the pages
+// directory as the app sees it (e.g. "./pages") and is joined with each file to form
+// the component path. Files and directories starting with "_" or "." are skipped.
+func PagesFromFS(fsys fs.FS, prefix string) ([]Route, error) {
+	var routes []Route
+	hasRootCatchAll := false
+	rootIndex := -1
+
+	err := fs.WalkDir(fsys, ".", func(rel string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if rel != "." && (strings.HasPrefix(name, "_") || strings.HasPrefix(name, ".")) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !pageFileExts[path.Ext(name)] {
+			return nil
+		}
+
+		pattern, err := PagePattern(rel)
+		if err != nil {
+			return err
+		}
+		switch {
+		case pattern == "/":
+			rootIndex = len(routes)
+		case !strings.Contains(strings.TrimPrefix(pattern, "/"), "/") && strings.HasSuffix(pattern, "...}"):
+			hasRootCatchAll = true
+		}
+		routes = append(routes, Page(pattern, pageComponentPath(prefix, rel)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// "/" already matches every path on http.ServeMux and would conflict with a root
+	// catch-all, so the index page only takes the exact root in that case.
+	if hasRootCatchAll && rootIndex >= 0 {
+		routes[rootIndex].Pattern = "/{$}"
+	}
+	return routes, nil
+}
+
+// PagePattern translates a page file path relative to the pages directory into a route
+// pattern: "about.tsx" → "/about", "blog/[slug].tsx" → "/blog/{slug}",
+// "docs/[...path].tsx" → "/docs/{path...}" and "index.tsx" → "/".
+func PagePattern(rel string) (string, error) {
+	rel = filepath.ToSlash(rel)
+	segments := strings.Split(strings.TrimSuffix(rel, path.Ext(rel)), "/")
+	if segments[len(segments)-1] == "index" {
+		segments = segments[:len(segments)-1]
+	}
+
+	for i, seg := range segments {
+		if !strings.ContainsAny(seg, "[]") {
+			continue
+		}
+		if !strings.HasPrefix(seg, "[") || !strings.HasSuffix(seg, "]") {
+			return "", fmt.Errorf("page %s: dynamic segment %q must be the whole path segment", rel, seg)
+		}
+		param := seg[1 : len(seg)-1]
+		wildcard := strings.HasPrefix(param, "...")
+		param = strings.TrimPrefix(param, "...")
+		if param == "" || strings.ContainsAny(param, "[]./") {
+			return "", fmt.Errorf("page %s: invalid parameter name in %q", rel, seg)
+		}
+		if wildcard {
+			if i != len(segments)-1 {
+				return "", fmt.Errorf("page %s: catch-all %q must be the last segment", rel, seg)
+			}
+			segments[i] = "{" + param + "...}"
+			continue
+		}
+		segments[i] = "{" + param + "}"
+	}
+
+	return "/" + strings.Join(segments, "/"), nil
+}
+
+func pageComponentPath(prefix, rel string) string {
+	prefix = filepath.ToSlash(prefix)
+	joined := path.Join(prefix, rel)
+	if strings.HasPrefix(prefix, "./") || prefix == "." {
+		return "./" + joined
+	}
+	return joined
+}
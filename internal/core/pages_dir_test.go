@@ -0,0 +1,107 @@
+package core
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestPagePattern(t *testing.T) {
+	tests := []struct {
+		rel     string
+		want    string
+		wantErr bool
+	}{
+		{rel: "index.tsx", want: "/"},
+		{rel: "about.tsx", want: "/about"},
+		{rel: "blog/index.tsx", want: "/blog"},
+		{rel: "blog/[slug].tsx", want: "/blog/{slug}"},
+		{rel: "users/[id]/posts.tsx", want: "/users/{id}/posts"},
+		{rel: "docs/[...path].tsx", want: "/docs/{path...}"},
+		{rel: "docs/[...path]/edit.tsx", wantErr: true},
+		{rel: "blog/post-[slug].tsx", wantErr: true},
+		{rel: "blog/[].tsx", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			got, err := PagePattern(tt.rel)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("PagePattern(%q) = %q, want error", tt.rel, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PagePattern(%q) error = %v", tt.rel, err)
+			}
+			if got != tt.want {
+				t.Errorf("PagePattern(%q) = %q, want %q", tt.rel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPagesFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.tsx":              {},
+		"about.tsx":              {},
+		"blog/[slug].tsx":        {},
+		"docs/[...path].tsx":     {},
+		"_components/Button.tsx": {},
+		"_layout.tsx":            {},
+		"styles.css":             {},
+		"data/posts.json":        {},
+	}
+
+	routes, err := PagesFromFS(fsys, "./pages")
+	if err != nil {
+		t.Fatalf("PagesFromFS() error = %v", err)
+	}
+
+	want := map[string]string{
+		"/":               "./pages/index.tsx",
+		"/about":          "./pages/about.tsx",
+		"/blog/{slug}":    "./pages/blog/[slug].tsx",
+		"/docs/{path...}": "./pages/docs/[...path].tsx",
+	}
+	if len(routes) != len(want) {
+		t.Fatalf("got %d routes, want %d: %+v", len(routes), len(want), routes)
+	}
+	for _, r := range routes {
+		if want[r.Pattern] != r.ComponentPath {
+			t.Errorf("route %q -> %q, want %q", r.Pattern, r.ComponentPath, want[r.Pattern])
+		}
+	}
+}
+
+func TestPagesFromFSRootCatchAll(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.tsx":     {},
+		"[...path].tsx": {},
+	}
+
+	routes, err := PagesFromFS(fsys, "pages")
+	if err != nil {
+		t.Fatalf("PagesFromFS() error = %v", err)
+	}
+	got := map[string]string{}
+	for _, r := range routes {
+		got[r.Pattern] = r.ComponentPath
+	}
+	if got["/{$}"] != "pages/index.tsx" || got["/{path...}"] != "pages/[...path].tsx" {
+		t.Errorf("unexpected routes: %v", got)
+	}
+}
+
+func TestEntryNameForDynamicPagePath(t *testing.T) {
+	tests := map[string]string{
+		"./pages/blog/[slug].tsx":    "pages-blog-_slug_-entry",
+		"./pages/docs/[...path].tsx": "pages-docs-__path_-entry",
+		"./pages/about.tsx":          "pages-about-entry",
+	}
+	for in, want := range tests {
+		if got := EntryNameForPath(in); got != want {
+			t.Errorf("EntryNameForPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -52,7 +52,7 @@ type EntryPaths struct {
 }
 
 func CalculateEntryPaths(componentPath string) EntryPaths {
-	entryDir := ".bifrost"
+	entryDir := OutputDir
 	outdir := filepath.Join(entryDir, "dist")
 	entryName := EntryNameForPath(componentPath)
 	entryPath := filepath.Join(entryDir, entryName+".tsx")
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PageDataPrefix is where WithPrefetch serves the props of SSR pages: the props of a page
+// at /blog/hello are at /__data/blog/hello.
+const PageDataPrefix = "/__data"
+
+// PrefetchCacheTTL is how long the prefetch script keeps a page's props.
+const PrefetchCacheTTL = 30 * time.Second
+
+// WithPrefetch serves the props of every SSR page as JSON under PageDataPrefix and adds an
+// inline script to SSR pages that prefetches them when a same-origin link is hovered,
+// focused or touched. Client code that navigates without a full page load reads the
+// cached props with window.__bifrostPrefetch.get(href), a Promise of the props or null.
+// Links with a data-no-prefetch attribute are skipped. Off by default.
+func WithPrefetch() ConfigOption {
+	return func(c *Config) {
+		c.Prefetch = true
+	}
+}
+
+// PageDataPattern returns the route pattern of the page data of a page registered under
+// pattern, keeping a method prefix such as "GET " in front.
+func PageDataPattern(pattern string) string {
+	return joinRoutePath(PageDataPrefix, pattern)
+}
+
+type pageDataKey struct{}
+
+// ContextWithPageData marks ctx as a request for a page's props rather than its HTML.
+func ContextWithPageData(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pageDataKey{}, true)
+}
+
+// IsPageDataRequest reports whether ctx belongs to a request under PageDataPrefix.
+func IsPageDataRequest(ctx context.Context) bool {
+	data, _ := ctx.Value(pageDataKey{}).(bool)
+	return data
+}
+
+const prefetchTemplate = `<script data-bifrost-prefetchBIFROST_NONCE>(function(){` +
+	`var p=BIFROST_DATA_PREFIX,t=BIFROST_TTL,c={};` +
+	`function k(h){var u=new URL(h,location.href);` +
+	`if(u.origin!==location.origin||u.pathname===p||u.pathname.indexOf(p+"/")===0)return null;return u.pathname+u.search;}` +
+	`function g(h){var x=k(h);if(x===null)return Promise.resolve(null);var e=c[x],n=Date.now();` +
+	`if(!e||n-e.t>t){e=c[x]={t:n,p:fetch(p+x,{headers:{Accept:"application/json"},credentials:"same-origin"})` +
+	`.then(function(r){return r.ok?r.json():null;}).catch(function(){return null;})};}return e.p;}` +
+	`function o(ev){var a=ev.target&&ev.target.closest&&ev.target.closest("a[href]");` +
+	`if(!a||a.hasAttribute("download")||a.hasAttribute("data-no-prefetch")||(a.target&&a.target!=="_self"))return;` +
+	`if(navigator.connection&&navigator.connection.saveData)return;g(a.href);}` +
+	`document.addEventListener("mouseover",o);document.addEventListener("focusin",o);` +
+	`document.addEventListener("touchstart",o,{passive:true});` +
+	`window.__bifrostPrefetch={get:g};` +
+	`})();</script>`
+
+// PrefetchScript returns the WithPrefetch inline script, with a CSP nonce on the tag when
+// nonce is set.
+func PrefetchScript(nonce string) string {
+	return strings.NewReplacer(
+		"BIFROST_NONCE", nonceAttr(nonce),
+		"BIFROST_DATA_PREFIX", inlineJSONString(PageDataPrefix),
+		"BIFROST_TTL", strconv.FormatInt(PrefetchCacheTTL.Milliseconds(), 10),
+	).Replace(prefetchTemplate)
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPrefetchScript(t *testing.T) {
+	script := PrefetchScript("abc")
+	for _, want := range []string{`<script data-bifrost-prefetch nonce="abc">`, `p="/__data"`, `t=30000`, `window.__bifrostPrefetch={get:g}`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Contains(PrefetchScript(""), "nonce") {
+		t.Error("script without a nonce has a nonce attribute")
+	}
+}
+
+func TestPageDataContext(t *testing.T) {
+	if IsPageDataRequest(context.Background()) {
+		t.Error("plain context is a page data request")
+	}
+	if !IsPageDataRequest(ContextWithPageData(context.Background())) {
+		t.Error("ContextWithPageData context is not a page data request")
+	}
+}
+
+func TestPageDataPattern(t *testing.T) {
+	tests := map[string]string{
+		"/":                "/__data/",
+		"/blog/{slug}":     "/__data/blog/{slug}",
+		"GET /blog/{slug}": "GET /__data/blog/{slug}",
+	}
+	for pattern, want := range tests {
+		if got := PageDataPattern(pattern); got != want {
+			t.Errorf("PageDataPattern(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"html"
+	"path"
+	"strings"
+)
+
+// WithStaticPreloadList adds <link rel="preload"> tags for paths to the head of every page
+// (SSR and static prerender), ahead of the stylesheets. The as attribute comes from the
+// file extension; see PreloadAs.
+func WithStaticPreloadList(paths []string) ConfigOption {
+	return func(c *Config) {
+		c.PreloadPaths = append(c.PreloadPaths, paths...)
+	}
+}
+
+var preloadAsByExt = map[string]string{
+	".avif":  "image",
+	".gif":   "image",
+	".ico":   "image",
+	".jpeg":  "image",
+	".jpg":   "image",
+	".png":   "image",
+	".svg":   "image",
+	".webp":  "image",
+	".css":   "style",
+	".js":    "script",
+	".mjs":   "script",
+	".otf":   "font",
+	".ttf":   "font",
+	".woff":  "font",
+	".woff2": "font",
+}
+
+// PreloadAs returns the preload as value for p: image, style, script or font by
+// extension, and fetch for anything else.
+func PreloadAs(p string) string {
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	if as, ok := preloadAsByExt[strings.ToLower(path.Ext(p))]; ok {
+		return as
+	}
+	return "fetch"
+}
+
+// RenderPreloadTags returns one <link rel="preload"> per path. Fonts and fetches are
+// requested in CORS mode, so they get crossorigin to match the later request.
+func RenderPreloadTags(paths []string) string {
+	var sb strings.Builder
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		as := PreloadAs(p)
+		sb.WriteString(`<link rel="preload" href="`)
+		sb.WriteString(html.EscapeString(p))
+		sb.WriteString(`" as="`)
+		sb.WriteString(as)
+		sb.WriteString(`"`)
+		if as == "font" || as == "fetch" {
+			sb.WriteString(" crossorigin")
+		}
+		sb.WriteString(" />")
+	}
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreloadAs(t *testing.T) {
+	tests := map[string]string{
+		"/public/hero.webp":      "image",
+		"/public/logo.SVG":       "image",
+		"/public/photo.jpg?w=80": "image",
+		"/dist/critical.css":     "style",
+		"/dist/app.js":           "script",
+		"/fonts/inter.woff2":     "font",
+		"/api/data.json":         "fetch",
+		"/public/no-extension":   "fetch",
+	}
+	for path, want := range tests {
+		if got := PreloadAs(path); got != want {
+			t.Errorf("PreloadAs(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRenderPreloadTags(t *testing.T) {
+	got := RenderPreloadTags([]string{"/public/hero.webp", "/dist/critical.css", "/data/menu.json", "", `/a"b.png`})
+	want := `<link rel="preload" href="/public/hero.webp" as="image" />` +
+		`<link rel="preload" href="/dist/critical.css" as="style" />` +
+		`<link rel="preload" href="/data/menu.json" as="fetch" crossorigin />` +
+		`<link rel="preload" href="/a&#34;b.png" as="image" />`
+	if got != want {
+		t.Errorf("RenderPreloadTags() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHTMLShellPreloadsBeforeStylesheets(t *testing.T) {
+	shell, err := NewHTMLDocumentShell("/dist/page.js", "", []string{"/dist/page.css"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewrite := LinkRewriter(func(u string) string { return "https://cdn.example.com" + u })
+	html, err := shell.WithPreloads([]string{"/public/hero.webp", "/dist/app.js"}).RewriteLinks(rewrite).Render("", nil, `<link rel="stylesheet" href="/head.css" />`, "en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hero := strings.Index(html, `<link rel="preload" href="https://cdn.example.com/public/hero.webp" as="image" />`)
+	script := strings.Index(html, `<link rel="preload" href="https://cdn.example.com/dist/app.js" as="script" />`)
+	stylesheet := strings.Index(html, `rel="stylesheet"`)
+	if hero < 0 || script < 0 {
+		t.Fatalf("expected both preload tags in:\n%s", html)
+	}
+	if stylesheet < 0 || hero > stylesheet || script > stylesheet {
+		t.Errorf("preload tags should come before the first stylesheet:\n%s", html)
+	}
+
+	plain, err := shell.Render("", nil, "", "en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain, `rel="preload"`) {
+		t.Errorf("shell without preloads should not write any:\n%s", plain)
+	}
+}
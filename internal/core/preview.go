@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"net/http"
+)
+
+// Preview query parameter and cookie read by PreviewToken.
+const (
+	PreviewParam  = "preview"
+	PreviewCookie = "bifrost_preview"
+)
+
+// WithPreview renders static prerender pages fresh through SSR, with their live static
+// data, for requests validate accepts. Other requests keep getting the prebuilt HTML.
+func WithPreview(validate func(*http.Request) bool) ConfigOption {
+	return func(c *Config) {
+		c.Preview = validate
+	}
+}
+
+// PreviewToken returns the ?preview= query parameter of req, or the bifrost_preview cookie
+// when the parameter is absent. validate functions typically compare or verify it.
+func PreviewToken(req *http.Request) string {
+	if token := req.URL.Query().Get(PreviewParam); token != "" {
+		return token
+	}
+	if cookie, err := req.Cookie(PreviewCookie); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+type previewKey struct{}
+
+func ContextWithPreview(ctx context.Context) context.Context {
+	return context.WithValue(ctx, previewKey{}, true)
+}
+
+// IsPreview reports whether ctx belongs to a preview render, so loaders can include drafts.
+func IsPreview(ctx context.Context) bool {
+	preview, _ := ctx.Value(previewKey{}).(bool)
+	return preview
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPreviewToken(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/blog/draft?preview=abc", nil)
+	req.AddCookie(&http.Cookie{Name: PreviewCookie, Value: "from-cookie"})
+	if got := PreviewToken(req); got != "abc" {
+		t.Errorf("query param: got %q, want %q", got, "abc")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/blog/draft", nil)
+	req.AddCookie(&http.Cookie{Name: PreviewCookie, Value: "from-cookie"})
+	if got := PreviewToken(req); got != "from-cookie" {
+		t.Errorf("cookie: got %q, want %q", got, "from-cookie")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/blog/draft", nil)
+	if got := PreviewToken(req); got != "" {
+		t.Errorf("no token: got %q", got)
+	}
+	if IsPreview(req.Context()) {
+		t.Error("plain request context should not be a preview")
+	}
+	if !IsPreview(ContextWithPreview(req.Context())) {
+		t.Error("ContextWithPreview should mark a preview")
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReactRuntimeEnvKey is the build env key WithReactRuntime sets, so components can read
+// the configured import source as process.env.BIFROST_REACT_RUNTIME.
+const ReactRuntimeEnvKey = "BIFROST_REACT_RUNTIME"
+
+// Default React imports of the generated entries.
+const (
+	reactImport       = "react"
+	reactClientImport = "react-dom/client"
+	reactServerImport = "react-dom/server"
+)
+
+// WithReactRuntime makes the generated SSR and client entries import React from
+// importSource instead of react and react-dom, for a React-compatible library such as
+// preact/compat. The entries import:
+//
+//   - importSource: the default export (createElement, Component) and Suspense
+//   - importSource + "/client": createRoot and hydrateRoot
+//   - importSource + "/server": renderToString and renderToReadableStream
+//
+// The source is not checked to export them; a missing export fails the Bun build of the
+// entry. Components that import "react" themselves still need a bundler alias, such as a
+// package.json dependency "react": "npm:@preact/compat".
+func WithReactRuntime(importSource string) ConfigOption {
+	return func(c *Config) {
+		c.ReactRuntime = importSource
+		if c.BuildEnv == nil {
+			c.BuildEnv = make(map[string]string, 1)
+		}
+		c.BuildEnv[ReactRuntimeEnvKey] = importSource
+	}
+}
+
+// ValidateReactRuntime checks that importSource can be written into an import statement.
+func ValidateReactRuntime(importSource string) error {
+	if importSource == "" || strings.HasSuffix(importSource, "/") || strings.ContainsAny(importSource, "\"'`\\ \t\r\n") {
+		return fmt.Errorf("invalid React runtime %q: want a module specifier such as preact/compat", importSource)
+	}
+	return nil
+}
+
+// RewriteReactImports points the react, react-dom/client and react-dom/server imports of
+// an entry at importSource and its /client and /server subpaths.
+func RewriteReactImports(entry string, importSource string) string {
+	return strings.NewReplacer(
+		`from "`+reactImport+`"`, `from "`+importSource+`"`,
+		`from "`+reactClientImport+`"`, `from "`+importSource+`/client"`,
+		`from "`+reactServerImport+`"`, `from "`+importSource+`/server"`,
+	).Replace(entry)
+}
+
+type reactRuntimeAdapter struct {
+	FrameworkAdapter
+	importSource string
+}
+
+func (a reactRuntimeAdapter) SSREntryTemplate() string {
+	return RewriteReactImports(a.FrameworkAdapter.SSREntryTemplate(), a.importSource)
+}
+
+func (a reactRuntimeAdapter) ClientEntryTemplate(mode PageMode, hydration HydrationStrategy) string {
+	return RewriteReactImports(a.FrameworkAdapter.ClientEntryTemplate(mode, hydration), a.importSource)
+}
+
+func (a reactRuntimeAdapter) ErrorBoundarySSREntryTemplate() string {
+	return RewriteReactImports(a.FrameworkAdapter.ErrorBoundarySSREntryTemplate(), a.importSource)
+}
+
+func (a reactRuntimeAdapter) ErrorBoundaryClientEntryTemplate(mode PageMode, hydration HydrationStrategy) string {
+	return RewriteReactImports(a.FrameworkAdapter.ErrorBoundaryClientEntryTemplate(mode, hydration), a.importSource)
+}
+
+func (a reactRuntimeAdapter) LoadingShellSSREntryTemplate() string {
+	return RewriteReactImports(a.FrameworkAdapter.LoadingShellSSREntryTemplate(), a.importSource)
+}
+
+func (a reactRuntimeAdapter) LoadingShellClientEntryTemplate(tmpl string) string {
+	return RewriteReactImports(a.FrameworkAdapter.LoadingShellClientEntryTemplate(tmpl), a.importSource)
+}
+
+func (a reactRuntimeAdapter) RuntimeImports() []string {
+	return []string{a.importSource, a.importSource + "/server", a.importSource + "/client"}
+}
+
+// WithReactRuntimeSource returns adapter with the React imports of its entry templates
+// rewritten to importSource, as WithReactRuntime describes. An empty importSource returns
+// adapter unchanged.
+func WithReactRuntimeSource(adapter FrameworkAdapter, importSource string) (FrameworkAdapter, error) {
+	if importSource == "" {
+		return adapter, nil
+	}
+	if err := ValidateReactRuntime(importSource); err != nil {
+		return nil, err
+	}
+	return reactRuntimeAdapter{FrameworkAdapter: adapter, importSource: importSource}, nil
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRewriteReactImports(t *testing.T) {
+	entry := `import React from "react";
+import { hydrateRoot } from "react-dom/client";
+import { renderToString } from "react-dom/server";
+import { Page } from "./pages/react.tsx";`
+
+	got := RewriteReactImports(entry, "preact/compat")
+	for _, want := range []string{
+		`import React from "preact/compat";`,
+		`import { hydrateRoot } from "preact/compat/client";`,
+		`import { renderToString } from "preact/compat/server";`,
+		`import { Page } from "./pages/react.tsx";`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rewritten entry missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestValidateReactRuntime(t *testing.T) {
+	for _, source := range []string{"preact/compat", "@scope/react-lite"} {
+		if err := ValidateReactRuntime(source); err != nil {
+			t.Errorf("ValidateReactRuntime(%q) = %v", source, err)
+		}
+	}
+	for _, source := range []string{"", "preact/", `preact"; alert(1); "`, "pre act"} {
+		if err := ValidateReactRuntime(source); err == nil {
+			t.Errorf("ValidateReactRuntime(%q) = nil, want an error", source)
+		}
+	}
+}
+
+func TestWithReactRuntimeSetsBuildEnv(t *testing.T) {
+	c := &Config{}
+	WithReactRuntime("preact/compat")(c)
+	if c.ReactRuntime != "preact/compat" || c.BuildEnv[ReactRuntimeEnvKey] != "preact/compat" {
+		t.Fatalf("config = %q, build env = %v", c.ReactRuntime, c.BuildEnv)
+	}
+}
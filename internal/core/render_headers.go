@@ -0,0 +1,44 @@
+package core
+
+import (
+	"maps"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// PropRequest is the props key that carries request data chosen with WithRenderHeaders to
+// the component, as {"headers": {"user-agent": "..."}}.
+const PropRequest = "__request"
+
+// WithRenderHeaders passes the named request headers to every SSR component under
+// PropRequest. Only the listed headers are forwarded, so cookies and credentials stay on
+// the server unless named explicitly.
+func WithRenderHeaders(headers ...string) ConfigOption {
+	return func(c *Config) {
+		for _, name := range headers {
+			if name = strings.TrimSpace(name); name != "" {
+				c.RenderHeaders = append(c.RenderHeaders, textproto.CanonicalMIMEHeaderKey(name))
+			}
+		}
+	}
+}
+
+// WithRequestProp returns props with PropRequest set to the names headers of req, keyed by
+// lower-case name; repeated headers are joined with ", ". Headers the request lacks are
+// left out. Without names props is returned as is.
+func WithRequestProp(props map[string]any, req *http.Request, names []string) map[string]any {
+	if len(names) == 0 || req == nil {
+		return props
+	}
+	headers := make(map[string]string, len(names))
+	for _, name := range names {
+		if values := req.Header.Values(name); len(values) > 0 {
+			headers[strings.ToLower(name)] = strings.Join(values, ", ")
+		}
+	}
+	out := make(map[string]any, len(props)+1)
+	maps.Copy(out, props)
+	out[PropRequest] = map[string]any{"headers": headers}
+	return out
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultSSRTimeout bounds a single SSR render when WithSSRTimeout is not set.
+const DefaultSSRTimeout = 30 * time.Second
+
+// RenderTimeoutError is returned when the renderer does not finish within the SSR timeout.
+type RenderTimeoutError struct {
+	Path    string
+	Timeout time.Duration
+}
+
+func (e *RenderTimeoutError) Error() string {
+	return fmt.Sprintf("ssr render of %s exceeded the %s render timeout", e.Path, e.Timeout)
+}
+
+func (e *RenderTimeoutError) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
+
+// WithSSRTimeout bounds every render of a page by the runtime, streamed or not; d <= 0
+// keeps DefaultSSRTimeout. A render that runs out of time fails with RenderTimeoutError.
+func WithSSRTimeout(d time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.SSRTimeout = d
+	}
+}
+
+// WithRenderRetries retries SSR renders up to n times when the runtime connection fails
+// (for example while Bun restarts). Errors returned by the renderer are not retried.
+func WithRenderRetries(n int) ConfigOption {
+	return func(c *Config) {
+		c.RenderRetries = n
+	}
+}
+
+// ResolveSSRTimeout returns d, or DefaultSSRTimeout when d is not positive.
+func ResolveSSRTimeout(d time.Duration) time.Duration {
+	if d <= 0 {
+		return DefaultSSRTimeout
+	}
+	return d
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RequestIDHeader is read by the request logger to tag log lines with request_id.
+const RequestIDHeader = "X-Request-Id"
+
+// WithLogger logs the Bun renderer's output through logger, one record per line with
+// source=bun, instead of copying it to the process's stdout and stderr.
+func WithLogger(logger *slog.Logger) ConfigOption {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+func WithRequestLogger(logger *slog.Logger) ConfigOption {
+	return func(c *Config) {
+		if logger == nil {
+			logger = slog.Default()
+		}
+		c.RequestLogger = logger
+	}
+}
+
+type loggerKey struct{}
+
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger returns the per-request logger installed by WithRequestLogger, or slog.Default().
+func Logger(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return slog.Default()
+}
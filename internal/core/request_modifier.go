@@ -0,0 +1,17 @@
+package core
+
+import "net/http"
+
+// RequestModifier returns the request the app should serve instead of r. It must not
+// mutate r: return r.WithContext(...) or r.Clone(...) with the changes.
+type RequestModifier func(r *http.Request) *http.Request
+
+// WithRequestModifier runs mod on every request before route matching, so pages, loaders
+// and the router see the request it returns. Modifiers run in the order they were added,
+// each getting the previous one's result. A modifier that panics or returns nil fails the
+// request with 500.
+func WithRequestModifier(mod RequestModifier) ConfigOption {
+	return func(c *Config) {
+		c.RequestModifiers = append(c.RequestModifiers, mod)
+	}
+}
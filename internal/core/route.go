@@ -24,3 +24,27 @@ func PageConfigFromRoute(route Route) PageConfig {
 	}
 	return config
 }
+
+// RouteInfo is a read-only summary of a registered page route.
+type RouteInfo struct {
+	Pattern       string
+	ComponentPath string
+	// Mode is "ssr", "client" or "static".
+	Mode          string
+	HasLoader     bool
+	HasStaticData bool
+	// Group is the WithPageGroup name, or "" when the page has none.
+	Group string
+}
+
+func RouteInfoFor(route Route) RouteInfo {
+	config := PageConfigFromRoute(route)
+	return RouteInfo{
+		Pattern:       route.Pattern,
+		ComponentPath: route.ComponentPath,
+		Mode:          config.Mode.BuildLabel(),
+		HasLoader:     config.PropsLoader != nil,
+		HasStaticData: config.HasStaticData(),
+		Group:         config.Group,
+	}
+}
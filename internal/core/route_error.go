@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// RouteErrorHandler serves the error page for pages whose path matches Pattern.
+type RouteErrorHandler struct {
+	Pattern string
+	Handler http.Handler
+}
+
+// WithCustomRouteError serves 5xx page errors for paths matching pattern with handler
+// instead of the built-in error page. pattern uses http.ServeMux syntax; a trailing "/*"
+// is accepted as shorthand for the "/" subtree match. When several patterns match, the
+// most specific wins, as in ServeMux. The handler reads the error with RouteError.
+func WithCustomRouteError(pattern string, handler http.Handler) ConfigOption {
+	return func(c *Config) {
+		c.RouteErrors = append(c.RouteErrors, RouteErrorHandler{Pattern: pattern, Handler: handler})
+	}
+}
+
+// RouteErrorMuxPattern converts a WithCustomRouteError pattern to http.ServeMux syntax.
+func RouteErrorMuxPattern(pattern string) string {
+	if trimmed, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return trimmed + "/"
+	}
+	return pattern
+}
+
+type routeErrorKey struct{}
+
+func ContextWithRouteError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, routeErrorKey{}, err)
+}
+
+// RouteErrorFromContext returns the page error stored in ctx, or nil.
+func RouteErrorFromContext(ctx context.Context) error {
+	err, _ := ctx.Value(routeErrorKey{}).(error)
+	return err
+}
+
+// RouteError returns the page error a WithCustomRouteError handler is serving, or nil.
+func RouteError(req *http.Request) error {
+	if req == nil {
+		return nil
+	}
+	return RouteErrorFromContext(req.Context())
+}
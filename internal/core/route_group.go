@@ -0,0 +1,59 @@
+package core
+
+import (
+	"slices"
+	"strings"
+)
+
+// RouteGroup registers pages that share options and, for nested groups, a base path.
+type RouteGroup struct {
+	basePath string
+	options  []PageOption
+	parent   *RouteGroup
+	routes   []Route
+}
+
+// Group returns a RouteGroup whose pages get opts before their own options.
+func Group(opts ...PageOption) *RouteGroup {
+	return &RouteGroup{options: slices.Clone(opts)}
+}
+
+// Group returns a nested group under basePath whose pages get g's options, then opts,
+// then their own. Its pages are also listed by g.Routes.
+func (g *RouteGroup) Group(basePath string, opts ...PageOption) *RouteGroup {
+	return &RouteGroup{
+		basePath: joinRoutePath(g.basePath, basePath),
+		options:  append(slices.Clone(g.options), opts...),
+		parent:   g,
+	}
+}
+
+// Page is Page with the group's base path before pattern and the group's options before
+// opts. Options run in order, so a page option that sets a value overrides the group's,
+// while WithInputValidation adds to the group's validators.
+func (g *RouteGroup) Page(pattern string, componentPath string, opts ...PageOption) Route {
+	route := Page(joinRoutePath(g.basePath, pattern), componentPath, append(slices.Clone(g.options), opts...)...)
+	for group := g; group != nil; group = group.parent {
+		group.routes = append(group.routes, route)
+	}
+	return route
+}
+
+// Routes returns every page registered on the group and its nested groups, in order.
+func (g *RouteGroup) Routes() []Route {
+	return slices.Clone(g.routes)
+}
+
+// joinRoutePath puts basePath in front of the path of pattern, keeping a method prefix
+// such as "GET " in front.
+func joinRoutePath(basePath string, pattern string) string {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if basePath == "" {
+		return pattern
+	}
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		return basePath + pattern
+	}
+	return method + " " + basePath + path
+}
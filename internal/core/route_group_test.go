@@ -0,0 +1,70 @@
+package core
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestRouteGroupNestsBasePathsAndOptions(t *testing.T) {
+	site := Group(WithHTMLLang("en"), WithPageGroup("site"))
+	home := site.Page("/", "./pages/home.tsx")
+	admin := site.Group("/admin", WithPageGroup("admin"), WithErrorBoundary("./pages/admin/_error.tsx"))
+	index := admin.Page("/", "./pages/admin/index.tsx")
+	users := admin.Page("GET /users", "./pages/admin/users.tsx", WithHTMLLang("de"))
+	reports := admin.Group("/reports/").Page("/daily", "./pages/admin/daily.tsx")
+
+	tests := []struct {
+		route         Route
+		pattern       string
+		lang          string
+		group         string
+		errorBoundary string
+	}{
+		{route: home, pattern: "/", lang: "en", group: "site"},
+		{route: index, pattern: "/admin/", lang: "en", group: "admin", errorBoundary: "./pages/admin/_error.tsx"},
+		{route: users, pattern: "GET /admin/users", lang: "de", group: "admin", errorBoundary: "./pages/admin/_error.tsx"},
+		{route: reports, pattern: "/admin/reports/daily", lang: "en", group: "admin", errorBoundary: "./pages/admin/_error.tsx"},
+	}
+	for _, tt := range tests {
+		config := PageConfigFromRoute(tt.route)
+		if tt.route.Pattern != tt.pattern {
+			t.Errorf("%s: pattern = %q, want %q", tt.route.ComponentPath, tt.route.Pattern, tt.pattern)
+		}
+		if config.HTMLLang != tt.lang || config.Group != tt.group || config.ErrorBoundary != tt.errorBoundary {
+			t.Errorf("%s: lang, group, boundary = %q, %q, %q, want %q, %q, %q", tt.route.ComponentPath,
+				config.HTMLLang, config.Group, config.ErrorBoundary, tt.lang, tt.group, tt.errorBoundary)
+		}
+	}
+
+	var patterns []string
+	for _, route := range site.Routes() {
+		patterns = append(patterns, route.Pattern)
+	}
+	if want := []string{"/", "/admin/", "GET /admin/users", "/admin/reports/daily"}; !slices.Equal(patterns, want) {
+		t.Errorf("site.Routes() = %v, want %v", patterns, want)
+	}
+	if got := len(admin.Routes()); got != 3 {
+		t.Errorf("len(admin.Routes()) = %d, want the 3 admin pages", got)
+	}
+}
+
+func TestRouteGroupPageOptionsOverrideGroup(t *testing.T) {
+	groupLoader := func(*http.Request) (map[string]any, error) { return map[string]any{"from": "group"}, nil }
+	pageLoader := func(*http.Request) (map[string]any, error) { return map[string]any{"from": "page"}, nil }
+	g := Group(WithLoader(groupLoader), WithStatic(), WithInputValidation(QueryValue("tab", OneOf("a", "b"))))
+
+	config := PageConfigFromRoute(g.Page("/about/{id}", "./pages/about.tsx",
+		WithLoader(pageLoader), WithClient(), WithInputValidation(PathValue("id", UUID()))))
+
+	props, _ := config.PropsLoader(nil)
+	if props["from"] != "page" {
+		t.Errorf("loader = %v, want the page's loader", props["from"])
+	}
+	if config.Mode != ModeClientOnly {
+		t.Errorf("mode = %v, want the page's mode", config.Mode)
+	}
+	if got := len(config.Validators); got != 2 {
+		t.Errorf("validators = %d, want the group's and the page's", got)
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"cmp"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// RouteIndexVersion is the version of the WithRouteIndex file format.
+const RouteIndexVersion = 1
+
+// RouteIndexFile is where WithRouteIndex writes the index under .bifrost/public, served at
+// /__bifrost/routes.json.
+const RouteIndexFile = "__bifrost/routes.json"
+
+// RouteIndex is the WithRouteIndex file: every path exported by a static page.
+type RouteIndex struct {
+	Version int               `json:"version"`
+	Routes  []RouteIndexEntry `json:"routes"`
+}
+
+// RouteIndexEntry is one exported path with the title and meta tags of its page.
+type RouteIndexEntry struct {
+	Path  string `json:"path"`
+	Title string `json:"title,omitempty"`
+	// Meta maps the name or property of each <meta> tag with content to its content.
+	Meta map[string]string `json:"meta,omitempty"`
+}
+
+// WithRouteIndex makes bifrost-build write /__bifrost/routes.json, listing every path of
+// the static pages with its title and meta tags, for client-side search or routing. SSR
+// and client-only pages are left out.
+func WithRouteIndex() ConfigOption {
+	return func(c *Config) {
+		c.RouteIndex = true
+	}
+}
+
+var (
+	metaTagPattern   = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	htmlAttrPattern  = regexp.MustCompile(`(?s)([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	headClosePattern = regexp.MustCompile(`(?i)</head\s*>`)
+)
+
+// RouteIndexEntryFromHTML returns the index entry of path from the head of its exported
+// page.
+func RouteIndexEntryFromHTML(path string, page string) RouteIndexEntry {
+	if loc := headClosePattern.FindStringIndex(page); loc != nil {
+		page = page[:loc[0]]
+	}
+	entry := RouteIndexEntry{Path: path}
+	if start, end, ok := findTitleText(page); ok {
+		entry.Title = strings.TrimSpace(html.UnescapeString(page[start:end]))
+	}
+	for _, tag := range metaTagPattern.FindAllString(page, -1) {
+		attrs := make(map[string]string)
+		for _, m := range htmlAttrPattern.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3])
+		}
+		name := cmp.Or(attrs["name"], attrs["property"])
+		content, ok := attrs["content"]
+		if name == "" || !ok {
+			continue
+		}
+		if entry.Meta == nil {
+			entry.Meta = make(map[string]string)
+		}
+		entry.Meta[name] = content
+	}
+	return entry
+}
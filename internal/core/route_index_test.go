@@ -0,0 +1,31 @@
+package core
+
+import "testing"
+
+func TestRouteIndexEntryFromHTML(t *testing.T) {
+	page := `<!doctype html><html><head>
+<meta charset="utf-8" />
+<title>Hello &amp; welcome</title>
+<meta name="description" content="First post" />
+<meta property='og:image' content='/og/hello.png'>
+<meta name="robots">
+</head><body><meta name="ignored" content="body" /></body></html>`
+
+	entry := RouteIndexEntryFromHTML("/blog/hello", page)
+	if entry.Path != "/blog/hello" || entry.Title != "Hello & welcome" {
+		t.Errorf("entry = %+v", entry)
+	}
+	want := map[string]string{"description": "First post", "og:image": "/og/hello.png"}
+	if len(entry.Meta) != len(want) {
+		t.Fatalf("meta = %v, want %v", entry.Meta, want)
+	}
+	for name, content := range want {
+		if entry.Meta[name] != content {
+			t.Errorf("meta %s = %q, want %q", name, entry.Meta[name], content)
+		}
+	}
+
+	if bare := RouteIndexEntryFromHTML("/", "<html><body>hi</body></html>"); bare.Title != "" || bare.Meta != nil {
+		t.Errorf("page without head = %+v", bare)
+	}
+}
@@ -0,0 +1,15 @@
+package core
+
+// Files bifrost-build --compress-runtime writes in place of the bifrost-renderer binary:
+// the gzipped binary and the hex SHA-256 of the uncompressed binary.
+const (
+	CompressedRuntimeSuffix = ".gz"
+	RuntimeChecksumSuffix   = ".sha256"
+)
+
+// Files bifrost-build --compress-ssr writes in place of each SSR bundle and chunk, named
+// after the bundle: the gzipped bundle and the hex SHA-256 of the uncompressed bundle.
+const (
+	CompressedSSRSuffix = ".gz"
+	SSRChecksumSuffix   = ".sha256"
+)
@@ -0,0 +1,25 @@
+package core
+
+import "io/fs"
+
+// RuntimeDataDirEnv names the env var that points the Bun runtime at extracted runtime data.
+const RuntimeDataDirEnv = "BIFROST_RUNTIME_DATA_DIR"
+
+// RuntimeData copies files under SrcPrefix in FS to DestPrefix inside the runtime data dir.
+type RuntimeData struct {
+	FS         fs.FS
+	SrcPrefix  string
+	DestPrefix string
+}
+
+// WithRuntimeData extracts files for SSR components to read at render time. It can be
+// given several times; each call adds a source.
+func WithRuntimeData(fsys fs.FS, srcPrefix, destPrefix string) ConfigOption {
+	return func(c *Config) {
+		c.RuntimeData = append(c.RuntimeData, RuntimeData{
+			FS:         fsys,
+			SrcPrefix:  srcPrefix,
+			DestPrefix: destPrefix,
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package core
+
+import "maps"
+
+// PropSlots is the props key holding the page's WithSlotInjection fragments, keyed by
+// slot name. It is only passed to the server render; the hydration props leave it out.
+const PropSlots = "__bifrost_slots__"
+
+// WithSlotInjection passes the trusted HTML content to the server render as
+// props.__bifrost_slots__[slot], for the component to render with dangerouslySetInnerHTML.
+// Call it once per slot; a later call for the same slot replaces the content.
+func WithSlotInjection(slot, content string) PageOption {
+	return func(c *PageConfig) {
+		if c.Slots == nil {
+			c.Slots = make(map[string]string)
+		}
+		c.Slots[slot] = content
+	}
+}
+
+// WithSlotsProp returns props with PropSlots set to slots, leaving the original map
+// untouched. No slots returns props as is.
+func WithSlotsProp(props map[string]any, slots map[string]string) map[string]any {
+	if len(slots) == 0 {
+		return props
+	}
+	out := make(map[string]any, len(props)+1)
+	maps.Copy(out, props)
+	out[PropSlots] = maps.Clone(slots)
+	return out
+}
+
+// withoutSlotsProp drops PropSlots from the props serialized for hydration.
+func withoutSlotsProp(props map[string]any) map[string]any {
+	if _, ok := props[PropSlots]; !ok {
+		return props
+	}
+	out := maps.Clone(props)
+	delete(out, PropSlots)
+	return out
+}
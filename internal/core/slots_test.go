@@ -0,0 +1,44 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithSlotsProp(t *testing.T) {
+	var config PageConfig
+	WithSlotInjection("topBanner", "<b>old</b>")(&config)
+	WithSlotInjection("topBanner", "<b>new</b>")(&config)
+	WithSlotInjection("footer", "<p>f</p>")(&config)
+
+	props := map[string]any{"title": "Home"}
+	got := WithSlotsProp(props, config.Slots)
+	slots, _ := got[PropSlots].(map[string]string)
+	if slots["topBanner"] != "<b>new</b>" || slots["footer"] != "<p>f</p>" || got["title"] != "Home" {
+		t.Errorf("WithSlotsProp() = %v", got)
+	}
+	if _, ok := props[PropSlots]; ok {
+		t.Error("WithSlotsProp must not modify the input map")
+	}
+	if got := WithSlotsProp(props, nil); len(got) != 1 {
+		t.Errorf("WithSlotsProp without slots = %v, want props unchanged", got)
+	}
+}
+
+func TestMarshalBifrostPropsJSONOmitsSlots(t *testing.T) {
+	data, err := MarshalBifrostPropsJSON(WithSlotsProp(map[string]any{"title": "Home"}, map[string]string{"topBanner": "<div>x</div>"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"title":"Home"}` {
+		t.Errorf("props JSON = %s, want slots left out", data)
+	}
+
+	data, err = MarshalBifrostPropsJSON(WithSlotsProp(nil, map[string]string{"topBanner": "<div>x</div>"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "topBanner") {
+		t.Errorf("props JSON = %s, want slots left out", data)
+	}
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"html"
+)
+
+// WithSRI makes bifrost-build record a sha384 Subresource Integrity hash for every
+// script, chunk and stylesheet, and adds integrity and crossorigin attributes to the
+// tags that load them. Assets served from another origin need CORS headers.
+func WithSRI() ConfigOption {
+	return func(c *Config) {
+		c.SRI = true
+	}
+}
+
+// SRIHash is the integrity attribute value for data, as stored in ManifestEntry.SRI.
+func SRIHash(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// SRIAttrs returns the integrity and crossorigin attributes for url, or "" when sri has
+// no hash for it.
+func SRIAttrs(sri map[string]string, url string) string {
+	hash, ok := sri[url]
+	if !ok || hash == "" {
+		return ""
+	}
+	return ` integrity="` + html.EscapeString(hash) + `" crossorigin="anonymous"`
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSRIHash(t *testing.T) {
+	// sha384 of the empty string, as printed by `openssl dgst -sha384 -binary | base64`.
+	if got, want := SRIHash(nil), "sha384-OLBgp1GsljhM2TJ+sbHjaiH9txEUvgdDTAzHv2P24donTt6/529l+9Ua0vFImLlb"; got != want {
+		t.Errorf("SRIHash = %q, want %q", got, want)
+	}
+}
+
+func TestHTMLDocumentShellWithIntegrity(t *testing.T) {
+	shell, err := NewHTMLDocumentShell("/dist/home.js", "", []string{"/dist/home.css"}, []string{"/dist/chunk-1.js"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sri := map[string]string{
+		"/dist/home.js":    "sha384-script",
+		"/dist/home.css":   "sha384-style",
+		"/dist/chunk-1.js": "sha384-chunk",
+	}
+	html, err := shell.WithIntegrity(sri).RewriteLinks(prefixApp).Render("", nil, "", "en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<link rel="stylesheet" href="/app/dist/home.css" integrity="sha384-style" crossorigin="anonymous" />`,
+		`<link rel="modulepreload" href="/app/dist/chunk-1.js" integrity="sha384-chunk" crossorigin="anonymous" />`,
+		`<link rel="modulepreload" href="/app/dist/home.js" integrity="sha384-script" crossorigin="anonymous" />`,
+		`<script src="/app/dist/chunk-1.js" type="module" integrity="sha384-chunk" crossorigin="anonymous" defer>`,
+		`<script src="/app/dist/home.js" type="module" integrity="sha384-script" crossorigin="anonymous" defer>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in:\n%s", want, html)
+		}
+	}
+}
+
+func TestHTMLDocumentShellWithoutIntegrity(t *testing.T) {
+	shell, err := NewHTMLDocumentShell("/dist/home.js", "", []string{"/dist/home.css"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, err := shell.WithIntegrity(map[string]string{"/dist/other.js": "sha384-x"}).Render("", nil, "", "en", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "integrity=") || strings.Contains(html, "crossorigin=") {
+		t.Errorf("unexpected integrity attributes for unhashed URLs:\n%s", html)
+	}
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// MissingSSRModuleError reports an SSR render that failed because the page's bundle imports
+// a file that is not on disk, usually a chunk that was not embedded or extracted with it.
+type MissingSSRModuleError struct {
+	Page string
+	File string
+	Err  error
+}
+
+func (e *MissingSSRModuleError) Error() string {
+	return fmt.Sprintf("page %s: SSR bundle imports missing file %s; rebuild with bifrost-build and embed every file under .bifrost/ssr", e.Page, e.File)
+}
+
+func (e *MissingSSRModuleError) Unwrap() error { return e.Err }
+
+var missingModulePattern = regexp.MustCompile(`(?:Cannot find module|Cannot find package|Could not resolve:?)\s+["']([^"']+)["']`)
+
+// WrapMissingSSRModule turns a Bun module-resolution error from rendering page into a
+// MissingSSRModuleError naming the file. Other errors are returned unchanged.
+func WrapMissingSSRModule(err error, page string) error {
+	if err == nil {
+		return nil
+	}
+	m := missingModulePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	return &MissingSSRModuleError{Page: page, File: m[1], Err: err}
+}
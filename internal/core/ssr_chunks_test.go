@@ -0,0 +1,34 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWrapMissingSSRModule(t *testing.T) {
+	bunErr := errors.New(`Cannot find module "./chunk-abc123.js" from "/tmp/bifrost-ssr-1/ssr/pages-home-entry-ssr.js"`)
+	err := WrapMissingSSRModule(bunErr, "./pages/home.tsx")
+
+	var missing *MissingSSRModuleError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected MissingSSRModuleError, got %T: %v", err, err)
+	}
+	if missing.File != "./chunk-abc123.js" || missing.Page != "./pages/home.tsx" {
+		t.Errorf("got %+v", missing)
+	}
+	if !strings.Contains(err.Error(), "./pages/home.tsx") || !strings.Contains(err.Error(), "./chunk-abc123.js") {
+		t.Errorf("error should name page and file: %v", err)
+	}
+	if !errors.Is(err, bunErr) {
+		t.Error("expected wrapped Bun error")
+	}
+
+	other := errors.New("TypeError: undefined is not a function")
+	if got := WrapMissingSSRModule(other, "./pages/home.tsx"); got != other {
+		t.Errorf("unrelated error changed: %v", got)
+	}
+	if WrapMissingSSRModule(nil, "./pages/home.tsx") != nil {
+		t.Error("nil error should stay nil")
+	}
+}
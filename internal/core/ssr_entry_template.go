@@ -0,0 +1,79 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// SSREntryTemplateData is the data a WithCustomSSREntryTemplate template is executed with.
+type SSREntryTemplateData struct {
+	// ComponentImport is the import path of the page component from the entry file.
+	ComponentImport string
+}
+
+// sampleComponentImport is the import used to validate a custom SSR entry template.
+const sampleComponentImport = "./pages/__bifrost_sample.tsx"
+
+// WithCustomSSREntryTemplate replaces the SSR entry file generated for each page with
+// tmpl, a text/template executed with SSREntryTemplateData. The entry must export the same
+// render(props, options) function as the built-in one. Pages with WithErrorBoundary keep
+// the built-in boundary entry.
+func WithCustomSSREntryTemplate(tmpl string) ConfigOption {
+	return func(c *Config) {
+		c.SSREntryTemplate = tmpl
+	}
+}
+
+// ExecuteSSREntryTemplate renders tmpl for the component at componentImport.
+func ExecuteSSREntryTemplate(tmpl, componentImport string) (string, error) {
+	t, err := template.New("ssr-entry").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid SSR entry template: %w", err)
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, SSREntryTemplateData{ComponentImport: componentImport}); err != nil {
+		return "", fmt.Errorf("invalid SSR entry template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// ValidateSSREntryTemplate executes tmpl with a sample component and checks that the
+// result imports it.
+func ValidateSSREntryTemplate(tmpl string) error {
+	out, err := ExecuteSSREntryTemplate(tmpl, sampleComponentImport)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(out, sampleComponentImport) {
+		return fmt.Errorf("invalid SSR entry template: it never uses {{.ComponentImport}}")
+	}
+	return nil
+}
+
+type customSSREntryAdapter struct {
+	FrameworkAdapter
+	ssrEntry string
+}
+
+func (a customSSREntryAdapter) SSREntryTemplate() string {
+	return a.ssrEntry
+}
+
+// WithSSREntryTemplate returns adapter with its SSR entry template replaced by tmpl. The
+// template is validated and executed once with COMPONENT_PATH as the import, the
+// placeholder the entry writers substitute. An empty tmpl returns adapter unchanged.
+func WithSSREntryTemplate(adapter FrameworkAdapter, tmpl string) (FrameworkAdapter, error) {
+	if tmpl == "" {
+		return adapter, nil
+	}
+	if err := ValidateSSREntryTemplate(tmpl); err != nil {
+		return nil, err
+	}
+	entry, err := ExecuteSSREntryTemplate(tmpl, "COMPONENT_PATH")
+	if err != nil {
+		return nil, err
+	}
+	return customSSREntryAdapter{FrameworkAdapter: adapter, ssrEntry: entry}, nil
+}
@@ -0,0 +1,29 @@
+package core
+
+import "errors"
+
+// ErrRendererUnavailable marks render errors where the Bun renderer could not be reached:
+// it is not running, crashed, or refused the connection.
+var ErrRendererUnavailable = errors.New("bifrost: renderer unavailable")
+
+// SSRFallbackMode controls what an SSR page serves when the renderer is unavailable.
+type SSRFallbackMode int
+
+const (
+	// FallbackError fails the request with 500.
+	FallbackError SSRFallbackMode = iota
+	// FallbackClientOnly serves the page's HTML shell with its props but no server-rendered
+	// markup; the client bundle renders the page in the browser.
+	FallbackClientOnly
+	// FallbackCachedOrError serves the last successful render of the page with the same
+	// props, or fails with 500 when there is none.
+	FallbackCachedOrError
+)
+
+// WithSSRFallback sets what HTML SSR pages serve when the renderer is unavailable. It does
+// not cover render errors thrown by the component or render timeouts.
+func WithSSRFallback(mode SSRFallbackMode) ConfigOption {
+	return func(c *Config) {
+		c.SSRFallback = mode
+	}
+}
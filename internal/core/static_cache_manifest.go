@@ -0,0 +1,23 @@
+package core
+
+// StaticCacheManifestFile is the file WithStaticCacheManifest writes under .bifrost/public,
+// served at /asset-cache-manifest.json.
+const StaticCacheManifestFile = "asset-cache-manifest.json"
+
+// AssetCacheEntry is one asset of the WithStaticCacheManifest file.
+type AssetCacheEntry struct {
+	URL string `json:"url"`
+	// Hash is the sha384 Subresource Integrity value of the built file, the same value
+	// ManifestEntry.SRI records with WithSRI.
+	Hash string `json:"hash"`
+}
+
+// WithStaticCacheManifest makes bifrost-build write asset-cache-manifest.json: every
+// script, stylesheet and chunk of the build with its hash, for a service worker to
+// precache. The file is written to .bifrost/public and served at
+// /asset-cache-manifest.json.
+func WithStaticCacheManifest() ConfigOption {
+	return func(c *Config) {
+		c.StaticCacheManifest = true
+	}
+}
@@ -0,0 +1,29 @@
+package core
+
+import "time"
+
+// DefaultStaticDataCacheTTL is how long dev reuses a page's StaticDataLoader result when
+// WithStaticDataCacheTTL is not set.
+const DefaultStaticDataCacheTTL = 5 * time.Second
+
+// WithStaticDataCacheTTL sets how long dev reuses a static page's StaticDataLoader result
+// across requests. A negative d disables the cache. Production never caches: static pages
+// are served from the build, and revalidation always reloads.
+func WithStaticDataCacheTTL(d time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.StaticDataCacheTTL = d
+	}
+}
+
+// ResolveStaticDataCacheTTL returns d, DefaultStaticDataCacheTTL when d is zero, or zero
+// (no caching) when d is negative.
+func ResolveStaticDataCacheTTL(d time.Duration) time.Duration {
+	switch {
+	case d < 0:
+		return 0
+	case d == 0:
+		return DefaultStaticDataCacheTTL
+	default:
+		return d
+	}
+}
@@ -0,0 +1,22 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveStaticDataCacheTTL(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want time.Duration
+	}{
+		{0, DefaultStaticDataCacheTTL},
+		{time.Minute, time.Minute},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		if got := ResolveStaticDataCacheTTL(tt.in); got != tt.want {
+			t.Errorf("ResolveStaticDataCacheTTL(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
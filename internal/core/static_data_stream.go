@@ -0,0 +1,43 @@
+package core
+
+import "context"
+
+// StaticDataStream produces a static page's paths one at a time by calling emit. It must
+// stop and return emit's error when emit fails.
+type StaticDataStream func(ctx context.Context, emit func(StaticPathData) error) error
+
+// WithStaticDataStream is WithStaticData for sites with too many paths to hold in memory:
+// the export renders and writes each path as the stream emits it.
+func WithStaticDataStream(stream StaticDataStream) PageOption {
+	return func(c *PageConfig) {
+		c.Mode = ModeStaticPrerender
+		c.StaticDataStream = stream
+	}
+}
+
+// HasStaticData reports whether the page lists its paths with WithStaticData or
+// WithStaticDataStream.
+func (c PageConfig) HasStaticData() bool {
+	return c.StaticDataLoader != nil || c.StaticDataStream != nil
+}
+
+// EachStaticPath calls fn for every path of the page's static data, streaming when the
+// page has a StaticDataStream. It stops at the first error from the loader or fn.
+func EachStaticPath(ctx context.Context, config PageConfig, fn func(StaticPathData) error) error {
+	if config.StaticDataStream != nil {
+		return config.StaticDataStream(ctx, fn)
+	}
+	if config.StaticDataLoader == nil {
+		return nil
+	}
+	entries, err := config.StaticDataLoader(ctx)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
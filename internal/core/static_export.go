@@ -0,0 +1,14 @@
+package core
+
+// ExportJobsEnv carries the bifrost-build --jobs value to the export process, where it
+// overrides WithConcurrentStaticExport.
+const ExportJobsEnv = "BIFROST_EXPORT_JOBS"
+
+// WithConcurrentStaticExport renders up to concurrency paths of a static page at once
+// during static export. Pages are still exported one after another, and each page's
+// routes are recorded in the order its data loader returned them. The default is 1.
+func WithConcurrentStaticExport(concurrency int) ConfigOption {
+	return func(c *Config) {
+		c.StaticExportConcurrency = concurrency
+	}
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"strconv"
+	"time"
+)
+
+// immutableMaxAge is one year, the longest max-age browsers honor.
+const immutableMaxAge = 365 * 24 * time.Hour
+
+// WithStaticFileMaxAge adds Cache-Control: public, max-age=N to public/ files.
+func WithStaticFileMaxAge(maxAge time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.StaticFileMaxAge = maxAge
+	}
+}
+
+// WithStaticFileImmutable marks public/ files as cacheable for a year and immutable, for
+// apps whose public file names change with their content.
+func WithStaticFileImmutable() ConfigOption {
+	return func(c *Config) {
+		c.StaticFileImmutable = true
+	}
+}
+
+// PublicCacheControl returns the Cache-Control value for public/ files, or "" when
+// neither WithStaticFileMaxAge nor WithStaticFileImmutable is set.
+func PublicCacheControl(c Config) string {
+	if c.StaticFileImmutable {
+		return "public, max-age=" + strconv.Itoa(int(immutableMaxAge.Seconds())) + ", immutable"
+	}
+	if c.StaticFileMaxAge <= 0 {
+		return ""
+	}
+	return "public, max-age=" + strconv.Itoa(int(c.StaticFileMaxAge.Seconds()))
+}
@@ -0,0 +1,21 @@
+package core
+
+// WithStaticNoJS prerenders the page like WithStatic but ships no JavaScript: the build
+// emits no client script for it, and the exported HTML has no script, modulepreload or
+// props tags, only the markup and its stylesheets. Use it for content pages without
+// interactivity; components still render on the server, but never hydrate.
+func WithStaticNoJS() PageOption {
+	return func(c *PageConfig) {
+		c.Mode = ModeStaticPrerender
+		c.NoJS = true
+	}
+}
+
+// NewPageShell returns the document shell for a page's artifacts: NewStaticHTMLShell for
+// WithStaticNoJS pages and NewHTMLDocumentShell for the rest.
+func NewPageShell(noJS bool, a PageArtifacts) (HTMLDocumentShell, error) {
+	if noJS {
+		return NewStaticHTMLShell(a.CriticalCSS, StylesheetHrefsFor(a)), nil
+	}
+	return NewHTMLDocumentShell(a.Script, a.CriticalCSS, StylesheetHrefsFor(a), a.Chunks)
+}
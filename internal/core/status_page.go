@@ -0,0 +1,41 @@
+package core
+
+import (
+	"context"
+	"net/http"
+)
+
+// WithStatusPage renders componentPath, as an SSR page, for page responses with status
+// code instead of the built-in error page: 404 when a page has nothing at the path, and
+// the status of a loader or render error (see StatusCodeError) otherwise. The component
+// gets statusCode and message props. Call it once per status code.
+func WithStatusPage(code int, componentPath string) ConfigOption {
+	return func(c *Config) {
+		if c.StatusPages == nil {
+			c.StatusPages = make(map[int]string)
+		}
+		c.StatusPages[code] = componentPath
+	}
+}
+
+// StatusPageInfo is what a WithStatusPage component is rendering.
+type StatusPageInfo struct {
+	StatusCode int
+	Message    string
+}
+
+type statusPageKey struct{}
+
+func ContextWithStatusPage(ctx context.Context, info StatusPageInfo) context.Context {
+	return context.WithValue(ctx, statusPageKey{}, info)
+}
+
+// StatusPageProps is the loader of WithStatusPage components: it returns the statusCode
+// and message stored in the request context.
+func StatusPageProps(req *http.Request) (map[string]any, error) {
+	info, _ := req.Context().Value(statusPageKey{}).(StatusPageInfo)
+	return map[string]any{
+		"statusCode": info.StatusCode,
+		"message":    info.Message,
+	}, nil
+}
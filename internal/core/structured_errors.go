@@ -0,0 +1,26 @@
+package core
+
+import "strings"
+
+// StructuredError is the JSON body written for errors when WithStructuredErrors is enabled.
+type StructuredError struct {
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+}
+
+// StructuredRedirect replaces a browser redirect for JSON requests.
+type StructuredRedirect struct {
+	Redirect string `json:"redirect"`
+	Code     int    `json:"code"`
+}
+
+func WithStructuredErrors(enabled bool) ConfigOption {
+	return func(c *Config) {
+		c.StructuredErrors = enabled
+	}
+}
+
+// AcceptsJSON reports whether an Accept header value asks for application/json.
+func AcceptsJSON(accept string) bool {
+	return strings.Contains(strings.ToLower(accept), "application/json")
+}
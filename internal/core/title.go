@@ -0,0 +1,90 @@
+package core
+
+import (
+	"html"
+	"strings"
+)
+
+// TitlePlaceholder marks where the page title goes in a title template.
+const TitlePlaceholder = "%s"
+
+// TitleConfig controls the document <title> for every page.
+type TitleConfig struct {
+	// Template wraps page titles, e.g. "%s | My App".
+	Template string
+	// Default is used for pages whose head has no <title>.
+	Default string
+}
+
+// WithTitleTemplate wraps every page-provided <title> with tmpl, replacing %s with the title.
+func WithTitleTemplate(tmpl string) ConfigOption {
+	return func(c *Config) {
+		c.Title.Template = tmpl
+	}
+}
+
+// WithDefaultTitle sets the <title> for pages that do not render one. Without it the
+// shell falls back to "Bifrost".
+func WithDefaultTitle(title string) ConfigOption {
+	return func(c *Config) {
+		c.Title.Default = title
+	}
+}
+
+// Apply rewrites headHTML so the first <title> is wrapped by the template, or prepends
+// the default title when the head has none. The page title is already HTML from the
+// renderer; the template and default are escaped.
+func (t TitleConfig) Apply(headHTML string) string {
+	if t == (TitleConfig{}) {
+		return headHTML
+	}
+	start, end, ok := findTitleText(headHTML)
+	if !ok {
+		if t.Default == "" {
+			return headHTML
+		}
+		return "<title>" + html.EscapeString(t.Default) + "</title>" + headHTML
+	}
+	if t.Template == "" {
+		return headHTML
+	}
+
+	before, after, found := strings.Cut(t.Template, TitlePlaceholder)
+	var sb strings.Builder
+	sb.WriteString(headHTML[:start])
+	sb.WriteString(html.EscapeString(before))
+	if found {
+		sb.WriteString(headHTML[start:end])
+		sb.WriteString(html.EscapeString(after))
+	}
+	sb.WriteString(headHTML[end:])
+	return sb.String()
+}
+
+// findTitleText returns the byte range of the first <title> element's text.
+func findTitleText(s string) (start, end int, ok bool) {
+	open := indexFold(s, "<title")
+	if open < 0 {
+		return 0, 0, false
+	}
+	gt := strings.IndexByte(s[open:], '>')
+	if gt < 0 {
+		return 0, 0, false
+	}
+	start = open + gt + 1
+	closeIdx := indexFold(s[start:], "</title")
+	if closeIdx < 0 {
+		return 0, 0, false
+	}
+	return start, start + closeIdx, true
+}
+
+func indexFold(s, substr string) int {
+	n := len(substr)
+	for i := 0; i+n <= len(s); i++ {
+		if strings.EqualFold(s[i:i+n], substr) {
+			return i
+		}
+	}
+	return -1
+}
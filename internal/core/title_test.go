@@ -0,0 +1,62 @@
+package core
+
+import "testing"
+
+func TestTitleConfigApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		title TitleConfig
+		head  string
+		want  string
+	}{
+		{
+			name: "no config leaves head untouched",
+			head: `<title>About</title>`,
+			want: `<title>About</title>`,
+		},
+		{
+			name:  "template wraps page title",
+			title: TitleConfig{Template: "%s | My App"},
+			head:  `<meta name="a" content="b" /><title>About</title>`,
+			want:  `<meta name="a" content="b" /><title>About | My App</title>`,
+		},
+		{
+			name:  "template keeps title attributes and case",
+			title: TitleConfig{Template: "%s - Docs"},
+			head:  `<TITLE data-rh="true">Guide</TITLE>`,
+			want:  `<TITLE data-rh="true">Guide - Docs</TITLE>`,
+		},
+		{
+			name:  "template is escaped",
+			title: TitleConfig{Template: "%s | A & B"},
+			head:  `<title>Tom &amp; Jerry</title>`,
+			want:  `<title>Tom &amp; Jerry | A &amp; B</title>`,
+		},
+		{
+			name:  "default title for head without one",
+			title: TitleConfig{Template: "%s | My App", Default: "My <App>"},
+			head:  `<meta name="a" content="b" />`,
+			want:  `<title>My &lt;App&gt;</title><meta name="a" content="b" />`,
+		},
+		{
+			name:  "default does not replace page title",
+			title: TitleConfig{Default: "My App"},
+			head:  `<title>About</title>`,
+			want:  `<title>About</title>`,
+		},
+		{
+			name:  "unterminated title is left alone",
+			title: TitleConfig{Template: "%s | My App"},
+			head:  `<title>About`,
+			want:  `<title>About`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.title.Apply(tt.head); got != tt.want {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// WithTrustProxy sets how many reverse proxies in front of the app append to
+// X-Forwarded-For. The client IP is the hops-th address from the right and replaces
+// r.RemoteAddr, and X-Forwarded-Proto sets r.URL.Scheme; 0 (the default) leaves the
+// request alone.
+func WithTrustProxy(hops int) ConfigOption {
+	return func(c *Config) {
+		if hops < 0 {
+			hops = 0
+		}
+		c.TrustProxyHops = hops
+	}
+}
+
+// WithTrustedProxies trusts forwarding headers only on requests whose immediate peer is in
+// one of cidrs (a bare IP is a single address). For those, the client IP from
+// X-Forwarded-For replaces r.RemoteAddr, X-Forwarded-Proto sets r.URL.Scheme and
+// X-Forwarded-Host sets r.Host. An invalid entry makes Wrap and Handler panic, and New
+// panics when WithTrustProxy is also set.
+func WithTrustedProxies(cidrs ...string) ConfigOption {
+	return func(c *Config) {
+		c.TrustedProxies = append(c.TrustedProxies, cidrs...)
+	}
+}
+
+// ValidateTrustProxy rejects WithTrustProxy together with WithTrustedProxies: counting
+// hops would read X-Forwarded-For from any peer and undo the trusted-peer check.
+func ValidateTrustProxy(hops int, cidrs []string) error {
+	if hops > 0 && len(cidrs) > 0 {
+		return fmt.Errorf("WithTrustProxy and WithTrustedProxies cannot be used together; use WithTrustedProxies alone")
+	}
+	return nil
+}
+
+// ParseTrustedProxies parses WithTrustedProxies entries as CIDR prefixes or single IPs.
+func ParseTrustedProxies(cidrs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if prefix, err := netip.ParsePrefix(cidr); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: want a CIDR such as 10.0.0.0/8 or an IP", cidr)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// IsTrustedProxy reports whether ip is in one of prefixes.
+func IsTrustedProxy(ip string, prefixes []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustedForwardedClientIP walks X-Forwarded-For from the right past trusted proxies and
+// returns the first untrusted address, or the leftmost one when every hop is trusted. It
+// returns "" when the header is missing or that address is not a valid IP.
+func TrustedForwardedClientIP(header http.Header, prefixes []netip.Prefix) string {
+	var addrs []string
+	for _, value := range header.Values("X-Forwarded-For") {
+		addrs = append(addrs, strings.Split(value, ",")...)
+	}
+	for i := len(addrs) - 1; i >= 0; i-- {
+		if i > 0 && IsTrustedProxy(addrs[i], prefixes) {
+			continue
+		}
+		ip := net.ParseIP(strings.TrimSpace(addrs[i]))
+		if ip == nil {
+			return ""
+		}
+		return ip.String()
+	}
+	return ""
+}
+
+// ForwardedClientIP returns the hops-th address from the right of X-Forwarded-For, or ""
+// when hops is 0, the header has fewer addresses, or the address is not a valid IP.
+func ForwardedClientIP(header http.Header, hops int) string {
+	if hops <= 0 {
+		return ""
+	}
+	var addrs []string
+	for _, value := range header.Values("X-Forwarded-For") {
+		addrs = append(addrs, strings.Split(value, ",")...)
+	}
+	if len(addrs) < hops {
+		return ""
+	}
+	ip := net.ParseIP(strings.TrimSpace(addrs[len(addrs)-hops]))
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+// ClientIP returns the client address of req without the port. Behind WithTrustProxy it
+// is the forwarded client IP.
+func ClientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
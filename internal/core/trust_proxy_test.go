@@ -0,0 +1,67 @@
+package core
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.10", "2001:db8::/32"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+	for ip, want := range map[string]bool{
+		"10.1.2.3":        true,
+		"192.0.2.10":      true,
+		"192.0.2.11":      false,
+		"::ffff:10.0.0.1": true,
+		"2001:db8::1":     true,
+		"203.0.113.9":     false,
+		"not-an-ip":       false,
+	} {
+		if got := IsTrustedProxy(ip, prefixes); got != want {
+			t.Errorf("IsTrustedProxy(%q) = %v, want %v", ip, got, want)
+		}
+	}
+
+	if _, err := ParseTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected an error for an invalid CIDR")
+	}
+}
+
+func TestTrustedForwardedClientIP(t *testing.T) {
+	prefixes, _ := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	tests := []struct {
+		forwarded string
+		want      string
+	}{
+		{"198.51.100.7, 203.0.113.9, 10.0.0.2", "203.0.113.9"},
+		{"198.51.100.7, 10.0.0.3, 10.0.0.2", "198.51.100.7"},
+		{"10.0.0.4, 10.0.0.2", "10.0.0.4"},
+		{"garbage, 10.0.0.2", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.forwarded != "" {
+			header.Set("X-Forwarded-For", tt.forwarded)
+		}
+		if got := TrustedForwardedClientIP(header, prefixes); got != tt.want {
+			t.Errorf("TrustedForwardedClientIP(%q) = %q, want %q", tt.forwarded, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTrustProxy(t *testing.T) {
+	if err := ValidateTrustProxy(1, []string{"10.0.0.0/8"}); err == nil {
+		t.Error("ValidateTrustProxy() accepted hops together with trusted proxies")
+	}
+	for _, tt := range []struct {
+		hops  int
+		cidrs []string
+	}{{1, nil}, {0, []string{"10.0.0.0/8"}}, {0, nil}} {
+		if err := ValidateTrustProxy(tt.hops, tt.cidrs); err != nil {
+			t.Errorf("ValidateTrustProxy(%d, %v) = %v", tt.hops, tt.cidrs, err)
+		}
+	}
+}
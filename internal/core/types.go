@@ -2,7 +2,9 @@ package core
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
+	"time"
 )
 
 type PropsLoader func(*http.Request) (map[string]any, error)
@@ -72,8 +74,22 @@ type PageConfig struct {
 	PropsLoader         PropsLoader
 	DeferredPropsLoader DeferredPropsLoader
 	StaticDataLoader    StaticDataLoader
+	StaticDataStream    StaticDataStream
 	HTMLLang            string
 	HTMLClass           string
+	Revalidate          time.Duration
+	Hydration           HydrationStrategy
+	ContentType         string
+	Group               string
+	ErrorBoundary       string
+	Slots               map[string]string
+	LoaderCache         *LoaderCache
+	Validators          []InputValidator
+	NoJS                bool
+	LoadingShell        string
+	Headers             http.Header
+	MaxPropsSize        int64
+	Negotiate           bool
 }
 
 type PageOption func(*PageConfig)
@@ -109,6 +125,31 @@ func WithStaticData(loader StaticDataLoader) PageOption {
 	}
 }
 
+// WithRevalidate regenerates a static prerender page in the background once its HTML is
+// older than ttl. Requests keep getting the stored HTML while it regenerates.
+func WithRevalidate(ttl time.Duration) PageOption {
+	return func(c *PageConfig) {
+		c.Revalidate = ttl
+	}
+}
+
+// WithContentType serves the page with Content-Type ct. Any type other than text/html
+// skips the HTML document shell: the response is the component's rendered body alone,
+// without head, props script or hydration scripts. Client-only pages ignore it.
+func WithContentType(ct string) PageOption {
+	return func(c *PageConfig) {
+		c.ContentType = ct
+	}
+}
+
+// WithPageGroup tags the page with a feature area such as "marketing" or "admin". The
+// build summary groups pages by it and App.Routes reports it.
+func WithPageGroup(name string) PageOption {
+	return func(c *PageConfig) {
+		c.Group = name
+	}
+}
+
 func WithHTMLLang(lang string) PageOption {
 	return func(c *PageConfig) {
 		c.HTMLLang = lang
@@ -157,8 +198,66 @@ type Renderer interface {
 }
 
 type Config struct {
-	Framework       Framework
-	DefaultHTMLLang string
+	Framework               Framework
+	DefaultHTMLLang         string
+	SecureHeaders           *SecureHeadersConfig
+	SSRTimeout              time.Duration
+	Messages                MessagesLoader
+	RequestLogger           *slog.Logger
+	Favicon                 *Favicon
+	StructuredErrors        bool
+	RenderRetries           int
+	GracePeriod             time.Duration
+	RuntimeData             []RuntimeData
+	MetaTags                []MetaTag
+	Timeouts                PageTimeouts
+	Title                   TitleConfig
+	DiagnosticsPath         string
+	Dependencies            any
+	LinkRewriter            LinkRewriter
+	CSP                     CSPConfig
+	StaticDataCacheTTL      time.Duration
+	Environment             string
+	CanonicalHost           CanonicalHostConfig
+	TrustProxyHops          int
+	TrustedProxies          []string
+	EmbedMounts             []EmbedMount
+	StatusPages             map[int]string
+	StaticFileMaxAge        time.Duration
+	StaticFileImmutable     bool
+	MinManifestVersion      *int
+	Logger                  *slog.Logger
+	SSREntryTemplate        string
+	ReactRuntime            string
+	StaticCacheManifest     bool
+	RenderHeaders           []string
+	StaticExportConcurrency int
+	BuildTime               time.Time
+	UserProvider            UserProvider
+	FeatureFlags            FlagProvider
+	BuildEnv                map[string]string
+	BuildConstants          map[string]any
+	BunSmol                 bool
+	CacheBust               CacheBustStrategy
+	RouteIndex              bool
+	BuildTarget             string
+	Prefetch                bool
+	ErrorTransform          ErrorTransform
+	ErrorTransformInDev     bool
+	DefaultHeaders          http.Header
+	RequestModifiers        []RequestModifier
+	PageCache               PageCacheStore
+	SSRFallback             SSRFallbackMode
+	Preview                 func(*http.Request) bool
+	PageMetrics             bool
+	PreloadPaths            []string
+	RouteErrors             []RouteErrorHandler
+	BuildPlugins            []BuildPlugin
+	AssetIntegrity          bool
+	CSRF                    *CSRFConfig
+	NodeModulesPath         string
+	SRI                     bool
+	OnFirstRender           func(routePattern string)
 }
 
 type ConfigOption func(*Config)
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"context"
+	"net/http"
+)
+
+// User is the current user as seen by bifrost: enough to tag logs with who made a request.
+type User interface {
+	ID() string
+	Name() string
+}
+
+// UserProvider resolves the user making req. It returns false for anonymous requests.
+type UserProvider func(req *http.Request) (User, bool)
+
+// WithUserProvider calls provider once per request and stores the user in the request
+// context for CurrentUser. Request log lines gain a user_id attribute.
+func WithUserProvider(provider UserProvider) ConfigOption {
+	return func(c *Config) {
+		c.UserProvider = provider
+	}
+}
+
+type userKey struct{}
+
+func ContextWithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// CurrentUser returns the user stored by WithUserProvider, or false when the request is
+// anonymous or no provider is configured.
+func CurrentUser(ctx context.Context) (User, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	user, ok := ctx.Value(userKey{}).(User)
+	return user, ok && user != nil
+}
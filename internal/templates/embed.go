@@ -32,6 +32,31 @@ func GetTemplate(name string) (fs.FS, error) {
 	}
 }
 
+// sharedFiles are present in every template; main.go.tmpl is the app entry point.
+var sharedFiles = []string{
+	".air.toml",
+	".bifrost/.gitkeep",
+	"go.mod.tmpl",
+	"main.go.tmpl",
+	"package.json",
+	"pages/home.tsx",
+	"tsconfig.json",
+}
+
+var templateFiles = map[string][]string{
+	"minimal": {".gitignore", "Dockerfile", "Makefile", "public/favicon.ico"},
+	"spa":     {".gitignore", "Dockerfile", "Makefile", "public/favicon.ico"},
+	"desktop": {".gitignore.tmpl", "Makefile.tmpl", "public/icon.png"},
+}
+
+// RequiredFiles lists the files a template's FS must contain for init to produce a
+// working project.
+func RequiredFiles(name string) []string {
+	files := make([]string, 0, len(sharedFiles)+len(templateFiles[name]))
+	files = append(files, sharedFiles...)
+	return append(files, templateFiles[name]...)
+}
+
 type TemplateData struct {
 	Module string
 }
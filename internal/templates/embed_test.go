@@ -324,3 +324,20 @@ func TestBifrostGitkeepExists(t *testing.T) {
 		})
 	}
 }
+
+func TestRequiredFilesExist(t *testing.T) {
+	for _, tmpl := range []string{"minimal", "spa", "desktop"} {
+		t.Run(tmpl, func(t *testing.T) {
+			templateFS, err := GetTemplate(tmpl)
+			if err != nil {
+				t.Fatalf("GetTemplate(%q) error = %v", tmpl, err)
+			}
+
+			for _, path := range RequiredFiles(tmpl) {
+				if _, err := fs.Stat(templateFS, path); err != nil {
+					t.Errorf("%s template should include %s: %v", tmpl, path, err)
+				}
+			}
+		})
+	}
+}
@@ -12,15 +12,15 @@ import (
 //go:embed clientonly_html_template.txt
 var clientOnlyHTMLTemplate string
 
-func (s *BuildService) writeClientOnlyHTML(htmlPath, title, script, criticalCSS string, cssHrefs []string, chunks []string, htmlLang string, htmlClass string) error {
+func (s *BuildService) writeClientOnlyHTML(htmlPath, title, script, criticalCSS string, cssHrefs []string, chunks []string, sri map[string]string, htmlLang string, htmlClass string, environment string) error {
 	var chunkLines strings.Builder
 	for _, c := range chunks {
 		chunkLines.WriteString(`    <script src="`)
 		chunkLines.WriteString(c)
-		chunkLines.WriteString(`" type="module" defer></script>
+		chunkLines.WriteString(`" type="module"` + core.SRIAttrs(sri, c) + ` defer></script>
 `)
 	}
-	styleTags := core.RenderStyleTags(criticalCSS, cssHrefs)
+	styleTags := core.RenderStyleTagsWithIntegrity(criticalCSS, cssHrefs, sri)
 	cssLink := ""
 	if styleTags != "" {
 		cssLink = "    " + strings.ReplaceAll(styleTags, "><", ">\n    <") + "\n"
@@ -29,36 +29,41 @@ func (s *BuildService) writeClientOnlyHTML(htmlPath, title, script, criticalCSS
 	for _, c := range chunks {
 		modulePreload.WriteString(`    <link rel="modulepreload" href="`)
 		modulePreload.WriteString(c)
-		modulePreload.WriteString(`" />
+		modulePreload.WriteString(`"` + core.SRIAttrs(sri, c) + ` />
 `)
 	}
 	modulePreload.WriteString(`    <link rel="modulepreload" href="`)
 	modulePreload.WriteString(script)
-	modulePreload.WriteString(`" />
+	modulePreload.WriteString(`"` + core.SRIAttrs(sri, script) + ` />
 `)
 	classAttr := ""
 	if sanitizedClass := core.SanitizeHTMLClass(htmlClass); sanitizedClass != "" {
 		classAttr = ` class="` + stdhtml.EscapeString(sanitizedClass) + `"`
 	}
+	appAttrs := ""
+	if environment != "" {
+		appAttrs = ` data-bifrost-env="` + stdhtml.EscapeString(environment) + `"`
+	}
 	html := clientOnlyHTMLTemplate
 	html = strings.ReplaceAll(html, "LANG_PLACEHOLDER", htmlLang)
 	html = strings.ReplaceAll(html, "HTML_CLASS_PLACEHOLDER", classAttr)
 	html = strings.ReplaceAll(html, "TITLE_PLACEHOLDER", title)
 	html = strings.ReplaceAll(html, "CSS_LINK_PLACEHOLDER", cssLink)
 	html = strings.ReplaceAll(html, "MODULEPRELOAD_PLACEHOLDER", modulePreload.String())
+	html = strings.ReplaceAll(html, "APP_ATTRS_PLACEHOLDER", appAttrs)
 	html = strings.ReplaceAll(html, "CHUNK_SCRIPTS_PLACEHOLDER", chunkLines.String())
-	html = strings.ReplaceAll(html, "SCRIPT_SRC_PLACEHOLDER", script)
+	html = strings.ReplaceAll(html, `"SCRIPT_SRC_PLACEHOLDER"`, `"`+script+`"`+core.SRIAttrs(sri, script))
 	return os.WriteFile(htmlPath, []byte(html), 0644)
 }
 
-func (s *BuildService) writeSSREntry(entryPath, importPath string) error {
-	return WriteSSREntryFile(s.adapter, entryPath, importPath)
+func (s *BuildService) writeSSREntry(adapter core.FrameworkAdapter, entryPath, importPath, fallbackImport, loadingImport string) error {
+	return WriteSSREntryFile(adapter, entryPath, importPath, fallbackImport, loadingImport)
 }
 
-func (s *BuildService) writeClientOnlyEntry(entryPath, importPath string) error {
-	return WriteClientEntryFile(s.adapter, entryPath, importPath, core.ModeClientOnly)
+func (s *BuildService) writeClientOnlyEntry(adapter core.FrameworkAdapter, entryPath, importPath, fallbackImport string) error {
+	return WriteClientEntryFile(adapter, entryPath, importPath, fallbackImport, "", core.ModeClientOnly, core.HydrationImmediate)
 }
 
-func (s *BuildService) writeHydrationEntry(entryPath, importPath string) error {
-	return WriteClientEntryFile(s.adapter, entryPath, importPath, core.ModeSSR)
+func (s *BuildService) writeHydrationEntry(adapter core.FrameworkAdapter, entryPath, importPath, fallbackImport, loadingImport string, hydration core.HydrationStrategy) error {
+	return WriteClientEntryFile(adapter, entryPath, importPath, fallbackImport, loadingImport, core.ModeSSR, hydration)
 }
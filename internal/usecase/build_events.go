@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// BuildEvent is one step of progress reported to BuildInput.OnEvent: PageDetected,
+// SSRBuilt, BundleBuilt, PrerenderDone, BuildWarning or BuildFailure.
+type BuildEvent interface {
+	buildEvent()
+}
+
+// PageDetected is sent for every page found in main.go, before anything is built.
+type PageDetected struct {
+	ComponentPath string
+	Entry         string
+	Mode          string
+}
+
+// SSRBuilt is sent for every page whose SSR bundle built. Duration covers the whole
+// batch when pages were bundled together.
+type SSRBuilt struct {
+	Entry    string
+	Duration time.Duration
+}
+
+// BundleBuilt is sent for every client bundle. Bytes is the size of the entry script;
+// Duration covers the whole batch when pages were bundled together.
+type BundleBuilt struct {
+	Entry    string
+	Bytes    int64
+	Duration time.Duration
+}
+
+// PrerenderDone is sent for every path written by the static prerender export.
+type PrerenderDone struct {
+	Entry string
+	Path  string
+}
+
+// BuildWarning mirrors a warning added to the build report.
+type BuildWarning struct {
+	BuildError
+}
+
+// BuildFailure mirrors an error added to the build report; the build does not succeed.
+type BuildFailure struct {
+	BuildError
+}
+
+func (PageDetected) buildEvent()  {}
+func (SSRBuilt) buildEvent()      {}
+func (BundleBuilt) buildEvent()   {}
+func (PrerenderDone) buildEvent() {}
+func (BuildWarning) buildEvent()  {}
+func (BuildFailure) buildEvent()  {}
+
+func (r *buildRun) emit(event BuildEvent) {
+	if r.input.OnEvent != nil {
+		r.input.OnEvent(event)
+	}
+}
+
+func (r *buildRun) addWarning(page string, message string, details []string) {
+	r.report.AddWarning(page, message, details)
+	r.emit(BuildWarning{BuildError{Page: page, Message: message, Details: details}})
+}
+
+func (r *buildRun) addError(page string, message string, details []string) {
+	r.report.AddError(page, message, details)
+	r.emit(BuildFailure{BuildError{Page: page, Message: message, Details: details}})
+}
+
+// bundleSize returns the size of a built asset such as "/dist/page.js", or 0 if it is
+// missing.
+func (r *buildRun) bundleSize(href string) int64 {
+	if href == "" {
+		return 0
+	}
+	info, err := os.Stat(filepath.Join(r.paths.bifrostDir, filepath.FromSlash(href)))
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// emitPrerendered reports the static routes the export wrote, in path order per page.
+func (r *buildRun) emitPrerendered() {
+	if r.input.OnEvent == nil {
+		return
+	}
+	for _, page := range r.pages {
+		routes := r.manifest.Entries[page.entryName].StaticRoutes
+		paths := make([]string, 0, len(routes))
+		for path := range routes {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			r.emit(PrerenderDone{Entry: page.entryName, Path: path})
+		}
+	}
+}
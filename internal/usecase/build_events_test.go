@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestBuildProjectEmitsEvents(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	_ = Page("/", "./pages/home.tsx")
+	_ = Page("/about", "./pages/about.tsx", WithClient())
+	_ = Page("/blog", "./pages/blog.tsx")
+}`)
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), "<title>Home</title>")
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "about.tsx"), "<title>About</title>")
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "blog.tsx"), "<title>Blog</title>")
+
+	renderer := &fakeRenderer{
+		buildFn: func(entrypoints []string, outdir string, entryNames []string) (map[string]core.ClientBuildResult, error) {
+			result := make(map[string]core.ClientBuildResult, len(entryNames))
+			for _, name := range entryNames {
+				writeTestFile(t, filepath.Join(outdir, name+".js"), "console.log(1)")
+				result[name] = core.ClientBuildResult{Script: "/dist/" + name + ".js"}
+			}
+			return result, nil
+		},
+		buildSSRFn: func(entrypoints []string, outdir string) error {
+			if len(entrypoints) > 1 {
+				return errors.New("batch failed")
+			}
+			name := strings.TrimSuffix(filepath.Base(entrypoints[0]), filepath.Ext(entrypoints[0]))
+			writeTestFile(t, filepath.Join(outdir, name+".js"), "// ssr")
+			return nil
+		},
+	}
+	service := NewBuildService(renderer, nil, &mockCLIOutput{}, nil)
+	service.compileRuntimeFn = func(bifrostDir string) error { return nil }
+
+	var events []BuildEvent
+	result := service.BuildProject(context.Background(), BuildInput{
+		MainFile:    filepath.Join(tmpDir, "main.go"),
+		OriginalCwd: tmpDir,
+		OnEvent:     func(e BuildEvent) { events = append(events, e) },
+	})
+	if !result.Success {
+		t.Fatalf("expected build success, got %v", result.Error)
+	}
+
+	var detected, ssr, bundles []string
+	for _, e := range events {
+		switch e := e.(type) {
+		case PageDetected:
+			detected = append(detected, e.ComponentPath+":"+e.Mode)
+		case SSRBuilt:
+			ssr = append(ssr, e.Entry)
+		case BundleBuilt:
+			if e.Bytes != int64(len("console.log(1)")) {
+				t.Errorf("%s: bundle bytes = %d", e.Entry, e.Bytes)
+			}
+			bundles = append(bundles, e.Entry)
+		case BuildFailure:
+			t.Errorf("unexpected failure event: %+v", e)
+		}
+	}
+	if strings.Join(detected, ",") != "./pages/home.tsx:ssr,./pages/about.tsx:client,./pages/blog.tsx:ssr" {
+		t.Errorf("page events = %v", detected)
+	}
+	if strings.Join(ssr, ",") != "pages-home-entry,pages-blog-entry" {
+		t.Errorf("ssr events = %v", ssr)
+	}
+	if len(bundles) != 3 {
+		t.Errorf("bundle events = %v", bundles)
+	}
+	if _, ok := events[0].(PageDetected); !ok {
+		t.Errorf("expected page detection first, got %T", events[0])
+	}
+
+	warned := false
+	for _, e := range events {
+		if w, ok := e.(BuildWarning); ok && w.Page == "SSR build" {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Error("expected a warning event for the SSR batch fallback")
+	}
+}
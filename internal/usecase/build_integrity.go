@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// assetHashes hashes the built files behind urls for ManifestEntry.IntegrityHash and
+// ManifestEntry.SRI. Files that cannot be read are left out rather than recorded with a
+// wrong hash.
+func (r *buildRun) assetHashes(urls []string, hash func([]byte) string) map[string]string {
+	hashes := make(map[string]string, len(urls))
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(r.paths.bifrostDir, filepath.FromSlash(url)))
+		if err != nil {
+			continue
+		}
+		hashes[url] = hash(data)
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	return hashes
+}
+
+// writeStaticCacheManifest writes the WithStaticCacheManifest file: the assets of every
+// manifest entry with their sha384 hashes, sorted by URL.
+func (s *BuildService) writeStaticCacheManifest(run *buildRun) error {
+	if !run.staticCacheManifest {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var urls []string
+	for _, entry := range run.manifest.Entries {
+		for _, url := range core.AssetURLs(core.PageArtifacts{
+			Script:   entry.Script,
+			CSS:      entry.CSS,
+			CSSFiles: entry.CSSFiles,
+			Chunks:   entry.Chunks,
+		}) {
+			if !seen[url] {
+				seen[url] = true
+				urls = append(urls, url)
+			}
+		}
+	}
+	hashes := run.assetHashes(urls, core.SRIHash)
+	assets := make([]core.AssetCacheEntry, 0, len(hashes))
+	for url, hash := range hashes {
+		assets = append(assets, core.AssetCacheEntry{URL: url, Hash: hash})
+	}
+	slices.SortFunc(assets, func(a, b core.AssetCacheEntry) int {
+		return strings.Compare(a.URL, b.URL)
+	})
+
+	data, err := json.MarshalIndent(assets, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal asset cache manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(run.paths.publicDestDir, core.StaticCacheManifestFile), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write asset cache manifest: %w", err)
+	}
+	return nil
+}
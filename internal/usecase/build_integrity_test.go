@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestBuildRunAssetHashes(t *testing.T) {
+	bifrostDir := t.TempDir()
+	writeTestFile(t, filepath.Join(bifrostDir, "dist", "home.js"), "console.log('home')")
+	run := &buildRun{paths: buildPaths{bifrostDir: bifrostDir}}
+
+	hashes := run.assetHashes([]string{"/dist/home.js", "/dist/missing.css", ""}, core.AssetIntegrityHash)
+	if len(hashes) != 1 {
+		t.Fatalf("hashes = %v, want only the built script", hashes)
+	}
+	if got, want := hashes["/dist/home.js"], core.AssetIntegrityHash([]byte("console.log('home')")); got != want {
+		t.Errorf("hash = %q, want %q", got, want)
+	}
+	if run.assetHashes(nil, core.AssetIntegrityHash) != nil {
+		t.Error("assetHashes(nil) should be nil so the manifest omits the field")
+	}
+	sri := run.assetHashes([]string{"/dist/home.js"}, core.SRIHash)
+	if got, want := sri["/dist/home.js"], core.SRIHash([]byte("console.log('home')")); got != want {
+		t.Errorf("sri = %q, want %q", got, want)
+	}
+}
+
+func TestWriteStaticCacheManifest(t *testing.T) {
+	bifrostDir := t.TempDir()
+	files := map[string]string{
+		"/dist/home.js":     "console.log('home')",
+		"/dist/about.js":    "console.log('about')",
+		"/dist/chunk-a.js":  "export const a = 1",
+		"/dist/home.css":    "body{}",
+		"/dist/missing.css": "",
+	}
+	for url, content := range files {
+		if content != "" {
+			writeTestFile(t, filepath.Join(bifrostDir, filepath.FromSlash(url)), content)
+		}
+	}
+	run := &buildRun{
+		paths:               buildPaths{bifrostDir: bifrostDir, publicDestDir: filepath.Join(bifrostDir, "public")},
+		staticCacheManifest: true,
+		manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			"pages-home":  {Script: "/dist/home.js", CSS: "/dist/home.css", Chunks: []string{"/dist/chunk-a.js"}},
+			"pages-about": {Script: "/dist/about.js", CSSFiles: []string{"/dist/missing.css"}, Chunks: []string{"/dist/chunk-a.js"}},
+		}},
+	}
+	if err := os.MkdirAll(run.paths.publicDestDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	service := &BuildService{}
+	if err := service.writeStaticCacheManifest(run); err != nil {
+		t.Fatalf("writeStaticCacheManifest: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(run.paths.publicDestDir, core.StaticCacheManifestFile))
+	if err != nil {
+		t.Fatalf("read asset cache manifest: %v", err)
+	}
+	var assets []core.AssetCacheEntry
+	if err := json.Unmarshal(data, &assets); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, data)
+	}
+	wantURLs := []string{"/dist/about.js", "/dist/chunk-a.js", "/dist/home.css", "/dist/home.js"}
+	if len(assets) != len(wantURLs) {
+		t.Fatalf("assets = %+v, want %v", assets, wantURLs)
+	}
+	for i, asset := range assets {
+		if asset.URL != wantURLs[i] {
+			t.Errorf("assets[%d].URL = %q, want %q", i, asset.URL, wantURLs[i])
+		}
+		if want := core.SRIHash([]byte(files[asset.URL])); asset.Hash != want {
+			t.Errorf("hash of %s = %q, want %q", asset.URL, asset.Hash, want)
+		}
+	}
+}
+
+func TestWriteStaticCacheManifestDisabled(t *testing.T) {
+	bifrostDir := t.TempDir()
+	run := &buildRun{
+		paths:    buildPaths{bifrostDir: bifrostDir, publicDestDir: bifrostDir},
+		manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{}},
+	}
+	if err := (&BuildService{}).writeStaticCacheManifest(run); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(bifrostDir, core.StaticCacheManifestFile)); !os.IsNotExist(err) {
+		t.Errorf("asset cache manifest written without WithStaticCacheManifest: %v", err)
+	}
+}
+
+func TestWriteRouteIndex(t *testing.T) {
+	bifrostDir := t.TempDir()
+	writeTestFile(t, filepath.Join(bifrostDir, "pages", "routes", "blog", "hello", "index.html"),
+		`<html><head><title>Hello</title><meta name="description" content="First post"></head></html>`)
+	writeTestFile(t, filepath.Join(bifrostDir, "pages", "routes", "about", "index.html"),
+		`<html><head><title>About</title></head></html>`)
+	run := &buildRun{
+		paths:      buildPaths{bifrostDir: bifrostDir, publicDestDir: filepath.Join(bifrostDir, "public")},
+		routeIndex: true,
+		manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			"pages-blog":  {StaticRoutes: map[string]string{"/blog/hello": "/pages/routes/blog/hello/index.html"}},
+			"pages-about": {StaticRoutes: map[string]string{"/about": "/pages/routes/about/index.html"}},
+			"pages-feed":  {Mode: "ssr"},
+		}},
+	}
+
+	if err := (&BuildService{}).writeRouteIndex(run); err != nil {
+		t.Fatalf("writeRouteIndex: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(run.paths.publicDestDir, "__bifrost", "routes.json"))
+	if err != nil {
+		t.Fatalf("read route index: %v", err)
+	}
+	var index core.RouteIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, data)
+	}
+	if index.Version != core.RouteIndexVersion || len(index.Routes) != 2 {
+		t.Fatalf("index = %+v", index)
+	}
+	if got := index.Routes[0]; got.Path != "/about" || got.Title != "About" {
+		t.Errorf("routes[0] = %+v", got)
+	}
+	if got := index.Routes[1]; got.Path != "/blog/hello" || got.Title != "Hello" || got.Meta["description"] != "First post" {
+		t.Errorf("routes[1] = %+v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// loadBuildPluginsTimeout bounds the app run that reports its build plugins; the app exits
+// as soon as it is created.
+const loadBuildPluginsTimeout = 5 * time.Minute
+
+// LoadBuildPlugins collects the WithBuildPlugin sources of the app at mainFile by running
+// it once with core.BuildPluginsOutEnv set. It returns nil without running anything when
+// mainFile does not call WithBuildPlugin.
+func LoadBuildPlugins(ctx context.Context, originalCwd, mainFile string) ([]core.BuildPluginSource, error) {
+	uses, err := scanCallsOption(mainFile, "WithBuildPlugin")
+	if err != nil || !uses {
+		return nil, err
+	}
+
+	tempDir, err := os.MkdirTemp("", "bifrost-plugins-*")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	outPath := filepath.Join(tempDir, "plugins.json")
+
+	ctx, cancel := context.WithTimeout(ctx, loadBuildPluginsTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "go", "run", mainFile)
+	cmd.Dir = originalCwd
+	cmd.Env = append(os.Environ(), core.BuildPluginsOutEnv+"="+outPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to run app for build plugins: %v\nOutput: %s", err, output)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		return nil, fmt.Errorf("app did not report its build plugins (is WithBuildPlugin passed to bifrost.New?): %w", err)
+	}
+	var plugins []core.BuildPluginSource
+	if err := json.Unmarshal(data, &plugins); err != nil {
+		return nil, fmt.Errorf("failed to parse build plugins: %w", err)
+	}
+	return plugins, nil
+}
+
+// WriteBuildPlugins validates plugins and writes them to path for LoadBuildPlugins.
+func WriteBuildPlugins(path string, plugins []core.BuildPlugin) error {
+	sources, err := core.BuildPluginSources(plugins)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestLoadBuildPlugins_SkipsAppsWithoutPlugins(t *testing.T) {
+	dir := t.TempDir()
+	mainFile := filepath.Join(dir, "main.go")
+	// The file does not compile, so running it would fail the test.
+	writeTestFile(t, mainFile, "package main\n\nfunc main() { bifrost.New(assets, bifrost.Page(\"/\", \"./pages/home.tsx\")) }\n")
+
+	plugins, err := LoadBuildPlugins(context.Background(), dir, mainFile)
+	if err != nil {
+		t.Fatalf("LoadBuildPlugins: %v", err)
+	}
+	if plugins != nil {
+		t.Fatalf("plugins = %v, want nil", plugins)
+	}
+}
+
+func TestWriteBuildPlugins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plugins.json")
+	plugin := core.BuildPluginSource{ID: "mdx", Source: "function bfPlugin_mdx() {}"}
+
+	if err := WriteBuildPlugins(path, []core.BuildPlugin{plugin}); err != nil {
+		t.Fatalf("WriteBuildPlugins: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []core.BuildPluginSource
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != plugin {
+		t.Fatalf("written plugins = %+v, want [%+v]", got, plugin)
+	}
+
+	if err := WriteBuildPlugins(path, []core.BuildPlugin{core.BuildPluginSource{ID: "bad-name"}}); err == nil {
+		t.Fatal("invalid plugin name should fail")
+	}
+}
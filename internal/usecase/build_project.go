@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/adapters/framework"
 	"github.com/3-lines-studio/bifrost/internal/core"
@@ -10,6 +11,19 @@ import (
 type BuildInput struct {
 	MainFile    string
 	OriginalCwd string
+	// OnEvent, when set, receives build progress as it happens, alongside the CLI report.
+	OnEvent func(BuildEvent)
+	// ExportTimeout bounds the static export subprocess; zero uses DefaultExportTimeout.
+	ExportTimeout time.Duration
+	// Jobs is how many paths of a static page the export renders at once, overriding
+	// WithConcurrentStaticExport; zero keeps the app's setting.
+	Jobs int
+	// CompressRuntime embeds the Bun renderer gzipped; it is decompressed at startup.
+	CompressRuntime bool
+	// CompressSSR embeds the SSR bundles gzipped; they are decompressed at startup.
+	CompressSSR bool
+	// SSRLint controls the check for browser globals at component top level.
+	SSRLint SSRLintMode
 }
 
 type BuildOutput struct {
@@ -59,6 +73,8 @@ func (s *BuildService) BuildProject(ctx context.Context, input BuildInput) Build
 		return BuildOutput{Success: false, Error: err}
 	}
 	s.copyPublicAssets(run)
+	s.lintBrowserGlobals(run)
+	s.lintBuildTarget(run)
 	s.buildSSRBundles(run)
 	s.generateClientEntries(run)
 	s.buildClientAssets(run)
@@ -67,12 +83,21 @@ func (s *BuildService) BuildProject(ctx context.Context, input BuildInput) Build
 	if err := s.writeManifest(run); err != nil {
 		return BuildOutput{Success: false, Error: err}
 	}
+	if err := s.writeStaticCacheManifest(run); err != nil {
+		return BuildOutput{Success: false, Error: err}
+	}
 	if err := s.compileRuntime(run); err != nil {
 		return BuildOutput{Success: false, Error: err}
 	}
 	if err := s.exportStaticPrerender(ctx, run); err != nil {
 		return BuildOutput{Success: false, Error: err}
 	}
+	if err := s.writeRouteIndex(run); err != nil {
+		return BuildOutput{Success: false, Error: err}
+	}
+	if err := s.compressSSR(run); err != nil {
+		return BuildOutput{Success: false, Error: err}
+	}
 	s.cleanupEntryFiles(run)
 
 	run.report.Render()
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/adapters/cli"
 	"github.com/3-lines-studio/bifrost/internal/core"
@@ -43,15 +46,22 @@ func (p buildPage) ssrEntryPath(adapter core.FrameworkAdapter, entriesDir string
 }
 
 type buildRun struct {
-	input              BuildInput
-	paths              buildPaths
-	report             *cli.BuildReport
-	pages              []buildPage
-	manifest           *core.Manifest
-	defaultHTMLLang    string
-	hasStaticPrerender bool
-	needsRuntime       bool
-	ssrFailed          map[string]struct{}
+	input               BuildInput
+	paths               buildPaths
+	report              *cli.BuildReport
+	pages               []buildPage
+	manifest            *core.Manifest
+	defaultHTMLLang     string
+	hasStaticPrerender  bool
+	needsRuntime        bool
+	preview             bool                  // main.go calls WithPreview
+	sri                 bool                  // main.go calls WithSRI
+	staticCacheManifest bool                  // main.go calls WithStaticCacheManifest
+	routeIndex          bool                  // main.go calls WithRouteIndex
+	buildTarget         string                // WithBuildTarget of main.go; empty for browser
+	clientAdapter       core.FrameworkAdapter // writes client entries, with any WithReactRuntime
+	ssrAdapter          core.FrameworkAdapter // writes SSR entries, with any WithReactRuntime and WithCustomSSREntryTemplate
+	ssrFailed           map[string]struct{}
 }
 
 func (r *buildRun) updateManifestEntry(entryName string, update func(*core.ManifestEntry)) {
@@ -70,34 +80,108 @@ func (r *buildRun) ssrFailedFor(entryName string) bool {
 }
 
 func (s *BuildService) newBuildRun(input BuildInput) (*buildRun, error) {
-	pageConfigs, defaultHTMLLang, err := s.scanPages(input.MainFile)
+	pageConfigs, defaultHTMLLang, err := s.scanPages(input.MainFile, input.OriginalCwd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan pages: %w", err)
 	}
 	if len(pageConfigs) == 0 {
 		return nil, fmt.Errorf("no pages found")
 	}
+	environment, err := scanEnvironment(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan pages: %w", err)
+	}
+	preview, err := scanPreview(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan pages: %w", err)
+	}
+	sri, err := scanSRI(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan pages: %w", err)
+	}
+	staticCacheManifest, err := scanStaticCacheManifest(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan pages: %w", err)
+	}
+	routeIndex, err := scanRouteIndex(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan pages: %w", err)
+	}
+	buildTarget, err := scanBuildTarget(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan pages: %w", err)
+	}
+	if err := core.ValidateBuildTarget(buildTarget); err != nil {
+		return nil, err
+	}
+	if targeter, ok := s.renderer.(BuildTargeter); ok {
+		targeter.SetBuildTarget(buildTarget)
+	}
+	ssrEntryTemplate, err := scanSSREntryTemplate(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan pages: %w", err)
+	}
+	reactRuntime, err := scanReactRuntime(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan pages: %w", err)
+	}
+	buildEnv, err := scanBuildEnv(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan build env: %w", err)
+	}
+	if reactRuntime != "" {
+		if buildEnv == nil {
+			buildEnv = make(map[string]string, 1)
+		}
+		buildEnv[core.ReactRuntimeEnvKey] = reactRuntime
+	}
+	buildConstants, err := scanBuildConstants(input.MainFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan build constants: %w", err)
+	}
+	defines, err := core.BuildDefines(buildEnv, buildConstants)
+	if err != nil {
+		return nil, err
+	}
+	if definer, ok := s.renderer.(BuildDefiner); ok {
+		definer.SetBuildDefines(defines)
+	}
+	clientAdapter, err := core.WithReactRuntimeSource(s.adapter, reactRuntime)
+	if err != nil {
+		return nil, err
+	}
+	ssrAdapter, err := core.WithSSREntryTemplate(clientAdapter, ssrEntryTemplate)
+	if err != nil {
+		return nil, err
+	}
 
 	paths := buildPaths{
-		bifrostDir:    filepath.Join(input.OriginalCwd, ".bifrost"),
-		outdir:        filepath.Join(input.OriginalCwd, ".bifrost", "dist"),
-		ssrDir:        filepath.Join(input.OriginalCwd, ".bifrost", "ssr"),
-		entriesDir:    filepath.Join(input.OriginalCwd, ".bifrost", "entries"),
-		pagesDir:      filepath.Join(input.OriginalCwd, ".bifrost", "pages"),
-		runtimeDir:    filepath.Join(input.OriginalCwd, ".bifrost", "runtime"),
+		bifrostDir:    filepath.Join(input.OriginalCwd, core.OutputDir),
+		outdir:        filepath.Join(input.OriginalCwd, core.OutputDir, "dist"),
+		ssrDir:        filepath.Join(input.OriginalCwd, core.OutputDir, "ssr"),
+		entriesDir:    filepath.Join(input.OriginalCwd, core.OutputDir, "entries"),
+		pagesDir:      filepath.Join(input.OriginalCwd, core.OutputDir, "pages"),
+		runtimeDir:    filepath.Join(input.OriginalCwd, core.OutputDir, "runtime"),
 		publicDir:     filepath.Join(input.OriginalCwd, "public"),
-		publicDestDir: filepath.Join(input.OriginalCwd, ".bifrost", "public"),
-		manifestPath:  filepath.Join(input.OriginalCwd, ".bifrost", "manifest.json"),
+		publicDestDir: filepath.Join(input.OriginalCwd, core.OutputDir, "public"),
+		manifestPath:  filepath.Join(input.OriginalCwd, core.OutputDir, "manifest.json"),
 	}
 
 	run := &buildRun{
-		input:           input,
-		paths:           paths,
-		report:          cli.NewBuildReport(s.cli, paths.bifrostDir),
-		pages:           make([]buildPage, len(pageConfigs)),
-		manifest:        &core.Manifest{Entries: make(map[string]core.ManifestEntry, len(pageConfigs))},
-		defaultHTMLLang: defaultHTMLLang,
-		ssrFailed:       make(map[string]struct{}),
+		input:               input,
+		paths:               paths,
+		report:              cli.NewBuildReport(s.cli, paths.bifrostDir),
+		pages:               make([]buildPage, len(pageConfigs)),
+		manifest:            &core.Manifest{Version: core.ManifestVersion, Entries: make(map[string]core.ManifestEntry, len(pageConfigs)), Environment: environment, BuiltAt: time.Now().UTC().Truncate(time.Second)},
+		defaultHTMLLang:     defaultHTMLLang,
+		ssrFailed:           make(map[string]struct{}),
+		preview:             preview,
+		sri:                 sri,
+		staticCacheManifest: staticCacheManifest,
+		routeIndex:          routeIndex,
+		buildTarget:         buildTarget,
+		clientAdapter:       clientAdapter,
+		ssrAdapter:          ssrAdapter,
 	}
 	run.report.SetPageCount(len(pageConfigs))
 
@@ -109,10 +193,12 @@ func (s *BuildService) newBuildRun(input BuildInput) (*buildRun, error) {
 			modeLabel:        config.Mode.BuildLabel(),
 		}
 		run.pages[i] = page
+		run.report.AddPage(page.entryName, config.Group)
+		run.emit(PageDetected{ComponentPath: config.ComponentPath, Entry: page.entryName, Mode: page.modeLabel})
 		if config.Mode == core.ModeStaticPrerender {
 			run.hasStaticPrerender = true
 		}
-		if config.Mode.NeedsSSRBundle() {
+		if config.Mode.NeedsSSRBundle() || (config.Mode == core.ModeStaticPrerender && (config.Revalidate > 0 || preview)) {
 			run.needsRuntime = true
 		}
 	}
@@ -165,7 +251,7 @@ func (s *BuildService) createOutputDirs(run *buildRun) error {
 
 func (s *BuildService) copyPublicAssets(run *buildRun) {
 	if err := s.copyPublicDir(run.paths.publicDir, run.paths.publicDestDir); err != nil {
-		run.report.AddWarning("Public assets", "Failed to copy public assets", []string{err.Error()})
+		run.addWarning("Public assets", "Failed to copy public assets", []string{err.Error()})
 	}
 }
 
@@ -194,8 +280,28 @@ func (s *BuildService) buildSSRBundles(run *buildRun) {
 			})
 			continue
 		}
+		fallbackImport, err := fallbackImportPath(run.input.OriginalCwd, ssrEntryPath, page.config)
+		if err != nil {
+			run.markSSRFailed(page.entryName)
+			errors = append(errors, BuildError{
+				Page:    page.config.ComponentPath,
+				Message: "Failed to calculate import path",
+				Details: []string{err.Error()},
+			})
+			continue
+		}
+		loadingImport, err := loadingImportPath(run.input.OriginalCwd, ssrEntryPath, page.config)
+		if err != nil {
+			run.markSSRFailed(page.entryName)
+			errors = append(errors, BuildError{
+				Page:    page.config.ComponentPath,
+				Message: "Failed to calculate import path",
+				Details: []string{err.Error()},
+			})
+			continue
+		}
 
-		if err := s.writeSSREntry(ssrEntryPath, importPath); err != nil {
+		if err := s.writeSSREntry(run.ssrAdapter, ssrEntryPath, importPath, fallbackImport, loadingImport); err != nil {
 			run.markSSRFailed(page.entryName)
 			errors = append(errors, BuildError{
 				Page:    page.config.ComponentPath,
@@ -210,23 +316,39 @@ func (s *BuildService) buildSSRBundles(run *buildRun) {
 		pagesToBuild = append(pagesToBuild, page)
 	}
 
+	durations := make(map[string]time.Duration, len(entryNames))
 	if len(entryPaths) > 0 {
+		start := time.Now()
 		if err := s.renderer.BuildSSR(entryPaths, run.paths.ssrDir); err != nil {
 			batchFallbackWarning = []string{err.Error()}
-			s.buildSSRBundlesIndividually(run, pagesToBuild, &errors)
+			s.buildSSRBundlesIndividually(run, pagesToBuild, &errors, durations)
+		} else {
+			for _, entryName := range entryNames {
+				durations[entryName] = time.Since(start)
+			}
 		}
 	}
 
 	s.validateSSRBundles(run, pagesToBuild, &errors)
 
+	ssrChunks, err := ssrBundleChunks(run.paths.ssrDir)
+	if err != nil {
+		errors = append(errors, BuildError{
+			Message: "Failed to list SSR chunks",
+			Details: []string{err.Error()},
+		})
+	}
+
 	for _, entryName := range entryNames {
 		if run.ssrFailedFor(entryName) {
 			continue
 		}
+		run.emit(SSRBuilt{Entry: entryName, Duration: durations[entryName]})
 		run.updateManifestEntry(entryName, func(entry *core.ManifestEntry) {
 			entry.Script = "/dist/" + entryName + ".js"
 			entry.CSS = "/dist/" + entryName + ".css"
 			entry.SSR = "/ssr/" + entryName + "-ssr.js"
+			entry.SSRChunks = ssrChunks
 			entry.Mode = "ssr"
 		})
 	}
@@ -234,24 +356,27 @@ func (s *BuildService) buildSSRBundles(run *buildRun) {
 	step.Success = len(errors) == 0
 	run.report.EndStep(step, step.Success, "")
 	if len(batchFallbackWarning) > 0 {
-		run.report.AddWarning("SSR build", "Batch SSR build failed; fell back to per-page builds", batchFallbackWarning)
+		run.addWarning("SSR build", "Batch SSR build failed; fell back to per-page builds", batchFallbackWarning)
 	}
 	for _, err := range errors {
 		if err.Page != "" {
-			run.report.AddError(err.Page, err.Message, err.Details)
+			run.addError(err.Page, err.Message, err.Details)
 		} else {
-			run.report.AddWarning("SSR build", err.Message, err.Details)
+			run.addWarning("SSR build", err.Message, err.Details)
 		}
 	}
 }
 
-func (s *BuildService) buildSSRBundlesIndividually(run *buildRun, pages []buildPage, errors *[]BuildError) {
+func (s *BuildService) buildSSRBundlesIndividually(run *buildRun, pages []buildPage, errors *[]BuildError, durations map[string]time.Duration) {
 	for _, page := range pages {
 		ssrEntryPath := page.ssrEntryPath(s.adapter, run.paths.entriesDir)
+		start := time.Now()
 		if err := s.renderer.BuildSSR([]string{ssrEntryPath}, run.paths.ssrDir); err != nil {
 			run.markSSRFailed(page.entryName)
 			*errors = append(*errors, parseBuildError(page.entryName, err))
+			continue
 		}
+		durations[page.entryName] = time.Since(start)
 	}
 }
 
@@ -286,12 +411,30 @@ func (s *BuildService) generateClientEntries(run *buildRun) {
 			})
 			continue
 		}
+		fallbackImport, err := fallbackImportPath(run.input.OriginalCwd, entryPath, page.config)
+		if err != nil {
+			errors = append(errors, BuildError{
+				Page:    page.config.ComponentPath,
+				Message: "Failed to calculate import path",
+				Details: []string{err.Error()},
+			})
+			continue
+		}
+		loadingImport, err := loadingImportPath(run.input.OriginalCwd, entryPath, page.config)
+		if err != nil {
+			errors = append(errors, BuildError{
+				Page:    page.config.ComponentPath,
+				Message: "Failed to calculate import path",
+				Details: []string{err.Error()},
+			})
+			continue
+		}
 
 		var writeErr error
 		if page.config.Mode == core.ModeClientOnly {
-			writeErr = s.writeClientOnlyEntry(entryPath, importPath)
+			writeErr = s.writeClientOnlyEntry(run.clientAdapter, entryPath, importPath, fallbackImport)
 		} else {
-			writeErr = s.writeHydrationEntry(entryPath, importPath)
+			writeErr = s.writeHydrationEntry(run.clientAdapter, entryPath, importPath, fallbackImport, loadingImport, page.config.Hydration)
 		}
 		if writeErr != nil {
 			errors = append(errors, BuildError{
@@ -305,7 +448,7 @@ func (s *BuildService) generateClientEntries(run *buildRun) {
 	step.Success = len(errors) == 0
 	run.report.EndStep(step, step.Success, "")
 	for _, err := range errors {
-		run.report.AddWarning(err.Page, err.Message, err.Details)
+		run.addWarning(err.Page, err.Message, err.Details)
 	}
 }
 
@@ -324,11 +467,17 @@ func (s *BuildService) buildClientAssets(run *buildRun) {
 	}
 
 	builtMap := make(map[string]core.ClientBuildResult)
+	durations := make(map[string]time.Duration, len(entryNames))
 	if len(entryPaths) > 0 {
 		var err error
+		start := time.Now()
 		builtMap, err = s.renderer.Build(entryPaths, run.paths.outdir, entryNames)
 		if err != nil {
-			builtMap = s.buildClientAssetsIndividually(run, &errors)
+			builtMap = s.buildClientAssetsIndividually(run, &errors, durations)
+		} else {
+			for _, entryName := range entryNames {
+				durations[entryName] = time.Since(start)
+			}
 		}
 	}
 
@@ -337,6 +486,9 @@ func (s *BuildService) buildClientAssets(run *buildRun) {
 		if !ok {
 			continue
 		}
+		if page.config.NoJS {
+			built = run.dropClientScript(built)
+		}
 		run.updateManifestEntry(page.entryName, func(entry *core.ManifestEntry) {
 			entry.Script = built.Script
 			entry.CriticalCSS = built.CriticalCSS
@@ -344,22 +496,55 @@ func (s *BuildService) buildClientAssets(run *buildRun) {
 			entry.CSSFiles = built.CSSFiles
 			entry.Chunks = built.Chunks
 			entry.Mode = page.modeLabel
+			entry.Revalidate = page.config.Mode == core.ModeStaticPrerender && page.config.Revalidate > 0
+			entry.Preview = page.config.Mode == core.ModeStaticPrerender && run.preview
+			entry.NoJS = page.config.NoJS
+			entry.Target = run.buildTarget
+			if page.config.Mode == core.ModeSSR {
+				entry.LoadingShell = page.config.LoadingShell
+			}
+			urls := core.AssetURLs(core.PageArtifacts{
+				Script:   built.Script,
+				CSS:      built.CSS,
+				CSSFiles: built.CSSFiles,
+				Chunks:   built.Chunks,
+			})
+			entry.IntegrityHash = run.assetHashes(urls, core.AssetIntegrityHash)
+			if run.sri {
+				entry.SRI = run.assetHashes(urls, core.SRIHash)
+			}
 		})
+		bytes := run.bundleSize(built.Script)
+		run.report.SetBundleSize(page.entryName, bytes)
+		run.emit(BundleBuilt{Entry: page.entryName, Bytes: bytes, Duration: durations[page.entryName]})
 	}
 
 	step.Success = len(errors) == 0
 	run.report.EndStep(step, step.Success, "")
 	for _, err := range errors {
-		run.report.AddError(err.Page, err.Message, err.Details)
+		run.addError(err.Page, err.Message, err.Details)
+	}
+}
+
+// dropClientScript removes the entry script Bun built for a WithStaticNoJS page, which is
+// only bundled to extract its stylesheets, and returns built without script or chunks.
+// Chunks may be shared with other pages and stay on disk.
+func (r *buildRun) dropClientScript(built core.ClientBuildResult) core.ClientBuildResult {
+	if built.Script != "" {
+		_ = os.Remove(filepath.Join(r.paths.bifrostDir, filepath.FromSlash(built.Script)))
 	}
+	built.Script = ""
+	built.Chunks = nil
+	return built
 }
 
-func (s *BuildService) buildClientAssetsIndividually(run *buildRun, errors *[]BuildError) map[string]core.ClientBuildResult {
+func (s *BuildService) buildClientAssetsIndividually(run *buildRun, errors *[]BuildError, durations map[string]time.Duration) map[string]core.ClientBuildResult {
 	builtMap := make(map[string]core.ClientBuildResult)
 	for _, page := range run.pages {
 		if run.ssrFailedFor(page.entryName) {
 			continue
 		}
+		start := time.Now()
 		result, err := s.renderer.Build(
 			[]string{page.entryPath(s.adapter, run.paths.entriesDir)},
 			run.paths.outdir,
@@ -370,6 +555,7 @@ func (s *BuildService) buildClientAssetsIndividually(run *buildRun, errors *[]Bu
 			continue
 		}
 		builtMap[page.entryName] = result[page.entryName]
+		durations[page.entryName] = time.Since(start)
 	}
 	return builtMap
 }
@@ -398,8 +584,10 @@ func (s *BuildService) generateClientOnlyHTML(run *buildRun) {
 			entry.CriticalCSS,
 			core.StylesheetHrefs(entry.CSS, entry.CSSFiles),
 			entry.Chunks,
+			entry.SRI,
 			lang,
 			page.config.HTMLClass,
+			run.manifest.Environment,
 		)
 		if err != nil {
 			errors = append(errors, BuildError{
@@ -418,7 +606,7 @@ func (s *BuildService) generateClientOnlyHTML(run *buildRun) {
 	step.Success = len(errors) == 0
 	run.report.EndStep(step, step.Success, "")
 	for _, err := range errors {
-		run.report.AddWarning(err.Page, err.Message, err.Details)
+		run.addWarning(err.Page, err.Message, err.Details)
 	}
 }
 
@@ -440,31 +628,39 @@ func (s *BuildService) compileRuntime(run *buildRun) error {
 
 	step := run.report.StartStep("Compiling Bun runtime")
 	if err := s.compileRuntimeFn(run.paths.bifrostDir); err != nil {
-		run.report.AddError("Runtime", "Failed to compile embedded runtime", []string{err.Error()})
+		run.addError("Runtime", "Failed to compile embedded runtime", []string{err.Error()})
 		run.report.EndStep(step, false, "")
 		return fmt.Errorf("runtime compilation failed: %w", err)
 	}
+	if run.input.CompressRuntime {
+		if err := compressEmbeddedRuntime(run.paths.runtimeDir); err != nil {
+			run.addError("Runtime", "Failed to compress embedded runtime", []string{err.Error()})
+			run.report.EndStep(step, false, "")
+			return fmt.Errorf("runtime compression failed: %w", err)
+		}
+	}
 	run.report.EndStep(step, true, "")
 	return nil
 }
 
-func (s *BuildService) exportStaticPrerender(_ context.Context, run *buildRun) error {
+func (s *BuildService) exportStaticPrerender(ctx context.Context, run *buildRun) error {
 	step := run.report.StartStep("Building StaticPrerender pages")
 	if !run.hasStaticPrerender {
 		run.report.EndStep(step, true, "")
 		return nil
 	}
 
-	if err := s.runExportMode(run.input.OriginalCwd, run.paths.bifrostDir, run.manifest, run.input.MainFile); err != nil {
-		run.report.AddError("StaticPrerender", "Export mode failed", []string{err.Error()})
+	if err := s.runExportMode(ctx, run.input.OriginalCwd, run.paths.bifrostDir, run.manifest, run.input.MainFile, run.input.ExportTimeout, run.input.Jobs); err != nil {
+		run.addError("StaticPrerender", "Export mode failed", []string{err.Error()})
 		run.report.EndStep(step, false, "")
 		return fmt.Errorf("export mode failed: %w", err)
 	}
 	run.report.EndStep(step, true, "")
+	run.emitPrerendered()
 
 	if !run.needsRuntime {
 		if err := os.RemoveAll(run.paths.runtimeDir); err != nil {
-			run.report.AddWarning("Cleanup", "Failed to remove runtime directory", []string{err.Error()})
+			run.addWarning("Cleanup", "Failed to remove runtime directory", []string{err.Error()})
 		}
 	}
 
@@ -478,6 +674,56 @@ func (s *BuildService) exportStaticPrerender(_ context.Context, run *buildRun) e
 	return nil
 }
 
+// writeRouteIndex writes the WithRouteIndex file from the pages the static export wrote,
+// sorted by path. It runs after the export, which fills in StaticRoutes.
+func (s *BuildService) writeRouteIndex(run *buildRun) error {
+	if !run.routeIndex {
+		return nil
+	}
+	index := core.RouteIndex{Version: core.RouteIndexVersion, Routes: []core.RouteIndexEntry{}}
+	for _, entry := range run.manifest.Entries {
+		for route, file := range entry.StaticRoutes {
+			page, err := os.ReadFile(filepath.Join(run.paths.bifrostDir, filepath.FromSlash(file)))
+			if err != nil {
+				return fmt.Errorf("failed to read exported page %s: %w", route, err)
+			}
+			index.Routes = append(index.Routes, core.RouteIndexEntryFromHTML(route, string(page)))
+		}
+	}
+	slices.SortFunc(index.Routes, func(a, b core.RouteIndexEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal route index: %w", err)
+	}
+	indexPath := filepath.Join(run.paths.publicDestDir, filepath.FromSlash(core.RouteIndexFile))
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0o755); err != nil {
+		return fmt.Errorf("failed to write route index: %w", err)
+	}
+	if err := os.WriteFile(indexPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write route index: %w", err)
+	}
+	return nil
+}
+
+// compressSSR gzips the SSR bundles for --compress-ssr. It runs after the static export,
+// which reads the bundles from disk.
+func (s *BuildService) compressSSR(run *buildRun) error {
+	if !run.input.CompressSSR {
+		return nil
+	}
+	step := run.report.StartStep("Compressing SSR bundles")
+	if err := compressSSRBundles(run.paths.ssrDir); err != nil {
+		run.addError("SSR", "Failed to compress SSR bundles", []string{err.Error()})
+		run.report.EndStep(step, false, "")
+		return fmt.Errorf("SSR compression failed: %w", err)
+	}
+	run.report.EndStep(step, true, "")
+	return nil
+}
+
 func (s *BuildService) cleanupEntryFiles(run *buildRun) {
 	step := run.report.StartStep("Cleaning up entry files")
 	for _, page := range run.pages {
@@ -1,23 +1,58 @@
 package usecase
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
 
-func (s *BuildService) runExportMode(originalCwd, bifrostDir string, manifest *core.Manifest, mainFile string) error {
-	binaryPath := filepath.Join(bifrostDir, "temp-app")
-	cmd := exec.Command("go", "build", "-o", binaryPath, mainFile)
-	cmd.Dir = originalCwd
-	cmd.Env = append(os.Environ(),
+// DefaultExportTimeout bounds the export subprocess when BuildInput.ExportTimeout is zero.
+const DefaultExportTimeout = 10 * time.Minute
+
+const (
+	exportStderrTailBytes = 8 << 10
+	exportStderrTailLines = 20
+	// exportWaitDelay is how long Wait keeps reading output after the export process
+	// exits, in case a Bun child still holds its stderr.
+	exportWaitDelay = 5 * time.Second
+)
+
+const exportHint = "hint: the export runs inside app.Wrap (or app.Handler). Register every page with app.Handle before calling Wrap, and call Wrap before ListenAndServe or any other blocking work in main."
+
+// exportEnv is the environment of the export build and process: export mode, the output
+// directory and, when jobs is positive, the --jobs value.
+func exportEnv(bifrostDir string, jobs int) []string {
+	env := append(os.Environ(),
 		"BIFROST_EXPORT=1",
 		"BIFROST_EXPORT_DIR="+bifrostDir,
 	)
+	if jobs > 0 {
+		env = append(env, core.ExportJobsEnv+"="+strconv.Itoa(jobs))
+	}
+	return env
+}
+
+func (s *BuildService) runExportMode(ctx context.Context, originalCwd, bifrostDir string, manifest *core.Manifest, mainFile string, timeout time.Duration, jobs int) error {
+	binaryPath := filepath.Join(bifrostDir, "temp-app")
+	cmd := exec.Command("go", "build", "-o", binaryPath, mainFile)
+	cmd.Dir = originalCwd
+	cmd.Env = exportEnv(bifrostDir, jobs)
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -30,17 +65,26 @@ func (s *BuildService) runExportMode(originalCwd, bifrostDir string, manifest *c
 
 	defer func() { _ = os.Remove(binaryPath) }()
 
-	exportCmd := exec.Command(binaryPath)
+	if timeout <= 0 {
+		timeout = DefaultExportTimeout
+	}
+	exportCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stderr := &tailBuffer{limit: exportStderrTailBytes}
+	exportCmd := exec.CommandContext(exportCtx, binaryPath)
 	exportCmd.Dir = originalCwd
-	exportCmd.Env = append(os.Environ(),
-		"BIFROST_EXPORT=1",
-		"BIFROST_EXPORT_DIR="+bifrostDir,
-	)
+	exportCmd.Env = exportEnv(bifrostDir, jobs)
 	exportCmd.Stdout = os.Stdout
-	exportCmd.Stderr = os.Stderr
+	exportCmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+	exportCmd.WaitDelay = exportWaitDelay
+	killProcessGroupOnCancel(exportCmd)
 
 	if err := exportCmd.Run(); err != nil {
-		return fmt.Errorf("export mode failed: %w", err)
+		if errors.Is(exportCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s (raise it with --export-timeout); a static data loader may be waiting on a slow service", timeout)
+		}
+		return exportFailure(err, stderr.String())
 	}
 
 	exportManifestPath := filepath.Join(bifrostDir, "export-manifest.json")
@@ -107,3 +151,126 @@ func (s *BuildService) compileEmbeddedRuntime(bifrostDir string) error {
 	_ = os.Remove(tempSourcePath)
 	return nil
 }
+
+// compressEmbeddedRuntime replaces the compiled renderer in runtimeDir with a gzipped copy
+// and a checksum file, which ExtractEmbeddedRuntime verifies after decompressing.
+func compressEmbeddedRuntime(runtimeDir string) error {
+	matches, err := filepath.Glob(filepath.Join(runtimeDir, "bifrost-renderer*"))
+	if err != nil {
+		return err
+	}
+	for _, binaryPath := range matches {
+		if filepath.Ext(binaryPath) != "" && filepath.Ext(binaryPath) != ".exe" {
+			continue
+		}
+		if err := compressFile(binaryPath, core.CompressedRuntimeSuffix, core.RuntimeChecksumSuffix); err != nil {
+			return fmt.Errorf("failed to compress %s: %w", filepath.Base(binaryPath), err)
+		}
+	}
+	return nil
+}
+
+// compressFile replaces the file at path with a gzipped copy (path+compressedSuffix) and
+// the hex SHA-256 of the original (path+checksumSuffix).
+func compressFile(path, compressedSuffix, checksumSuffix string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(path + compressedSuffix)
+	if err != nil {
+		return err
+	}
+	hash := sha256.New()
+	zw, err := gzip.NewWriterLevel(out, gzip.BestCompression)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	if _, err := io.Copy(io.MultiWriter(zw, hash), in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil)) + "\n"
+	if err := os.WriteFile(path+checksumSuffix, []byte(sum), 0o644); err != nil {
+		return err
+	}
+	_ = in.Close()
+	return os.Remove(path)
+}
+
+// exportFailure describes a failed export subprocess with the tail of its stderr and a
+// hint about the usual cause: the app never reaching Wrap.
+func exportFailure(err error, stderr string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%v", err)
+	if tail := lastLines(stderr, exportStderrTailLines); tail != "" {
+		b.WriteString("\nstderr (last lines):\n")
+		b.WriteString(tail)
+	}
+	b.WriteString("\n")
+	b.WriteString(exportHint)
+	return errors.New(b.String())
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	mu    sync.Mutex
+	limit int
+	buf   bytes.Buffer
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := len(p)
+	if len(p) > t.limit {
+		p = p[len(p)-t.limit:]
+	}
+	if over := t.buf.Len() + len(p) - t.limit; over > 0 {
+		t.buf.Next(over)
+	}
+	t.buf.Write(p)
+	return n, nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.buf.String()
+}
+
+// compressSSRBundles replaces every SSR bundle and chunk under ssrDir with a gzipped copy
+// and a checksum file, which StageSSRBundles verifies after decompressing.
+func compressSSRBundles(ssrDir string) error {
+	return filepath.WalkDir(ssrDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".js" {
+			return nil
+		}
+		if err := compressFile(path, core.CompressedSSRSuffix, core.SSRChecksumSuffix); err != nil {
+			return fmt.Errorf("failed to compress %s: %w", filepath.Base(path), err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package usecase
+
+import "os/exec"
+
+// killProcessGroupOnCancel leaves the default cancel behaviour, which kills only the
+// app process.
+func killProcessGroupOnCancel(cmd *exec.Cmd) {}
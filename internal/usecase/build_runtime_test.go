@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestTailBuffer_KeepsLastBytes(t *testing.T) {
+	tail := &tailBuffer{limit: 8}
+	_, _ = tail.Write([]byte("hello "))
+	_, _ = tail.Write([]byte("world"))
+	if got := tail.String(); got != "lo world" {
+		t.Fatalf("tail = %q, want %q", got, "lo world")
+	}
+
+	n, _ := tail.Write([]byte("0123456789"))
+	if n != 10 {
+		t.Fatalf("Write returned %d, want 10", n)
+	}
+	if got := tail.String(); got != "23456789" {
+		t.Fatalf("tail = %q, want %q", got, "23456789")
+	}
+}
+
+func TestExportFailure_IncludesStderrTailAndHint(t *testing.T) {
+	var stderr strings.Builder
+	for i := range 30 {
+		fmt.Fprintf(&stderr, "line %d\n", i)
+	}
+
+	err := exportFailure(errors.New("exit status 1"), stderr.String())
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "exit status 1\n") {
+		t.Fatalf("message should start with the cause, got %q", msg)
+	}
+	if strings.Contains(msg, "line 9\n") || !strings.Contains(msg, "line 10\n") || !strings.Contains(msg, "line 29") {
+		t.Fatalf("message should hold the last %d stderr lines, got %q", exportStderrTailLines, msg)
+	}
+	if !strings.Contains(msg, "app.Wrap") {
+		t.Fatalf("message should hint at Wrap, got %q", msg)
+	}
+
+	if msg := exportFailure(errors.New("boom"), "").Error(); strings.Contains(msg, "stderr") {
+		t.Fatalf("empty stderr should not add a section, got %q", msg)
+	}
+}
+
+func TestKillProcessGroupOnCancel_KillsChildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are unix only")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// The shell waits on a child sleep; killing only the shell would leave it running and
+	// keep the output pipe open.
+	cmd := exec.CommandContext(ctx, "sh", "-c", "sleep 30 & wait")
+	cmd.Stderr = &tailBuffer{limit: 64}
+	cmd.WaitDelay = 10 * time.Second
+	killProcessGroupOnCancel(cmd)
+
+	start := time.Now()
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected the command to be killed")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Run took %s; the child sleep survived the kill", elapsed)
+	}
+}
+
+func TestCompressEmbeddedRuntime(t *testing.T) {
+	runtimeDir := t.TempDir()
+	binary := bytes.Repeat([]byte("bun runtime "), 1000)
+	binaryPath := filepath.Join(runtimeDir, "bifrost-renderer")
+	writeTestFile(t, binaryPath, string(binary))
+
+	if err := compressEmbeddedRuntime(runtimeDir); err != nil {
+		t.Fatalf("compressEmbeddedRuntime: %v", err)
+	}
+
+	if _, err := os.Stat(binaryPath); !os.IsNotExist(err) {
+		t.Fatalf("uncompressed binary should be removed, stat err = %v", err)
+	}
+	f, err := os.Open(binaryPath + core.CompressedRuntimeSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, binary) {
+		t.Fatal("decompressed runtime differs from the original")
+	}
+
+	checksum, err := os.ReadFile(binaryPath + core.RuntimeChecksumSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(binary)
+	if got := strings.TrimSpace(string(checksum)); got != hex.EncodeToString(sum[:]) {
+		t.Fatalf("checksum = %s, want %x", got, sum)
+	}
+}
+
+func TestCompressSSRBundles(t *testing.T) {
+	ssrDir := t.TempDir()
+	bundle := filepath.Join(ssrDir, "pages-home-entry-ssr.js")
+	chunk := filepath.Join(ssrDir, "chunks", "chunk-a.js")
+	writeTestFile(t, bundle, "// home")
+	writeTestFile(t, chunk, "// chunk")
+	writeTestFile(t, filepath.Join(ssrDir, "pages-home-entry-ssr.js.map"), "{}")
+
+	if err := compressSSRBundles(ssrDir); err != nil {
+		t.Fatalf("compressSSRBundles: %v", err)
+	}
+
+	for path, want := range map[string]string{bundle: "// home", chunk: "// chunk"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed, stat err = %v", filepath.Base(path), err)
+		}
+		if _, err := os.Stat(path + core.SSRChecksumSuffix); err != nil {
+			t.Errorf("%s has no checksum file: %v", filepath.Base(path), err)
+		}
+		f, err := os.Open(path + core.CompressedSSRSuffix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(zr)
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("%s decompressed = %q, want %q", filepath.Base(path), data, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(ssrDir, "pages-home-entry-ssr.js.map")); err != nil {
+		t.Errorf("non-JS file should be left alone: %v", err)
+	}
+}
+
+func TestExportEnv(t *testing.T) {
+	env := exportEnv("/app/.bifrost", 8)
+	for _, want := range []string{"BIFROST_EXPORT=1", "BIFROST_EXPORT_DIR=/app/.bifrost", core.ExportJobsEnv + "=8"} {
+		if !slices.Contains(env, want) {
+			t.Errorf("env missing %s", want)
+		}
+	}
+	for _, kv := range exportEnv("/app/.bifrost", 0) {
+		if strings.HasPrefix(kv, core.ExportJobsEnv+"=") {
+			t.Errorf("env without --jobs has %s", kv)
+		}
+	}
+}
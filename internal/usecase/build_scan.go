@@ -1,14 +1,18 @@
 package usecase
 
 import (
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
@@ -31,26 +35,328 @@ func callExprSimpleName(call *ast.CallExpr) string {
 }
 
 func scanDefaultHTMLLang(f *ast.File) string {
-	var lang string
+	return scanStringOption(f, "WithDefaultHTMLLang")
+}
+
+// scanEnvironment returns the WithEnvironment name set in mainFile, if any.
+func scanEnvironment(mainFile string) (string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), mainFile, nil, 0)
+	if err != nil {
+		return "", err
+	}
+	return scanStringOption(node, "WithEnvironment"), nil
+}
+
+// scanBuildTarget returns the WithBuildTarget target set in mainFile, if any.
+func scanBuildTarget(mainFile string) (string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), mainFile, nil, 0)
+	if err != nil {
+		return "", err
+	}
+	return scanStringOption(node, "WithBuildTarget"), nil
+}
+
+// scanPreview reports whether mainFile calls WithPreview, in which case static pages keep
+// their SSR bundle and the Bun runtime.
+func scanPreview(mainFile string) (bool, error) {
+	return scanCallsOption(mainFile, "WithPreview")
+}
+
+// scanSRI reports whether mainFile calls WithSRI, in which case the build records
+// Subresource Integrity hashes in the manifest.
+func scanSRI(mainFile string) (bool, error) {
+	return scanCallsOption(mainFile, "WithSRI")
+}
+
+// scanStaticCacheManifest reports whether mainFile calls WithStaticCacheManifest, in
+// which case the build writes the asset cache manifest.
+func scanStaticCacheManifest(mainFile string) (bool, error) {
+	return scanCallsOption(mainFile, "WithStaticCacheManifest")
+}
+
+// scanRouteIndex reports whether mainFile calls WithRouteIndex, in which case the build
+// writes the route index of the static pages.
+func scanRouteIndex(mainFile string) (bool, error) {
+	return scanCallsOption(mainFile, "WithRouteIndex")
+}
+
+// scanCallsOption reports whether mainFile calls a function named option.
+func scanCallsOption(mainFile string, option string) (bool, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), mainFile, nil, 0)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok && callExprSimpleName(call) == option {
+			found = true
+		}
+		return !found
+	})
+	return found, nil
+}
+
+// scanSSREntryTemplate returns the WithCustomSSREntryTemplate template set in mainFile: a
+// string literal, or a constant or variable declared with one in the same file.
+func scanSSREntryTemplate(mainFile string) (string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), mainFile, nil, 0)
+	if err != nil {
+		return "", err
+	}
+	var value string
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || callExprSimpleName(call) != "WithCustomSSREntryTemplate" || len(call.Args) < 1 {
+			return true
+		}
+		if v, ok := fileStringValue(node, call.Args[0]); ok {
+			value = v
+		}
+		return true
+	})
+	return value, nil
+}
+
+// scanReactRuntime returns the WithReactRuntime import source set in mainFile: a string
+// literal, or a constant or variable declared with one in the same file.
+func scanReactRuntime(mainFile string) (string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), mainFile, nil, 0)
+	if err != nil {
+		return "", err
+	}
+	var value string
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || callExprSimpleName(call) != "WithReactRuntime" || len(call.Args) < 1 {
+			return true
+		}
+		if v, ok := fileStringValue(node, call.Args[0]); ok {
+			value = v
+		}
+		return true
+	})
+	return value, nil
+}
+
+// scanBuildEnv returns the WithBuildEnv entries set in mainFile. Each call must pass a
+// map literal, or a top-level variable initialized with one, whose keys and values are
+// string literals or constants; anything else is an error, since the build cannot
+// evaluate it.
+func scanBuildEnv(mainFile string) (map[string]string, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), mainFile, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var env map[string]string
+	ast.Inspect(node, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok || callExprSimpleName(call) != "WithBuildEnv" || len(call.Args) < 1 {
+			return true
+		}
+		lit, ok := fileCompositeLit(node, call.Args[0])
+		if !ok {
+			err = fmt.Errorf("WithBuildEnv needs a map literal that bifrost-build can read")
+			return false
+		}
+		if env == nil {
+			env = make(map[string]string, len(lit.Elts))
+		}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, keyOK := fileStringValue(node, kv.Key)
+			value, valueOK := fileStringValue(node, kv.Value)
+			if !keyOK || !valueOK {
+				err = fmt.Errorf("WithBuildEnv keys and values must be string literals or constants")
+				return false
+			}
+			env[key] = value
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// scanBuildConstants returns the WithBuildConstants values set in mainFile. Each call must
+// pass a map literal, or a top-level variable initialized with one, whose keys are string
+// literals or constants and whose values are string, number or boolean literals,
+// constants, or nil.
+func scanBuildConstants(mainFile string) (map[string]any, error) {
+	node, err := parser.ParseFile(token.NewFileSet(), mainFile, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	var constants map[string]any
+	ast.Inspect(node, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok || callExprSimpleName(call) != "WithBuildConstants" || len(call.Args) < 1 {
+			return true
+		}
+		lit, ok := fileCompositeLit(node, call.Args[0])
+		if !ok {
+			err = fmt.Errorf("WithBuildConstants needs a map literal that bifrost-build can read")
+			return false
+		}
+		if constants == nil {
+			constants = make(map[string]any, len(lit.Elts))
+		}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, keyOK := fileStringValue(node, kv.Key)
+			value, valueOK := fileLiteralValue(node, kv.Value)
+			if !keyOK || !valueOK {
+				err = fmt.Errorf("WithBuildConstants keys must be string literals or constants, and values string, number or boolean literals, constants or nil")
+				return false
+			}
+			constants[key] = value
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return constants, nil
+}
+
+// fileLiteralValue returns the value of expr when it is a string, number or boolean
+// literal, nil, or the name of a top-level constant or variable initialized with one.
+func fileLiteralValue(f *ast.File, expr ast.Expr) (any, bool) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		switch ident.Name {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		case "nil":
+			return nil, true
+		}
+		expr = fileTopLevelValue(f, ident.Name)
+		if expr == nil {
+			return nil, false
+		}
+		return fileLiteralValue(f, expr)
+	}
+	negative := false
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.SUB {
+		negative = true
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return nil, false
+	}
+	switch lit.Kind {
+	case token.STRING:
+		if negative {
+			return nil, false
+		}
+		value, err := strconv.Unquote(lit.Value)
+		return value, err == nil
+	case token.INT:
+		value, err := strconv.ParseInt(lit.Value, 0, 64)
+		if negative {
+			value = -value
+		}
+		return value, err == nil
+	case token.FLOAT:
+		value, err := strconv.ParseFloat(lit.Value, 64)
+		if negative {
+			value = -value
+		}
+		return value, err == nil
+	}
+	return nil, false
+}
+
+// fileCompositeLit returns expr when it is a composite literal, or the literal a top-level
+// variable named by expr is initialized with.
+func fileCompositeLit(f *ast.File, expr ast.Expr) (*ast.CompositeLit, bool) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		expr = fileTopLevelValue(f, ident.Name)
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	return lit, ok
+}
+
+// fileStringValue returns the value of expr when it is a string literal or the name of a
+// top-level constant or variable initialized with one.
+func fileStringValue(f *ast.File, expr ast.Expr) (string, bool) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		expr = fileTopLevelValue(f, ident.Name)
+	}
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// fileTopLevelValue returns the initial value of the top-level constant or variable
+// called name, or nil.
+func fileTopLevelValue(f *ast.File, name string) ast.Expr {
+	var value ast.Expr
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || (gen.Tok != token.CONST && gen.Tok != token.VAR) {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, ident := range vs.Names {
+				if ident.Name == name && i < len(vs.Values) {
+					value = vs.Values[i]
+				}
+			}
+		}
+	}
+	return value
+}
+
+// scanStringOption returns the string literal passed to the last call named option.
+func scanStringOption(f *ast.File, option string) string {
+	var value string
 	ast.Inspect(f, func(n ast.Node) bool {
 		call, ok := n.(*ast.CallExpr)
 		if !ok {
 			return true
 		}
-		if callExprSimpleName(call) != "WithDefaultHTMLLang" || len(call.Args) < 1 {
+		if callExprSimpleName(call) != option || len(call.Args) < 1 {
 			return true
 		}
 		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
 			if u, err := strconv.Unquote(lit.Value); err == nil {
-				lang = u
+				value = u
 			}
 		}
 		return true
 	})
-	return lang
+	return value
 }
 
-func parsePageBuildOptions(args []ast.Expr) (htmlLang string, htmlClass string) {
+// scannedRevalidate marks a page that calls WithRevalidate. The build only needs to know
+// that the page regenerates at runtime, not its TTL, which may not be a constant.
+const scannedRevalidate = time.Nanosecond
+
+// parsePageBuildOptions copies the page options the build depends on into config.
+func parsePageBuildOptions(args []ast.Expr, config *core.PageConfig) {
 	for _, arg := range args {
 		call, ok := arg.(*ast.CallExpr)
 		if !ok {
@@ -62,21 +368,192 @@ func parsePageBuildOptions(args []ast.Expr) (htmlLang string, htmlClass string)
 				continue
 			}
 			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
-				htmlLang, _ = strconv.Unquote(lit.Value)
+				config.HTMLLang, _ = strconv.Unquote(lit.Value)
 			}
 		case "WithHTMLClass":
 			if len(call.Args) < 1 {
 				continue
 			}
 			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
-				htmlClass, _ = strconv.Unquote(lit.Value)
+				config.HTMLClass, _ = strconv.Unquote(lit.Value)
+			}
+		case "WithPageGroup":
+			if len(call.Args) < 1 {
+				continue
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				config.Group, _ = strconv.Unquote(lit.Value)
+			}
+		case "WithErrorBoundary":
+			if len(call.Args) < 1 {
+				continue
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				config.ErrorBoundary, _ = strconv.Unquote(lit.Value)
+			}
+		case "WithLoadingShell":
+			if len(call.Args) < 1 {
+				continue
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				config.LoadingShell, _ = strconv.Unquote(lit.Value)
+			}
+		case "WithRevalidate":
+			config.Revalidate = scannedRevalidate
+		case "WithStaticNoJS":
+			config.NoJS = true
+		case "WithHydrationStrategy":
+			if len(call.Args) < 1 {
+				continue
 			}
+			config.Hydration = parseHydrationStrategy(call.Args[0])
 		}
 	}
-	return htmlLang, htmlClass
 }
 
-func (s *BuildService) scanPages(mainFile string) ([]core.PageConfig, string, error) {
+// parseHydrationStrategy resolves HydrationImmediate or bifrost.HydrationVisible style
+// identifiers; anything else keeps the default, HydrationLazy.
+func parseHydrationStrategy(expr ast.Expr) core.HydrationStrategy {
+	var name string
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	}
+	switch name {
+	case "HydrationImmediate":
+		return core.HydrationImmediate
+	case "HydrationVisible":
+		return core.HydrationVisible
+	default:
+		return core.HydrationLazy
+	}
+}
+
+// scanPagesDirCall returns the directory of a PagesFromDir("<dir>") call.
+func scanPagesDirCall(call *ast.CallExpr) (string, bool) {
+	if callExprSimpleName(call) != "PagesFromDir" || len(call.Args) < 1 {
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	dir, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return dir, true
+}
+
+// scanPagesDirs expands PagesFromDir calls into SSR page configs. Pages already declared
+// with an explicit Page call keep that declaration's mode and options.
+func scanPagesDirs(rootDir string, dirs []string, seen map[string]bool) []core.PageConfig {
+	var configs []core.PageConfig
+	for _, dir := range dirs {
+		absDir := dir
+		if !filepath.IsAbs(dir) {
+			absDir = filepath.Join(rootDir, dir)
+		}
+		routes, err := core.PagesFromFS(os.DirFS(absDir), dir)
+		if err != nil {
+			slog.Warn("Failed to scan pages directory", "dir", dir, "error", err)
+			continue
+		}
+		for _, route := range routes {
+			if seen[route.ComponentPath] {
+				continue
+			}
+			seen[route.ComponentPath] = true
+			configs = append(configs, core.PageConfigFromRoute(route))
+		}
+	}
+	return configs
+}
+
+// scanStatusPageCall returns the component of a WithStatusPage(code, "<component>") call.
+// Status pages are always server-rendered.
+func scanStatusPageCall(call *ast.CallExpr) (string, bool) {
+	if len(call.Args) < 2 {
+		return "", false
+	}
+	lit, ok := call.Args[1].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	path, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// scannedGroups maps RouteGroup variables in main.go to the page options they add.
+type scannedGroups map[string][]ast.Expr
+
+// scanRouteGroups collects variables assigned a Group call, bifrost.Group(opts...) or a
+// nested parent.Group("/base", opts...), with their options in the order they apply.
+func scanRouteGroups(f *ast.File) scannedGroups {
+	groups := make(scannedGroups)
+	record := func(name ast.Expr, value ast.Expr) {
+		ident, ok := name.(*ast.Ident)
+		if !ok {
+			return
+		}
+		if opts, ok := groups.options(value); ok {
+			groups[ident.Name] = opts
+		}
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for i := 0; i < len(stmt.Lhs) && i < len(stmt.Rhs); i++ {
+				record(stmt.Lhs[i], stmt.Rhs[i])
+			}
+		case *ast.ValueSpec:
+			for i := 0; i < len(stmt.Names) && i < len(stmt.Values); i++ {
+				record(stmt.Names[i], stmt.Values[i])
+			}
+		}
+		return true
+	})
+	return groups
+}
+
+// options returns the page options of the group expr evaluates to: a scanned group
+// variable or a Group call.
+func (g scannedGroups) options(expr ast.Expr) ([]ast.Expr, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		opts, ok := g[e.Name]
+		return opts, ok
+	case *ast.CallExpr:
+		if callExprSimpleName(e) != "Group" {
+			return nil, false
+		}
+		if sel, ok := e.Fun.(*ast.SelectorExpr); ok {
+			if parent, ok := g.options(sel.X); ok {
+				if len(e.Args) == 0 {
+					return parent, true
+				}
+				return append(slices.Clone(parent), e.Args[1:]...), true
+			}
+		}
+		return e.Args, true
+	}
+	return nil, false
+}
+
+type scannedPageDecl struct {
+	mode core.PageMode
+	pos  token.Position
+}
+
+// scanPages collects one page config per component path. When a component is declared
+// by several Page calls, the first call's mode and options win; differing modes are
+// reported because the other routes would be served from artifacts built for that mode.
+func (s *BuildService) scanPages(mainFile string, rootDir string) ([]core.PageConfig, string, error) {
 	fset := token.NewFileSet()
 	node, err := parser.ParseFile(fset, mainFile, nil, parser.ParseComments)
 	if err != nil {
@@ -84,9 +561,13 @@ func (s *BuildService) scanPages(mainFile string) ([]core.PageConfig, string, er
 	}
 
 	defaultHTMLLang := scanDefaultHTMLLang(node)
+	groups := scanRouteGroups(node)
 
 	var configs []core.PageConfig
+	var pagesDirs []string
+	var statusPages []string
 	seen := make(map[string]bool)
+	firstDecl := make(map[string]scannedPageDecl)
 
 	ast.Inspect(node, func(n ast.Node) bool {
 		callExpr, ok := n.(*ast.CallExpr)
@@ -94,18 +575,32 @@ func (s *BuildService) scanPages(mainFile string) ([]core.PageConfig, string, er
 			return true
 		}
 
+		if dir, ok := scanPagesDirCall(callExpr); ok {
+			pagesDirs = append(pagesDirs, dir)
+			return true
+		}
+
 		var funcName string
+		var groupOpts []ast.Expr
 		argIndex := 1
 
 		switch fn := callExpr.Fun.(type) {
 		case *ast.SelectorExpr:
 			funcName = fn.Sel.Name
+			groupOpts, _ = groups.options(fn.X)
 		case *ast.Ident:
 			funcName = fn.Name
 		default:
 			return true
 		}
 
+		if funcName == "WithStatusPage" {
+			if path, ok := scanStatusPageCall(callExpr); ok {
+				statusPages = append(statusPages, path)
+			}
+			return true
+		}
+
 		if funcName != "Page" {
 			return true
 		}
@@ -127,31 +622,67 @@ func (s *BuildService) scanPages(mainFile string) ([]core.PageConfig, string, er
 			return true
 		}
 
+		// A mode set on the page replaces the group's, as it does at runtime.
 		mode := s.detectPageMode(callExpr.Args[argIndex:])
+		if !hasPageModeOption(callExpr.Args[argIndex:]) {
+			mode = s.detectPageMode(groupOpts)
+		}
 
-		var optArgs []ast.Expr
+		optArgs := slices.Clone(groupOpts)
 		if len(callExpr.Args) > 2 {
-			optArgs = callExpr.Args[2:]
+			optArgs = append(optArgs, callExpr.Args[2:]...)
+		}
+
+		pos := fset.Position(callExpr.Pos())
+		if first, ok := firstDecl[path]; ok {
+			if first.mode != mode {
+				s.cli.PrintWarning("%s is registered as %s at %s and as %s at %s; the build uses the first mode (%s)",
+					path, first.mode.BuildLabel(), first.pos, mode.BuildLabel(), pos, first.mode.BuildLabel())
+			}
+		} else {
+			firstDecl[path] = scannedPageDecl{mode: mode, pos: pos}
 		}
-		htmlLang, htmlClass := parsePageBuildOptions(optArgs)
 
 		if !seen[path] {
 			seen[path] = true
-			configs = append(configs, core.PageConfig{
+			config := core.PageConfig{
 				ComponentPath:    path,
 				Mode:             mode,
-				HTMLLang:         htmlLang,
-				HTMLClass:        htmlClass,
 				StaticDataLoader: nil,
-			})
+			}
+			parsePageBuildOptions(optArgs, &config)
+			configs = append(configs, config)
 		}
 
 		return true
 	})
 
+	configs = append(configs, scanPagesDirs(rootDir, pagesDirs, seen)...)
+	for _, path := range statusPages {
+		if !seen[path] {
+			seen[path] = true
+			configs = append(configs, core.PageConfig{ComponentPath: path, Mode: core.ModeSSR})
+		}
+	}
+
 	return configs, defaultHTMLLang, nil
 }
 
+// hasPageModeOption reports whether args include an option that sets the page mode.
+func hasPageModeOption(args []ast.Expr) bool {
+	for _, arg := range args {
+		call, ok := arg.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		switch callExprSimpleName(call) {
+		case "WithClient", "WithStatic", "WithStaticNoJS", "WithStaticData", "WithStaticDataStream":
+			return true
+		}
+	}
+	return false
+}
+
 func (s *BuildService) detectPageMode(args []ast.Expr) core.PageMode {
 	hasClientOnly := false
 	hasStaticPrerender := false
@@ -173,9 +704,9 @@ func (s *BuildService) detectPageMode(args []ast.Expr) core.PageMode {
 		switch funcName {
 		case "WithClient":
 			hasClientOnly = true
-		case "WithStatic":
+		case "WithStatic", "WithStaticNoJS":
 			hasStaticPrerender = true
-		case "WithStaticData":
+		case "WithStaticData", "WithStaticDataStream":
 			hasStaticPrerender = true
 		}
 	}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// SSRLintMode controls how the build reports components that touch browser globals at
+// module top level.
+type SSRLintMode int
+
+const (
+	// SSRLintWarn reports each use as a build warning. It is the default.
+	SSRLintWarn SSRLintMode = iota
+	// SSRLintOff skips the check.
+	SSRLintOff
+	// SSRLintError fails the build on the first use.
+	SSRLintError
+)
+
+// ParseSSRLintMode parses the bifrost-build --ssr-lint value: off, warn or error.
+func ParseSSRLintMode(value string) (SSRLintMode, error) {
+	switch value {
+	case "warn":
+		return SSRLintWarn, nil
+	case "off":
+		return SSRLintOff, nil
+	case "error":
+		return SSRLintError, nil
+	}
+	return SSRLintWarn, fmt.Errorf("invalid --ssr-lint %q: want off, warn or error", value)
+}
+
+// browserGlobals are the globals that exist in the browser but not in the SSR runtime.
+var browserGlobals = []string{"window", "document", "localStorage", "sessionStorage", "navigator"}
+
+type browserGlobalUse struct {
+	Line   int
+	Global string
+}
+
+// scanTopLevelBrowserGlobals reports browser globals read outside any block in a component
+// source, where they run as soon as the SSR bundle is imported. It is a heuristic: strings
+// and comments are skipped, lines guarded with typeof and arrow function bodies without
+// braces are ignored, and imported modules are not followed.
+func scanTopLevelBrowserGlobals(src string) []browserGlobalUse {
+	return scanBrowserGlobals(src, true)
+}
+
+// scanBrowserGlobals reports browser globals read in a component source, only those at
+// module top level when topLevelOnly is set. Lines guarded with typeof are ignored.
+func scanBrowserGlobals(src string, topLevelOnly bool) []browserGlobalUse {
+	var uses []browserGlobalUse
+	depth := 0
+	line := 1
+	lineStart := 0
+	arrowOnLine := false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			lineStart = i + 1
+			arrowOnLine = false
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			end := strings.IndexByte(src[i:], '\n')
+			if end == -1 {
+				return uses
+			}
+			i += end - 1
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				return uses
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 3
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				} else if src[j] == '\n' {
+					line++
+				}
+				j++
+			}
+			i = j
+		case c == '{':
+			depth++
+		case c == '}':
+			if depth > 0 {
+				depth--
+			}
+		case c == '=' && i+1 < len(src) && src[i+1] == '>':
+			arrowOnLine = true
+		case isIdentByte(c) && (i == 0 || !isIdentByte(src[i-1])):
+			j := i
+			for j < len(src) && isIdentByte(src[j]) {
+				j++
+			}
+			word := src[i:j]
+			topLevel := depth == 0 && !arrowOnLine
+			if (topLevel || !topLevelOnly) && isBrowserGlobal(word) && !isPropertyAccess(src, i) && !lineHasTypeof(src, lineStart) {
+				uses = append(uses, browserGlobalUse{Line: line, Global: word})
+			}
+			i = j - 1
+		}
+	}
+	return uses
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isBrowserGlobal(word string) bool {
+	for _, g := range browserGlobals {
+		if word == g {
+			return true
+		}
+	}
+	return false
+}
+
+// isPropertyAccess reports whether the identifier at i follows a dot, as in props.document.
+func isPropertyAccess(src string, i int) bool {
+	for i--; i >= 0; i-- {
+		switch src[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '.':
+			return !(i > 0 && src[i-1] == '.')
+		}
+		return false
+	}
+	return false
+}
+
+func lineHasTypeof(src string, lineStart int) bool {
+	end := strings.IndexByte(src[lineStart:], '\n')
+	if end == -1 {
+		end = len(src) - lineStart
+	}
+	return strings.Contains(src[lineStart:lineStart+end], "typeof ")
+}
+
+// lintBrowserGlobals checks every server-rendered page component for browser globals used
+// at module top level, which throw when the SSR bundle loads.
+func (s *BuildService) lintBrowserGlobals(run *buildRun) {
+	if run.input.SSRLint == SSRLintOff {
+		return
+	}
+	for _, page := range run.pages {
+		if page.config.Mode == core.ModeClientOnly {
+			continue
+		}
+		data, err := os.ReadFile(page.absComponentPath)
+		if err != nil {
+			continue
+		}
+		uses := scanTopLevelBrowserGlobals(string(data))
+		if len(uses) == 0 {
+			continue
+		}
+		details := make([]string, len(uses))
+		for i, use := range uses {
+			details[i] = fmt.Sprintf("%s:%d: %s is read at module top level", page.config.ComponentPath, use.Line, use.Global)
+		}
+		details = append(details, "Move the access into useEffect or an event handler, or render the page with WithClient()")
+		message := "Browser globals used at module top level will fail during SSR"
+		if run.input.SSRLint == SSRLintError {
+			run.addError(page.entryName, message, details)
+		} else {
+			run.addWarning(page.entryName, message, details)
+		}
+	}
+}
+
+// lintBuildTarget fails the build of every page component that reads browser globals when
+// WithBuildTarget bundles client code for bun or node, where they do not exist.
+func (s *BuildService) lintBuildTarget(run *buildRun) {
+	if run.buildTarget == "" || run.buildTarget == core.BuildTargetBrowser {
+		return
+	}
+	for _, page := range run.pages {
+		if page.config.NoJS {
+			continue
+		}
+		data, err := os.ReadFile(page.absComponentPath)
+		if err != nil {
+			continue
+		}
+		uses := scanBrowserGlobals(string(data), false)
+		if len(uses) == 0 {
+			continue
+		}
+		details := make([]string, len(uses))
+		for i, use := range uses {
+			details[i] = fmt.Sprintf("%s:%d: %s is read", page.config.ComponentPath, use.Line, use.Global)
+		}
+		details = append(details, "Guard the access with typeof, or remove WithBuildTarget to build for the browser")
+		run.addError(page.entryName, "Browser globals are not available for build target "+run.buildTarget, details)
+	}
+}
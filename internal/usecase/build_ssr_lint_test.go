@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/adapters/cli"
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestScanTopLevelBrowserGlobals(t *testing.T) {
+	src := `import { useEffect } from "react";
+// window.scrollTo(0, 0) in a comment
+const theme = localStorage.getItem("theme");
+const label = "document title";
+/* document
+   spans lines */
+const width = window.innerWidth;
+const isBrowser = typeof window !== "undefined" ? window.location.href : "";
+const read = () => document.cookie;
+const props = { document: 1 };
+const title = props.document;
+
+export function Page() {
+  useEffect(() => {
+    document.title = "Home";
+  }, []);
+  return <p>{navigator.userAgent}</p>;
+}
+`
+	var got []string
+	for _, use := range scanTopLevelBrowserGlobals(src) {
+		got = append(got, fmt.Sprintf("%s:%d", use.Global, use.Line))
+	}
+	if want := "localStorage:3,window:7"; strings.Join(got, ",") != want {
+		t.Errorf("uses = %v, want %s", got, want)
+	}
+}
+
+func TestParseSSRLintMode(t *testing.T) {
+	for value, want := range map[string]SSRLintMode{"off": SSRLintOff, "warn": SSRLintWarn, "error": SSRLintError} {
+		got, err := ParseSSRLintMode(value)
+		if err != nil || got != want {
+			t.Errorf("ParseSSRLintMode(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	if _, err := ParseSSRLintMode("strict"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
+
+func TestLintBrowserGlobals(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), "const w = window.innerWidth;\nexport function Page() { return null; }\n")
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "app.tsx"), "const w = window.innerWidth;\n")
+
+	newRun := func(mode SSRLintMode, events *[]BuildEvent) *buildRun {
+		return &buildRun{
+			input:  BuildInput{SSRLint: mode, OnEvent: func(e BuildEvent) { *events = append(*events, e) }},
+			report: cli.NewBuildReport(&mockCLIOutput{}, tmpDir),
+			pages: []buildPage{
+				{config: core.PageConfig{ComponentPath: "./pages/home.tsx", Mode: core.ModeSSR}, entryName: "pages-home-entry", absComponentPath: filepath.Join(tmpDir, "pages", "home.tsx")},
+				{config: core.PageConfig{ComponentPath: "./pages/app.tsx", Mode: core.ModeClientOnly}, entryName: "pages-app-entry", absComponentPath: filepath.Join(tmpDir, "pages", "app.tsx")},
+			},
+		}
+	}
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+
+	var events []BuildEvent
+	run := newRun(SSRLintWarn, &events)
+	service.lintBrowserGlobals(run)
+	if len(events) != 1 {
+		t.Fatalf("events = %+v, want one warning for the SSR page", events)
+	}
+	warning, ok := events[0].(BuildWarning)
+	if !ok || warning.Page != "pages-home-entry" || !strings.Contains(strings.Join(warning.Details, "\n"), "./pages/home.tsx:1: window") {
+		t.Errorf("warning = %+v", events[0])
+	}
+	if run.report.HasFailures() {
+		t.Error("warn mode should not fail the build")
+	}
+
+	events = nil
+	run = newRun(SSRLintError, &events)
+	service.lintBrowserGlobals(run)
+	if len(events) != 1 || !run.report.HasFailures() {
+		t.Errorf("error mode: events = %+v, failures = %v", events, run.report.HasFailures())
+	}
+
+	events = nil
+	service.lintBrowserGlobals(newRun(SSRLintOff, &events))
+	if len(events) != 0 {
+		t.Errorf("off mode: events = %+v", events)
+	}
+}
+
+func TestLintBuildTarget(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), "export function Page() {\n\tconst wide = typeof window !== \"undefined\" && window.innerWidth;\n\treturn <p>{window.location.pathname}</p>;\n}\n")
+
+	newRun := func(target string, events *[]BuildEvent) *buildRun {
+		return &buildRun{
+			input:       BuildInput{OnEvent: func(e BuildEvent) { *events = append(*events, e) }},
+			report:      cli.NewBuildReport(&mockCLIOutput{}, tmpDir),
+			buildTarget: target,
+			pages: []buildPage{
+				{config: core.PageConfig{ComponentPath: "./pages/home.tsx", Mode: core.ModeClientOnly}, entryName: "pages-home-entry", absComponentPath: filepath.Join(tmpDir, "pages", "home.tsx")},
+			},
+		}
+	}
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+
+	var events []BuildEvent
+	run := newRun(core.BuildTargetNode, &events)
+	service.lintBuildTarget(run)
+	if len(events) != 1 || !run.report.HasFailures() {
+		t.Fatalf("node target: events = %+v, failures = %v", events, run.report.HasFailures())
+	}
+	failure, ok := events[0].(BuildFailure)
+	if !ok || !strings.Contains(failure.Message, "build target node") {
+		t.Fatalf("event = %+v, want a build target error", events[0])
+	}
+	if details := strings.Join(failure.Details, "\n"); !strings.Contains(details, "./pages/home.tsx:3: window") || strings.Contains(details, "home.tsx:2") {
+		t.Errorf("details = %q, want only the unguarded read on line 3", details)
+	}
+
+	for _, target := range []string{"", core.BuildTargetBrowser} {
+		events = nil
+		run = newRun(target, &events)
+		service.lintBuildTarget(run)
+		if len(events) != 0 || run.report.HasFailures() {
+			t.Errorf("target %q: events = %+v", target, events)
+		}
+	}
+}
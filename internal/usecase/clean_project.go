@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// generatedArtifacts are the .bifrost entries written by the build; BuildProject
+// recreates all of them on the next run.
+var generatedArtifacts = []string{
+	"dist",
+	"ssr",
+	"entries",
+	"pages",
+	"runtime",
+	"public",
+	"manifest.json",
+	"export-manifest.json",
+}
+
+const bifrostGitkeep = ".gitkeep"
+
+// CleanProject removes generated build artifacts from projectDir/.bifrost and returns the
+// removed paths. With all set, every other entry in .bifrost (caches, stale files from
+// older versions) is removed as well. .bifrost/.gitkeep is always preserved.
+func CleanProject(projectDir string, all bool) ([]string, error) {
+	bifrostDir := filepath.Join(projectDir, core.OutputDir)
+
+	names := generatedArtifacts
+	if all {
+		entries, err := os.ReadDir(bifrostDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read .bifrost dir: %w", err)
+		}
+		names = names[:0:0]
+		for _, entry := range entries {
+			if entry.Name() != bifrostGitkeep {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+
+	var removed []string
+	for _, name := range names {
+		path := filepath.Join(bifrostDir, name)
+		if _, err := os.Lstat(path); err != nil {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		removed = append(removed, path)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCleanProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		all     bool
+		removed []string
+		kept    []string
+	}{
+		{
+			name: "generated artifacts only",
+			removed: []string{
+				"dist/app.js",
+				"ssr/app-ssr.js",
+				"entries/app.tsx",
+				"pages/routes/index.html",
+				"runtime/bifrost-renderer",
+				"public/logo.svg",
+				"manifest.json",
+				"export-manifest.json",
+			},
+			kept: []string{".gitkeep", "cache/build.json"},
+		},
+		{
+			name:    "all",
+			all:     true,
+			removed: []string{"dist/app.js", "manifest.json", "cache/build.json"},
+			kept:    []string{".gitkeep"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for _, rel := range append(append([]string{}, tt.removed...), tt.kept...) {
+				writeTestFile(t, filepath.Join(tmpDir, ".bifrost", rel), "x")
+			}
+
+			if _, err := CleanProject(tmpDir, tt.all); err != nil {
+				t.Fatalf("CleanProject() error = %v", err)
+			}
+
+			for _, rel := range tt.removed {
+				if _, err := os.Stat(filepath.Join(tmpDir, ".bifrost", rel)); !os.IsNotExist(err) {
+					t.Errorf("expected %s removed", rel)
+				}
+			}
+			for _, rel := range tt.kept {
+				if _, err := os.Stat(filepath.Join(tmpDir, ".bifrost", rel)); err != nil {
+					t.Errorf("expected %s kept: %v", rel, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCleanProjectMissingBifrostDir(t *testing.T) {
+	for _, all := range []bool{false, true} {
+		removed, err := CleanProject(t.TempDir(), all)
+		if err != nil || len(removed) != 0 {
+			t.Fatalf("CleanProject(all=%v) = %v, %v; want nothing removed", all, removed, err)
+		}
+	}
+}
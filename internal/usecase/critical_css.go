@@ -2,14 +2,17 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
 
 type stylesheetCache struct {
+	mu    sync.Mutex
 	byKey map[string]string
 }
 
@@ -56,12 +59,16 @@ func (s *BuildService) renderCriticalHTML(ctx context.Context, run *buildRun, pa
 		return ""
 	case core.ModeStaticPrerender:
 		props := map[string]any{}
-		if page.config.StaticDataLoader != nil {
-			entries, err := page.config.StaticDataLoader(ctx)
-			if err != nil || len(entries) == 0 {
+		if page.config.HasStaticData() {
+			var first *core.StaticPathData
+			err := core.EachStaticPath(ctx, page.config, func(entry core.StaticPathData) error {
+				first = &entry
+				return errStopStaticPaths
+			})
+			if first == nil || (err != nil && !errors.Is(err, errStopStaticPaths)) {
 				return ""
 			}
-			props = entries[0].Props
+			props = first.Props
 		}
 		return s.renderCriticalPage(filepath.Join(run.paths.bifrostDir, "ssr", page.entryName+"-ssr.js"), props)
 	default:
@@ -94,6 +101,8 @@ func (c *stylesheetCache) load(root string, hrefs []string) string {
 		return ""
 	}
 	key := root + "\x00" + strings.Join(hrefs, "\x00")
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if css, ok := c.byKey[key]; ok {
 		return css
 	}
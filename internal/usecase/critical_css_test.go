@@ -35,8 +35,10 @@ func TestWriteClientOnlyHTML_IncludesCriticalAndStylesheet(t *testing.T) {
 		".hero{display:block}",
 		[]string{"/dist/page.css"},
 		[]string{"/dist/chunk-a.js"},
+		nil,
 		"en",
 		"",
+		"staging",
 	)
 	if err != nil {
 		t.Fatalf("writeClientOnlyHTML failed: %v", err)
@@ -56,6 +58,9 @@ func TestWriteClientOnlyHTML_IncludesCriticalAndStylesheet(t *testing.T) {
 	if strings.Contains(html, `media="print"`) {
 		t.Fatal("did not expect deferred non-critical stylesheet when critical CSS is present")
 	}
+	if !strings.Contains(html, `<div id="app" data-bifrost-env="staging"></div>`) {
+		t.Fatal("expected environment attribute on #app")
+	}
 }
 
 func TestWriteClientOnlyHTML_MultipleStylesheets(t *testing.T) {
@@ -70,8 +75,10 @@ func TestWriteClientOnlyHTML_MultipleStylesheets(t *testing.T) {
 		"",
 		[]string{"/dist/a.css", "/dist/b.css"},
 		nil,
+		nil,
 		"en",
 		"",
+		"",
 	)
 	if err != nil {
 		t.Fatalf("writeClientOnlyHTML failed: %v", err)
@@ -126,3 +133,33 @@ func TestPageServiceRenderPageHTML_IncludesCriticalAndStylesheet(t *testing.T) {
 		t.Fatal("expected one blocking stylesheet link per href")
 	}
 }
+
+func TestWriteClientOnlyHTML_SRI(t *testing.T) {
+	svc := &BuildService{}
+	htmlPath := filepath.Join(t.TempDir(), "page.html")
+	sri := map[string]string{
+		"/dist/page.js":    "sha384-script",
+		"/dist/page.css":   "sha384-style",
+		"/dist/chunk-a.js": "sha384-chunk",
+	}
+
+	err := svc.writeClientOnlyHTML(htmlPath, "Client Page", "/dist/page.js", "", []string{"/dist/page.css"}, []string{"/dist/chunk-a.js"}, sri, "en", "", "")
+	if err != nil {
+		t.Fatalf("writeClientOnlyHTML failed: %v", err)
+	}
+	data, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{
+		`<link rel="stylesheet" href="/dist/page.css" integrity="sha384-style" crossorigin="anonymous" />`,
+		`<link rel="modulepreload" href="/dist/chunk-a.js" integrity="sha384-chunk" crossorigin="anonymous" />`,
+		`<script src="/dist/chunk-a.js" type="module" integrity="sha384-chunk" crossorigin="anonymous" defer></script>`,
+		`<script src="/dist/page.js" integrity="sha384-script" crossorigin="anonymous" type="module"></script>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("missing %s in:\n%s", want, html)
+		}
+	}
+}
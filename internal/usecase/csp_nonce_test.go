@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestRenderSSRPassesCSPNonceToPropsAndShell(t *testing.T) {
+	var renderedProps map[string]any
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			renderedProps = props
+			if err := onHead(""); err != nil {
+				return err
+			}
+			_, err := w.Write([]byte("<div>Hello</div>"))
+			return err
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+
+	shell, err := core.NewHTMLDocumentShell("/dist/home.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(core.ContextWithCSPNonce(req.Context(), "abc"))
+
+	output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/home.tsx",
+			Mode:          core.ModeSSR,
+		},
+		EntryName:   "pages-home-entry",
+		RequestPath: "/",
+		Request:     req,
+		Shell:       &shell,
+	}))
+	if output.Error != nil {
+		t.Fatalf("renderSSR() error = %v", output.Error)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := output.Stream(rec); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+	if renderedProps[core.PropNonce] != "abc" {
+		t.Errorf("component props = %v, want %s=abc", renderedProps, core.PropNonce)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`<script id="__BIFROST_PROPS__" type="application/json" nonce="abc">{"__nonce":"abc"}</script>`,
+		`<script src="/dist/home.js" type="module" nonce="abc" defer></script>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in\n%s", want, body)
+		}
+	}
+}
@@ -48,10 +48,19 @@ func WriteStaticBuildExport(w io.Writer, routes []core.Route, pageConfigs map[st
 			continue
 		}
 
-		var entries []core.StaticPathData
-		if config.StaticDataLoader != nil {
-			var err error
-			entries, err = config.StaticDataLoader(ctx)
+		pageExport := staticPageExport{
+			ComponentPath: componentPath,
+			Entries:       make([]staticPathExport, 0),
+		}
+
+		if config.HasStaticData() {
+			err := core.EachStaticPath(ctx, *config, func(entry core.StaticPathData) error {
+				pageExport.Entries = append(pageExport.Entries, staticPathExport{
+					Path:  entry.Path,
+					Props: entry.Props,
+				})
+				return nil
+			})
 			if err != nil {
 				return fmt.Errorf("failed to load static data for %s: %w", componentPath, err)
 			}
@@ -60,24 +69,10 @@ func WriteStaticBuildExport(w io.Writer, routes []core.Route, pageConfigs map[st
 			if pattern == "" {
 				continue
 			}
-			entries = []core.StaticPathData{
-				{
-					Path:  pattern,
-					Props: map[string]any{},
-				},
-			}
-		}
-
-		pageExport := staticPageExport{
-			ComponentPath: componentPath,
-			Entries:       make([]staticPathExport, len(entries)),
-		}
-
-		for i, entry := range entries {
-			pageExport.Entries[i] = staticPathExport{
-				Path:  entry.Path,
-				Props: entry.Props,
-			}
+			pageExport.Entries = append(pageExport.Entries, staticPathExport{
+				Path:  pattern,
+				Props: map[string]any{},
+			})
 		}
 
 		export.Pages = append(export.Pages, pageExport)
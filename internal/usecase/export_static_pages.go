@@ -8,6 +8,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
@@ -29,9 +30,27 @@ func ExportStaticPages(in ExportStaticPagesInput) error {
 	}
 
 	exportManifest := &core.Manifest{
+		Version: core.ManifestVersion,
 		Entries: make(map[string]core.ManifestEntry),
 	}
 	cache := stylesheetCache{byKey: make(map[string]string)}
+	globalHead := ""
+	var title core.TitleConfig
+	var linkRewriter core.LinkRewriter
+	var environment string
+	var preloads []string
+	concurrency := 1
+	ctx := context.Background()
+	if in.AppConfig != nil {
+		ctx = core.ContextWithDependencies(ctx, in.AppConfig.Dependencies)
+		ctx = core.ContextWithBuildTime(ctx, in.AppConfig.BuildTime)
+		globalHead = core.RenderMetaTags(in.AppConfig.MetaTags)
+		title = in.AppConfig.Title
+		linkRewriter = in.AppConfig.LinkRewriter
+		environment = in.AppConfig.Environment
+		preloads = in.AppConfig.PreloadPaths
+		concurrency = in.AppConfig.StaticExportConcurrency
+	}
 
 	for _, route := range in.Routes {
 		config := core.PageConfigFromRoute(route)
@@ -46,23 +65,6 @@ func ExportStaticPages(in ExportStaticPagesInput) error {
 			continue
 		}
 
-		var entries []core.StaticPathData
-		if config.StaticDataLoader != nil {
-			var err error
-			entries, err = config.StaticDataLoader(context.Background())
-			if err != nil {
-				fmt.Printf("Warning: Failed to load static data for %s: %v, skipping\n", route.Pattern, err)
-				continue
-			}
-		} else {
-			entries = []core.StaticPathData{
-				{
-					Path:  route.Pattern,
-					Props: map[string]any{},
-				},
-			}
-		}
-
 		srcEntry := core.ManifestEntry{}
 		if in.Manifest != nil {
 			srcEntry = in.Manifest.Entries[entryName]
@@ -75,22 +77,25 @@ func ExportStaticPages(in ExportStaticPagesInput) error {
 			CSSFiles:     srcEntry.CSSFiles,
 			Chunks:       srcEntry.Chunks,
 			Mode:         "static",
+			NoJS:         srcEntry.NoJS,
 			StaticRoutes: make(map[string]string),
 		}
 
-		for _, entry := range entries {
+		exportPath := func(entry core.StaticPathData) ExportResult {
 			fmt.Printf("Exporting %s...\n", entry.Path)
+			result := ExportResult{Path: entry.Path}
 
 			appDefault := ""
 			if in.AppConfig != nil {
 				appDefault = in.AppConfig.DefaultHTMLLang
 			}
 			lang, htmlClass, propsForReact := core.ResolveHTMLDocumentAttrs(appDefault, config.HTMLLang, config.HTMLClass, entry.Props)
+			propsForReact = core.WithSlotsProp(propsForReact, config.Slots)
 
 			page, err := in.Renderer.Render(ssrBundlePath, propsForReact)
 			if err != nil {
-				fmt.Printf("Warning: Failed to render %s: %v, skipping\n", entry.Path, err)
-				continue
+				result.Error = fmt.Errorf("failed to render %s: %w", entry.Path, err)
+				return result
 			}
 
 			criticalCSS := manifestEntry.CriticalCSS
@@ -104,56 +109,173 @@ func ExportStaticPages(in ExportStaticPagesInput) error {
 				}
 			}
 
-			html, err := core.RenderHTMLShell(page.Body, propsForReact, manifestEntry.Script, page.Head, criticalCSS, styleHrefs, manifestEntry.Chunks, lang, htmlClass)
-			if err != nil {
-				fmt.Printf("Warning: Failed to build HTML for %s: %v, skipping\n", entry.Path, err)
-				continue
+			html := page.Body
+			if core.IsHTMLContentType(config.ContentType) {
+				var shell core.HTMLDocumentShell
+				var err error
+				if config.NoJS {
+					shell = core.NewStaticHTMLShell(criticalCSS, styleHrefs)
+				} else {
+					shell, err = core.NewHTMLDocumentShell(manifestEntry.Script, criticalCSS, styleHrefs, manifestEntry.Chunks)
+				}
+				if err == nil {
+					html, err = shell.WithIntegrity(manifestEntry.SRI).WithPreloads(preloads).RewriteLinks(linkRewriter).ForEnvironment(environment).Render(page.Body, propsForReact, title.Apply(globalHead+page.Head), lang, htmlClass)
+				}
+				if err != nil {
+					result.Error = fmt.Errorf("failed to build HTML for %s: %w", entry.Path, err)
+					return result
+				}
 			}
 
 			cleanedRoutePath := path.Clean("/" + entry.Path)
 			if strings.Contains(cleanedRoutePath, "..") {
-				fmt.Printf("Warning: Unsafe route path %s, skipping\n", entry.Path)
-				continue
+				result.Error = fmt.Errorf("unsafe route path %s", entry.Path)
+				return result
 			}
 
 			htmlPath := filepath.Join(pagesDir, filepath.FromSlash(cleanedRoutePath), "index.html")
 			absHTML, err := filepath.Abs(htmlPath)
 			if err != nil {
-				fmt.Printf("Warning: Failed to resolve path for %s: %v, skipping\n", entry.Path, err)
-				continue
+				result.Error = fmt.Errorf("failed to resolve path for %s: %w", entry.Path, err)
+				return result
 			}
 			absPages, err := filepath.Abs(pagesDir)
 			if err != nil {
-				fmt.Printf("Warning: Failed to resolve pages dir: %v, skipping\n", err)
-				continue
+				result.Error = fmt.Errorf("failed to resolve pages dir: %w", err)
+				return result
 			}
 			if !strings.HasPrefix(absHTML, absPages+string(filepath.Separator)) {
-				fmt.Printf("Warning: Route path %s escapes output directory, skipping\n", entry.Path)
-				continue
+				result.Error = fmt.Errorf("route path %s escapes output directory", entry.Path)
+				return result
 			}
 
 			if err := os.MkdirAll(filepath.Dir(htmlPath), 0755); err != nil {
-				fmt.Printf("Warning: Failed to create directory for %s: %v, skipping\n", entry.Path, err)
-				continue
+				result.Error = fmt.Errorf("failed to create directory for %s: %w", entry.Path, err)
+				return result
 			}
 
 			if err := os.WriteFile(htmlPath, []byte(html), 0644); err != nil {
-				fmt.Printf("Warning: Failed to write %s: %v, skipping\n", entry.Path, err)
-				continue
+				result.Error = fmt.Errorf("failed to write %s: %w", entry.Path, err)
+				return result
 			}
 
-			normalizedPath := core.NormalizePath(entry.Path)
-			manifestEntry.StaticRoutes[normalizedPath] = "/pages/routes" + cleanedRoutePath + "/index.html"
+			result.Route = core.NormalizePath(entry.Path)
+			result.File = "/pages/routes" + cleanedRoutePath + "/index.html"
+			return result
+		}
+
+		var results []ExportResult
+		var loadErr error
+		if config.HasStaticData() {
+			results, loadErr = exportStaticPaths(ctx, config, concurrency, exportPath)
+			if loadErr != nil {
+				fmt.Printf("Warning: Failed to load static data for %s: %v, skipping\n", route.Pattern, loadErr)
+			}
+		} else {
+			results = []ExportResult{exportPath(core.StaticPathData{Path: route.Pattern, Props: map[string]any{}})}
+		}
+		for _, result := range results {
+			if result.Error != nil {
+				fmt.Printf("Warning: %v, skipping\n", result.Error)
+				continue
+			}
+			manifestEntry.StaticRoutes[result.Route] = result.File
+		}
+		if loadErr != nil && len(manifestEntry.StaticRoutes) == 0 {
+			continue
 		}
 
 		exportManifest.Entries[entryName] = manifestEntry
+		if err := writeExportManifest(in.OutputDir, exportManifest); err != nil {
+			return err
+		}
+	}
+
+	return writeExportManifest(in.OutputDir, exportManifest)
+}
+
+// ExportResult is the outcome of exporting one static path. Route and File are set when
+// the page was written; Error when it was skipped.
+type ExportResult struct {
+	Path  string
+	Route string
+	File  string
+	Error error
+}
+
+// exportStaticPaths runs export for every path of config, up to concurrency at a time,
+// and returns the results in the order the data loader produced the paths. A failing path
+// does not stop the others. A path the loader already produced is not exported again, so
+// concurrent renders never write the same file; its result carries an error instead. The
+// error is the data loader's; results then cover the paths it produced before failing.
+func exportStaticPaths(ctx context.Context, config core.PageConfig, concurrency int, export func(core.StaticPathData) ExportResult) ([]ExportResult, error) {
+	seen := make(map[string]struct{})
+	duplicate := func(entry core.StaticPathData) bool {
+		route := core.NormalizePath(entry.Path)
+		if _, ok := seen[route]; ok {
+			return true
+		}
+		seen[route] = struct{}{}
+		return false
 	}
+	duplicateResult := func(entry core.StaticPathData) ExportResult {
+		return ExportResult{Path: entry.Path, Error: fmt.Errorf("duplicate static path %s", entry.Path)}
+	}
+
+	if concurrency <= 1 {
+		var results []ExportResult
+		err := core.EachStaticPath(ctx, config, func(entry core.StaticPathData) error {
+			if duplicate(entry) {
+				results = append(results, duplicateResult(entry))
+				return nil
+			}
+			results = append(results, export(entry))
+			return nil
+		})
+		return results, err
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results []ExportResult
+	)
+	sem := make(chan struct{}, concurrency)
+	err := core.EachStaticPath(ctx, config, func(entry core.StaticPathData) error {
+		mu.Lock()
+		index := len(results)
+		if duplicate(entry) {
+			results = append(results, duplicateResult(entry))
+			mu.Unlock()
+			return nil
+		}
+		results = append(results, ExportResult{Path: entry.Path})
+		mu.Unlock()
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result := export(entry)
+			mu.Lock()
+			results[index] = result
+			mu.Unlock()
+		}()
+		return nil
+	})
+	wg.Wait()
+	return results, err
+}
 
+// writeExportManifest is called after every page, so an export interrupted partway through
+// a long path stream leaves a manifest that covers the pages already written.
+func writeExportManifest(outputDir string, exportManifest *core.Manifest) error {
 	manifestData, err := json.MarshalIndent(exportManifest, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal export manifest: %w", err)
 	}
 
-	manifestPath := filepath.Join(in.OutputDir, "export-manifest.json")
+	manifestPath := filepath.Join(outputDir, "export-manifest.json")
 	return os.WriteFile(manifestPath, manifestData, 0644)
 }
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// concurrentRenderer renders paths in parallel, later paths first, and records the
+// highest number of renders in flight.
+type concurrentRenderer struct {
+	fakeRenderer
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (r *concurrentRenderer) Render(componentPath string, props map[string]any) (core.RenderedPage, error) {
+	n := r.inFlight.Add(1)
+	defer r.inFlight.Add(-1)
+	for {
+		peak := r.peak.Load()
+		if n <= peak || r.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	index := props["index"].(int)
+	time.Sleep(time.Duration(6-index) * 5 * time.Millisecond)
+	if index == 2 {
+		return core.RenderedPage{}, errors.New("boom")
+	}
+	return core.RenderedPage{Body: fmt.Sprintf("<p>%d</p>", index)}, nil
+}
+
+func TestExportStaticPathsConcurrently(t *testing.T) {
+	var paths []core.StaticPathData
+	for i := range 6 {
+		paths = append(paths, core.StaticPathData{Path: fmt.Sprintf("/docs/%d", i), Props: map[string]any{"index": i}})
+	}
+	config := core.PageConfigFromRoute(core.Page("/docs/{n}", "./pages/docs.tsx", core.WithStaticData(func(context.Context) ([]core.StaticPathData, error) {
+		return paths, nil
+	})))
+
+	renderer := &concurrentRenderer{}
+	results, err := exportStaticPaths(context.Background(), config, 3, func(entry core.StaticPathData) ExportResult {
+		if _, err := renderer.Render("", entry.Props); err != nil {
+			return ExportResult{Path: entry.Path, Error: err}
+		}
+		return ExportResult{Path: entry.Path, Route: entry.Path}
+	})
+	if err != nil {
+		t.Fatalf("exportStaticPaths() error = %v", err)
+	}
+	if len(results) != len(paths) {
+		t.Fatalf("got %d results, want %d", len(results), len(paths))
+	}
+	for i, result := range results {
+		if result.Path != paths[i].Path {
+			t.Errorf("results[%d].Path = %q, want %q", i, result.Path, paths[i].Path)
+		}
+		if (result.Error != nil) != (i == 2) {
+			t.Errorf("results[%d].Error = %v", i, result.Error)
+		}
+	}
+	if peak := renderer.peak.Load(); peak < 2 || peak > 3 {
+		t.Errorf("peak concurrency = %d, want 2 or 3", peak)
+	}
+}
+
+func TestExportStaticPathsSkipsDuplicates(t *testing.T) {
+	paths := []core.StaticPathData{{Path: "/docs/a"}, {Path: "/docs/b"}, {Path: "/docs/a/"}, {Path: "/docs/b"}}
+	config := core.PageConfigFromRoute(core.Page("/docs/{n}", "./pages/docs.tsx", core.WithStaticData(func(context.Context) ([]core.StaticPathData, error) {
+		return paths, nil
+	})))
+
+	for _, concurrency := range []int{1, 4} {
+		var exported atomic.Int32
+		results, err := exportStaticPaths(context.Background(), config, concurrency, func(entry core.StaticPathData) ExportResult {
+			exported.Add(1)
+			return ExportResult{Path: entry.Path, Route: core.NormalizePath(entry.Path)}
+		})
+		if err != nil {
+			t.Fatalf("concurrency %d: exportStaticPaths() error = %v", concurrency, err)
+		}
+		if n := exported.Load(); n != 2 {
+			t.Errorf("concurrency %d: exported %d paths, want 2", concurrency, n)
+		}
+		if len(results) != len(paths) {
+			t.Fatalf("concurrency %d: got %d results, want %d", concurrency, len(results), len(paths))
+		}
+		for i, result := range results {
+			if (result.Error != nil) != (i >= 2) {
+				t.Errorf("concurrency %d: results[%d].Error = %v", concurrency, i, result.Error)
+			}
+		}
+		if !strings.Contains(results[2].Error.Error(), "duplicate static path /docs/a/") {
+			t.Errorf("concurrency %d: duplicate error = %v", concurrency, results[2].Error)
+		}
+	}
+}
+
+func TestExportStaticPagesConcurrencyRecordsSuccessfulRoutes(t *testing.T) {
+	tmpDir := t.TempDir()
+	var paths []core.StaticPathData
+	for i := range 6 {
+		paths = append(paths, core.StaticPathData{Path: fmt.Sprintf("/docs/%d", i), Props: map[string]any{"index": i}})
+	}
+	routes := []core.Route{
+		core.Page("/docs/{n}", "./pages/docs.tsx", core.WithStaticData(func(context.Context) ([]core.StaticPathData, error) {
+			return paths, nil
+		})),
+	}
+
+	err := ExportStaticPages(ExportStaticPagesInput{
+		OutputDir: tmpDir,
+		Routes:    routes,
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			core.EntryNameForPath("./pages/docs.tsx"): {Script: "/dist/docs.js", Mode: "static"},
+		}},
+		AppConfig:    &core.Config{StaticExportConcurrency: 3},
+		SSBundlePath: func(string) string { return "/ssr/docs-ssr.js" },
+		Renderer:     &concurrentRenderer{},
+	})
+	if err != nil {
+		t.Fatalf("ExportStaticPages() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "export-manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest core.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	staticRoutes := manifest.Entries[core.EntryNameForPath("./pages/docs.tsx")].StaticRoutes
+	if len(staticRoutes) != 5 {
+		t.Fatalf("StaticRoutes = %v, want the 5 successful paths", staticRoutes)
+	}
+	if _, ok := staticRoutes["/docs/2"]; ok {
+		t.Error("failed path /docs/2 should not be in StaticRoutes")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "pages", "routes", "docs", "5", "index.html")); err != nil {
+		t.Errorf("expected /docs/5 to be written: %v", err)
+	}
+}
+
+func TestExportStaticPagesPassesBuildTime(t *testing.T) {
+	buildTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var got time.Time
+	routes := []core.Route{
+		core.Page("/docs/{n}", "./pages/docs.tsx", core.WithStaticData(func(ctx context.Context) ([]core.StaticPathData, error) {
+			got = core.BuildTime(ctx)
+			return []core.StaticPathData{{Path: "/docs/1", Props: map[string]any{"index": 1}}}, nil
+		})),
+	}
+
+	err := ExportStaticPages(ExportStaticPagesInput{
+		OutputDir: t.TempDir(),
+		Routes:    routes,
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			core.EntryNameForPath("./pages/docs.tsx"): {Script: "/dist/docs.js", Mode: "static"},
+		}},
+		AppConfig:    &core.Config{BuildTime: buildTime},
+		SSBundlePath: func(string) string { return "/ssr/docs-ssr.js" },
+		Renderer:     &concurrentRenderer{},
+	})
+	if err != nil {
+		t.Fatalf("ExportStaticPages() error = %v", err)
+	}
+	if !got.Equal(buildTime) {
+		t.Errorf("BuildTime() in loader = %v, want %v", got, buildTime)
+	}
+}
+
+func TestExportStaticPagesWithoutJS(t *testing.T) {
+	outputDir := t.TempDir()
+	routes := []core.Route{
+		core.Page("/docs/{n}", "./pages/docs.tsx", core.WithStaticNoJS(), core.WithStaticData(func(context.Context) ([]core.StaticPathData, error) {
+			return []core.StaticPathData{{Path: "/docs/1", Props: map[string]any{"index": 1}}}, nil
+		})),
+	}
+
+	err := ExportStaticPages(ExportStaticPagesInput{
+		OutputDir: outputDir,
+		Routes:    routes,
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			core.EntryNameForPath("./pages/docs.tsx"): {CSS: "/dist/docs.css", Mode: "static", NoJS: true},
+		}},
+		AppConfig:    &core.Config{},
+		SSBundlePath: func(string) string { return "/ssr/docs-ssr.js" },
+		Renderer:     &concurrentRenderer{},
+	})
+	if err != nil {
+		t.Fatalf("ExportStaticPages() error = %v", err)
+	}
+
+	var html string
+	_ = filepath.WalkDir(outputDir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && filepath.Ext(path) == ".html" {
+			data, _ := os.ReadFile(path)
+			html = string(data)
+		}
+		return nil
+	})
+	if !strings.Contains(html, "<p>1</p>") {
+		t.Fatalf("exported HTML missing body: %q", html)
+	}
+	if !strings.Contains(html, `href="/dist/docs.css"`) {
+		t.Errorf("exported HTML missing stylesheet: %q", html)
+	}
+	for _, tag := range []string{"<script", "modulepreload", "__BIFROST_PROPS__"} {
+		if strings.Contains(html, tag) {
+			t.Errorf("exported HTML contains %s: %q", tag, html)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+type flagProviderFunc func(userID string) map[string]bool
+
+func (f flagProviderFunc) Flags(userID string) map[string]bool { return f(userID) }
+
+func renderWithFlags(t *testing.T, provider core.FlagProvider, loader core.PropsLoader, loaderTimeout time.Duration) (map[string]any, error) {
+	t.Helper()
+	var renderedProps map[string]any
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			renderedProps = props
+			return onHead("")
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+	shell, err := core.NewHTMLDocumentShell("/dist/home.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req = req.WithContext(core.ContextWithAnonymousID(req.Context(), "anon-1"))
+	output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/home.tsx",
+			Mode:          core.ModeSSR,
+			PropsLoader:   loader,
+		},
+		EntryName:     "pages-home-entry",
+		RequestPath:   "/",
+		Request:       req,
+		Shell:         &shell,
+		LoaderTimeout: loaderTimeout,
+		FlagProvider:  provider,
+	}))
+	if output.Error != nil {
+		return nil, output.Error
+	}
+	if err := output.Stream(httptest.NewRecorder()); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+	return renderedProps, nil
+}
+
+func TestRenderSSRAddsFeatureFlags(t *testing.T) {
+	var gotUserID string
+	provider := flagProviderFunc(func(userID string) map[string]bool {
+		gotUserID = userID
+		return map[string]bool{"newCheckout": true}
+	})
+	loader := func(*http.Request) (map[string]any, error) {
+		return map[string]any{"title": "Home"}, nil
+	}
+
+	props, err := renderWithFlags(t, provider, loader, 0)
+	if err != nil {
+		t.Fatalf("renderSSR() error = %v", err)
+	}
+	if gotUserID != "anon-1" {
+		t.Errorf("provider user ID = %q, want anonymous ID", gotUserID)
+	}
+	flags, _ := props[core.PropFlags].(map[string]bool)
+	if !flags["newCheckout"] || props["title"] != "Home" {
+		t.Errorf("props = %v, want loader props and flags", props)
+	}
+}
+
+func TestRenderSSRLoaderFlagsWin(t *testing.T) {
+	provider := flagProviderFunc(func(string) map[string]bool {
+		return map[string]bool{"newCheckout": true}
+	})
+	loader := func(*http.Request) (map[string]any, error) {
+		return map[string]any{"flags": "from loader"}, nil
+	}
+
+	props, err := renderWithFlags(t, provider, loader, 0)
+	if err != nil {
+		t.Fatalf("renderSSR() error = %v", err)
+	}
+	if props[core.PropFlags] != "from loader" {
+		t.Errorf("flags = %v, want the loader value", props[core.PropFlags])
+	}
+}
+
+func TestRenderSSRSlowFlagProviderTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	provider := flagProviderFunc(func(string) map[string]bool {
+		<-release
+		return nil
+	})
+
+	_, err := renderWithFlags(t, provider, nil, 20*time.Millisecond)
+	var timeoutErr *core.LoaderTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("renderSSR() error = %v, want LoaderTimeoutError", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/fs"
 	"path/filepath"
+	"strings"
 
 	"github.com/3-lines-studio/bifrost/internal/templates"
 )
@@ -21,14 +22,16 @@ type InitOutput struct {
 }
 
 type InitService struct {
-	fs  FileSystem
-	cli CLIOutput
+	fs          FileSystem
+	cli         CLIOutput
+	getTemplate func(name string) (fs.FS, error)
 }
 
 func NewInitService(fs FileSystem, cli CLIOutput) *InitService {
 	return &InitService{
-		fs:  fs,
-		cli: cli,
+		fs:          fs,
+		cli:         cli,
+		getTemplate: templates.GetTemplate,
 	}
 }
 
@@ -60,7 +63,7 @@ func (s *InitService) InitProject(input InitInput) InitOutput {
 		}
 	}
 
-	templateFS, err := templates.GetTemplate(input.Template)
+	templateFS, err := s.getTemplate(input.Template)
 	if err != nil {
 		return InitOutput{
 			Success: false,
@@ -68,6 +71,13 @@ func (s *InitService) InitProject(input InitInput) InitOutput {
 		}
 	}
 
+	if err := validateTemplateFiles(templateFS, input.Template); err != nil {
+		return InitOutput{
+			Success: false,
+			Error:   err,
+		}
+	}
+
 	err = s.copyTemplateFiles(templateFS, input.ProjectDir, input.ModuleName)
 	if err != nil {
 		return InitOutput{
@@ -82,6 +92,21 @@ func (s *InitService) InitProject(input InitInput) InitOutput {
 	}
 }
 
+// validateTemplateFiles checks that the template has every required file before anything is
+// written, so a broken template fails cleanly instead of leaving a half-copied project.
+func validateTemplateFiles(templateFS fs.FS, name string) error {
+	var missing []string
+	for _, path := range templates.RequiredFiles(name) {
+		if _, err := fs.Stat(templateFS, path); err != nil {
+			missing = append(missing, path)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("template %q is missing files: %s", name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func (s *InitService) copyTemplateFiles(templateFS fs.FS, projectDir string, moduleName string) error {
 	return fs.WalkDir(templateFS, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
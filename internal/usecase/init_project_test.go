@@ -2,8 +2,11 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	iofs "io/fs"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 type mockFileSystem struct {
@@ -67,6 +70,7 @@ func (m *mockFileSystem) Remove(path string) error {
 
 type mockCLIOutput struct {
 	messages []string
+	warnings []string
 }
 
 func (m *mockCLIOutput) PrintHeader(msg string)                   {}
@@ -74,14 +78,16 @@ func (m *mockCLIOutput) PrintStep(emoji, msg string, args ...any) {}
 func (m *mockCLIOutput) PrintSuccess(msg string, args ...any) {
 	m.messages = append(m.messages, msg)
 }
-func (m *mockCLIOutput) PrintWarning(msg string, args ...any) {}
-func (m *mockCLIOutput) PrintError(msg string, args ...any)   {}
-func (m *mockCLIOutput) PrintFile(path string)                {}
-func (m *mockCLIOutput) PrintDone(msg string)                 {}
-func (m *mockCLIOutput) Green(text string) string             { return text }
-func (m *mockCLIOutput) Yellow(text string) string            { return text }
-func (m *mockCLIOutput) Red(text string) string               { return text }
-func (m *mockCLIOutput) Gray(text string) string              { return text }
+func (m *mockCLIOutput) PrintWarning(msg string, args ...any) {
+	m.warnings = append(m.warnings, fmt.Sprintf(msg, args...))
+}
+func (m *mockCLIOutput) PrintError(msg string, args ...any) {}
+func (m *mockCLIOutput) PrintFile(path string)              {}
+func (m *mockCLIOutput) PrintDone(msg string)               {}
+func (m *mockCLIOutput) Green(text string) string           { return text }
+func (m *mockCLIOutput) Yellow(text string) string          { return text }
+func (m *mockCLIOutput) Red(text string) string             { return text }
+func (m *mockCLIOutput) Gray(text string) string            { return text }
 
 func TestInitProject_DirectoryNotEmpty(t *testing.T) {
 	fs := newMockFileSystem()
@@ -246,6 +252,43 @@ func TestInitProject_IntegrationWithRealFS(t *testing.T) {
 	}
 }
 
+func TestInitProject_MissingTemplateFiles(t *testing.T) {
+	fs := newMockFileSystem()
+	cli := &mockCLIOutput{}
+	service := NewInitService(fs, cli)
+	service.getTemplate = func(name string) (iofs.FS, error) {
+		return fstest.MapFS{
+			".air.toml":         {Data: []byte("")},
+			".bifrost/.gitkeep": {Data: []byte("")},
+			"go.mod.tmpl":       {Data: []byte("module {{.Module}}")},
+			"package.json":      {Data: []byte("{}")},
+			"pages/home.tsx":    {Data: []byte("")},
+			"tsconfig.json":     {Data: []byte("{}")},
+			".gitignore":        {Data: []byte("")},
+			"Dockerfile":        {Data: []byte("")},
+			"Makefile":          {Data: []byte("")},
+		}, nil
+	}
+
+	result := service.InitProject(InitInput{
+		ProjectDir: "/test/project",
+		Template:   "minimal",
+		ModuleName: "testproject",
+	})
+
+	if result.Error == nil || result.Success {
+		t.Fatalf("expected failure for incomplete template, got %+v", result)
+	}
+	for _, missing := range []string{"main.go.tmpl", "public/favicon.ico"} {
+		if !strings.Contains(result.Error.Error(), missing) {
+			t.Errorf("error %q should list %s", result.Error, missing)
+		}
+	}
+	if len(fs.mkdirCalls) > 0 || len(fs.writeCalls) > 0 {
+		t.Errorf("expected nothing written, got mkdir %v and writes %d", fs.mkdirCalls, len(fs.writeCalls))
+	}
+}
+
 type testOSFileSystem struct{}
 
 func (m *testOSFileSystem) ReadFile(path string) ([]byte, error) {
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// LoaderCache keeps loader results of pages that use WithLoaderCache. Entries are stored
+// per user key and page, so one key can be invalidated across every page that uses it.
+// A page is identified by its WithLoaderCache setting, so two routes that render the same
+// component with different loaders never share an entry. Expired entries are dropped when
+// looked up, and by a sweep of the whole cache at most once per ttl when a result is
+// stored, so keys that are never requested again do not stay in memory.
+type LoaderCache struct {
+	now   func() time.Time
+	group singleflightGroup
+
+	mu        sync.Mutex
+	entries   map[string]map[*core.LoaderCache]loaderCacheEntry
+	nextSweep time.Time
+	// generations counts the Invalidate calls per key, so a load that started before
+	// one does not store its now stale result.
+	generations map[string]uint64
+}
+
+type loaderCacheEntry struct {
+	props     map[string]any
+	expiresAt time.Time
+}
+
+func NewLoaderCache() *LoaderCache {
+	return &LoaderCache{
+		now:         time.Now,
+		entries:     make(map[string]map[*core.LoaderCache]loaderCacheEntry),
+		generations: make(map[string]uint64),
+	}
+}
+
+// SetLoaderCache enables WithLoaderCache for the pages this service renders.
+func (s *PageService) SetLoaderCache(cache *LoaderCache) {
+	s.loaderCache = cache
+}
+
+// Invalidate drops the entries stored under key for every page. Loads of key already in
+// flight still return their result but do not store it.
+func (c *LoaderCache) Invalidate(key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.generations[key]++
+	c.mu.Unlock()
+}
+
+// load returns a copy of the cached props for key and page, calling loader when there is
+// no entry younger than page.TTL. Concurrent misses share one loader call; errors are not
+// cached. The copy is shallow: nested maps and slices are shared by every request that
+// gets the entry, so they must not be modified.
+func (c *LoaderCache) load(key string, page *core.LoaderCache, loader func() (map[string]any, error)) (map[string]any, error) {
+	if props, ok := c.lookup(key, page); ok {
+		return props, nil
+	}
+
+	var props map[string]any
+	shared := true
+	err := c.group.Do(fmt.Sprintf("%p\x00%s", page, key), func() error {
+		shared = false
+		generation := c.generation(key)
+		var err error
+		props, err = loader()
+		if err != nil {
+			return err
+		}
+		c.store(key, page, generation, props)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !shared {
+		return maps.Clone(props), nil
+	}
+	if props, ok := c.lookup(key, page); ok {
+		return props, nil
+	}
+	return loader()
+}
+
+func (c *LoaderCache) lookup(key string, page *core.LoaderCache) (map[string]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key][page]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		c.deleteLocked(key, page)
+		return nil, false
+	}
+	return maps.Clone(entry.props), true
+}
+
+func (c *LoaderCache) generation(key string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generations[key]
+}
+
+// store keeps props for key and page unless key was invalidated after generation was read.
+func (c *LoaderCache) store(key string, page *core.LoaderCache, generation uint64, props map[string]any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[key] != generation {
+		return
+	}
+	now := c.now()
+	if !now.Before(c.nextSweep) {
+		for k, pages := range c.entries {
+			for p, entry := range pages {
+				if !now.Before(entry.expiresAt) {
+					c.deleteLocked(k, p)
+				}
+			}
+		}
+		c.nextSweep = now.Add(page.TTL)
+	}
+	if c.entries[key] == nil {
+		c.entries[key] = make(map[*core.LoaderCache]loaderCacheEntry)
+	}
+	c.entries[key][page] = loaderCacheEntry{props: props, expiresAt: now.Add(page.TTL)}
+}
+
+// deleteLocked drops the entry for key and page, and key once it has no pages left.
+func (c *LoaderCache) deleteLocked(key string, page *core.LoaderCache) {
+	delete(c.entries[key], page)
+	if len(c.entries[key]) == 0 {
+		delete(c.entries, key)
+	}
+}
+
+// loadProps runs the page's props loader through the loader cache when the page uses
+// WithLoaderCache.
+func (s *PageService) loadProps(ctx context.Context, input ServePageInput) (map[string]any, error) {
+	cfg := input.Config.LoaderCache
+	if cfg == nil || s.loaderCache == nil || input.Request == nil {
+		return runPropsLoader(ctx, input)
+	}
+	key := cfg.Key(input.Request)
+	if key == "" {
+		return runPropsLoader(ctx, input)
+	}
+	return s.loaderCache.load(key, cfg, func() (map[string]any, error) {
+		return runPropsLoader(ctx, input)
+	})
+}
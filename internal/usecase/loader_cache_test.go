@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestLoaderCacheReusesLoaderResults(t *testing.T) {
+	calls := 0
+	var loaderErr error
+	loader := func(req *http.Request) (map[string]any, error) {
+		calls++
+		if loaderErr != nil {
+			return nil, loaderErr
+		}
+		return map[string]any{"user": req.URL.Query().Get("user")}, nil
+	}
+	config := core.PageConfigFromRoute(core.Page("/nav", "./pages/nav.tsx",
+		core.WithLoader(loader),
+		core.WithLoaderCache(time.Minute, func(req *http.Request) string { return req.URL.Query().Get("user") }),
+	))
+
+	service := NewPageService(nil, nil, nil)
+	cache := NewLoaderCache()
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	service.SetLoaderCache(cache)
+
+	load := func(user string) map[string]any {
+		t.Helper()
+		props, err := service.loadProps(context.Background(), ServePageInput{
+			Config:    config,
+			EntryName: "pages-nav-entry",
+			Request:   httptest.NewRequest(http.MethodGet, "/nav?user="+user, nil),
+		})
+		if err != nil {
+			t.Fatalf("loadProps(%q) error = %v", user, err)
+		}
+		return props
+	}
+
+	load("ada")["extra"] = true
+	if props := load("ada"); calls != 1 || props["extra"] != nil {
+		t.Fatalf("second load: %d calls, props %v; want one call and an unmodified copy", calls, props)
+	}
+	load("bob")
+	if calls != 2 {
+		t.Fatalf("expected a loader call for a new key, got %d calls", calls)
+	}
+
+	cache.Invalidate("ada")
+	load("ada")
+	if calls != 3 {
+		t.Fatalf("expected a loader call after invalidation, got %d calls", calls)
+	}
+
+	now = now.Add(time.Minute)
+	loaderErr = errors.New("db down")
+	if _, err := service.loadProps(context.Background(), ServePageInput{
+		Config:    config,
+		EntryName: "pages-nav-entry",
+		Request:   httptest.NewRequest(http.MethodGet, "/nav?user=ada", nil),
+	}); err == nil {
+		t.Fatal("expected the expired entry to rerun the failing loader")
+	}
+	loaderErr = nil
+	load("ada")
+	if calls != 5 {
+		t.Fatalf("expected the error not to be cached, got %d calls", calls)
+	}
+}
+
+func TestLoaderCacheSkipsEmptyKey(t *testing.T) {
+	calls := 0
+	config := core.PageConfigFromRoute(core.Page("/nav", "./pages/nav.tsx",
+		core.WithLoader(func(*http.Request) (map[string]any, error) {
+			calls++
+			return map[string]any{}, nil
+		}),
+		core.WithLoaderCache(time.Minute, func(*http.Request) string { return "" }),
+	))
+	service := NewPageService(nil, nil, nil)
+	service.SetLoaderCache(NewLoaderCache())
+
+	for range 2 {
+		if _, err := service.loadProps(context.Background(), ServePageInput{
+			Config:  config,
+			Request: httptest.NewRequest(http.MethodGet, "/nav", nil),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected no caching for an empty key, got %d calls", calls)
+	}
+}
+
+func TestLoaderCacheDropsExpiredEntries(t *testing.T) {
+	cache := NewLoaderCache()
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	loader := func() (map[string]any, error) { return map[string]any{}, nil }
+	page := &core.LoaderCache{TTL: time.Minute}
+
+	for _, key := range []string{"ada", "bob"} {
+		if _, err := cache.load(key, page, loader); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(time.Minute)
+	if _, ok := cache.lookup("ada", page); ok {
+		t.Fatal("lookup returned an expired entry")
+	}
+	if _, ok := cache.entries["ada"]; ok {
+		t.Error("expired entry kept after lookup")
+	}
+
+	if _, err := cache.load("cy", page, loader); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.entries["bob"]; ok {
+		t.Error("expired entry of a key never requested again kept after a store")
+	}
+	if _, ok := cache.entries["cy"]; !ok {
+		t.Error("new entry not stored")
+	}
+}
+
+func TestLoaderCacheSeparatesRoutesOnOneComponent(t *testing.T) {
+	keyFn := func(*http.Request) string { return "nav" }
+	route := func(pattern, title string) core.PageConfig {
+		return core.PageConfigFromRoute(core.Page(pattern, "./pages/list.tsx",
+			core.WithLoader(func(*http.Request) (map[string]any, error) {
+				return map[string]any{"title": title}, nil
+			}),
+			core.WithLoaderCache(time.Minute, keyFn),
+		))
+	}
+	blog, news := route("/blog", "Blog"), route("/news", "News")
+
+	service := NewPageService(nil, nil, nil)
+	service.SetLoaderCache(NewLoaderCache())
+	for _, tt := range []struct {
+		config core.PageConfig
+		path   string
+		want   string
+	}{
+		{blog, "/blog", "Blog"},
+		{news, "/news", "News"},
+		{blog, "/blog", "Blog"},
+	} {
+		props, err := service.loadProps(context.Background(), ServePageInput{
+			Config:    tt.config,
+			EntryName: "pages-list-entry",
+			Request:   httptest.NewRequest(http.MethodGet, tt.path, nil),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if props["title"] != tt.want {
+			t.Errorf("%s: title = %v, want %s", tt.path, props["title"], tt.want)
+		}
+	}
+}
+
+func TestLoaderCacheInvalidateDuringLoad(t *testing.T) {
+	cache := NewLoaderCache()
+	page := &core.LoaderCache{TTL: time.Minute}
+
+	props, err := cache.load("nav", page, func() (map[string]any, error) {
+		cache.Invalidate("nav")
+		return map[string]any{"stale": true}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props["stale"] != true {
+		t.Errorf("load returned %v, want the loader result", props)
+	}
+	if _, ok := cache.lookup("nav", page); ok {
+		t.Error("result of a load invalidated while running was stored")
+	}
+
+	if _, err := cache.load("nav", page, func() (map[string]any, error) { return map[string]any{}, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.lookup("nav", page); !ok {
+		t.Error("load after the invalidation was not stored")
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/adapters/framework"
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestWriteEntryFilesLoadingShell(t *testing.T) {
+	t.Parallel()
+	adapter := framework.DefaultAdapter()
+	dir := t.TempDir()
+
+	ssrPath := filepath.Join(dir, "home-ssr.tsx")
+	if err := WriteSSREntryFile(adapter, ssrPath, "../pages/home", "", "../components/skeleton"); err != nil {
+		t.Fatal(err)
+	}
+	clientPath := filepath.Join(dir, "home.tsx")
+	if err := WriteClientEntryFile(adapter, clientPath, "../pages/home", "", "../components/skeleton", core.ModeSSR, core.HydrationImmediate); err != nil {
+		t.Fatal(err)
+	}
+	clientOnlyPath := filepath.Join(dir, "client.tsx")
+	if err := WriteClientEntryFile(adapter, clientOnlyPath, "../pages/home", "", "../components/skeleton", core.ModeClientOnly, core.HydrationImmediate); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	ssr := read(ssrPath)
+	for _, want := range []string{`import { Loading } from "../components/skeleton";`, "React.createElement(Loading, {})"} {
+		if !strings.Contains(ssr, want) {
+			t.Errorf("SSR entry missing %q:\n%s", want, ssr)
+		}
+	}
+	if strings.Contains(ssr, "React.createElement(Page") {
+		t.Errorf("SSR entry should not render the page:\n%s", ssr)
+	}
+	client := read(clientPath)
+	for _, want := range []string{`import { Loading } from "../components/skeleton";`, "hydrateRoot(container, React.createElement(Loading, {})).render(root)"} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client entry missing %q:\n%s", want, client)
+		}
+	}
+	if clientOnly := read(clientOnlyPath); strings.Contains(clientOnly, "Loading") {
+		t.Errorf("client-only entry should ignore the loading shell:\n%s", clientOnly)
+	}
+}
+
+func TestBuildProjectRecordsLoadingShell(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	_ = Page("/", "./pages/home.tsx", WithLoader(load), WithLoadingShell("./components/skeleton.tsx"))
+}`)
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), `export function Page() { return <p>Home</p>; }`)
+	writeTestFile(t, filepath.Join(tmpDir, "components", "skeleton.tsx"), `export function Loading() { return <p>Loading</p>; }`)
+
+	var ssrEntry string
+	renderer := &fakeRenderer{
+		buildFn: func(entrypoints []string, outdir string, entryNames []string) (map[string]core.ClientBuildResult, error) {
+			return map[string]core.ClientBuildResult{entryNames[0]: {Script: "/dist/" + entryNames[0] + ".js"}}, nil
+		},
+		buildSSRFn: func(entrypoints []string, outdir string) error {
+			data, err := os.ReadFile(entrypoints[0])
+			if err != nil {
+				return err
+			}
+			ssrEntry = string(data)
+			name := strings.TrimSuffix(filepath.Base(entrypoints[0]), filepath.Ext(entrypoints[0]))
+			writeTestFile(t, filepath.Join(outdir, name+".js"), "// ssr")
+			return nil
+		},
+	}
+	service := NewBuildService(renderer, nil, &mockCLIOutput{}, nil)
+	service.compileRuntimeFn = func(string) error { return nil }
+	result := service.BuildProject(context.Background(), BuildInput{
+		MainFile:    filepath.Join(tmpDir, "main.go"),
+		OriginalCwd: tmpDir,
+	})
+	if result.Error != nil {
+		t.Fatalf("BuildProject() error = %v", result.Error)
+	}
+
+	if !strings.Contains(ssrEntry, `import { Loading } from "../../components/skeleton.tsx";`) {
+		t.Errorf("SSR entry does not import the skeleton:\n%s", ssrEntry)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".bifrost", "manifest.json"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var manifest core.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	if got := manifest.Entries["pages-home-entry"].LoadingShell; got != "./components/skeleton.tsx" {
+		t.Errorf("manifest loadingShell = %q", got)
+	}
+}
+
+func TestRenderSSRSendsLoadingShellWithClientScript(t *testing.T) {
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			if err := onHead(""); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, `<p class="skeleton">Loading</p>`)
+			return err
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+	shell, err := core.NewHTMLDocumentShell("/dist/home.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+
+	output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/home.tsx",
+			Mode:          core.ModeSSR,
+			LoadingShell:  "./components/skeleton.tsx",
+			PropsLoader: func(*http.Request) (map[string]any, error) {
+				return map[string]any{"title": "Home"}, nil
+			},
+		},
+		EntryName:   "pages-home-entry",
+		RequestPath: "/",
+		Request:     httptest.NewRequest(http.MethodGet, "/", nil),
+		Shell:       &shell,
+	}))
+	if output.Error != nil {
+		t.Fatalf("renderSSR() error = %v", output.Error)
+	}
+	rec := httptest.NewRecorder()
+	if err := output.Stream(rec); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{`<div id="app"><p class="skeleton">Loading</p>`, `src="/dist/home.js"`, `"title":"Home"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("response missing %q:\n%s", want, body)
+		}
+	}
+}
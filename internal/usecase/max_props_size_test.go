@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// countingJSON counts how often the props are marshalled.
+type countingJSON struct {
+	value string
+	calls *int
+}
+
+func (c countingJSON) MarshalJSON() ([]byte, error) {
+	*c.calls++
+	return json.Marshal(c.value)
+}
+
+func renderWithMaxPropsSize(t *testing.T, limit int64, value countingJSON) (*fakeRenderer, *httptest.ResponseRecorder, error) {
+	t.Helper()
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			return onHead("")
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+	shell, err := core.NewHTMLDocumentShell("/dist/report.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+	output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/report.tsx",
+			Mode:          core.ModeSSR,
+			MaxPropsSize:  limit,
+			PropsLoader: func(*http.Request) (map[string]any, error) {
+				return map[string]any{"rows": value}, nil
+			},
+		},
+		EntryName:   "pages-report-entry",
+		RequestPath: "/report",
+		Request:     httptest.NewRequest(http.MethodGet, "/report", nil),
+		Shell:       &shell,
+	}))
+	rr := httptest.NewRecorder()
+	if output.Error != nil {
+		return renderer, rr, output.Error
+	}
+	return renderer, rr, output.Stream(rr)
+}
+
+func TestRenderSSRMaxPropsSize(t *testing.T) {
+	secret := strings.Repeat("secret ", 100)
+	tests := []struct {
+		name    string
+		limit   int64
+		wantErr bool
+	}{
+		{name: "under the limit", limit: 1024},
+		{name: "unlimited", limit: 0},
+		{name: "over the limit", limit: 100, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			renderer, rr, err := renderWithMaxPropsSize(t, tt.limit, countingJSON{value: secret, calls: &calls})
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("error = %v, want the page rendered", err)
+				}
+				if !strings.Contains(rr.Body.String(), secret) {
+					t.Error("props missing from the page")
+				}
+				if calls != 1 {
+					t.Errorf("props marshalled %d times, want once for the check and the page", calls)
+				}
+				return
+			}
+
+			var tooLarge *core.PropsTooLargeError
+			if !errors.As(err, &tooLarge) {
+				t.Fatalf("error = %v, want PropsTooLargeError", err)
+			}
+			if core.ErrorStatusCode(err) != http.StatusInternalServerError {
+				t.Errorf("status = %d, want 500", core.ErrorStatusCode(err))
+			}
+			if !strings.Contains(err.Error(), "props too large") || strings.Contains(err.Error(), "secret") {
+				t.Errorf("error = %q, want a size error without the props", err)
+			}
+			if renderer.streamCalls != 0 {
+				t.Error("renderer called for oversized props")
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// renderCacheKey returns the key a render of the page with props (marshalled as
+// propsJSON) is stored under, by WithPageCache and for FallbackCachedOrError, or "" when
+// neither keeps it. Pages are not kept in dev, where components change under them, when
+// they have a deferred loader, whose props are not known until the page is written, or
+// when props carry a per-request value, which would give every request its own entry.
+func renderCacheKey(input ServePageInput, props map[string]any, propsJSON []byte) string {
+	if input.PageCache == nil && input.SSRFallback != core.FallbackCachedOrError {
+		return ""
+	}
+	if input.IsDev || input.Config.DeferredPropsLoader != nil {
+		return ""
+	}
+	for _, key := range []string{core.PropNonce, core.PropCSRF, core.PropRequest} {
+		if _, ok := props[key]; ok {
+			return ""
+		}
+	}
+	return core.PageCacheKey(input.Config.ComponentPath, propsJSON)
+}
+
+// storeRender keeps a complete render of the page under key.
+func (s *PageService) storeRender(input ServePageInput, key string, html string) {
+	if input.PageCache != nil {
+		input.PageCache.Set(key, html, 0)
+	}
+	if input.SSRFallback == core.FallbackCachedOrError {
+		s.lastRenders.Set(key, html, 0)
+	}
+}
+
+// pageCacheWriter keeps a copy of the streamed page so it can be stored once complete.
+type pageCacheWriter struct {
+	http.ResponseWriter
+	body bytes.Buffer
+	// fellBack is set when the error boundary replaced the page; fallbacks are not cached.
+	fellBack bool
+}
+
+func (w *pageCacheWriter) Write(p []byte) (int, error) {
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *pageCacheWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// pageCacheStub counts the pages stored in a MemoryPageCache.
+type pageCacheStub struct {
+	*core.MemoryPageCache
+	sets int
+}
+
+func (c *pageCacheStub) Set(key string, html string, ttl time.Duration) {
+	c.sets++
+	c.MemoryPageCache.Set(key, html, ttl)
+}
+
+func servePageCached(t *testing.T, service *PageService, store core.PageCacheStore, id string) string {
+	t.Helper()
+	return servePageCachedRequest(t, service, store, id, httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func servePageCachedRequest(t *testing.T, service *PageService, store core.PageCacheStore, id string, req *http.Request) string {
+	t.Helper()
+	shell, err := core.NewHTMLDocumentShell("/dist/home.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+	output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/home.tsx",
+			Mode:          core.ModeSSR,
+			PropsLoader: func(*http.Request) (map[string]any, error) {
+				return map[string]any{"id": id}, nil
+			},
+		},
+		EntryName:   "pages-home-entry",
+		RequestPath: "/",
+		Request:     req,
+		Shell:       &shell,
+		PageCache:   store,
+	}))
+	if output.Error != nil {
+		t.Fatalf("renderSSR() error = %v", output.Error)
+	}
+	if output.Stream == nil {
+		return output.HTML
+	}
+	rr := httptest.NewRecorder()
+	if err := output.Stream(rr); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+	return rr.Body.String()
+}
+
+func newPageCacheRenderer() *fakeRenderer {
+	return &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			if err := onHead("<title>Home</title>"); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, "<h1>"+props["id"].(string)+"</h1>")
+			return err
+		},
+	}
+}
+
+func TestRenderSSRPageCache(t *testing.T) {
+	renderer := newPageCacheRenderer()
+	service := NewPageService(renderer, nil, nil)
+	store := &pageCacheStub{MemoryPageCache: core.NewMemoryPageCache(10, 50*time.Millisecond)}
+
+	first := servePageCached(t, service, store, "1")
+	if renderer.streamCalls != 1 || store.sets != 1 {
+		t.Fatalf("miss: renders = %d, sets = %d, want the page rendered and stored", renderer.streamCalls, store.sets)
+	}
+
+	if got := servePageCached(t, service, store, "1"); got != first {
+		t.Errorf("hit = %q, want the stored page %q", got, first)
+	}
+	if renderer.streamCalls != 1 {
+		t.Errorf("renders = %d after a hit, want the renderer skipped", renderer.streamCalls)
+	}
+
+	servePageCached(t, service, store, "2")
+	if renderer.streamCalls != 2 {
+		t.Errorf("renders = %d, want other props rendered separately", renderer.streamCalls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	servePageCached(t, service, store, "1")
+	if renderer.streamCalls != 3 {
+		t.Errorf("renders = %d after the ttl, want the page rendered again", renderer.streamCalls)
+	}
+}
+
+func TestRenderSSRPageCacheSkipsNonce(t *testing.T) {
+	renderer := newPageCacheRenderer()
+	service := NewPageService(renderer, nil, nil)
+	store := &pageCacheStub{MemoryPageCache: core.NewMemoryPageCache(10, time.Minute)}
+
+	for _, nonce := range []string{"nonce-a", "nonce-b"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(core.ContextWithCSPNonce(req.Context(), nonce))
+		servePageCachedRequest(t, service, store, "1", req)
+	}
+	if store.sets != 0 {
+		t.Errorf("sets = %d, want pages with a nonce never stored", store.sets)
+	}
+	if renderer.streamCalls != 2 {
+		t.Errorf("renders = %d, want every request rendered", renderer.streamCalls)
+	}
+}
+
+func TestRenderSSRPageCacheDelete(t *testing.T) {
+	renderer := newPageCacheRenderer()
+	service := NewPageService(renderer, nil, nil)
+	store := core.NewMemoryPageCache(10, time.Minute)
+
+	servePageCached(t, service, store, "1")
+	propsJSON, err := core.MarshalBifrostPropsJSON(map[string]any{"id": "1"})
+	if err != nil {
+		t.Fatalf("marshal props: %v", err)
+	}
+	key := core.PageCacheKey("./pages/home.tsx", propsJSON)
+	if _, ok := store.Get(key); !ok {
+		t.Fatal("page not stored under PageCacheKey(component, props)")
+	}
+
+	store.Delete(key)
+	servePageCached(t, service, store, "1")
+	if renderer.streamCalls != 2 {
+		t.Errorf("renders = %d, want the deleted page rendered again", renderer.streamCalls)
+	}
+}
@@ -44,23 +44,65 @@ func CalculateImportPath(entryPath, absComponentPath string) (string, error) {
 }
 
 // WriteSSREntryFile writes the framework SSR entry template with COMPONENT_PATH replaced.
-func WriteSSREntryFile(adapter core.FrameworkAdapter, entryPath, importPath string) error {
-	content := strings.ReplaceAll(adapter.SSREntryTemplate(), "COMPONENT_PATH", importPath)
+// A non-empty fallbackImport selects the error boundary template (see WithErrorBoundary),
+// and a non-empty loadingImport the loading shell template (see WithLoadingShell), which
+// never renders the page on the server and so takes precedence.
+func WriteSSREntryFile(adapter core.FrameworkAdapter, entryPath, importPath, fallbackImport, loadingImport string) error {
+	tmpl := adapter.SSREntryTemplate()
+	if fallbackImport != "" {
+		tmpl = strings.ReplaceAll(adapter.ErrorBoundarySSREntryTemplate(), "FALLBACK_PATH", fallbackImport)
+	}
+	if loadingImport != "" {
+		tmpl = strings.ReplaceAll(adapter.LoadingShellSSREntryTemplate(), "LOADING_PATH", loadingImport)
+	}
+	content := strings.ReplaceAll(tmpl, "COMPONENT_PATH", importPath)
 	return os.WriteFile(entryPath, []byte(content), 0o644)
 }
 
-// WriteClientEntryFile writes the client/hydration entry for the given page mode.
-func WriteClientEntryFile(adapter core.FrameworkAdapter, entryPath, importPath string, mode core.PageMode) error {
-	var tmpl string
-	if mode == core.ModeClientOnly {
-		tmpl = adapter.ClientEntryTemplate(core.ModeClientOnly)
-	} else {
-		tmpl = adapter.ClientEntryTemplate(core.ModeSSR)
+// WriteClientEntryFile writes the client/hydration entry for the given page mode and
+// hydration strategy. A non-empty fallbackImport wraps the page in an error boundary, and
+// a non-empty loadingImport makes a hydration entry replace the loading skeleton.
+func WriteClientEntryFile(adapter core.FrameworkAdapter, entryPath, importPath, fallbackImport, loadingImport string, mode core.PageMode, hydration core.HydrationStrategy) error {
+	if mode != core.ModeClientOnly {
+		mode = core.ModeSSR
+	}
+	tmpl := adapter.ClientEntryTemplate(mode, hydration)
+	if fallbackImport != "" {
+		tmpl = strings.ReplaceAll(adapter.ErrorBoundaryClientEntryTemplate(mode, hydration), "FALLBACK_PATH", fallbackImport)
+	}
+	if loadingImport != "" && mode == core.ModeSSR {
+		tmpl = strings.ReplaceAll(adapter.LoadingShellClientEntryTemplate(tmpl), "LOADING_PATH", loadingImport)
 	}
 	content := strings.ReplaceAll(tmpl, "COMPONENT_PATH", importPath)
 	return os.WriteFile(entryPath, []byte(content), 0o644)
 }
 
+// fallbackImportPath is the import of config's error boundary fallback from entryPath, or
+// "" when the page has none.
+func fallbackImportPath(cwd, entryPath string, config core.PageConfig) (string, error) {
+	if config.ErrorBoundary == "" {
+		return "", nil
+	}
+	importPath, err := CalculateImportPath(entryPath, AbsoluteComponentPath(cwd, config.ErrorBoundary))
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate error boundary import path: %w", err)
+	}
+	return importPath, nil
+}
+
+// loadingImportPath is the import of config's loading skeleton from entryPath, or "" when
+// the page has none. Only SSR pages use a loading shell.
+func loadingImportPath(cwd, entryPath string, config core.PageConfig) (string, error) {
+	if config.LoadingShell == "" || config.Mode != core.ModeSSR {
+		return "", nil
+	}
+	importPath, err := CalculateImportPath(entryPath, AbsoluteComponentPath(cwd, config.LoadingShell))
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate loading shell import path: %w", err)
+	}
+	return importPath, nil
+}
+
 // CompileDevPageOnDemand writes client + SSR entry files under .bifrost/entries and runs
 // client Build and SSR BuildSSR. Used by the dev server first-request setup path.
 func CompileDevPageOnDemand(renderer Renderer, cwd string, entryName string, config core.PageConfig, adapter core.FrameworkAdapter) error {
@@ -71,9 +113,9 @@ func CompileDevPageOnDemand(renderer Renderer, cwd string, entryName string, con
 		return fmt.Errorf("adapter is nil")
 	}
 
-	entryDir := filepath.Join(cwd, ".bifrost", "entries")
-	outdir := filepath.Join(cwd, ".bifrost", "dist")
-	ssrDir := filepath.Join(cwd, ".bifrost", "ssr")
+	entryDir := filepath.Join(cwd, core.OutputDir, "entries")
+	outdir := filepath.Join(cwd, core.OutputDir, "dist")
+	ssrDir := filepath.Join(cwd, core.OutputDir, "ssr")
 
 	if err := os.MkdirAll(entryDir, 0o755); err != nil {
 		return fmt.Errorf("failed to create entries directory: %w", err)
@@ -90,7 +132,15 @@ func CompileDevPageOnDemand(renderer Renderer, cwd string, entryName string, con
 		return fmt.Errorf("failed to calculate import path: %w", err)
 	}
 
-	if err := WriteClientEntryFile(adapter, entryFile, importPath, config.Mode); err != nil {
+	fallbackImport, err := fallbackImportPath(cwd, entryFile, config)
+	if err != nil {
+		return err
+	}
+	loadingImport, err := loadingImportPath(cwd, entryFile, config)
+	if err != nil {
+		return err
+	}
+	if err := WriteClientEntryFile(adapter, entryFile, importPath, fallbackImport, loadingImport, config.Mode, config.Hydration); err != nil {
 		return fmt.Errorf("failed to write client entry file: %w", err)
 	}
 
@@ -109,7 +159,15 @@ func CompileDevPageOnDemand(renderer Renderer, cwd string, entryName string, con
 	if err != nil {
 		return fmt.Errorf("failed to calculate SSR import path: %w", err)
 	}
-	if err := WriteSSREntryFile(adapter, ssrEntryFile, ssrImportPath); err != nil {
+	ssrFallbackImport, err := fallbackImportPath(cwd, ssrEntryFile, config)
+	if err != nil {
+		return err
+	}
+	ssrLoadingImport, err := loadingImportPath(cwd, ssrEntryFile, config)
+	if err != nil {
+		return err
+	}
+	if err := WriteSSREntryFile(adapter, ssrEntryFile, ssrImportPath, ssrFallbackImport, ssrLoadingImport); err != nil {
 		return fmt.Errorf("failed to write SSR entry file: %w", err)
 	}
 	if err := renderer.BuildSSR([]string{ssrEntryFile}, ssrDir); err != nil {
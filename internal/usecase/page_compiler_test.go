@@ -3,7 +3,11 @@ package usecase
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/adapters/framework"
+	"github.com/3-lines-studio/bifrost/internal/core"
 )
 
 func TestAbsoluteComponentPath(t *testing.T) {
@@ -49,3 +53,104 @@ func TestCalculateImportPath(t *testing.T) {
 		t.Fatalf("resolved %q want %q (rel was %q)", resolved, wantAbs, rel)
 	}
 }
+
+func TestWriteClientEntryFileHydrationStrategy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		hydration core.HydrationStrategy
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:      "immediate",
+			hydration: core.HydrationImmediate,
+			want:      []string{"hydrateRoot(container, root);"},
+			notWant:   []string{"requestIdleCallback", "IntersectionObserver"},
+		},
+		{
+			name:      "lazy",
+			hydration: core.HydrationLazy,
+			want:      []string{"requestIdleCallback(() => hydrateRoot(container, root)", "setTimeout(() => hydrateRoot(container, root), 100)"},
+			notWant:   []string{"IntersectionObserver"},
+		},
+		{
+			name:      "visible",
+			hydration: core.HydrationVisible,
+			want:      []string{"new IntersectionObserver(", "observer.observe(container)"},
+			notWant:   []string{"requestIdleCallback"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			entryPath := filepath.Join(t.TempDir(), "entry.tsx")
+			if err := WriteClientEntryFile(framework.DefaultAdapter(), entryPath, "../pages/home", "", "", core.ModeSSR, tt.hydration); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(entryPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			content := string(data)
+			if strings.Contains(content, "BIFROST_HYDRATE") || strings.Contains(content, "BIFROST_CLIENT_ROOT") {
+				t.Fatalf("unreplaced placeholder in entry:\n%s", content)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("entry missing %q:\n%s", want, content)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(content, notWant) {
+					t.Errorf("entry should not contain %q:\n%s", notWant, content)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteEntryFilesErrorBoundary(t *testing.T) {
+	t.Parallel()
+	adapter := framework.DefaultAdapter()
+	dir := t.TempDir()
+
+	ssrPath := filepath.Join(dir, "home-ssr.tsx")
+	if err := WriteSSREntryFile(adapter, ssrPath, "../pages/home", "../pages/oops", ""); err != nil {
+		t.Fatal(err)
+	}
+	clientPath := filepath.Join(dir, "home.tsx")
+	if err := WriteClientEntryFile(adapter, clientPath, "../pages/home", "../pages/oops", "", core.ModeSSR, core.HydrationImmediate); err != nil {
+		t.Fatal(err)
+	}
+	plainPath := filepath.Join(dir, "plain.tsx")
+	if err := WriteClientEntryFile(adapter, plainPath, "../pages/home", "", "", core.ModeSSR, core.HydrationImmediate); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	ssr := read(ssrPath)
+	for _, want := range []string{`import { Fallback } from "../pages/oops";`, "boundaryError: error", "React.createElement(Fallback, { ...props, error })"} {
+		if !strings.Contains(ssr, want) {
+			t.Errorf("SSR entry missing %q:\n%s", want, ssr)
+		}
+	}
+	client := read(clientPath)
+	for _, want := range []string{`import { Fallback } from "../pages/oops";`, "React.createElement(BifrostErrorBoundary, { pageProps: props }, React.createElement(Page, props))"} {
+		if !strings.Contains(client, want) {
+			t.Errorf("client entry missing %q:\n%s", want, client)
+		}
+	}
+	plain := read(plainPath)
+	if strings.Contains(plain, "Fallback") || strings.Contains(plain, "BIFROST_CLIENT_PRELUDE") {
+		t.Errorf("entry without a boundary should be unchanged:\n%s", plain)
+	}
+}
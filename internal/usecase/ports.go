@@ -16,6 +16,18 @@ type Renderer interface {
 	BuildSSR(entrypoints []string, outdir string) error
 }
 
+// BuildDefiner is implemented by renderers that can replace identifiers at build time.
+// BuildProject passes it the WithBuildEnv entries of main.go.
+type BuildDefiner interface {
+	SetBuildDefines(defines map[string]string)
+}
+
+// BuildTargeter is implemented by renderers that can bundle client code for a target other
+// than the browser. BuildProject passes it the WithBuildTarget value of main.go.
+type BuildTargeter interface {
+	SetBuildTarget(target string)
+}
+
 type CLIOutput interface {
 	PrintHeader(msg string)
 	PrintStep(emoji, msg string, args ...any)
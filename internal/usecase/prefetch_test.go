@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestRenderSSRAddsPrefetchScript(t *testing.T) {
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			if err := onHead("<title>Home</title>"); err != nil {
+				return err
+			}
+			_, err := w.Write([]byte("<div>Hello</div>"))
+			return err
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+	shell, err := core.NewHTMLDocumentShell("/dist/home.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+
+	for _, prefetch := range []bool{true, false} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(core.ContextWithCSPNonce(req.Context(), "abc"))
+		output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+			Config:      core.PageConfig{ComponentPath: "./pages/home.tsx", Mode: core.ModeSSR},
+			EntryName:   "pages-home-entry",
+			RequestPath: "/",
+			Request:     req,
+			Shell:       &shell,
+			Prefetch:    prefetch,
+		}))
+		if output.Error != nil {
+			t.Fatalf("renderSSR() error = %v", output.Error)
+		}
+		rec := httptest.NewRecorder()
+		if err := output.Stream(rec); err != nil {
+			t.Fatalf("stream error = %v", err)
+		}
+		body := rec.Body.String()
+		if got := strings.Contains(body, core.PrefetchScript("abc")); got != prefetch {
+			t.Errorf("Prefetch %v: page has prefetch script = %v\n%s", prefetch, got, body)
+		}
+	}
+}
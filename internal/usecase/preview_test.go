@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestServePagePreviewRendersStaticPageFresh(t *testing.T) {
+	var renderedPath string
+	renderer := &fakeRenderer{
+		renderFn: func(componentPath string, props map[string]any) (core.RenderedPage, error) {
+			renderedPath = componentPath
+			return core.RenderedPage{Body: "<h1>" + props["title"].(string) + "</h1>"}, nil
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+
+	var sawPreview bool
+	input := ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/post.tsx",
+			Mode:          core.ModeStaticPrerender,
+			StaticDataLoader: func(ctx context.Context) ([]core.StaticPathData, error) {
+				sawPreview = core.IsPreview(ctx)
+				entries := []core.StaticPathData{{Path: "/blog/hello", Props: map[string]any{"title": "Hello"}}}
+				if core.IsPreview(ctx) {
+					entries = append(entries, core.StaticPathData{Path: "/blog/draft", Props: map[string]any{"title": "Draft"}})
+				}
+				return entries, nil
+			},
+		},
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			"pages-post-entry": {
+				Script:       "/dist/pages-post-entry.js",
+				Mode:         "static",
+				SSR:          "/ssr/pages-post-entry-ssr.js",
+				Preview:      true,
+				StaticRoutes: map[string]string{"/blog/hello": "/pages/routes/blog/hello/index.html"},
+			},
+		}},
+		EntryName:   "pages-post-entry",
+		StaticPath:  "/ssr/pages-post-entry-ssr.js",
+		RequestPath: "/blog/draft",
+	}
+
+	output := service.ServePage(t.Context(), input)
+	if output.Action != core.ActionNotFound {
+		t.Fatalf("without preview: expected not found for a draft, got %v", output.Action)
+	}
+
+	input.Preview = true
+	output = service.ServePage(core.ContextWithPreview(t.Context()), input)
+	if output.Error != nil {
+		t.Fatalf("preview render: %v", output.Error)
+	}
+	if output.Action != core.ActionRenderStaticPrerender {
+		t.Fatalf("preview: expected fresh render, got %v", output.Action)
+	}
+	if !sawPreview {
+		t.Error("static data loader should see a preview context")
+	}
+	if renderedPath != "/ssr/pages-post-entry-ssr.js" {
+		t.Errorf("rendered %q, want the SSR bundle", renderedPath)
+	}
+	if !strings.Contains(output.HTML, "<h1>Draft</h1>") {
+		t.Errorf("expected draft content, got %q", output.HTML)
+	}
+
+	input.Preview = false
+	input.RequestPath = "/blog/hello"
+	output = service.ServePage(t.Context(), input)
+	if output.Action != core.ActionServeRouteFile {
+		t.Fatalf("without preview: expected prebuilt HTML, got %v", output.Action)
+	}
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestRenderSSRPassesRenderHeaders(t *testing.T) {
+	var renderedProps map[string]any
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			renderedProps = props
+			return onHead("")
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+
+	shell, err := core.NewHTMLDocumentShell("/dist/home.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+	var cfg core.Config
+	core.WithRenderHeaders("user-agent", "Accept-Language")(&cfg)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("User-Agent", "Mobile Safari")
+	req.Header.Set("Cookie", "session=secret")
+
+	output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/home.tsx",
+			Mode:          core.ModeSSR,
+		},
+		EntryName:     "pages-home-entry",
+		RequestPath:   "/",
+		Request:       req,
+		Shell:         &shell,
+		RenderHeaders: cfg.RenderHeaders,
+	}))
+	if output.Error != nil {
+		t.Fatalf("renderSSR() error = %v", output.Error)
+	}
+	rec := httptest.NewRecorder()
+	if err := output.Stream(rec); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+
+	request, _ := renderedProps[core.PropRequest].(map[string]any)
+	headers, _ := request["headers"].(map[string]string)
+	if len(headers) != 1 || headers["user-agent"] != "Mobile Safari" {
+		t.Errorf("component headers = %v, want only user-agent", headers)
+	}
+	want := `{"__request":{"headers":{"user-agent":"Mobile Safari"}}}`
+	if body := rec.Body.String(); !strings.Contains(body, want) {
+		t.Errorf("expected hydration props %s in\n%s", want, body)
+	}
+}
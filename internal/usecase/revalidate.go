@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// revalidateRenderTimeout bounds one background regeneration so a hung render never
+// blocks later regenerations of the same path.
+const revalidateRenderTimeout = 30 * time.Second
+
+// RevalidateCache keeps regenerated HTML for static prerender pages that use
+// WithRevalidate. The HTML embedded at build time counts as generated when the cache was
+// created; each regeneration writes its HTML to a file under dir.
+type RevalidateCache struct {
+	dir       string
+	createdAt time.Time
+	now       func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]revalidateEntry
+	running map[string]bool
+}
+
+type revalidateEntry struct {
+	file        string
+	generatedAt time.Time
+}
+
+// NewRevalidateCache creates a cache that stores regenerated HTML in a new temporary
+// directory. Call Close to remove it.
+func NewRevalidateCache() (*RevalidateCache, error) {
+	dir, err := os.MkdirTemp("", "bifrost-revalidate-*")
+	if err != nil {
+		return nil, err
+	}
+	return &RevalidateCache{
+		dir:       dir,
+		createdAt: time.Now(),
+		now:       time.Now,
+		entries:   make(map[string]revalidateEntry),
+		running:   make(map[string]bool),
+	}, nil
+}
+
+// Close removes the cache directory.
+func (c *RevalidateCache) Close() error {
+	if c == nil {
+		return nil
+	}
+	return os.RemoveAll(c.dir)
+}
+
+// lookup returns the regenerated HTML for path, if any, and whether the current HTML is
+// older than ttl and not already being regenerated. A stale result claims the
+// regeneration; the caller must call store or release.
+func (c *RevalidateCache) lookup(path string, ttl time.Duration) (html string, ok bool, stale bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, hasEntry := c.entries[path]
+	generatedAt := c.createdAt
+	if hasEntry {
+		generatedAt = entry.generatedAt
+	}
+	if c.now().Sub(generatedAt) >= ttl && !c.running[path] {
+		c.running[path] = true
+		stale = true
+	}
+	if !hasEntry {
+		return "", false, stale
+	}
+
+	data, err := os.ReadFile(entry.file)
+	if err != nil {
+		slog.Error("bifrost revalidate cache read failed", "path", path, "error", err)
+		return "", false, stale
+	}
+	return string(data), true, stale
+}
+
+func (c *RevalidateCache) store(path, html string) error {
+	sum := sha256.Sum256([]byte(path))
+	file := filepath.Join(c.dir, hex.EncodeToString(sum[:8])+".html")
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(html), 0o644); err != nil {
+		c.release(path)
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		c.release(path)
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[path] = revalidateEntry{file: file, generatedAt: c.now()}
+	delete(c.running, path)
+	return nil
+}
+
+func (c *RevalidateCache) release(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.running, path)
+}
+
+// SetRevalidateCache enables background regeneration for static prerender pages with a
+// revalidate TTL. Without a cache those pages are always served from the build output.
+func (s *PageService) SetRevalidateCache(cache *RevalidateCache) {
+	s.revalidate = cache
+}
+
+// serveRevalidated serves the latest HTML for a revalidating static page and starts a
+// background regeneration when it is stale. ok is false when the build output should be
+// served instead.
+func (s *PageService) serveRevalidated(state pageRequestState) (ServePageOutput, bool) {
+	input := state.input
+	path := core.NormalizePath(input.RequestPath)
+	html, ok, stale := s.revalidate.lookup(path, input.Config.Revalidate)
+	if stale {
+		go s.regenerate(path, state)
+	}
+	if !ok {
+		return ServePageOutput{}, false
+	}
+	return ServePageOutput{
+		Action: core.ActionRenderStaticPrerender,
+		HTML:   html,
+	}, true
+}
+
+func (s *PageService) regenerate(path string, state pageRequestState) {
+	ctx, cancel := context.WithTimeout(context.Background(), revalidateRenderTimeout)
+	defer cancel()
+
+	// The stored HTML is served to every request, so it must not carry this request's
+	// CSP nonce.
+	state.input.Request = nil
+	output := s.renderStaticPrerender(ctx, state)
+	err := output.Error
+	if err == nil && output.Action != core.ActionRenderStaticPrerender {
+		err = errors.New("page no longer renders for this path")
+	}
+	if err != nil {
+		s.revalidate.release(path)
+		slog.Error("bifrost revalidate failed", "entry", state.input.EntryName, "path", path, "error", err)
+		return
+	}
+	if err := s.revalidate.store(path, output.HTML); err != nil {
+		slog.Error("bifrost revalidate cache write failed", "entry", state.input.EntryName, "path", path, "error", err)
+	}
+}
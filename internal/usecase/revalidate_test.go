@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestServePageRevalidatesStaticPrerender(t *testing.T) {
+	cache, err := NewRevalidateCache()
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+	defer func() { _ = cache.Close() }()
+
+	var clockMu sync.Mutex
+	now := cache.createdAt
+	cache.now = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		now = now.Add(d)
+	}
+
+	rendered := make(chan struct{}, 1)
+	renderer := &fakeRenderer{
+		renderFn: func(componentPath string, props map[string]any) (core.RenderedPage, error) {
+			defer func() { rendered <- struct{}{} }()
+			return core.RenderedPage{Body: "<main>fresh</main>"}, nil
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+	service.SetRevalidateCache(cache)
+
+	input := ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/pricing.tsx",
+			Mode:          core.ModeStaticPrerender,
+			Revalidate:    time.Minute,
+		},
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			"pages-pricing-entry": {
+				Script:       "/dist/pages-pricing-entry.js",
+				Mode:         "static",
+				SSR:          "/ssr/pages-pricing-entry-ssr.js",
+				StaticRoutes: map[string]string{"/pricing": "/pages/routes/pricing/index.html"},
+			},
+		}},
+		EntryName:   "pages-pricing-entry",
+		StaticPath:  "/ssr/pages-pricing-entry-ssr.js",
+		RequestPath: "/pricing",
+	}
+
+	output := service.ServePage(t.Context(), input)
+	if output.Action != core.ActionServeRouteFile {
+		t.Fatalf("fresh page: expected route file, got %v", output.Action)
+	}
+
+	advance(time.Minute)
+	output = service.ServePage(t.Context(), input)
+	if output.Action != core.ActionServeRouteFile {
+		t.Fatalf("stale page: expected build HTML while regenerating, got %v", output.Action)
+	}
+	select {
+	case <-rendered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a background render")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		output = service.ServePage(t.Context(), input)
+		if output.Action == core.ActionRenderStaticPrerender || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if output.Action != core.ActionRenderStaticPrerender {
+		t.Fatalf("expected regenerated HTML, got %v", output.Action)
+	}
+	if !strings.Contains(output.HTML, "<main>fresh</main>") {
+		t.Fatalf("expected regenerated body, got %q", output.HTML)
+	}
+	select {
+	case <-rendered:
+		t.Fatal("fresh regenerated HTML should not render again")
+	default:
+	}
+}
+
+func TestRevalidateCacheRunsOneRegenerationPerPath(t *testing.T) {
+	cache, err := NewRevalidateCache()
+	if err != nil {
+		t.Fatalf("new cache: %v", err)
+	}
+	defer func() { _ = cache.Close() }()
+	cache.now = func() time.Time { return cache.createdAt.Add(time.Hour) }
+
+	if _, _, stale := cache.lookup("/a", time.Minute); !stale {
+		t.Fatal("expected first lookup to claim regeneration")
+	}
+	if _, _, stale := cache.lookup("/a", time.Minute); stale {
+		t.Fatal("expected second lookup to see regeneration in flight")
+	}
+	if _, _, stale := cache.lookup("/b", time.Minute); !stale {
+		t.Fatal("expected other paths to regenerate independently")
+	}
+
+	cache.release("/a")
+	if _, _, stale := cache.lookup("/a", time.Minute); !stale {
+		t.Fatal("expected released path to regenerate again")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/3-lines-studio/bifrost/internal/adapters/framework"
 	"github.com/3-lines-studio/bifrost/internal/core"
@@ -21,6 +22,28 @@ type ServePageInput struct {
 	HasRenderer     bool
 	Request         *http.Request
 	Shell           *core.HTMLDocumentShell
+	SSRTimeout      time.Duration
+	LoaderTimeout   time.Duration
+	Messages        core.MessagesLoader
+	GlobalHeadHTML  string
+	Title           core.TitleConfig
+	LinkRewriter    core.LinkRewriter
+	// StaticDataCacheTTL is how long dev reuses StaticDataLoader results; zero disables.
+	StaticDataCacheTTL time.Duration
+	Environment        string
+	// Preview renders a static prerender page fresh instead of serving the build output.
+	Preview      bool
+	PreloadPaths []string
+	// RenderHeaders are the request headers passed to SSR components (WithRenderHeaders).
+	RenderHeaders []string
+	// FlagProvider adds feature flags to the props (WithFeatureFlags).
+	FlagProvider core.FlagProvider
+	// PageCache serves and stores rendered SSR pages (WithPageCache).
+	PageCache core.PageCacheStore
+	// SSRFallback is what SSR pages serve when the renderer is unavailable (WithSSRFallback).
+	SSRFallback core.SSRFallbackMode
+	// Prefetch adds the WithPrefetch script to the page head.
+	Prefetch bool
 }
 
 type ServePageOutput struct {
@@ -33,13 +56,20 @@ type ServePageOutput struct {
 	Error      error
 	// Stream is set for SSR when the HTML response should be written with chunked flushing (see PageHandler).
 	Stream func(http.ResponseWriter) error
+	// BuildDuration is how long this request spent compiling the page in dev; zero when
+	// it did not build (production, or another request built it).
+	BuildDuration time.Duration
 }
 
 type PageService struct {
-	renderer   Renderer
-	fs         FileSystem
-	adapter    core.FrameworkAdapter
-	buildGroup singleflightGroup
+	renderer    Renderer
+	fs          FileSystem
+	adapter     core.FrameworkAdapter
+	buildGroup  singleflightGroup
+	revalidate  *RevalidateCache
+	staticData  *staticDataCache
+	loaderCache *LoaderCache
+	lastRenders *core.MemoryPageCache
 }
 
 type pageRequestState struct {
@@ -56,9 +86,11 @@ func NewPageService(renderer Renderer, fs FileSystem, adapter core.FrameworkAdap
 		adapter = framework.DefaultAdapter()
 	}
 	return &PageService{
-		renderer: renderer,
-		fs:       fs,
-		adapter:  adapter,
+		renderer:    renderer,
+		fs:          fs,
+		adapter:     adapter,
+		staticData:  newStaticDataCache(),
+		lastRenders: core.NewMemoryPageCache(maxLastRenders, 0),
 	}
 }
 
@@ -82,6 +114,7 @@ func (s *PageService) prepareRequest(input ServePageInput) pageRequestState {
 		EntryName:   input.EntryName,
 		StaticPath:  input.StaticPath,
 		HasRenderer: s.renderer != nil,
+		Preview:     input.Preview,
 	}
 
 	return pageRequestState{
@@ -103,6 +136,11 @@ func (s *PageService) executeRequest(ctx context.Context, state pageRequestState
 		}
 
 	case core.ActionServeRouteFile:
+		if s.revalidate != nil && state.input.Config.Revalidate > 0 && !state.input.IsDev {
+			if output, ok := s.serveRevalidated(state); ok {
+				return output
+			}
+		}
 		return ServePageOutput{
 			Action:    core.ActionServeRouteFile,
 			RoutePath: state.decision.HTMLPath,
@@ -115,16 +153,22 @@ func (s *PageService) executeRequest(ctx context.Context, state pageRequestState
 
 	case core.ActionNeedsSetup:
 		if state.input.IsDev && s.renderer != nil {
+			var buildDur time.Duration
 			buildErr := s.buildGroup.Do(state.input.EntryName, func() error {
+				buildStart := time.Now()
+				defer func() { buildDur = time.Since(buildStart) }()
 				return s.buildAndRender(ctx, state.input)
 			})
 			if buildErr != nil {
 				return ServePageOutput{
-					Action: core.ActionNeedsSetup,
-					Error:  buildErr,
+					Action:        core.ActionNeedsSetup,
+					Error:         buildErr,
+					BuildDuration: buildDur,
 				}
 			}
-			return s.renderForMode(ctx, s.prepareRequest(state.input))
+			output := s.renderForMode(ctx, s.prepareRequest(state.input))
+			output.BuildDuration = buildDur
+			return output
 		}
 		return ServePageOutput{
 			Action:     core.ActionNeedsSetup,
@@ -147,7 +191,7 @@ func (s *PageService) executeRequest(ctx context.Context, state pageRequestState
 func (s *PageService) renderForMode(ctx context.Context, state pageRequestState) ServePageOutput {
 	switch state.input.Config.Mode {
 	case core.ModeClientOnly:
-		html, err := s.renderClientOnlyShell(state)
+		html, err := s.renderClientOnlyShell(ctx, state)
 		return ServePageOutput{
 			Action: core.ActionRenderClientOnlyShell,
 			HTML:   html,
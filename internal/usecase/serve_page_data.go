@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"maps"
+)
+
+// ServePageData runs the page's props loaders as ServePage would and returns their props,
+// for a WithContentNegotiation page answering in JSON. Deferred props are awaited and
+// merged in, since a JSON response cannot stream them. A page without loaders has no
+// props.
+func (s *PageService) ServePageData(ctx context.Context, input ServePageInput) (map[string]any, error) {
+	props := map[string]any{}
+	if input.Config.PropsLoader != nil {
+		loaded, err := s.loadProps(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(props, loaded)
+	}
+	if input.Config.DeferredPropsLoader != nil {
+		deferred, err := input.Config.DeferredPropsLoader(input.Request)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(props, deferred)
+	}
+	return props, nil
+}
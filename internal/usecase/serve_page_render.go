@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
@@ -12,7 +14,7 @@ import (
 	"github.com/3-lines-studio/bifrost/internal/core"
 )
 
-func (s *PageService) renderClientOnlyShell(state pageRequestState) (string, error) {
+func (s *PageService) renderClientOnlyShell(ctx context.Context, state pageRequestState) (string, error) {
 	input := state.input
 	shell, err := s.resolveShell(state)
 	if err != nil {
@@ -20,26 +22,26 @@ func (s *PageService) renderClientOnlyShell(state pageRequestState) (string, err
 	}
 
 	if input.IsDev && s.renderer != nil {
-		ssrPath := filepath.Join(".bifrost/ssr", input.EntryName+"-ssr.js")
+		ssrPath := filepath.Join(core.OutputDir, "ssr", input.EntryName+"-ssr.js")
 		if _, err := os.Stat(ssrPath); err == nil {
-			page, err := s.renderer.Render(ssrPath, map[string]any{})
+			page, err := s.renderWithTimeout(ctx, input, ssrPath, map[string]any{})
 			if err == nil {
 				lang, htmlClass, _ := core.ResolveHTMLDocumentAttrs(input.DefaultHTMLLang, input.Config.HTMLLang, input.Config.HTMLClass, nil)
-				return shell.Render(page.Body, nil, page.Head, lang, htmlClass)
+				return shell.Render(page.Body, nil, input.Title.Apply(page.Head+input.GlobalHeadHTML), lang, htmlClass)
 			}
 		}
 	}
 
 	lang, htmlClass, _ := core.ResolveHTMLDocumentAttrs(input.DefaultHTMLLang, input.Config.HTMLLang, input.Config.HTMLClass, nil)
-	return shell.Render("", nil, "", lang, htmlClass)
+	return shell.Render("", nil, input.Title.Apply(input.GlobalHeadHTML), lang, htmlClass)
 }
 
 func (s *PageService) renderStaticPrerender(ctx context.Context, state pageRequestState) ServePageOutput {
 	input := state.input
 	requestPath := core.NormalizePath(input.RequestPath)
 
-	if input.Config.StaticDataLoader != nil {
-		entries, err := input.Config.StaticDataLoader(ctx)
+	if input.Config.HasStaticData() {
+		props, found, err := s.findStaticPath(ctx, input, requestPath)
 		if err != nil {
 			return ServePageOutput{
 				Action: core.ActionRenderStaticPrerender,
@@ -47,16 +49,6 @@ func (s *PageService) renderStaticPrerender(ctx context.Context, state pageReque
 			}
 		}
 
-		var props map[string]any
-		found := false
-		for _, entry := range entries {
-			if core.NormalizePath(entry.Path) == requestPath {
-				props = entry.Props
-				found = true
-				break
-			}
-		}
-
 		if !found {
 			return ServePageOutput{
 				Action: core.ActionNotFound,
@@ -64,6 +56,7 @@ func (s *PageService) renderStaticPrerender(ctx context.Context, state pageReque
 		}
 
 		lang, htmlClass, propsForReact := core.ResolveHTMLDocumentAttrs(input.DefaultHTMLLang, input.Config.HTMLLang, input.Config.HTMLClass, props)
+		propsForReact = core.WithSlotsProp(propsForReact, input.Config.Slots)
 
 		if s.renderer == nil {
 			return ServePageOutput{
@@ -72,11 +65,11 @@ func (s *PageService) renderStaticPrerender(ctx context.Context, state pageReque
 			}
 		}
 
-		page, err := s.renderer.Render(state.renderPath, propsForReact)
+		page, err := s.renderWithTimeout(ctx, input, state.renderPath, propsForReact)
 		if err != nil {
 			return ServePageOutput{
 				Action: core.ActionRenderStaticPrerender,
-				Error:  err,
+				Error:  core.WrapMissingSSRModule(err, input.Config.ComponentPath),
 			}
 		}
 
@@ -97,12 +90,13 @@ func (s *PageService) renderStaticPrerender(ctx context.Context, state pageReque
 	}
 
 	lang, htmlClass, propsForReact := core.ResolveHTMLDocumentAttrs(input.DefaultHTMLLang, input.Config.HTMLLang, input.Config.HTMLClass, nil)
+	propsForReact = core.WithSlotsProp(propsForReact, input.Config.Slots)
 
-	page, err := s.renderer.Render(state.renderPath, propsForReact)
+	page, err := s.renderWithTimeout(ctx, input, state.renderPath, propsForReact)
 	if err != nil {
 		return ServePageOutput{
 			Action: core.ActionRenderStaticPrerender,
-			Error:  err,
+			Error:  core.WrapMissingSSRModule(err, input.Config.ComponentPath),
 		}
 	}
 
@@ -114,6 +108,49 @@ func (s *PageService) renderStaticPrerender(ctx context.Context, state pageReque
 	}
 }
 
+// errStopStaticPaths ends a static data stream once the wanted path has been emitted.
+var errStopStaticPaths = errors.New("bifrost: static path found")
+
+// findStaticPath returns the props for requestPath from the page's static data. A
+// StaticDataStream is read only until the path turns up.
+func (s *PageService) findStaticPath(ctx context.Context, input ServePageInput, requestPath string) (map[string]any, bool, error) {
+	if input.Config.StaticDataLoader != nil {
+		entries, err := s.loadStaticData(ctx, input)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, entry := range entries {
+			if core.NormalizePath(entry.Path) == requestPath {
+				return entry.Props, true, nil
+			}
+		}
+		return nil, false, nil
+	}
+
+	var props map[string]any
+	found := false
+	err := input.Config.StaticDataStream(ctx, func(entry core.StaticPathData) error {
+		if core.NormalizePath(entry.Path) != requestPath {
+			return nil
+		}
+		props = entry.Props
+		found = true
+		return errStopStaticPaths
+	})
+	if err != nil && !(found && errors.Is(err, errStopStaticPaths)) {
+		return nil, false, err
+	}
+	return props, found, nil
+}
+
+// loadStaticData runs the page's StaticDataLoader, reusing a recent result in dev.
+func (s *PageService) loadStaticData(ctx context.Context, input ServePageInput) ([]core.StaticPathData, error) {
+	if !input.IsDev || s.staticData == nil {
+		return input.Config.StaticDataLoader(ctx)
+	}
+	return s.staticData.load(ctx, input.EntryName, input.Config.ComponentPath, input.StaticDataCacheTTL, input.Config.StaticDataLoader)
+}
+
 type pageTiming struct {
 	propsDur    time.Duration
 	renderStart time.Time
@@ -137,7 +174,7 @@ func (s *PageService) renderSSR(ctx context.Context, state pageRequestState) Ser
 	if input.Config.PropsLoader != nil {
 		propsStart := time.Now()
 		var err error
-		syncProps, err = input.Config.PropsLoader(input.Request)
+		syncProps, err = s.loadProps(ctx, input)
 		timing.propsDur = time.Since(propsStart)
 		if err != nil {
 			return ServePageOutput{
@@ -147,6 +184,26 @@ func (s *PageService) renderSSR(ctx context.Context, state pageRequestState) Ser
 		}
 	}
 
+	if input.FlagProvider != nil && input.Request != nil {
+		flags, err := loadFlags(ctx, input)
+		if err != nil {
+			return ServePageOutput{
+				Action: core.ActionRenderSSR,
+				Error:  err,
+			}
+		}
+		syncProps = core.WithFlagsProp(syncProps, flags)
+	}
+
+	if input.Messages != nil {
+		locale, messages := input.Messages(input.Request)
+		syncProps = core.ApplyMessages(syncProps, locale, messages)
+	}
+
+	if !core.IsHTMLContentType(input.Config.ContentType) {
+		return s.renderSSRBody(ctx, state, syncProps)
+	}
+
 	type deferredResult struct {
 		props map[string]any
 		err   error
@@ -165,20 +222,48 @@ func (s *PageService) renderSSR(ctx context.Context, state pageRequestState) Ser
 	}
 
 	lang, htmlClass, syncPropsForReact := core.ResolveHTMLDocumentAttrs(input.DefaultHTMLLang, input.Config.HTMLLang, input.Config.HTMLClass, syncProps)
+	syncPropsForReact = core.WithNonceProp(syncPropsForReact, core.CSPNonce(input.Request))
+	syncPropsForReact = core.WithCSRFProp(syncPropsForReact, core.CSRFToken(input.Request))
+	syncPropsForReact = core.WithSlotsProp(syncPropsForReact, input.Config.Slots)
+	syncPropsForReact = core.WithRequestProp(syncPropsForReact, input.Request, input.RenderHeaders)
 
-	if s.renderer == nil {
+	shell, err := s.resolveShell(state)
+	if err != nil {
 		return ServePageOutput{
 			Action: core.ActionRenderSSR,
-			Error:  fmt.Errorf("renderer not available for SSR"),
+			Error:  err,
 		}
 	}
-	shell, err := s.resolveShell(state)
+
+	// Marshalled once for the size limit, the cache key and the props script, which only
+	// needs marshalling again when deferred props are merged in.
+	syncPropsJSON, err := core.MarshalBifrostPropsJSON(syncPropsForReact)
+	if err == nil {
+		err = core.CheckPropsSize(input.RequestPath, syncPropsJSON, input.Config.MaxPropsSize)
+	}
 	if err != nil {
 		return ServePageOutput{
 			Action: core.ActionRenderSSR,
 			Error:  err,
 		}
 	}
+	cacheKey := renderCacheKey(input, syncPropsForReact, syncPropsJSON)
+	if cacheKey != "" && input.PageCache != nil {
+		if html, ok := input.PageCache.Get(cacheKey); ok {
+			return ServePageOutput{
+				Action: core.ActionRenderSSR,
+				HTML:   html,
+				Props:  syncPropsForReact,
+			}
+		}
+	}
+
+	fallback := func(err error) ServePageOutput {
+		return s.renderSSRFallback(state, shell, cacheKey, syncPropsForReact, lang, htmlClass, err)
+	}
+	if s.renderer == nil {
+		return fallback(fmt.Errorf("%w for SSR", core.ErrRendererUnavailable))
+	}
 
 	flush := func(w http.ResponseWriter) func() {
 		return func() {
@@ -188,51 +273,81 @@ func (s *PageService) renderSSR(ctx context.Context, state pageRequestState) Ser
 		}
 	}
 
+	renderTimeout := core.ResolveSSRTimeout(input.SSRTimeout)
 	streamFn := func(w http.ResponseWriter) error {
-		doFlush := flush(w)
-		rCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		rCtx, cancel := context.WithTimeout(ctx, renderTimeout)
 		defer cancel()
 
+		var cached *pageCacheWriter
+		if cacheKey != "" {
+			cached = &pageCacheWriter{ResponseWriter: w}
+			w = cached
+			rCtx = core.ContextWithBoundaryErrorReporter(rCtx, func(err *core.BoundaryError) {
+				cached.fellBack = true
+				core.ReportBoundaryError(ctx, err)
+			})
+		}
+		doFlush := flush(w)
+
 		timing.renderStart = time.Now()
+		headWritten := false
 		err := s.renderer.RenderBodyStream(rCtx, state.renderPath, syncPropsForReact, w, doFlush,
 			func(head string) error {
+				headWritten = true
 				timing.renderDur = time.Since(timing.renderStart)
 				w.Header().Set("Content-Type", "text/html; charset=utf-8")
 				w.Header().Set("Server-Timing", timing.serverTimingHeader())
 				w.WriteHeader(http.StatusOK)
-				if err := shell.WritePreamble(w, head, lang, htmlClass); err != nil {
+				if err := shell.WritePreamble(w, pageHeadHTML(input, head), lang, htmlClass); err != nil {
 					return err
 				}
 				doFlush()
 				return nil
 			})
 		if err != nil {
-			return err
+			if errors.Is(rCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+				slog.Error("bifrost render timed out",
+					"entry", timing.entryName,
+					"path", timing.path,
+					"render_timeout_ms", renderTimeout.Milliseconds(),
+				)
+				return &core.RenderTimeoutError{Path: timing.path, Timeout: renderTimeout}
+			}
+			if errors.Is(err, core.ErrRendererUnavailable) && !headWritten {
+				output := fallback(err)
+				if output.Error != nil {
+					return output.Error
+				}
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.WriteHeader(http.StatusOK)
+				_, err := io.WriteString(w, output.HTML)
+				return err
+			}
+			return core.WrapMissingSSRModule(err, input.Config.ComponentPath)
 		}
 
-		mergedProps := syncPropsForReact
+		propsJSON := syncPropsJSON
 		if deferredCh != nil {
 			select {
 			case d := <-deferredCh:
 				timing.deferredDur = d.dur
 				if d.err != nil {
 					slog.Error("deferred loader failed", "error", d.err)
-				} else {
-					mergedProps = core.MergeProps(syncPropsForReact, d.props)
+				} else if propsJSON, err = core.MarshalBifrostPropsJSON(core.MergeProps(syncPropsForReact, d.props)); err != nil {
+					return err
 				}
 			case <-rCtx.Done():
 				slog.Error("deferred loader timed out", "error", rCtx.Err())
 			}
 		}
 
-		propsJSON, err := core.MarshalBifrostPropsJSON(mergedProps)
-		if err != nil {
-			return err
-		}
 		if err := shell.WriteSuffix(w, propsJSON); err != nil {
 			return err
 		}
 		doFlush()
+		if cached != nil && !cached.fellBack {
+			s.storeRender(input, cacheKey, cached.body.String())
+		}
 
 		slog.Info("bifrost page timing",
 			"entry", timing.entryName,
@@ -240,6 +355,7 @@ func (s *PageService) renderSSR(ctx context.Context, state pageRequestState) Ser
 			"props_ms", timing.propsDur.Milliseconds(),
 			"render_ms", timing.renderDur.Milliseconds(),
 			"deferred_ms", timing.deferredDur.Milliseconds(),
+			"render_timeout_ms", renderTimeout.Milliseconds(),
 		)
 		return nil
 	}
@@ -251,11 +367,126 @@ func (s *PageService) renderSSR(ctx context.Context, state pageRequestState) Ser
 	}
 }
 
+// renderSSRBody renders a non-HTML page: the component's body alone, without the document
+// shell. Nothing hydrates, so deferred props are not loaded.
+func (s *PageService) renderSSRBody(ctx context.Context, state pageRequestState, props map[string]any) ServePageOutput {
+	input := state.input
+	if s.renderer == nil {
+		return ServePageOutput{
+			Action: core.ActionRenderSSR,
+			Error:  fmt.Errorf("renderer not available for SSR"),
+		}
+	}
+	_, _, propsForReact := core.ResolveHTMLDocumentAttrs(input.DefaultHTMLLang, input.Config.HTMLLang, input.Config.HTMLClass, props)
+	propsForReact = core.WithSlotsProp(propsForReact, input.Config.Slots)
+	page, err := s.renderWithTimeout(ctx, input, state.renderPath, propsForReact)
+	if err != nil {
+		return ServePageOutput{
+			Action: core.ActionRenderSSR,
+			Error:  core.WrapMissingSSRModule(err, input.Config.ComponentPath),
+		}
+	}
+	return ServePageOutput{
+		Action: core.ActionRenderSSR,
+		HTML:   page.Body,
+		Props:  propsForReact,
+	}
+}
+
+// renderWithTimeout renders the component at renderPath in one piece, bounded by the SSR
+// timeout like a streamed render. A render that runs out of time returns a
+// RenderTimeoutError; one cut short because ctx ended returns the renderer's error.
+func (s *PageService) renderWithTimeout(ctx context.Context, input ServePageInput, renderPath string, props map[string]any) (core.RenderedPage, error) {
+	renderTimeout := core.ResolveSSRTimeout(input.SSRTimeout)
+	rCtx, cancel := context.WithTimeout(ctx, renderTimeout)
+	defer cancel()
+
+	var page core.RenderedPage
+	err := s.renderer.RenderChunked(rCtx, renderPath, props,
+		func(head string) error {
+			page.Head = head
+			return nil
+		},
+		func(body string) error {
+			page.Body = body
+			return nil
+		},
+	)
+	if err != nil {
+		if errors.Is(rCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			slog.Error("bifrost render timed out",
+				"entry", input.EntryName,
+				"path", input.RequestPath,
+				"render_timeout_ms", renderTimeout.Milliseconds(),
+			)
+			return core.RenderedPage{}, &core.RenderTimeoutError{Path: input.RequestPath, Timeout: renderTimeout}
+		}
+		return core.RenderedPage{}, err
+	}
+	return page, nil
+}
+
+// runPropsLoader calls the page's props loader. When the loader timeout is set or ctx
+// carries a deadline, the loader runs with a derived request context and is abandoned
+// once that context ends; loaders should honour req.Context() to stop their own work.
+func runPropsLoader(ctx context.Context, input ServePageInput) (map[string]any, error) {
+	return runWithLoaderTimeout(ctx, input, input.Config.PropsLoader)
+}
+
+// loadFlags asks the WithFeatureFlags provider for the flags of the request's user, under
+// the same timeout as the props loader.
+func loadFlags(ctx context.Context, input ServePageInput) (map[string]bool, error) {
+	return runWithLoaderTimeout(ctx, input, func(req *http.Request) (map[string]bool, error) {
+		return input.FlagProvider.Flags(core.FlagUserID(req.Context())), nil
+	})
+}
+
+func runWithLoaderTimeout[T any](ctx context.Context, input ServePageInput, load func(*http.Request) (T, error)) (T, error) {
+	_, hasDeadline := ctx.Deadline()
+	if input.LoaderTimeout <= 0 && !hasDeadline {
+		return load(input.Request)
+	}
+
+	lCtx := ctx
+	if input.LoaderTimeout > 0 {
+		var cancel context.CancelFunc
+		lCtx, cancel = context.WithTimeout(ctx, input.LoaderTimeout)
+		defer cancel()
+	}
+
+	type loaderResult struct {
+		value T
+		err   error
+	}
+	done := make(chan loaderResult, 1)
+	req := input.Request.WithContext(lCtx)
+	go func() {
+		value, err := load(req)
+		done <- loaderResult{value: value, err: err}
+	}()
+
+	var zero T
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-lCtx.Done():
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+		slog.Error("bifrost loader timed out",
+			"entry", input.EntryName,
+			"path", input.RequestPath,
+			"loader_timeout_ms", input.LoaderTimeout.Milliseconds(),
+		)
+		return zero, &core.LoaderTimeoutError{Path: input.RequestPath, Timeout: input.LoaderTimeout}
+	}
+}
+
 func (s *PageService) resolveRenderPath(input ServePageInput) string {
 	if !input.IsDev {
 		return core.ResolveRenderPath(input.IsDev, input.StaticPath, input.Config.ComponentPath)
 	}
-	ssrPath := filepath.Join(".bifrost/ssr", input.EntryName+"-ssr.js")
+	ssrPath := filepath.Join(core.OutputDir, "ssr", input.EntryName+"-ssr.js")
 	if _, err := os.Stat(ssrPath); err == nil {
 		return ssrPath
 	}
@@ -267,21 +498,41 @@ func (s *PageService) renderPageHTML(input ServePageInput, props map[string]any,
 }
 
 func (s *PageService) renderPageHTMLWithArtifacts(state pageRequestState, props map[string]any, page core.RenderedPage, htmlLang string, htmlClass string) (string, error) {
+	if !core.IsHTMLContentType(state.input.Config.ContentType) {
+		return page.Body, nil
+	}
 	shell, err := s.resolveShell(state)
 	if err != nil {
 		return "", err
 	}
-	return shell.Render(page.Body, props, page.Head, htmlLang, htmlClass)
+	return shell.Render(page.Body, props, pageHeadHTML(state.input, page.Head), htmlLang, htmlClass)
+}
+
+// pageHeadHTML orders the head as: dev hydration reporter (so it runs before the client
+// entry), app-wide tags, the component's own head, then the WithPrefetch script, with the
+// title template applied.
+func pageHeadHTML(input ServePageInput, head string) string {
+	if input.GlobalHeadHTML != "" {
+		head = input.GlobalHeadHTML + head
+	}
+	head = input.Title.Apply(head)
+	if input.Prefetch {
+		head += core.PrefetchScript(core.CSPNonce(input.Request))
+	}
+	if input.IsDev {
+		head = core.HydrationReporterScriptWithNonce(input.Config.ComponentPath, core.CSPNonce(input.Request)) + head
+	}
+	return head
 }
 
 func (s *PageService) resolveShell(state pageRequestState) (core.HTMLDocumentShell, error) {
+	nonce := core.CSPNonce(state.input.Request)
 	if state.shell != nil {
-		return *state.shell, nil
+		return state.shell.WithNonce(nonce), nil
 	}
-	return core.NewHTMLDocumentShell(
-		state.artifacts.Script,
-		state.artifacts.CriticalCSS,
-		core.StylesheetHrefsFor(state.artifacts),
-		state.artifacts.Chunks,
-	)
+	shell, err := core.NewPageShell(state.input.Config.NoJS, state.artifacts)
+	if err != nil {
+		return core.HTMLDocumentShell{}, err
+	}
+	return shell.WithPreloads(state.input.PreloadPaths).RewriteLinks(state.input.LinkRewriter).ForEnvironment(state.input.Environment).WithNonce(nonce), nil
 }
@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -27,6 +29,16 @@ type fakeRenderer struct {
 	buildSSRFn           func(entrypoints []string, outdir string) error
 	renderFn             func(componentPath string, props map[string]any) (core.RenderedPage, error)
 	streamFn             func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error
+	defines              map[string]string
+	target               string
+}
+
+func (f *fakeRenderer) SetBuildDefines(defines map[string]string) {
+	f.defines = defines
+}
+
+func (f *fakeRenderer) SetBuildTarget(target string) {
+	f.target = target
 }
 
 func (f *fakeRenderer) Render(componentPath string, props map[string]any) (core.RenderedPage, error) {
@@ -38,7 +50,14 @@ func (f *fakeRenderer) Render(componentPath string, props map[string]any) (core.
 }
 
 func (f *fakeRenderer) RenderChunked(ctx context.Context, componentPath string, props map[string]any, onHead func(head string) error, onBody func(body string) error) error {
-	return nil
+	page, err := f.Render(componentPath, props)
+	if err != nil {
+		return err
+	}
+	if err := onHead(page.Head); err != nil {
+		return err
+	}
+	return onBody(page.Body)
 }
 
 func (f *fakeRenderer) RenderBodyStream(ctx context.Context, componentPath string, props map[string]any, w io.Writer, flush func(), onHead func(head string) error) error {
@@ -133,6 +152,9 @@ func TestPageServiceDevSSRBuildsThenStreams(t *testing.T) {
 	if !strings.Contains(body, "<title>Home</title>") {
 		t.Fatalf("expected streamed head, got %q", body)
 	}
+	if !strings.Contains(body, core.HydrationErrorPath) {
+		t.Fatalf("expected dev hydration reporter in head, got %q", body)
+	}
 }
 
 func TestPageServiceStaticPrerenderReturnsNotFoundForMissingPath(t *testing.T) {
@@ -514,6 +536,202 @@ func main() {
 	}
 }
 
+func TestBuildProjectUsesCustomSSREntryTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), "package main\n"+
+		"const ssrEntry = `import { renderToString } from \"react-dom/server\";\n"+
+		"import { ServerStyleSheet } from \"styled-components\";\n"+
+		"import { Page } from \"{{.ComponentImport}}\";\n"+
+		"export async function render(props) {\n"+
+		"\tconst sheet = new ServerStyleSheet();\n"+
+		"\tconst html = renderToString(sheet.collectStyles(<Page {...props} />));\n"+
+		"\treturn { html, head: sheet.getStyleTags() };\n"+
+		"}`\n"+
+		"func main() {\n"+
+		"\tapp := bifrost.New(assets, bifrost.WithCustomSSREntryTemplate(ssrEntry))\n"+
+		"\t_ = Page(\"/\", \"./pages/home.tsx\")\n"+
+		"}")
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), "export function Page() { return <p>Home</p> }")
+
+	var entry string
+	renderer := &fakeRenderer{
+		buildFn: func(entrypoints []string, outdir string, entryNames []string) (map[string]core.ClientBuildResult, error) {
+			return map[string]core.ClientBuildResult{
+				entryNames[0]: {Script: "/dist/" + entryNames[0] + ".js"},
+			}, nil
+		},
+		buildSSRFn: func(entrypoints []string, outdir string) error {
+			data, err := os.ReadFile(entrypoints[0])
+			if err != nil {
+				return err
+			}
+			entry = string(data)
+			name := strings.TrimSuffix(filepath.Base(entrypoints[0]), filepath.Ext(entrypoints[0]))
+			writeTestFile(t, filepath.Join(outdir, name+".js"), "// ssr")
+			return nil
+		},
+	}
+	service := NewBuildService(renderer, nil, &mockCLIOutput{}, nil)
+	service.compileRuntimeFn = func(bifrostDir string) error { return nil }
+
+	result := service.BuildProject(context.Background(), BuildInput{
+		MainFile:    filepath.Join(tmpDir, "main.go"),
+		OriginalCwd: tmpDir,
+	})
+	if result.Error != nil || !result.Success {
+		t.Fatalf("BuildProject() success = %v, error = %v", result.Success, result.Error)
+	}
+	for _, want := range []string{
+		`import { Page } from "../../pages/home.tsx";`,
+		"renderToString(sheet.collectStyles(<Page {...props} />))",
+		"head: sheet.getStyleTags()",
+	} {
+		if !strings.Contains(entry, want) {
+			t.Errorf("SSR entry missing %q:\n%s", want, entry)
+		}
+	}
+}
+
+func TestBuildProjectUsesReactRuntime(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+const runtime = "preact/compat"
+func main() {
+	app := bifrost.New(assets, bifrost.WithReactRuntime(runtime))
+	_ = Page("/", "./pages/home.tsx")
+}`)
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), "export function Page() { return <p>Home</p> }")
+
+	var clientEntry, ssrEntry string
+	renderer := &fakeRenderer{
+		buildFn: func(entrypoints []string, outdir string, entryNames []string) (map[string]core.ClientBuildResult, error) {
+			data, err := os.ReadFile(entrypoints[0])
+			if err != nil {
+				return nil, err
+			}
+			clientEntry = string(data)
+			return map[string]core.ClientBuildResult{
+				entryNames[0]: {Script: "/dist/" + entryNames[0] + ".js"},
+			}, nil
+		},
+		buildSSRFn: func(entrypoints []string, outdir string) error {
+			data, err := os.ReadFile(entrypoints[0])
+			if err != nil {
+				return err
+			}
+			ssrEntry = string(data)
+			name := strings.TrimSuffix(filepath.Base(entrypoints[0]), filepath.Ext(entrypoints[0]))
+			writeTestFile(t, filepath.Join(outdir, name+".js"), "// ssr")
+			return nil
+		},
+	}
+	service := NewBuildService(renderer, nil, &mockCLIOutput{}, nil)
+	service.compileRuntimeFn = func(bifrostDir string) error { return nil }
+
+	result := service.BuildProject(context.Background(), BuildInput{
+		MainFile:    filepath.Join(tmpDir, "main.go"),
+		OriginalCwd: tmpDir,
+	})
+	if result.Error != nil || !result.Success {
+		t.Fatalf("BuildProject() success = %v, error = %v", result.Success, result.Error)
+	}
+	for _, want := range []string{`from "preact/compat"`, `from "preact/compat/client"`} {
+		if !strings.Contains(clientEntry, want) {
+			t.Errorf("client entry missing %q:\n%s", want, clientEntry)
+		}
+	}
+	for _, want := range []string{`from "preact/compat"`, `from "preact/compat/server"`} {
+		if !strings.Contains(ssrEntry, want) {
+			t.Errorf("SSR entry missing %q:\n%s", want, ssrEntry)
+		}
+	}
+	if strings.Contains(clientEntry+ssrEntry, "react-dom") {
+		t.Errorf("entries still import react-dom:\n%s\n%s", clientEntry, ssrEntry)
+	}
+	if got := renderer.defines["process.env."+core.ReactRuntimeEnvKey]; got != `"preact/compat"` {
+		t.Errorf("build define = %q, want the import source", got)
+	}
+}
+
+func TestBuildProjectBuildTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		option      string
+		wantSuccess bool
+	}{
+		{name: "browser", option: `bifrost.WithBuildTarget("browser")`, wantSuccess: true},
+		{name: "node", option: `bifrost.WithBuildTarget("node")`, wantSuccess: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	app := bifrost.New(assets, `+tt.option+`)
+	_ = Page("/", "./pages/home.tsx", WithClient())
+}`)
+			writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), `export function Page() {
+	return <p>{window.location.pathname}</p>
+}`)
+
+			renderer := &fakeRenderer{
+				buildFn: func(entrypoints []string, outdir string, entryNames []string) (map[string]core.ClientBuildResult, error) {
+					return map[string]core.ClientBuildResult{
+						entryNames[0]: {Script: "/dist/" + entryNames[0] + ".js"},
+					}, nil
+				},
+			}
+			cliOutput := &mockCLIOutput{}
+			service := NewBuildService(renderer, nil, cliOutput, nil)
+			service.compileRuntimeFn = func(bifrostDir string) error { return nil }
+
+			result := service.BuildProject(context.Background(), BuildInput{
+				MainFile:    filepath.Join(tmpDir, "main.go"),
+				OriginalCwd: tmpDir,
+			})
+			if result.Error != nil || result.Success != tt.wantSuccess {
+				t.Fatalf("BuildProject() success = %v, error = %v; want success %v", result.Success, result.Error, tt.wantSuccess)
+			}
+			if renderer.target != tt.name {
+				t.Errorf("renderer target = %q, want %q", renderer.target, tt.name)
+			}
+			if !tt.wantSuccess {
+				return
+			}
+			data, err := os.ReadFile(filepath.Join(tmpDir, ".bifrost", "manifest.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var manifest core.Manifest
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				t.Fatal(err)
+			}
+			if got := manifest.Entries["pages-home-entry"].Target; got != tt.name {
+				t.Errorf("manifest target = %q, want %q", got, tt.name)
+			}
+		})
+	}
+}
+
+func TestBuildProjectRejectsInvalidSSREntryTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	app := bifrost.New(assets, bifrost.WithCustomSSREntryTemplate("export const render = {{.Component}}"))
+	_ = Page("/", "./pages/home.tsx")
+}`)
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), "export function Page() {}")
+
+	service := NewBuildService(&fakeRenderer{}, nil, &mockCLIOutput{}, nil)
+	result := service.BuildProject(context.Background(), BuildInput{
+		MainFile:    filepath.Join(tmpDir, "main.go"),
+		OriginalCwd: tmpDir,
+	})
+	if result.Error == nil || !strings.Contains(result.Error.Error(), "invalid SSR entry template") {
+		t.Fatalf("BuildProject() error = %v, want an invalid template error", result.Error)
+	}
+}
+
 func chdirForTest(t *testing.T, dir string) func() {
 	t.Helper()
 	cwd, err := os.Getwd()
@@ -736,3 +954,822 @@ func TestDeferredLoaderWithoutSyncLoader(t *testing.T) {
 		t.Fatalf("expected deferred props in __BIFROST_PROPS__, got %q", body)
 	}
 }
+
+func TestSSRTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		renderDelay time.Duration
+		wantTimeout bool
+	}{
+		{name: "fast render completes", renderDelay: 0},
+		{name: "slow render times out", renderDelay: time.Second, wantTimeout: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := &fakeRenderer{
+				streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+					select {
+					case <-time.After(tt.renderDelay):
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+					if err := onHead("<title>Home</title>"); err != nil {
+						return err
+					}
+					_, err := w.Write([]byte("<div>Hello</div>"))
+					return err
+				},
+			}
+			service := NewPageService(renderer, nil, nil)
+
+			output := service.ServePage(context.Background(), ServePageInput{
+				Config: core.PageConfig{
+					ComponentPath: "./pages/home.tsx",
+					Mode:          core.ModeSSR,
+				},
+				StaticPath:  "/ssr/pages-home-entry-ssr.js",
+				EntryName:   core.EntryNameForPath("./pages/home.tsx"),
+				RequestPath: "/",
+				Request:     httptest.NewRequest(http.MethodGet, "/", nil),
+				Shell:       &core.HTMLDocumentShell{},
+				SSRTimeout:  50 * time.Millisecond,
+			})
+			if output.Error != nil {
+				t.Fatalf("ServePage() error = %v", output.Error)
+			}
+
+			err := output.Stream(httptest.NewRecorder())
+			if !tt.wantTimeout {
+				if err != nil {
+					t.Fatalf("stream error = %v", err)
+				}
+				return
+			}
+
+			var timeoutErr *core.RenderTimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("stream error = %v, want RenderTimeoutError", err)
+			}
+			if timeoutErr.Timeout != 50*time.Millisecond {
+				t.Errorf("Timeout = %v, want 50ms", timeoutErr.Timeout)
+			}
+			if !strings.Contains(err.Error(), "timeout") {
+				t.Errorf("error %q should mention the timeout", err.Error())
+			}
+			if got := core.ErrorStatusCode(err); got != http.StatusServiceUnavailable {
+				t.Errorf("ErrorStatusCode() = %d, want 503", got)
+			}
+		})
+	}
+}
+
+func TestSSRInjectsMessagesIntoPropsAndLang(t *testing.T) {
+	var renderedProps map[string]any
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			renderedProps = props
+			return onHead("")
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+
+	output := service.ServePage(context.Background(), ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/home.tsx",
+			Mode:          core.ModeSSR,
+			PropsLoader: func(*http.Request) (map[string]any, error) {
+				return map[string]any{"title": "x"}, nil
+			},
+		},
+		StaticPath:  "/ssr/pages-home-entry-ssr.js",
+		EntryName:   core.EntryNameForPath("./pages/home.tsx"),
+		RequestPath: "/",
+		Request:     httptest.NewRequest(http.MethodGet, "/", nil),
+		Shell:       &core.HTMLDocumentShell{},
+		Messages: func(*http.Request) (string, map[string]string) {
+			return "de", map[string]string{"home.title": "Willkommen"}
+		},
+	})
+	if output.Error != nil {
+		t.Fatalf("ServePage() error = %v", output.Error)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := output.Stream(rec); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+	body := rec.Body.String()
+
+	msgs, ok := renderedProps[core.PropMessages].(map[string]string)
+	if !ok || msgs["home.title"] != "Willkommen" {
+		t.Fatalf("SSR props missing messages: %v", renderedProps)
+	}
+	if !strings.Contains(body, `"__messages":{"home.title":"Willkommen"}`) {
+		t.Errorf("expected messages in __BIFROST_PROPS__, got %q", body)
+	}
+	if !strings.Contains(body, `lang="de"`) {
+		t.Errorf("expected lang=de, got %q", body)
+	}
+	if strings.Contains(body, core.HydrationErrorPath) {
+		t.Errorf("hydration reporter must not be injected outside dev, got %q", body)
+	}
+}
+
+func TestSSRPrependsGlobalMetaTagsToHead(t *testing.T) {
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			return onHead(`<meta name="description" content="page" />`)
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+
+	output := service.ServePage(context.Background(), ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/home.tsx",
+			Mode:          core.ModeSSR,
+		},
+		StaticPath:     "/ssr/pages-home-entry-ssr.js",
+		EntryName:      core.EntryNameForPath("./pages/home.tsx"),
+		RequestPath:    "/",
+		Request:        httptest.NewRequest(http.MethodGet, "/", nil),
+		Shell:          &core.HTMLDocumentShell{},
+		GlobalHeadHTML: core.RenderMetaTags([]core.MetaTag{core.MetaProperty("og:site_name", "A & B")}),
+	})
+	if output.Error != nil {
+		t.Fatalf("ServePage() error = %v", output.Error)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := output.Stream(rec); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+	body := rec.Body.String()
+
+	global := strings.Index(body, `<meta property="og:site_name" content="A &amp; B" />`)
+	page := strings.Index(body, `<meta name="description" content="page" />`)
+	if global < 0 || page < 0 {
+		t.Fatalf("expected global and page meta tags, got %q", body)
+	}
+	if global > page {
+		t.Errorf("expected global meta tags before page head, got %q", body)
+	}
+}
+
+func TestExportStaticPages_IncludesGlobalMetaTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	renderer := &fakeRenderer{
+		renderFn: func(componentPath string, props map[string]any) (core.RenderedPage, error) {
+			return core.RenderedPage{Body: `<div>about</div>`, Head: `<title>About</title>`}, nil
+		},
+	}
+
+	err := ExportStaticPages(ExportStaticPagesInput{
+		OutputDir: tmpDir,
+		Routes:    []core.Route{core.Page("/about", "./pages/about.tsx", core.WithStatic())},
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			core.EntryNameForPath("./pages/about.tsx"): {Script: "/dist/about.js", Mode: "static"},
+		}},
+		AppConfig: &core.Config{
+			MetaTags: []core.MetaTag{core.MetaName("description", "Acme docs")},
+			Title:    core.TitleConfig{Template: "%s | Acme"},
+		},
+		SSBundlePath: func(string) string {
+			return "/ssr/about-ssr.js"
+		},
+		Renderer: renderer,
+	})
+	if err != nil {
+		t.Fatalf("ExportStaticPages() error = %v", err)
+	}
+
+	html, err := os.ReadFile(filepath.Join(tmpDir, "pages", "routes", "about", "index.html"))
+	if err != nil {
+		t.Fatalf("read about html: %v", err)
+	}
+	doc := string(html)
+	global := strings.Index(doc, `<meta name="description" content="Acme docs" />`)
+	if global < 0 {
+		t.Fatalf("expected global meta tag in static page: %s", doc)
+	}
+	if title := strings.Index(doc, `<title>About | Acme</title>`); title < 0 || title < global {
+		t.Errorf("expected page head after global meta tags: %s", doc)
+	}
+}
+
+func TestExportStaticPages_BakesPreloadTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	renderer := &fakeRenderer{
+		renderFn: func(componentPath string, props map[string]any) (core.RenderedPage, error) {
+			return core.RenderedPage{Body: `<div>home</div>`}, nil
+		},
+	}
+
+	err := ExportStaticPages(ExportStaticPagesInput{
+		OutputDir: tmpDir,
+		Routes:    []core.Route{core.Page("/", "./pages/home.tsx", core.WithStatic())},
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			core.EntryNameForPath("./pages/home.tsx"): {Script: "/dist/home.js", CSS: "/dist/home.css", Mode: "static"},
+		}},
+		AppConfig:    &core.Config{PreloadPaths: []string{"/public/hero.webp"}},
+		SSBundlePath: func(string) string { return "/ssr/home-ssr.js" },
+		Renderer:     renderer,
+	})
+	if err != nil {
+		t.Fatalf("ExportStaticPages() error = %v", err)
+	}
+
+	html, err := os.ReadFile(filepath.Join(tmpDir, "pages", "routes", "index.html"))
+	if err != nil {
+		t.Fatalf("read home html: %v", err)
+	}
+	doc := string(html)
+	preload := strings.Index(doc, `<link rel="preload" href="/public/hero.webp" as="image" />`)
+	if preload < 0 {
+		t.Fatalf("expected preload tag in static page: %s", doc)
+	}
+	if stylesheet := strings.Index(doc, `rel="stylesheet"`); stylesheet >= 0 && stylesheet < preload {
+		t.Errorf("expected preload before stylesheet: %s", doc)
+	}
+}
+
+func TestSSRAppliesTitleTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		head string
+		want string
+	}{
+		{name: "page title wrapped", head: `<title>About</title>`, want: `<title>About | Acme</title>`},
+		{name: "default title", head: `<meta name="a" content="b" />`, want: `<title>Acme</title>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := &fakeRenderer{
+				streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+					return onHead(tt.head)
+				},
+			}
+			service := NewPageService(renderer, nil, nil)
+
+			output := service.ServePage(context.Background(), ServePageInput{
+				Config: core.PageConfig{
+					ComponentPath: "./pages/about.tsx",
+					Mode:          core.ModeSSR,
+				},
+				StaticPath:  "/ssr/pages-about-entry-ssr.js",
+				EntryName:   core.EntryNameForPath("./pages/about.tsx"),
+				RequestPath: "/about",
+				Request:     httptest.NewRequest(http.MethodGet, "/about", nil),
+				Shell:       &core.HTMLDocumentShell{},
+				Title:       core.TitleConfig{Template: "%s | Acme", Default: "Acme"},
+			})
+			if output.Error != nil {
+				t.Fatalf("ServePage() error = %v", output.Error)
+			}
+
+			rec := httptest.NewRecorder()
+			if err := output.Stream(rec); err != nil {
+				t.Fatalf("stream error = %v", err)
+			}
+			body := rec.Body.String()
+			if !strings.Contains(body, tt.want) {
+				t.Errorf("expected %q in %q", tt.want, body)
+			}
+			if strings.Contains(body, "<title>Bifrost</title>") {
+				t.Errorf("unexpected fallback title in %q", body)
+			}
+		})
+	}
+}
+
+func TestScanPagesExpandsPagesFromDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	routes := append(PagesFromDir("./pages"), Page("/about", "./pages/about.tsx", WithClient()))
+	_ = routes
+}`)
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "index.tsx"), "")
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "about.tsx"), "")
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "blog", "[slug].tsx"), "")
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "_components", "nav.tsx"), "")
+
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+	configs, _, err := service.scanPages(filepath.Join(tmpDir, "main.go"), tmpDir)
+	if err != nil {
+		t.Fatalf("scanPages() error = %v", err)
+	}
+
+	modes := make(map[string]core.PageMode)
+	for _, c := range configs {
+		modes[c.ComponentPath] = c.Mode
+	}
+	want := map[string]core.PageMode{
+		"./pages/index.tsx":       core.ModeSSR,
+		"./pages/about.tsx":       core.ModeClientOnly,
+		"./pages/blog/[slug].tsx": core.ModeSSR,
+	}
+	if len(modes) != len(want) || len(configs) != len(want) {
+		t.Fatalf("scanPages() = %v, want %v", modes, want)
+	}
+	for path, mode := range want {
+		if got, ok := modes[path]; !ok || got != mode {
+			t.Errorf("%s: mode = %v (found %v), want %v", path, got, ok, mode)
+		}
+	}
+}
+
+func TestScanPagesAppliesRouteGroupOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	docs := bifrost.Group(bifrost.WithStatic(), bifrost.WithHTMLLang("en"))
+	docs.Page("/docs", "./pages/docs.tsx")
+	var admin = docs.Group("/admin", bifrost.WithPageGroup("admin"))
+	admin.Page("/", "./pages/admin.tsx", bifrost.WithClient(), bifrost.WithHTMLLang("de"))
+	bifrost.Group(bifrost.WithClient()).Page("/app", "./pages/app.tsx")
+	other.Page("/plain", "./pages/plain.tsx")
+}`)
+
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+	configs, _, err := service.scanPages(filepath.Join(tmpDir, "main.go"), tmpDir)
+	if err != nil {
+		t.Fatalf("scanPages() error = %v", err)
+	}
+
+	got := make(map[string]core.PageConfig)
+	for _, c := range configs {
+		got[c.ComponentPath] = c
+	}
+	want := map[string]core.PageConfig{
+		"./pages/docs.tsx":  {Mode: core.ModeStaticPrerender, HTMLLang: "en"},
+		"./pages/admin.tsx": {Mode: core.ModeClientOnly, HTMLLang: "de", Group: "admin"},
+		"./pages/app.tsx":   {Mode: core.ModeClientOnly},
+		"./pages/plain.tsx": {Mode: core.ModeSSR},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("scanPages() found %d pages, want %d", len(got), len(want))
+	}
+	for path, w := range want {
+		c := got[path]
+		if c.Mode != w.Mode || c.HTMLLang != w.HTMLLang || c.Group != w.Group {
+			t.Errorf("%s: mode, lang, group = %v, %q, %q, want %v, %q, %q", path, c.Mode, c.HTMLLang, c.Group, w.Mode, w.HTMLLang, w.Group)
+		}
+	}
+}
+
+func TestScanPagesWarnsOnDuplicateComponentModes(t *testing.T) {
+	tests := []struct {
+		name         string
+		main         string
+		wantWarnings int
+		wantMode     core.PageMode
+	}{
+		{
+			name: "different modes",
+			main: `package main
+func main() {
+	_ = Page("/a", "./pages/home.tsx")
+	_ = Page("/b", "./pages/home.tsx", WithClient())
+}`,
+			wantWarnings: 1,
+			wantMode:     core.ModeSSR,
+		},
+		{
+			name: "same mode",
+			main: `package main
+func main() {
+	_ = Page("/a", "./pages/home.tsx", WithClient())
+	_ = Page("/b", "./pages/home.tsx", WithClient())
+}`,
+			wantMode: core.ModeClientOnly,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			mainFile := filepath.Join(tmpDir, "main.go")
+			writeTestFile(t, mainFile, tt.main)
+
+			cli := &mockCLIOutput{}
+			service := NewBuildService(nil, nil, cli, nil)
+			configs, _, err := service.scanPages(mainFile, tmpDir)
+			if err != nil {
+				t.Fatalf("scanPages() error = %v", err)
+			}
+			if len(configs) != 1 || configs[0].Mode != tt.wantMode {
+				t.Fatalf("configs = %+v, want one page with mode %v", configs, tt.wantMode)
+			}
+			if len(cli.warnings) != tt.wantWarnings {
+				t.Fatalf("warnings = %q, want %d", cli.warnings, tt.wantWarnings)
+			}
+			if tt.wantWarnings > 0 {
+				w := cli.warnings[0]
+				for _, want := range []string{"./pages/home.tsx", "main.go:3:", "main.go:4:", "ssr", "client"} {
+					if !strings.Contains(w, want) {
+						t.Errorf("warning %q missing %q", w, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestScanPagesDetectsRevalidate(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	ttl := 10 * time.Minute
+	_ = Page("/pricing", "./pages/pricing.tsx", WithStatic(), WithRevalidate(ttl))
+	_ = Page("/about", "./pages/about.tsx", WithStatic())
+}`)
+
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+	configs, _, err := service.scanPages(filepath.Join(tmpDir, "main.go"), tmpDir)
+	if err != nil {
+		t.Fatalf("scanPages() error = %v", err)
+	}
+	for _, c := range configs {
+		got := c.Revalidate > 0
+		want := c.ComponentPath == "./pages/pricing.tsx"
+		if got != want {
+			t.Errorf("%s: revalidate = %v, want %v", c.ComponentPath, got, want)
+		}
+	}
+}
+
+func TestScanPagesDetectsHydrationStrategy(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	_ = Page("/", "./pages/home.tsx", bifrost.WithHydrationStrategy(bifrost.HydrationVisible))
+	_ = Page("/blog", "./pages/blog.tsx", WithStatic(), WithHydrationStrategy(HydrationImmediate))
+	_ = Page("/about", "./pages/about.tsx")
+}`)
+
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+	configs, _, err := service.scanPages(filepath.Join(tmpDir, "main.go"), tmpDir)
+	if err != nil {
+		t.Fatalf("scanPages() error = %v", err)
+	}
+	want := map[string]core.HydrationStrategy{
+		"./pages/home.tsx":  core.HydrationVisible,
+		"./pages/blog.tsx":  core.HydrationImmediate,
+		"./pages/about.tsx": core.HydrationLazy,
+	}
+	for _, c := range configs {
+		if c.Hydration != want[c.ComponentPath] {
+			t.Errorf("%s: hydration = %v, want %v", c.ComponentPath, c.Hydration, want[c.ComponentPath])
+		}
+	}
+}
+
+func TestScanPagesDetectsPageGroup(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	_ = Page("/", "./pages/home.tsx", bifrost.WithPageGroup("marketing"))
+	_ = Page("/about", "./pages/about.tsx")
+}`)
+
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+	configs, _, err := service.scanPages(filepath.Join(tmpDir, "main.go"), tmpDir)
+	if err != nil {
+		t.Fatalf("scanPages() error = %v", err)
+	}
+	want := map[string]string{
+		"./pages/home.tsx":  "marketing",
+		"./pages/about.tsx": "",
+	}
+	for _, c := range configs {
+		if c.Group != want[c.ComponentPath] {
+			t.Errorf("%s: group = %q, want %q", c.ComponentPath, c.Group, want[c.ComponentPath])
+		}
+	}
+}
+
+func TestScanPagesDetectsErrorBoundary(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	_ = Page("/", "./pages/home.tsx", bifrost.WithErrorBoundary("./pages/oops.tsx"))
+	_ = Page("/about", "./pages/about.tsx")
+}`)
+
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+	configs, _, err := service.scanPages(filepath.Join(tmpDir, "main.go"), tmpDir)
+	if err != nil {
+		t.Fatalf("scanPages() error = %v", err)
+	}
+	want := map[string]string{
+		"./pages/home.tsx":  "./pages/oops.tsx",
+		"./pages/about.tsx": "",
+	}
+	for _, c := range configs {
+		if c.ErrorBoundary != want[c.ComponentPath] {
+			t.Errorf("%s: error boundary = %q, want %q", c.ComponentPath, c.ErrorBoundary, want[c.ComponentPath])
+		}
+	}
+}
+
+func TestScanPagesIncludesStatusPages(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	app := bifrost.New(assets,
+		bifrost.WithStatusPage(404, "./pages/not-found.tsx"),
+		bifrost.WithStatusPage(500, "./pages/error.tsx"),
+		bifrost.WithStatusPage(503, "./pages/error.tsx"),
+	)
+	_ = Page("/", "./pages/home.tsx", bifrost.WithStatic())
+}`)
+
+	service := NewBuildService(nil, nil, &mockCLIOutput{}, nil)
+	configs, _, err := service.scanPages(filepath.Join(tmpDir, "main.go"), tmpDir)
+	if err != nil {
+		t.Fatalf("scanPages() error = %v", err)
+	}
+	modes := make(map[string]core.PageMode)
+	for _, c := range configs {
+		modes[c.ComponentPath] = c.Mode
+	}
+	want := map[string]core.PageMode{
+		"./pages/home.tsx":      core.ModeStaticPrerender,
+		"./pages/not-found.tsx": core.ModeSSR,
+		"./pages/error.tsx":     core.ModeSSR,
+	}
+	if len(modes) != len(want) || len(configs) != len(want) {
+		t.Fatalf("scanPages() = %v, want %v", modes, want)
+	}
+	for path, mode := range want {
+		if got := modes[path]; got != mode {
+			t.Errorf("%s: mode = %v, want %v", path, got, mode)
+		}
+	}
+}
+
+func TestScanBuildEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.go")
+	writeTestFile(t, mainFile, `package main
+const apiURL = "https://api.example.com"
+var publicEnv = map[string]string{"NEXT_PUBLIC_SITE": "docs"}
+func main() {
+	app := bifrost.NewWithOptions(assets, []bifrost.ConfigOption{
+		bifrost.WithBuildEnv(map[string]string{"NEXT_PUBLIC_API_URL": apiURL}),
+		bifrost.WithBuildEnv(publicEnv),
+	})
+}`)
+
+	got, err := scanBuildEnv(mainFile)
+	if err != nil {
+		t.Fatalf("scanBuildEnv() error = %v", err)
+	}
+	want := map[string]string{"NEXT_PUBLIC_API_URL": "https://api.example.com", "NEXT_PUBLIC_SITE": "docs"}
+	if !maps.Equal(got, want) {
+		t.Errorf("scanBuildEnv() = %v, want %v", got, want)
+	}
+
+	writeTestFile(t, mainFile, `package main
+func main() {
+	app := bifrost.NewWithOptions(assets, []bifrost.ConfigOption{
+		bifrost.WithBuildEnv(map[string]string{"NEXT_PUBLIC_API_URL": os.Getenv("API_URL")}),
+	})
+}`)
+	if _, err := scanBuildEnv(mainFile); err == nil {
+		t.Error("scanBuildEnv() with a runtime value should fail")
+	}
+}
+
+func TestScanBuildConstants(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.go")
+	writeTestFile(t, mainFile, `package main
+const buildSHA = "abc123"
+var constants = map[string]any{"BIFROST_BETA": true, "BIFROST_RATIO": -0.5}
+func main() {
+	app := bifrost.NewWithOptions(assets, []bifrost.ConfigOption{
+		bifrost.WithBuildConstants(map[string]any{"BIFROST_BUILD_SHA": buildSHA, "BIFROST_BUILD_NUMBER": 42, "BIFROST_LEGACY": nil}),
+		bifrost.WithBuildConstants(constants),
+	})
+}`)
+
+	got, err := scanBuildConstants(mainFile)
+	if err != nil {
+		t.Fatalf("scanBuildConstants() error = %v", err)
+	}
+	want := map[string]any{
+		"BIFROST_BUILD_SHA":    "abc123",
+		"BIFROST_BUILD_NUMBER": int64(42),
+		"BIFROST_LEGACY":       nil,
+		"BIFROST_BETA":         true,
+		"BIFROST_RATIO":        -0.5,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("scanBuildConstants() = %v, want %v", got, want)
+	}
+
+	writeTestFile(t, mainFile, `package main
+func main() {
+	app := bifrost.NewWithOptions(assets, []bifrost.ConfigOption{
+		bifrost.WithBuildConstants(map[string]any{"BIFROST_BUILD_SHA": os.Getenv("SHA")}),
+	})
+}`)
+	if _, err := scanBuildConstants(mainFile); err == nil {
+		t.Error("scanBuildConstants() with a runtime value should fail")
+	}
+}
+
+func TestScanEnvironment(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.go")
+	writeTestFile(t, mainFile, `package main
+func main() {
+	app := bifrost.NewWithOptions(assets, []bifrost.ConfigOption{
+		bifrost.WithEnvironment("staging"),
+	})
+}`)
+
+	got, err := scanEnvironment(mainFile)
+	if err != nil {
+		t.Fatalf("scanEnvironment() error = %v", err)
+	}
+	if got != "staging" {
+		t.Errorf("scanEnvironment() = %q, want %q", got, "staging")
+	}
+}
+
+func TestScanPreview(t *testing.T) {
+	tmpDir := t.TempDir()
+	mainFile := filepath.Join(tmpDir, "main.go")
+	writeTestFile(t, mainFile, `package main
+func main() {
+	app := bifrost.NewWithOptions(assets, []bifrost.ConfigOption{
+		bifrost.WithPreview(func(r *http.Request) bool { return bifrost.PreviewToken(r) == secret }),
+	})
+}`)
+
+	got, err := scanPreview(mainFile)
+	if err != nil {
+		t.Fatalf("scanPreview() error = %v", err)
+	}
+	if !got {
+		t.Error("scanPreview() = false, want true")
+	}
+}
+
+func TestSSRNonHTMLContentTypeRendersBodyOnly(t *testing.T) {
+	renderer := &fakeRenderer{
+		renderFn: func(componentPath string, props map[string]any) (core.RenderedPage, error) {
+			return core.RenderedPage{Body: `<rss version="2.0"><channel><title>` + props["title"].(string) + `</title></channel></rss>`, Head: `<title>ignored</title>`}, nil
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+
+	output := service.ServePage(context.Background(), ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/feed.tsx",
+			Mode:          core.ModeSSR,
+			ContentType:   "application/rss+xml",
+			PropsLoader: func(*http.Request) (map[string]any, error) {
+				return map[string]any{"title": "Posts"}, nil
+			},
+			DeferredPropsLoader: func(*http.Request) (map[string]any, error) {
+				t.Error("deferred loader should not run for non-HTML pages")
+				return nil, nil
+			},
+		},
+		StaticPath:  "/ssr/pages-feed-entry-ssr.js",
+		EntryName:   core.EntryNameForPath("./pages/feed.tsx"),
+		RequestPath: "/rss.xml",
+		Request:     httptest.NewRequest(http.MethodGet, "/rss.xml", nil),
+		Shell:       &core.HTMLDocumentShell{},
+	})
+	if output.Error != nil {
+		t.Fatalf("ServePage() error = %v", output.Error)
+	}
+	if output.Stream != nil {
+		t.Fatal("expected a buffered body, not a stream")
+	}
+	if renderer.streamCalls != 0 {
+		t.Errorf("expected no streaming render, got %d", renderer.streamCalls)
+	}
+	want := `<rss version="2.0"><channel><title>Posts</title></channel></rss>`
+	if output.HTML != want {
+		t.Errorf("body = %q, want %q", output.HTML, want)
+	}
+}
+
+func TestExportStaticPages_NonHTMLContentTypeWritesBodyOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	renderer := &fakeRenderer{
+		renderFn: func(componentPath string, props map[string]any) (core.RenderedPage, error) {
+			return core.RenderedPage{Body: `<svg xmlns="http://www.w3.org/2000/svg"></svg>`}, nil
+		},
+	}
+
+	err := ExportStaticPages(ExportStaticPagesInput{
+		OutputDir: tmpDir,
+		Routes:    []core.Route{core.Page("/logo.svg", "./pages/logo.tsx", core.WithStatic(), core.WithContentType("image/svg+xml"))},
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			core.EntryNameForPath("./pages/logo.tsx"): {Script: "/dist/logo.js", Mode: "static"},
+		}},
+		SSBundlePath: func(string) string {
+			return "/ssr/logo-ssr.js"
+		},
+		Renderer: renderer,
+	})
+	if err != nil {
+		t.Fatalf("ExportStaticPages() error = %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(tmpDir, "pages", "routes", "logo.svg", "index.html"))
+	if err != nil {
+		t.Fatalf("read exported file: %v", err)
+	}
+	if string(got) != `<svg xmlns="http://www.w3.org/2000/svg"></svg>` {
+		t.Errorf("exported file = %q, want body only", got)
+	}
+}
+
+func TestBuildProjectKeepsEveryStylesheetOfAnEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	_ = Page("/", "./pages/home.tsx", WithClient())
+}`)
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), `import "./base.css";
+import "./theme.css";
+export default function Home() { return <title>Home</title>; }`)
+
+	renderer := &fakeRenderer{
+		buildFn: func(entrypoints []string, outdir string, entryNames []string) (map[string]core.ClientBuildResult, error) {
+			name := entryNames[0]
+			return map[string]core.ClientBuildResult{
+				name: {
+					Script:   "/dist/" + name + "-abc.js",
+					CSS:      "/dist/base-abc.css",
+					CSSFiles: []string{"/dist/theme-def.css"},
+				},
+			}, nil
+		},
+	}
+	service := NewBuildService(renderer, nil, &mockCLIOutput{}, nil)
+
+	result := service.BuildProject(context.Background(), BuildInput{
+		MainFile:    filepath.Join(tmpDir, "main.go"),
+		OriginalCwd: tmpDir,
+	})
+	if result.Error != nil {
+		t.Fatalf("BuildProject() error = %v", result.Error)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".bifrost", "manifest.json"))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	var manifest core.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	entry := manifest.Entries["pages-home-entry"]
+	hrefs := core.StylesheetHrefs(entry.CSS, entry.CSSFiles)
+	if len(hrefs) != 2 || hrefs[0] != "/dist/base-abc.css" || hrefs[1] != "/dist/theme-def.css" {
+		t.Fatalf("stylesheets = %v, want both CSS files in import order", hrefs)
+	}
+
+	html, err := os.ReadFile(filepath.Join(tmpDir, ".bifrost", "pages", "pages-home-entry.html"))
+	if err != nil {
+		t.Fatalf("read html shell: %v", err)
+	}
+	for _, href := range hrefs {
+		if !strings.Contains(string(html), `href="`+href+`"`) {
+			t.Errorf("html shell missing stylesheet %s:\n%s", href, html)
+		}
+	}
+}
+
+func TestBuildProjectPassesBuildEnvDefines(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestFile(t, filepath.Join(tmpDir, "main.go"), `package main
+func main() {
+	_ = NewWithOptions(assets, []ConfigOption{WithBuildEnv(map[string]string{"NEXT_PUBLIC_API_URL": "https://api.example.com"})},
+		Page("/", "./pages/home.tsx", WithClient()))
+}`)
+	writeTestFile(t, filepath.Join(tmpDir, "pages", "home.tsx"), `export default function Home() { return <p>{process.env.NEXT_PUBLIC_API_URL}</p>; }`)
+
+	renderer := &fakeRenderer{}
+	service := NewBuildService(renderer, nil, &mockCLIOutput{}, nil)
+	result := service.BuildProject(context.Background(), BuildInput{
+		MainFile:    filepath.Join(tmpDir, "main.go"),
+		OriginalCwd: tmpDir,
+	})
+	if result.Error != nil {
+		t.Fatalf("BuildProject() error = %v", result.Error)
+	}
+	if got := renderer.defines["process.env.NEXT_PUBLIC_API_URL"]; got != `"https://api.example.com"` {
+		t.Errorf("defines = %v, want process.env.NEXT_PUBLIC_API_URL as a JSON string", renderer.defines)
+	}
+}
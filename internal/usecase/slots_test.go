@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestRenderSSRInjectsSlotsOutsideHydrationProps(t *testing.T) {
+	renderer := &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			if err := onHead(""); err != nil {
+				return err
+			}
+			// Stands in for a component rendering each slot with dangerouslySetInnerHTML.
+			slots, _ := props[core.PropSlots].(map[string]string)
+			_, err := w.Write([]byte("<h1>" + props["title"].(string) + "</h1>" + slots["topBanner"] + slots["cookieNotice"]))
+			return err
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+
+	shell, err := core.NewHTMLDocumentShell("/dist/home.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+	config := core.PageConfig{
+		ComponentPath: "./pages/home.tsx",
+		Mode:          core.ModeSSR,
+		PropsLoader: func(*http.Request) (map[string]any, error) {
+			return map[string]any{"title": "Home"}, nil
+		},
+	}
+	core.WithSlotInjection("topBanner", `<div class="banner">Sale</div>`)(&config)
+	core.WithSlotInjection("cookieNotice", `<div id="cookies">We use cookies</div>`)(&config)
+
+	output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+		Config:      config,
+		EntryName:   "pages-home-entry",
+		RequestPath: "/",
+		Request:     httptest.NewRequest(http.MethodGet, "/", nil),
+		Shell:       &shell,
+	}))
+	if output.Error != nil {
+		t.Fatalf("renderSSR() error = %v", output.Error)
+	}
+	rec := httptest.NewRecorder()
+	if err := output.Stream(rec); err != nil {
+		t.Fatalf("stream error = %v", err)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`<h1>Home</h1><div class="banner">Sale</div><div id="cookies">We use cookies</div>`,
+		`<script id="__BIFROST_PROPS__" type="application/json">{"title":"Home"}</script>`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, core.PropSlots) {
+		t.Errorf("slots leaked into the hydration props:\n%s", body)
+	}
+}
@@ -4,8 +4,33 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
+// ssrBundleChunks lists the files Bun wrote next to the SSR entry bundles in ssrDir as
+// manifest paths ("/ssr/chunk-abc.js"). Bun does not report which entry imports which
+// chunk, so every SSR entry lists all of them.
+func ssrBundleChunks(ssrDir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(ssrDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list SSR chunks: %w", err)
+	}
+	var chunks []string
+	for _, d := range dirEntries {
+		name := d.Name()
+		if d.IsDir() || !strings.HasSuffix(name, ".js") || strings.HasSuffix(name, "-ssr.js") {
+			continue
+		}
+		chunks = append(chunks, "/ssr/"+name)
+	}
+	sort.Strings(chunks)
+	return chunks, nil
+}
+
 func normalizeSSRBundle(ssrDir, entryName string) (string, error) {
 	expectedPath := filepath.Join(ssrDir, entryName+"-ssr.js")
 	if _, err := os.Stat(expectedPath); err == nil {
@@ -114,3 +114,21 @@ func TestCompileDevPageOnDemandNormalizesNestedSSRBundle(t *testing.T) {
 		t.Fatalf("expected nested SSR bundle removed, got %v", err)
 	}
 }
+
+func TestSSRBundleChunksListsSiblingFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "pages-home-entry-ssr.js"), "// ssr")
+	writeTestFile(t, filepath.Join(dir, "chunk-b.js"), "// chunk")
+	writeTestFile(t, filepath.Join(dir, "chunk-a.js"), "// chunk")
+	writeTestFile(t, filepath.Join(dir, "styles.css"), "")
+
+	got, err := ssrBundleChunks(dir)
+	if err != nil {
+		t.Fatalf("ssrBundleChunks() error = %v", err)
+	}
+	want := []string{"/ssr/chunk-a.js", "/ssr/chunk-b.js"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("ssrBundleChunks() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"log/slog"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// maxLastRenders bounds the renders kept for FallbackCachedOrError.
+const maxLastRenders = 1000
+
+// renderSSRFallback answers an SSR page whose renderer is unavailable according to the
+// page's WithSSRFallback mode; err is the error FallbackError fails with.
+func (s *PageService) renderSSRFallback(state pageRequestState, shell core.HTMLDocumentShell, cacheKey string, props map[string]any, lang string, htmlClass string, err error) ServePageOutput {
+	input := state.input
+	switch input.SSRFallback {
+	case core.FallbackClientOnly:
+		slog.Warn("bifrost: renderer unavailable, serving the client-only shell", "entry", input.EntryName, "path", input.RequestPath, "error", err)
+		html, err := shell.Render("", props, pageHeadHTML(input, ""), lang, htmlClass)
+		return ServePageOutput{
+			Action: core.ActionRenderSSR,
+			HTML:   html,
+			Props:  props,
+			Error:  err,
+		}
+	case core.FallbackCachedOrError:
+		if cacheKey != "" {
+			if html, ok := s.lastRenders.Get(cacheKey); ok {
+				slog.Warn("bifrost: renderer unavailable, serving the last render", "entry", input.EntryName, "path", input.RequestPath, "error", err)
+				return ServePageOutput{
+					Action: core.ActionRenderSSR,
+					HTML:   html,
+					Props:  props,
+				}
+			}
+		}
+	}
+	return ServePageOutput{
+		Action: core.ActionRenderSSR,
+		Error:  err,
+	}
+}
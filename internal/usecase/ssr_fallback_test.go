@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// serveWithFallback renders /home through renderSSR and writes the result like the page
+// handler does, returning the response and the error the handler would turn into a 500.
+func serveWithFallback(t *testing.T, service *PageService, mode core.SSRFallbackMode) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	shell, err := core.NewHTMLDocumentShell("/dist/home.js", "", nil, nil)
+	if err != nil {
+		t.Fatalf("new shell: %v", err)
+	}
+	output := service.renderSSR(context.Background(), service.prepareRequest(ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/home.tsx",
+			Mode:          core.ModeSSR,
+			PropsLoader: func(*http.Request) (map[string]any, error) {
+				return map[string]any{"name": "Ada"}, nil
+			},
+		},
+		EntryName:   "pages-home-entry",
+		RequestPath: "/home",
+		Request:     httptest.NewRequest(http.MethodGet, "/home", nil),
+		Shell:       &shell,
+		SSRFallback: mode,
+	}))
+	rr := httptest.NewRecorder()
+	if output.Error != nil {
+		return rr, output.Error
+	}
+	if output.Stream != nil {
+		return rr, output.Stream(rr)
+	}
+	rr.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(rr, output.HTML)
+	return rr, nil
+}
+
+// newFlakyRenderer renders the page until down is set, then fails as a crashed runtime.
+func newFlakyRenderer(down *bool) *fakeRenderer {
+	return &fakeRenderer{
+		streamFn: func(ctx context.Context, componentPath string, props map[string]any, w http.ResponseWriter, flush func(), onHead func(head string) error) error {
+			if *down {
+				return fmt.Errorf("%w: dial unix /tmp/bifrost.sock: connect: connection refused", core.ErrRendererUnavailable)
+			}
+			if err := onHead(""); err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, "<h1>Hello "+props["name"].(string)+"</h1>")
+			return err
+		},
+	}
+}
+
+func TestRenderSSRFallbackClientOnly(t *testing.T) {
+	down := true
+	for _, tt := range []struct {
+		name    string
+		service *PageService
+	}{
+		{name: "no renderer", service: NewPageService(nil, nil, nil)},
+		{name: "renderer down", service: NewPageService(newFlakyRenderer(&down), nil, nil)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, err := serveWithFallback(t, tt.service, core.FallbackClientOnly)
+			if err != nil {
+				t.Fatalf("error = %v, want the client-only shell", err)
+			}
+			body := rr.Body.String()
+			if rr.Code != http.StatusOK {
+				t.Errorf("status = %d, want 200", rr.Code)
+			}
+			if !strings.Contains(body, `src="/dist/home.js"`) || !strings.Contains(body, `"name":"Ada"`) {
+				t.Errorf("body = %q, want the page script and props", body)
+			}
+			if strings.Contains(body, "<h1>") {
+				t.Errorf("body = %q, want no server-rendered markup", body)
+			}
+		})
+	}
+}
+
+func TestRenderSSRFallbackCachedOrError(t *testing.T) {
+	down := false
+	service := NewPageService(newFlakyRenderer(&down), nil, nil)
+
+	rendered, err := serveWithFallback(t, service, core.FallbackCachedOrError)
+	if err != nil {
+		t.Fatalf("first render error = %v", err)
+	}
+
+	down = true
+	rr, err := serveWithFallback(t, service, core.FallbackCachedOrError)
+	if err != nil {
+		t.Fatalf("error = %v, want the last render", err)
+	}
+	if rr.Code != http.StatusOK || rr.Body.String() != rendered.Body.String() {
+		t.Errorf("fallback = %d %q, want 200 with the last render %q", rr.Code, rr.Body.String(), rendered.Body.String())
+	}
+
+	_, err = serveWithFallback(t, NewPageService(newFlakyRenderer(&down), nil, nil), core.FallbackCachedOrError)
+	if !errors.Is(err, core.ErrRendererUnavailable) {
+		t.Errorf("error without a previous render = %v, want ErrRendererUnavailable", err)
+	}
+}
+
+func TestRenderSSRFallbackError(t *testing.T) {
+	down := true
+	for _, service := range []*PageService{NewPageService(nil, nil, nil), NewPageService(newFlakyRenderer(&down), nil, nil)} {
+		rr, err := serveWithFallback(t, service, core.FallbackError)
+		if !errors.Is(err, core.ErrRendererUnavailable) {
+			t.Errorf("error = %v, want ErrRendererUnavailable", err)
+		}
+		if rr.Body.Len() != 0 {
+			t.Errorf("body = %q, want nothing written before the error page", rr.Body.String())
+		}
+	}
+}
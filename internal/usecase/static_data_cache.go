@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+// staticDataCache keeps each static page's StaticDataLoader result for a short TTL in dev,
+// so matching one request path does not refetch the whole path list. An entry is dropped
+// early when the page's component file changes.
+type staticDataCache struct {
+	now   func() time.Time
+	group singleflightGroup
+
+	mu      sync.Mutex
+	entries map[string]staticDataCacheEntry
+}
+
+type staticDataCacheEntry struct {
+	data      []core.StaticPathData
+	loadedAt  time.Time
+	sourceMod time.Time
+}
+
+func newStaticDataCache() *staticDataCache {
+	return &staticDataCache{
+		now:     time.Now,
+		entries: make(map[string]staticDataCacheEntry),
+	}
+}
+
+// load returns the loader result for key, calling loader when there is no fresh entry.
+// Concurrent misses for the same key share one loader call.
+func (c *staticDataCache) load(ctx context.Context, key, componentPath string, ttl time.Duration, loader core.StaticDataLoader) ([]core.StaticPathData, error) {
+	if ttl <= 0 {
+		return loader(ctx)
+	}
+	sourceMod := fileModTime(componentPath)
+	if data, ok := c.lookup(key, ttl, sourceMod); ok {
+		return data, nil
+	}
+
+	err := c.group.Do(key, func() error {
+		if _, ok := c.lookup(key, ttl, sourceMod); ok {
+			return nil
+		}
+		data, err := loader(ctx)
+		if err != nil {
+			return err
+		}
+		c.mu.Lock()
+		c.entries[key] = staticDataCacheEntry{data: data, loadedAt: c.now(), sourceMod: sourceMod}
+		c.mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data, ok := c.lookup(key, ttl, sourceMod); ok {
+		return data, nil
+	}
+	return loader(ctx)
+}
+
+func (c *staticDataCache) lookup(key string, ttl time.Duration, sourceMod time.Time) ([]core.StaticPathData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !entry.sourceMod.Equal(sourceMod) || c.now().Sub(entry.loadedAt) >= ttl {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
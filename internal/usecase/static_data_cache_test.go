@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestDevStaticPrerenderCachesStaticData(t *testing.T) {
+	component := filepath.Join(t.TempDir(), "post.tsx")
+	writeTestFile(t, component, "export function Page() {}")
+
+	calls := 0
+	loader := func(context.Context) ([]core.StaticPathData, error) {
+		calls++
+		return []core.StaticPathData{
+			{Path: "/blog/a", Props: map[string]any{"slug": "a"}},
+			{Path: "/blog/b", Props: map[string]any{"slug": "b"}},
+		}, nil
+	}
+	service := NewPageService(&fakeRenderer{}, nil, nil)
+	now := time.Now()
+	service.staticData.now = func() time.Time { return now }
+
+	serve := func(path string, ttl time.Duration) {
+		t.Helper()
+		output := service.renderStaticPrerender(context.Background(), service.prepareRequest(ServePageInput{
+			Config: core.PageConfig{
+				ComponentPath:    component,
+				Mode:             core.ModeStaticPrerender,
+				StaticDataLoader: loader,
+			},
+			IsDev:              true,
+			EntryName:          "pages-blog-post-entry",
+			RequestPath:        path,
+			Shell:              &core.HTMLDocumentShell{},
+			StaticDataCacheTTL: ttl,
+		}))
+		if output.Error != nil || output.Action != core.ActionRenderStaticPrerender {
+			t.Fatalf("serve %s: action %v, error %v", path, output.Action, output.Error)
+		}
+	}
+
+	serve("/blog/a", time.Minute)
+	serve("/blog/b", time.Minute)
+	if calls != 1 {
+		t.Fatalf("expected one loader call within the TTL, got %d", calls)
+	}
+
+	now = now.Add(time.Minute)
+	serve("/blog/a", time.Minute)
+	if calls != 2 {
+		t.Fatalf("expected reload after the TTL, got %d calls", calls)
+	}
+
+	later := now.Add(time.Hour)
+	if err := os.Chtimes(component, later, later); err != nil {
+		t.Fatal(err)
+	}
+	serve("/blog/a", time.Minute)
+	if calls != 3 {
+		t.Fatalf("expected reload after the component changed, got %d calls", calls)
+	}
+
+	serve("/blog/a", 0)
+	serve("/blog/a", 0)
+	if calls != 5 {
+		t.Fatalf("expected no caching with a zero TTL, got %d calls", calls)
+	}
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/3-lines-studio/bifrost/internal/core"
+)
+
+func TestExportStaticPages_StreamRendersPathsAsEmitted(t *testing.T) {
+	tmpDir := t.TempDir()
+	renderer := &fakeRenderer{
+		renderFn: func(componentPath string, props map[string]any) (core.RenderedPage, error) {
+			return core.RenderedPage{Body: fmt.Sprintf("<h1>%v</h1>", props["n"])}, nil
+		},
+	}
+	routeFile := func(n int) string {
+		return filepath.Join(tmpDir, "pages", "routes", "items", fmt.Sprint(n), "index.html")
+	}
+
+	stream := func(ctx context.Context, emit func(core.StaticPathData) error) error {
+		for n := 1; n <= 3; n++ {
+			if n > 1 {
+				if _, err := os.Stat(routeFile(n - 1)); err != nil {
+					t.Errorf("path %d not written before the next emit: %v", n-1, err)
+				}
+			}
+			if err := emit(core.StaticPathData{Path: fmt.Sprintf("/items/%d", n), Props: map[string]any{"n": n}}); err != nil {
+				return err
+			}
+		}
+		return errors.New("db went away")
+	}
+
+	err := ExportStaticPages(ExportStaticPagesInput{
+		OutputDir: tmpDir,
+		Routes:    []core.Route{core.Page("/items/{n}", "./pages/item.tsx", core.WithStaticDataStream(stream))},
+		Manifest: &core.Manifest{Entries: map[string]core.ManifestEntry{
+			core.EntryNameForPath("./pages/item.tsx"): {Script: "/dist/item.js", Mode: "static"},
+		}},
+		SSBundlePath: func(string) string {
+			return "/ssr/item-ssr.js"
+		},
+		Renderer: renderer,
+	})
+	if err != nil {
+		t.Fatalf("ExportStaticPages() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "export-manifest.json"))
+	if err != nil {
+		t.Fatalf("read export manifest: %v", err)
+	}
+	var man core.Manifest
+	if err := json.Unmarshal(data, &man); err != nil {
+		t.Fatalf("parse export manifest: %v", err)
+	}
+	routes := man.Entries[core.EntryNameForPath("./pages/item.tsx")].StaticRoutes
+	if len(routes) != 3 {
+		t.Fatalf("expected the 3 paths emitted before the stream failed, got %v", routes)
+	}
+	if routes["/items/2"] != "/pages/routes/items/2/index.html" {
+		t.Errorf("unexpected route file for /items/2: %q", routes["/items/2"])
+	}
+}
+
+func TestPageServiceStaticDataStreamStopsAtRequestedPath(t *testing.T) {
+	renderer := &fakeRenderer{
+		renderFn: func(componentPath string, props map[string]any) (core.RenderedPage, error) {
+			return core.RenderedPage{Body: fmt.Sprintf("<h1>%v</h1>", props["n"])}, nil
+		},
+	}
+	service := NewPageService(renderer, nil, nil)
+
+	emitted := 0
+	input := ServePageInput{
+		Config: core.PageConfig{
+			ComponentPath: "./pages/item.tsx",
+			Mode:          core.ModeStaticPrerender,
+			StaticDataStream: func(ctx context.Context, emit func(core.StaticPathData) error) error {
+				for n := 1; n <= 100; n++ {
+					emitted++
+					if err := emit(core.StaticPathData{Path: fmt.Sprintf("/items/%d", n), Props: map[string]any{"n": n}}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		IsDev:       true,
+		EntryName:   "pages-item-entry",
+		RequestPath: "/items/3",
+		Shell:       &core.HTMLDocumentShell{},
+	}
+
+	output := service.renderStaticPrerender(context.Background(), service.prepareRequest(input))
+	if output.Error != nil {
+		t.Fatalf("ServePage() error = %v", output.Error)
+	}
+	if output.Action != core.ActionRenderStaticPrerender {
+		t.Fatalf("ServePage() action = %v", output.Action)
+	}
+	if output.Props["n"] != 3 {
+		t.Errorf("props = %v, want n=3", output.Props)
+	}
+	if emitted != 3 {
+		t.Errorf("stream emitted %d paths, want it to stop at 3", emitted)
+	}
+
+	input.RequestPath = "/items/missing"
+	output = service.renderStaticPrerender(context.Background(), service.prepareRequest(input))
+	if output.Action != core.ActionNotFound {
+		t.Fatalf("missing path: action = %v, want not found", output.Action)
+	}
+}